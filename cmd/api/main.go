@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
@@ -17,6 +18,7 @@ import (
 	"github.com/grafikarsa/backend/internal/auth"
 	"github.com/grafikarsa/backend/internal/config"
 	"github.com/grafikarsa/backend/internal/database"
+	"github.com/grafikarsa/backend/internal/embed"
 	"github.com/grafikarsa/backend/internal/handler"
 	"github.com/grafikarsa/backend/internal/middleware"
 	"github.com/grafikarsa/backend/internal/repository"
@@ -61,33 +63,72 @@ func main() {
 	changelogRepo := repository.NewChangelogRepository(db)
 	commentRepo := repository.NewCommentRepository(db)
 	dmRepo := repository.NewDMRepository(db)
+	teacherRepo := repository.NewTeacherRepository(db)
+	revisionRepo := repository.NewRevisionRepository(db)
+	draftRepo := repository.NewDraftRepository(db)
+	collabRepo := repository.NewCollaboratorRepository(db)
+	assignmentRepo := repository.NewSeriesAssignmentRepository(db)
+	embedRepo := repository.NewEmbedRepository(db)
+	shareLinkRepo := repository.NewShareLinkRepository(db)
+	bookmarkRepo := repository.NewBookmarkRepository(db)
+	reportRepo := repository.NewReportRepository(db)
+	reviewRepo := repository.NewReviewRepository(db)
+	rejectionRepo := repository.NewRejectionRepository(db)
+	textFilterRepo := repository.NewTextFilterRepository(db)
+	analyticsRepo := repository.NewAnalyticsRepository(db)
 
 	// Initialize services
-	notificationService := service.NewNotificationService(notificationRepo)
+	notificationService := service.NewNotificationService(notificationRepo, collabRepo)
+	textFilterService := service.NewTextFilterService(textFilterRepo, reportRepo)
+	analyticsService := service.NewPortfolioAnalyticsService(analyticsRepo)
 	feedService := service.NewFeedService(portfolioRepo, followRepo, viewRepo, interestRepo)
 	commentService := service.NewCommentService(commentRepo, userRepo, portfolioRepo, notificationRepo)
 	commentService.SetNotificationService(notificationService)
+	commentService.SetTextFilter(textFilterService)
 	dmService := service.NewDMService(dmRepo, userRepo, followRepo)
+	dmService.SetTextFilter(textFilterService)
+	revisionService := service.NewRevisionService(revisionRepo)
+	draftService := service.NewDraftService(draftRepo, adminRepo)
+	publishScheduler := service.NewPublishScheduler(portfolioRepo, notificationService)
+	collabService := service.NewCollaborationService(collabRepo, userRepo, notificationService)
+	templateService := service.NewSeriesTemplateService(portfolioRepo)
+	assignmentService := service.NewSeriesAssignmentService(assignmentRepo, notificationService)
+	// Uploaded files are checked in the bucket; the storage URL may not be publicly routable
+	embedProviders := append([]embed.Provider{embed.NewStorageProvider(cfg.MinIO.PublicURL, minioClient)}, embed.DefaultProviders(embed.NewHTTPClient(10*time.Second))...)
+	embedResolver := embed.NewResolver(embedProviders...)
+	embedService := service.NewEmbedService(embedResolver, embedRepo, portfolioRepo, notificationService)
+	shareLinkService := service.NewShareLinkService(shareLinkRepo, portfolioRepo, notificationService)
+	bookmarkService := service.NewBookmarkService(bookmarkRepo, portfolioRepo)
+	profileOrderService := service.NewProfileOrderService(portfolioRepo)
+	reviewClaimService := service.NewReviewClaimService(reviewRepo, adminRepo, userRepo)
+	rejectionService := service.NewRejectionService(rejectionRepo)
+	reportService := service.NewReportService(reportRepo, portfolioRepo, commentRepo, dmRepo, userRepo, adminRepo, notificationService)
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(userRepo, authRepo, jwtService)
 	userHandler := handler.NewUserHandler(userRepo, followRepo, notificationService)
-	profileHandler := handler.NewProfileHandler(userRepo, adminRepo)
-	portfolioHandler := handler.NewPortfolioHandler(portfolioRepo, userRepo, viewRepo, interestRepo, notificationService)
-	contentBlockHandler := handler.NewContentBlockHandler(portfolioRepo)
-	adminHandler := handler.NewAdminHandler(adminRepo, userRepo, portfolioRepo, notificationService)
-	uploadHandler := handler.NewUploadHandler(minioClient, userRepo, portfolioRepo)
+	profileHandler := handler.NewProfileHandler(userRepo, adminRepo, textFilterService, jwtService)
+	portfolioHandler := handler.NewPortfolioHandler(portfolioRepo, userRepo, viewRepo, interestRepo, notificationService, revisionService, draftService, collabService, templateService, shareLinkRepo, shareLinkService, profileOrderService, rejectionRepo, textFilterService, analyticsService)
+	contentBlockHandler := handler.NewContentBlockHandler(portfolioRepo, draftService, collabService, templateService, embedService, textFilterService)
+	adminHandler := handler.NewAdminHandler(adminRepo, userRepo, portfolioRepo, notificationService, revisionService, draftService, assignmentRepo, assignmentService, reviewRepo, reviewClaimService, rejectionRepo, rejectionService)
+	uploadHandler := handler.NewUploadHandler(minioClient, userRepo, portfolioRepo, draftService, collabService)
 	tagHandler := handler.NewTagHandler(adminRepo)
 	publicHandler := handler.NewPublicHandler(adminRepo, userRepo)
 	feedHandler := handler.NewFeedHandler(feedRepo, feedService, interestRepo, userRepo)
 	searchHandler := handler.NewSearchHandler(userRepo, portfolioRepo)
 	feedbackHandler := handler.NewFeedbackHandler(feedbackRepo, userRepo, notificationService)
-	assessmentHandler := handler.NewAssessmentHandler(assessmentRepo, portfolioRepo)
-	notificationHandler := handler.NewNotificationHandler(notificationRepo, userRepo, followRepo)
+	assessmentHandler := handler.NewAssessmentHandler(assessmentRepo, portfolioRepo, revisionService)
+	notificationHandler := handler.NewNotificationHandler(notificationRepo, userRepo, followRepo, collabService)
 	importHandler := handler.NewImportHandler(adminRepo, userRepo)
+	teacherHandler := handler.NewTeacherHandler(teacherRepo, adminRepo, portfolioRepo, assessmentRepo, assignmentService)
 	changelogHandler := handler.NewChangelogHandler(changelogRepo, notificationService, userRepo)
 	commentHandler := handler.NewCommentHandler(commentService)
 	dmHandler := handler.NewDMHandler(dmService)
+	revisionHandler := handler.NewRevisionHandler(revisionRepo, portfolioRepo, adminRepo, teacherRepo)
+	collaboratorHandler := handler.NewCollaboratorHandler(collabService, collabRepo, portfolioRepo, userRepo)
+	bookmarkHandler := handler.NewBookmarkHandler(bookmarkService, bookmarkRepo, portfolioRepo, userRepo)
+	reportHandler := handler.NewReportHandler(reportService, reportRepo, userRepo)
+	textFilterHandler := handler.NewTextFilterHandler(textFilterRepo, textFilterService)
 	wsHandler := handler.NewWebSocketHandler()
 
 	// Initialize auth middleware
@@ -190,6 +231,7 @@ func main() {
 	userRoutes.Get("/:username/following", authMiddleware.Optional(), userHandler.GetFollowing)
 	userRoutes.Post("/:username/follow", authMiddleware.Required(), userHandler.Follow)
 	userRoutes.Delete("/:username/follow", authMiddleware.Required(), userHandler.Unfollow)
+	userRoutes.Get("/:username/collections", authMiddleware.Optional(), bookmarkHandler.ListByUser)
 
 	// Profile routes (me)
 	api.Get("/me", authMiddleware.Required(), profileHandler.GetMe)
@@ -198,6 +240,10 @@ func main() {
 	api.Put("/me/social-links", authMiddleware.Required(), profileHandler.UpdateSocialLinks)
 	api.Get("/me/check-username", authMiddleware.Required(), profileHandler.CheckUsername)
 	api.Get("/me/portfolios", authMiddleware.Required(), portfolioHandler.GetMyPortfolios)
+	api.Put("/me/portfolios/reorder", authMiddleware.Required(), portfolioHandler.ReorderMyPortfolios)
+	api.Post("/me/portfolios/:id/pin", authMiddleware.Required(), portfolioHandler.PinPortfolio)
+	api.Delete("/me/portfolios/:id/pin", authMiddleware.Required(), portfolioHandler.UnpinPortfolio)
+	api.Get("/me/collections", authMiddleware.Required(), bookmarkHandler.ListMine)
 
 	// Portfolio routes
 	portfolioRoutes := api.Group("/portfolios")
@@ -210,9 +256,54 @@ func main() {
 	portfolioRoutes.Post("/:id/submit", authMiddleware.Required(), portfolioHandler.Submit)
 	portfolioRoutes.Post("/:id/archive", authMiddleware.Required(), portfolioHandler.Archive)
 	portfolioRoutes.Post("/:id/unarchive", authMiddleware.Required(), portfolioHandler.Unarchive)
+	portfolioRoutes.Delete("/:id/draft", authMiddleware.Required(), portfolioHandler.DiscardDraft)
+	portfolioRoutes.Get("/:id/revisions", authMiddleware.Required(), revisionHandler.List)
+	portfolioRoutes.Get("/:id/revisions/diff", authMiddleware.Required(), revisionHandler.Diff)
+	portfolioRoutes.Get("/:id/revisions/:number", authMiddleware.Required(), revisionHandler.Get)
+	portfolioRoutes.Get("/:id/collaborators", authMiddleware.Optional(), collaboratorHandler.List)
+	portfolioRoutes.Post("/:id/collaborators", authMiddleware.Required(), collaboratorHandler.Invite)
+	portfolioRoutes.Patch("/:id/collaborators/:collaborator_id", authMiddleware.Required(), collaboratorHandler.UpdateRole)
+	portfolioRoutes.Delete("/:id/collaborators/:collaborator_id", authMiddleware.Required(), collaboratorHandler.Remove)
 	portfolioRoutes.Post("/:id/like", authMiddleware.Required(), portfolioHandler.Like)
 	portfolioRoutes.Post("/:id/like", authMiddleware.Required(), portfolioHandler.Like)
 	portfolioRoutes.Delete("/:id/like", authMiddleware.Required(), portfolioHandler.Unlike)
+	portfolioRoutes.Get("/:id/analytics", authMiddleware.Required(), portfolioHandler.GetAnalytics)
+	portfolioRoutes.Get("/:id/share-links", authMiddleware.Required(), portfolioHandler.ListShareLinks)
+	portfolioRoutes.Post("/:id/share-links", authMiddleware.Required(), portfolioHandler.CreateShareLink)
+	portfolioRoutes.Get("/:id/share-links/comments", authMiddleware.Required(), portfolioHandler.ListShareComments)
+	portfolioRoutes.Delete("/:id/share-links/:link_id", authMiddleware.Required(), portfolioHandler.RevokeShareLink)
+
+	// Shared portfolio routes (no login, the token is the credential)
+	shareCommentLimiter := limiter.New(limiter.Config{
+		Max:        5,
+		Expiration: 1 * time.Minute,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return c.IP()
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"success": false,
+				"error": fiber.Map{
+					"code":    "TOO_MANY_REQUESTS",
+					"message": "Terlalu banyak komentar. Silakan coba lagi dalam satu menit.",
+				},
+			})
+		},
+	})
+	sharedRoutes := api.Group("/shared")
+	sharedRoutes.Get("/:token", portfolioHandler.GetShared)
+	sharedRoutes.Get("/:token/comments", portfolioHandler.GetSharedComments)
+	sharedRoutes.Post("/:token/comments", shareCommentLimiter, portfolioHandler.CreateSharedComment)
+
+	// Bookmark collection routes; public collections are shared by their link
+	collectionRoutes := api.Group("/collections")
+	collectionRoutes.Post("/", authMiddleware.Required(), bookmarkHandler.Create)
+	collectionRoutes.Get("/:id", authMiddleware.Optional(), bookmarkHandler.Get)
+	collectionRoutes.Patch("/:id", authMiddleware.Required(), bookmarkHandler.Update)
+	collectionRoutes.Delete("/:id", authMiddleware.Required(), bookmarkHandler.Delete)
+	collectionRoutes.Post("/:id/items", authMiddleware.Required(), bookmarkHandler.AddItem)
+	collectionRoutes.Put("/:id/items/reorder", authMiddleware.Required(), bookmarkHandler.ReorderItems)
+	collectionRoutes.Delete("/:id/items/:portfolio_id", authMiddleware.Required(), bookmarkHandler.RemoveItem)
 
 	// Comment Routes (nested under portfolios)
 	portfolioRoutes.Post("/:portfolio_id/comments", authMiddleware.Required(), commentHandler.Create)
@@ -237,7 +328,7 @@ func main() {
 	api.Get("/series", publicHandler.ListSeries)
 	api.Get("/series/:id", publicHandler.GetSeries)
 	api.Get("/top-students", publicHandler.GetTopStudents)
-	api.Get("/top-projects", publicHandler.GetTopProjects)
+	api.Get("/top-projects", authMiddleware.Optional(), publicHandler.GetTopProjects)
 
 	// Feed routes
 	api.Get("/feed", authMiddleware.Optional(), feedHandler.GetFeed)
@@ -259,6 +350,8 @@ func main() {
 	notifRoutes.Get("/count", notificationHandler.Count)
 	notifRoutes.Patch("/:id/read", notificationHandler.MarkAsRead)
 	notifRoutes.Post("/read-all", notificationHandler.MarkAllAsRead)
+	notifRoutes.Post("/:id/accept", notificationHandler.AcceptInvite)
+	notifRoutes.Post("/:id/decline", notificationHandler.DeclineInvite)
 	notifRoutes.Delete("/:id", notificationHandler.Delete)
 
 	// Search routes
@@ -273,6 +366,16 @@ func main() {
 	uploadRoutes.Delete("/*", authMiddleware.Required(), uploadHandler.Delete)
 	uploadRoutes.Get("/presign-view", authMiddleware.Required(), uploadHandler.PresignView)
 
+	// Teacher routes (wali kelas, scoped to own classes)
+	teacherRoutes := api.Group("/teacher", authMiddleware.Required(), authMiddleware.TeacherOnly())
+	teacherRoutes.Get("/classes", teacherHandler.ListClasses)
+	teacherRoutes.Get("/classes/:id/students", teacherHandler.ListStudents)
+	teacherRoutes.Get("/classes/:id/portfolios", teacherHandler.ListPortfolios)
+	teacherRoutes.Get("/classes/:id/assessments", teacherHandler.ListAssessments)
+	teacherRoutes.Get("/portfolios/:id", teacherHandler.GetPortfolio)
+	teacherRoutes.Get("/portfolios/:id/assessment", teacherHandler.GetPortfolioAssessment)
+	teacherRoutes.Get("/series/:id/report", teacherHandler.GetSeriesReport)
+
 	// Admin routes - base group with auth required
 	adminRoutes := api.Group("/admin", authMiddleware.Required())
 
@@ -297,6 +400,8 @@ func main() {
 	adminRoutes.Patch("/kelas/:id", capMiddleware.RequireCapability("classes"), adminHandler.UpdateKelas)
 	adminRoutes.Delete("/kelas/:id", capMiddleware.RequireCapability("classes"), adminHandler.DeleteKelas)
 	adminRoutes.Get("/kelas/:id/students", capMiddleware.RequireCapability("classes"), adminHandler.GetKelasStudents)
+	adminRoutes.Get("/kelas/:id/roster", capMiddleware.RequireCapability("classes"), adminHandler.GetKelasRoster)
+	adminRoutes.Post("/kelas/transfer", capMiddleware.RequireCapability("classes"), adminHandler.TransferStudents)
 
 	// Admin - Tags (requires tags capability)
 	adminRoutes.Get("/tags", capMiddleware.RequireCapability("tags"), adminHandler.ListTags)
@@ -312,6 +417,9 @@ func main() {
 	adminRoutes.Delete("/series/:id", capMiddleware.RequireCapability("series"), adminHandler.DeleteSeries)
 	adminRoutes.Get("/series/:id/export/preview", capMiddleware.RequireCapability("series"), adminHandler.GetSeriesExportPreview)
 	adminRoutes.Get("/series/:id/export", capMiddleware.RequireCapability("series"), adminHandler.GetSeriesExportData)
+	adminRoutes.Get("/series/:id/report", capMiddleware.RequireCapability("series"), adminHandler.GetSeriesReport)
+	adminRoutes.Get("/series/:id/checklist", capMiddleware.RequireCapability("series"), adminHandler.GetSeriesChecklist)
+	adminRoutes.Put("/series/:id/checklist", capMiddleware.RequireCapability("series"), adminHandler.UpdateSeriesChecklist)
 
 	// Admin - Users (requires users capability)
 	adminRoutes.Get("/users", capMiddleware.RequireCapability("users"), adminHandler.ListUsers)
@@ -332,15 +440,49 @@ func main() {
 	// Admin - Import Students (requires users capability)
 	adminRoutes.Post("/import/students", capMiddleware.RequireCapability("users"), importHandler.ImportStudents)
 	adminRoutes.Get("/import/students/template", capMiddleware.RequireCapability("users"), importHandler.DownloadTemplate)
+	adminRoutes.Get("/import/jobs/:id", capMiddleware.RequireCapability("users"), importHandler.GetImportJob)
+	adminRoutes.Post("/import/jobs/:id/cancel", capMiddleware.RequireCapability("users"), importHandler.CancelImportJob)
+	adminRoutes.Get("/import/jobs/:id/errors", capMiddleware.RequireCapability("users"), importHandler.DownloadImportJobErrors)
+	adminRoutes.Get("/import/credentials/:id", capMiddleware.RequireCapability("users"), importHandler.DownloadCredentialSlips)
+	adminRoutes.Post("/import/teachers", capMiddleware.RequireCapability("users"), importHandler.ImportEntity("teachers"))
+	adminRoutes.Get("/import/teachers/template", capMiddleware.RequireCapability("users"), importHandler.DownloadEntityTemplate("teachers"))
+	adminRoutes.Post("/import/tags", capMiddleware.RequireCapability("tags"), importHandler.ImportEntity("tags"))
+	adminRoutes.Get("/import/tags/template", capMiddleware.RequireCapability("tags"), importHandler.DownloadEntityTemplate("tags"))
+	adminRoutes.Post("/import/jurusan", capMiddleware.RequireCapability("majors"), importHandler.ImportEntity("jurusan"))
+	adminRoutes.Get("/import/jurusan/template", capMiddleware.RequireCapability("majors"), importHandler.DownloadEntityTemplate("jurusan"))
+	adminRoutes.Post("/import/kelas", capMiddleware.RequireCapability("classes"), importHandler.ImportEntity("kelas"))
+	adminRoutes.Get("/import/kelas/template", capMiddleware.RequireCapability("classes"), importHandler.DownloadEntityTemplate("kelas"))
 
 	// Admin - Portfolios (requires portfolios capability)
 	adminRoutes.Get("/portfolios", capMiddleware.RequireCapability("portfolios"), adminHandler.ListAllPortfolios)
 	adminRoutes.Get("/portfolios/pending", capMiddleware.RequireCapability("moderation"), adminHandler.ListPendingPortfolios)
+	adminRoutes.Get("/portfolios/my-queue", capMiddleware.RequireCapability("moderation"), adminHandler.MyReviewQueue)
 	adminRoutes.Get("/portfolios/:id", capMiddleware.RequireCapability("portfolios"), adminHandler.GetPortfolio)
 	adminRoutes.Patch("/portfolios/:id", capMiddleware.RequireCapability("portfolios"), adminHandler.UpdatePortfolio)
 	adminRoutes.Delete("/portfolios/:id", capMiddleware.RequireCapability("portfolios"), adminHandler.DeletePortfolio)
 	adminRoutes.Post("/portfolios/:id/approve", capMiddleware.RequireCapability("moderation"), adminHandler.ApprovePortfolio)
 	adminRoutes.Post("/portfolios/:id/reject", capMiddleware.RequireCapability("moderation"), adminHandler.RejectPortfolio)
+	adminRoutes.Post("/portfolios/:id/claim", capMiddleware.RequireCapability("moderation"), adminHandler.ClaimPortfolio)
+	adminRoutes.Delete("/portfolios/:id/claim", capMiddleware.RequireCapability("moderation"), adminHandler.ReleaseClaim)
+	adminRoutes.Get("/review-pools", capMiddleware.RequireCapability("moderation"), adminHandler.ListReviewerPools)
+	adminRoutes.Put("/review-pools/:jurusan_id", authMiddleware.AdminOnly(), adminHandler.SetReviewerPool)
+	adminRoutes.Get("/rejection-reasons", capMiddleware.RequireCapability("moderation"), adminHandler.ListRejectionReasons)
+	adminRoutes.Post("/rejection-reasons", capMiddleware.RequireCapability("moderation"), adminHandler.CreateRejectionReason)
+	adminRoutes.Put("/rejection-reasons/reorder", capMiddleware.RequireCapability("moderation"), adminHandler.ReorderRejectionReasons)
+	adminRoutes.Patch("/rejection-reasons/:id", capMiddleware.RequireCapability("moderation"), adminHandler.UpdateRejectionReason)
+	adminRoutes.Delete("/rejection-reasons/:id", capMiddleware.RequireCapability("moderation"), adminHandler.DeleteRejectionReason)
+
+	// Admin - Content Reports (requires moderation capability)
+	adminRoutes.Get("/reports", capMiddleware.RequireCapability("moderation"), reportHandler.AdminList)
+	adminRoutes.Get("/reports/:target_type/:target_id", capMiddleware.RequireCapability("moderation"), reportHandler.AdminGet)
+	adminRoutes.Post("/reports/:target_type/:target_id/resolve", capMiddleware.RequireCapability("moderation"), reportHandler.AdminResolve)
+
+	// Admin - Text Filter (requires moderation capability)
+	adminRoutes.Get("/text-filters", capMiddleware.RequireCapability("moderation"), textFilterHandler.List)
+	adminRoutes.Post("/text-filters", capMiddleware.RequireCapability("moderation"), textFilterHandler.Create)
+	adminRoutes.Post("/text-filters/test", capMiddleware.RequireCapability("moderation"), textFilterHandler.Test)
+	adminRoutes.Patch("/text-filters/:id", capMiddleware.RequireCapability("moderation"), textFilterHandler.Update)
+	adminRoutes.Delete("/text-filters/:id", capMiddleware.RequireCapability("moderation"), textFilterHandler.Delete)
 
 	// Admin - Feedback (requires feedback capability)
 	adminRoutes.Get("/feedback", capMiddleware.RequireCapability("feedback"), feedbackHandler.AdminListFeedback)
@@ -386,6 +528,25 @@ func main() {
 	// Public Feedback route (auth optional)
 	api.Post("/feedback", authMiddleware.Optional(), feedbackHandler.CreateFeedback)
 
+	// Content reports
+	reportLimiter := limiter.New(limiter.Config{
+		Max:        10,
+		Expiration: 1 * time.Minute,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return c.IP()
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"success": false,
+				"error": fiber.Map{
+					"code":    "TOO_MANY_REQUESTS",
+					"message": "Terlalu banyak laporan. Silakan coba lagi dalam satu menit.",
+				},
+			})
+		},
+	})
+	api.Post("/reports", authMiddleware.Required(), reportLimiter, reportHandler.Create)
+
 	// Direct Messaging routes
 	conversationRoutes := api.Group("/conversations", authMiddleware.Required())
 	conversationRoutes.Get("/", dmHandler.ListConversations)
@@ -418,6 +579,13 @@ func main() {
 	app.Use("/api/v1/ws/chat", wsHandler.WebSocketUpgrade(authMiddleware))
 	app.Get("/api/v1/ws/chat", websocket.New(wsHandler.HandleWebSocket))
 
+	// Publish approved portfolios whose scheduled time has come and remind students of closing series
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	go publishScheduler.Start(schedulerCtx, time.Minute)
+	go assignmentService.Start(schedulerCtx, 15*time.Minute)
+	go embedService.Start(schedulerCtx, 10*time.Minute)
+	go reviewClaimService.Start(schedulerCtx, time.Minute)
+
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
@@ -425,6 +593,7 @@ func main() {
 	go func() {
 		<-quit
 		log.Println("Gracefully shutting down...")
+		stopScheduler()
 		_ = app.Shutdown()
 	}()
 
@@ -42,6 +42,8 @@ func main() {
 			seedData(cfg)
 		case "6":
 			deleteDatabase(cfg)
+		case "7":
+			scanBlockPayloads(cfg)
 		case "0":
 			fmt.Println("Keluar...")
 			os.Exit(0)
@@ -67,6 +69,7 @@ func printMenu() {
 	fmt.Println("4. Truncate Tables (kecuali reference data)")
 	fmt.Println("5. Seed Data (generate dummy data)")
 	fmt.Println("6. Hapus Database")
+	fmt.Println("7. Scan Payload Content Block (laporan block tidak valid)")
 	fmt.Println("0. Keluar")
 	fmt.Println()
 	fmt.Println("----------------------------------------")
@@ -378,7 +381,7 @@ func createEnumTypes(db *sql.DB) error {
 		EXCEPTION WHEN duplicate_object THEN NULL; END $$`,
 
 		`DO $$ BEGIN
-			CREATE TYPE content_block_type AS ENUM ('text', 'image', 'table', 'youtube', 'button', 'embed', 'figma', 'canva', 'ppt', 'pdf', 'doc');
+			CREATE TYPE content_block_type AS ENUM ('text', 'image', 'table', 'youtube', 'button', 'embed', 'figma', 'canva', 'ppt', 'pdf', 'doc', 'code', 'gallery', 'audio', 'model_3d', 'markdown');
 		EXCEPTION WHEN duplicate_object THEN NULL; END $$`,
 
 		`DO $$ BEGIN
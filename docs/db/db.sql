@@ -41,9 +41,9 @@ CREATE EXTENSION IF NOT EXISTS "pg_trgm";  -- untuk fuzzy search
 -- ENUM TYPES
 -- ============================================================================
 
-CREATE TYPE user_role AS ENUM ('student', 'alumni', 'admin');
-CREATE TYPE portfolio_status AS ENUM ('draft', 'pending_review', 'rejected', 'published', 'archived');
-CREATE TYPE content_block_type AS ENUM ('text', 'image', 'table', 'youtube', 'button', 'embed', 'figma', 'canva', 'ppt', 'pdf', 'doc');
+CREATE TYPE user_role AS ENUM ('student', 'alumni', 'admin', 'teacher');
+CREATE TYPE portfolio_status AS ENUM ('draft', 'pending_review', 'rejected', 'published', 'archived', 'scheduled');
+CREATE TYPE content_block_type AS ENUM ('text', 'image', 'table', 'youtube', 'button', 'embed', 'figma', 'canva', 'ppt', 'pdf', 'doc', 'code', 'gallery', 'audio', 'model_3d', 'markdown');
 CREATE TYPE social_platform AS ENUM (
     'facebook', 'instagram', 'github', 'linkedin', 'twitter',
     'personal_website', 'tiktok', 'youtube', 'behance', 'dribbble',
@@ -51,6 +51,8 @@ CREATE TYPE social_platform AS ENUM (
 );
 CREATE TYPE feedback_kategori AS ENUM ('bug', 'saran', 'lainnya');
 CREATE TYPE feedback_status AS ENUM ('pending', 'read', 'resolved');
+CREATE TYPE embed_status AS ENUM ('ok', 'dead', 'private');
+CREATE TYPE portfolio_visibility AS ENUM ('public', 'logged_in', 'jurusan', 'unlisted');
 
 -- ============================================================================
 -- CORE TABLES
@@ -143,9 +145,14 @@ CREATE TABLE series (
     nama VARCHAR(100) NOT NULL UNIQUE,
     deskripsi TEXT,
     is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    opens_at TIMESTAMPTZ,
+    closes_at TIMESTAMPTZ,
+    reminder_sent_at TIMESTAMPTZ,
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-    deleted_at TIMESTAMPTZ
+    deleted_at TIMESTAMPTZ,
+
+    CONSTRAINT series_window_valid CHECK (opens_at IS NULL OR closes_at IS NULL OR closes_at > opens_at)
 );
 
 CREATE INDEX idx_series_nama ON series(nama) WHERE deleted_at IS NULL;
@@ -154,6 +161,9 @@ CREATE INDEX idx_series_active ON series(is_active) WHERE deleted_at IS NULL;
 COMMENT ON TABLE series IS 'Template series untuk struktur portofolio dengan block konten yang sudah ditentukan';
 COMMENT ON COLUMN series.deskripsi IS 'Deskripsi/penjelasan tentang series template ini';
 COMMENT ON COLUMN series.is_active IS 'Status aktif series, hanya series aktif yang ditampilkan ke user';
+COMMENT ON COLUMN series.opens_at IS 'Mulai tugas; portfolio baru untuk series ini ditolak sebelum waktu ini';
+COMMENT ON COLUMN series.closes_at IS 'Deadline tugas; submit setelah waktu ini ditandai terlambat';
+COMMENT ON COLUMN series.reminder_sent_at IS 'Waktu pengingat deadline dikirim, dikosongkan lagi saat deadline diubah';
 
 -- Series Blocks (template block konten untuk series)
 CREATE TABLE series_blocks (
@@ -162,6 +172,7 @@ CREATE TABLE series_blocks (
     block_type content_block_type NOT NULL,
     block_order INTEGER NOT NULL,
     instruksi TEXT NOT NULL,
+    is_required BOOLEAN NOT NULL DEFAULT true,
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     
@@ -176,6 +187,26 @@ COMMENT ON TABLE series_blocks IS 'Template block konten untuk series';
 COMMENT ON COLUMN series_blocks.block_type IS 'Tipe block: text, image, youtube, dll';
 COMMENT ON COLUMN series_blocks.instruksi IS 'Instruksi/panduan untuk mengisi block ini';
 COMMENT ON COLUMN series_blocks.block_order IS 'Urutan block dalam template (dimulai dari 0)';
+COMMENT ON COLUMN series_blocks.is_required IS 'Block wajib tidak bisa dihapus dari portfolio dan harus diisi sebelum submit';
+
+-- Series Targets (kelas/jurusan yang ditugaskan mengerjakan series)
+CREATE TABLE series_targets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    series_id UUID NOT NULL REFERENCES series(id) ON DELETE CASCADE,
+    kelas_id UUID REFERENCES kelas(id) ON DELETE CASCADE,
+    jurusan_id UUID REFERENCES jurusan(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT series_targets_one_target CHECK ((kelas_id IS NULL) <> (jurusan_id IS NULL))
+);
+
+CREATE INDEX idx_series_targets_series ON series_targets(series_id);
+CREATE UNIQUE INDEX idx_series_targets_kelas ON series_targets(series_id, kelas_id) WHERE kelas_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_series_targets_jurusan ON series_targets(series_id, jurusan_id) WHERE jurusan_id IS NOT NULL;
+
+COMMENT ON TABLE series_targets IS 'Target tugas series: seluruh siswa aktif di kelas atau jurusan ini';
+COMMENT ON COLUMN series_targets.kelas_id IS 'Kelas target; diisi salah satu dengan jurusan_id';
+COMMENT ON COLUMN series_targets.jurusan_id IS 'Jurusan target, mencakup semua kelas di jurusan tersebut';
 
 -- ============================================================================
 -- USER & AUTHENTICATION
@@ -201,6 +232,7 @@ CREATE TABLE users (
     tahun_lulus INTEGER,
     
     is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    must_change_password BOOLEAN NOT NULL DEFAULT FALSE,
     last_login_at TIMESTAMPTZ,
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
@@ -244,15 +276,25 @@ CREATE TABLE student_class_history (
     kelas_id UUID NOT NULL REFERENCES kelas(id) ON DELETE RESTRICT,
     tahun_ajaran_id UUID NOT NULL REFERENCES tahun_ajaran(id) ON DELETE RESTRICT,
     is_current BOOLEAN NOT NULL DEFAULT FALSE,
+    effective_date DATE NOT NULL DEFAULT CURRENT_DATE,
+    end_date DATE,
+    reason TEXT,
+    moved_by UUID REFERENCES users(id) ON DELETE SET NULL,
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     
-    CONSTRAINT student_class_history_unique UNIQUE (user_id, kelas_id, tahun_ajaran_id)
+    CONSTRAINT student_class_history_unique UNIQUE (user_id, kelas_id, tahun_ajaran_id),
+    CONSTRAINT student_class_history_dates CHECK (end_date IS NULL OR end_date >= effective_date)
 );
 
 CREATE INDEX idx_student_class_history_user ON student_class_history(user_id);
 CREATE INDEX idx_student_class_history_current ON student_class_history(user_id, is_current) WHERE is_current = TRUE;
+CREATE INDEX idx_student_class_history_kelas ON student_class_history(kelas_id, effective_date);
 
 COMMENT ON TABLE student_class_history IS 'Riwayat kelas siswa per tahun ajaran';
+COMMENT ON COLUMN student_class_history.effective_date IS 'Tanggal siswa mulai berada di kelas ini';
+COMMENT ON COLUMN student_class_history.end_date IS 'Tanggal siswa pindah keluar (eksklusif), NULL jika masih di kelas';
+COMMENT ON COLUMN student_class_history.reason IS 'Alasan perpindahan kelas';
+COMMENT ON COLUMN student_class_history.moved_by IS 'Admin yang memindahkan siswa';
 
 -- ============================================================================
 -- JWT REFRESH TOKEN MANAGEMENT
@@ -331,10 +373,13 @@ CREATE TABLE portfolios (
     slug VARCHAR(250) NOT NULL,
     thumbnail_url TEXT,
     status portfolio_status NOT NULL DEFAULT 'draft',
+    visibility portfolio_visibility NOT NULL DEFAULT 'public',
     admin_review_note TEXT,
     reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
     reviewed_at TIMESTAMPTZ,
     published_at TIMESTAMPTZ,
+    publish_at TIMESTAMPTZ,
+    submitted_at TIMESTAMPTZ,
     series_id UUID REFERENCES series(id) ON DELETE SET NULL,
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
@@ -349,10 +394,15 @@ CREATE INDEX idx_portfolios_published ON portfolios(published_at DESC) WHERE sta
 CREATE INDEX idx_portfolios_pending ON portfolios(created_at) WHERE status = 'pending_review' AND deleted_at IS NULL;
 CREATE INDEX idx_portfolios_slug ON portfolios(slug) WHERE deleted_at IS NULL;
 CREATE INDEX idx_portfolios_series ON portfolios(series_id) WHERE deleted_at IS NULL AND series_id IS NOT NULL;
+CREATE INDEX idx_portfolios_scheduled ON portfolios(publish_at) WHERE status = 'scheduled' AND deleted_at IS NULL;
+CREATE INDEX idx_portfolios_visibility ON portfolios(visibility) WHERE status = 'published' AND deleted_at IS NULL;
 
 COMMENT ON TABLE portfolios IS 'Portofolio karya user';
 COMMENT ON COLUMN portfolios.slug IS 'URL-friendly identifier, auto-generated dari judul';
+COMMENT ON COLUMN portfolios.visibility IS 'Siapa yang boleh melihat portfolio published: public, logged_in (user login), jurusan (jurusan yang sama dengan pemilik), unlisted (hanya lewat link, tidak tampil di daftar)';
 COMMENT ON COLUMN portfolios.admin_review_note IS 'Catatan review dari admin (alasan reject, feedback, dll)';
+COMMENT ON COLUMN portfolios.publish_at IS 'Jadwal publish yang diminta saat submit/approve; status scheduled sampai waktu ini tiba';
+COMMENT ON COLUMN portfolios.submitted_at IS 'Waktu pertama kali diajukan untuk review, dibandingkan dengan deadline series';
 COMMENT ON COLUMN portfolios.series_id IS 'Series template yang digunakan (NULL jika portofolio bebas)';
 
 -- Portfolio Tags (many-to-many)
@@ -368,13 +418,33 @@ CREATE INDEX idx_portfolio_tags_tag ON portfolio_tags(tag_id);
 
 COMMENT ON TABLE portfolio_tags IS 'Relasi many-to-many portfolio dan tags';
 
+-- Pin dan urutan tampil portfolio di profil (per user, termasuk co-author)
+CREATE TABLE profile_portfolios (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    portfolio_id UUID NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    is_pinned BOOLEAN NOT NULL DEFAULT FALSE,
+    display_order INT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (user_id, portfolio_id)
+);
+
+CREATE INDEX idx_profile_portfolios_pinned ON profile_portfolios(user_id) WHERE is_pinned = TRUE;
+
+COMMENT ON TABLE profile_portfolios IS 'Pengaturan tampilan portfolio di profil: pin (maksimal 6) dan urutan manual';
+COMMENT ON COLUMN profile_portfolios.display_order IS 'Urutan manual di profil; NULL tampil setelah yang diurutkan, terbaru dulu';
+
 -- Content Blocks
 CREATE TABLE content_blocks (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     portfolio_id UUID NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    series_block_id UUID REFERENCES series_blocks(id) ON DELETE SET NULL,
     block_type content_block_type NOT NULL,
     block_order INTEGER NOT NULL,
     payload JSONB NOT NULL DEFAULT '{}',
+    embed_status embed_status,
+    embed_meta JSONB,
+    embed_checked_at TIMESTAMPTZ,
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     
@@ -384,10 +454,17 @@ CREATE TABLE content_blocks (
 
 CREATE INDEX idx_content_blocks_portfolio ON content_blocks(portfolio_id);
 CREATE INDEX idx_content_blocks_order ON content_blocks(portfolio_id, block_order);
+CREATE INDEX idx_content_blocks_series_block ON content_blocks(series_block_id) WHERE series_block_id IS NOT NULL;
+CREATE INDEX idx_content_blocks_embed_check ON content_blocks(embed_checked_at NULLS FIRST)
+    WHERE block_type IN ('youtube', 'figma', 'canva', 'embed', 'ppt', 'pdf', 'doc');
 
 COMMENT ON TABLE content_blocks IS 'Modular content blocks untuk portofolio';
-COMMENT ON COLUMN content_blocks.block_type IS 'Tipe block: text, image, table, youtube, button, embed';
+COMMENT ON COLUMN content_blocks.block_type IS 'Tipe block: text, image, table, youtube, button, embed, figma, canva, ppt, pdf, doc, code, gallery, audio, model_3d, markdown';
 COMMENT ON COLUMN content_blocks.payload IS 'Konten block dalam format JSON sesuai tipe';
+COMMENT ON COLUMN content_blocks.series_block_id IS 'Block template series asal block ini, dibuat saat portfolio dibuat dari series';
+COMMENT ON COLUMN content_blocks.embed_status IS 'Hasil pengecekan terakhir link eksternal block: ok, dead (hilang), private (tidak publik/tidak bisa di-embed)';
+COMMENT ON COLUMN content_blocks.embed_meta IS 'Cache metadata link: provider, canonical_url, title, thumbnail_url, aspect_ratio';
+COMMENT ON COLUMN content_blocks.embed_checked_at IS 'Waktu pengecekan link terakhir, NULL berarti belum dicek dan akan diambil job berikutnya';
 
 -- Portfolio Likes
 CREATE TABLE portfolio_likes (
@@ -399,6 +476,7 @@ CREATE TABLE portfolio_likes (
 );
 
 CREATE INDEX idx_portfolio_likes_portfolio ON portfolio_likes(portfolio_id);
+CREATE INDEX idx_portfolio_likes_portfolio_created ON portfolio_likes(portfolio_id, created_at);
 
 COMMENT ON TABLE portfolio_likes IS 'Like/favorit portofolio oleh user';
 
@@ -682,14 +760,20 @@ BEGIN
         -- Get tahun_ajaran_id from kelas
         SELECT tahun_ajaran_id INTO v_tahun_ajaran_id FROM kelas WHERE id = NEW.kelas_id;
         
-        -- Set all previous history to not current
-        UPDATE student_class_history SET is_current = FALSE WHERE user_id = NEW.id;
+        -- Close previous history (keeps an end_date already set by a dated transfer)
+        UPDATE student_class_history
+        SET is_current = FALSE, end_date = COALESCE(end_date, CURRENT_DATE)
+        WHERE user_id = NEW.id AND kelas_id <> NEW.kelas_id;
         
-        -- Insert or update history
+        -- Insert or update history; re-entering a closed kelas starts a new stay
         INSERT INTO student_class_history (user_id, kelas_id, tahun_ajaran_id, is_current)
         VALUES (NEW.id, NEW.kelas_id, v_tahun_ajaran_id, TRUE)
         ON CONFLICT (user_id, kelas_id, tahun_ajaran_id) 
-        DO UPDATE SET is_current = TRUE;
+        DO UPDATE SET
+            is_current = TRUE,
+            effective_date = CASE WHEN student_class_history.end_date IS NULL
+                THEN student_class_history.effective_date ELSE CURRENT_DATE END,
+            end_date = NULL;
     END IF;
     RETURN NEW;
 END;
@@ -916,7 +1000,7 @@ COMMENT ON FUNCTION cleanup_expired_tokens() IS 'Hapus refresh tokens dan blackl
 
 -- Notification type enum
 -- Notification type enum
-CREATE TYPE notification_type AS ENUM ('new_follower', 'portfolio_liked', 'portfolio_approved', 'portfolio_rejected', 'feedback_updated', 'new_comment', 'reply_comment');
+CREATE TYPE notification_type AS ENUM ('new_follower', 'portfolio_liked', 'portfolio_approved', 'portfolio_rejected', 'feedback_updated', 'new_comment', 'reply_comment', 'collab_invite', 'collab_responded', 'series_reminder', 'embed_broken', 'share_comment', 'report_resolved');
 
 -- Notifications table
 CREATE TABLE notifications (
@@ -1006,6 +1090,7 @@ CREATE TABLE comments (
     parent_id UUID REFERENCES comments(id) ON DELETE CASCADE,
     content TEXT NOT NULL,
     is_edited BOOLEAN DEFAULT false,
+    is_hidden BOOLEAN NOT NULL DEFAULT false,
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     deleted_at TIMESTAMPTZ
@@ -1018,6 +1103,7 @@ CREATE INDEX idx_comments_created_at ON comments(created_at);
 
 COMMENT ON TABLE comments IS 'Komentar pada portfolio, mendukung threading (replies)';
 COMMENT ON COLUMN comments.parent_id IS 'ID komentar induk jika ini adalah balasan (NULL untuk top-level comment)';
+COMMENT ON COLUMN comments.is_hidden IS 'Disembunyikan moderator karena laporan; isi diganti placeholder, balasan tetap tampil';
 
 -- Trigger updated_at for comments
 CREATE TRIGGER trg_comments_updated_at 
@@ -1225,3 +1311,427 @@ CREATE TRIGGER trg_changelog_section_blocks_updated_at
     BEFORE UPDATE ON changelog_section_blocks 
     FOR EACH ROW 
     EXECUTE FUNCTION update_updated_at();
+
+-- ============================================================================
+-- WALI KELAS
+-- ============================================================================
+
+-- Guru (role teacher) sebagai wali kelas; ditambahkan di sini karena kelas dibuat sebelum users
+ALTER TABLE kelas ADD COLUMN wali_kelas_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_kelas_wali_kelas ON kelas(wali_kelas_id) WHERE wali_kelas_id IS NOT NULL AND deleted_at IS NULL;
+
+COMMENT ON COLUMN kelas.wali_kelas_id IS 'User dengan role teacher yang menjadi wali kelas';
+
+-- ============================================================================
+-- PORTFOLIO REVISIONS
+-- ============================================================================
+
+-- Snapshot immutable portfolio (metadata, tags, blocks) pada submit, approval dan penilaian
+CREATE TABLE portfolio_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    portfolio_id UUID NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    revision_number INTEGER NOT NULL,
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('submit', 'approve', 'assessment')),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT portfolio_revisions_unique UNIQUE (portfolio_id, revision_number)
+);
+
+CREATE INDEX idx_portfolio_revisions_portfolio ON portfolio_revisions(portfolio_id, revision_number DESC);
+
+COMMENT ON TABLE portfolio_revisions IS 'Riwayat revisi portfolio, tidak pernah diubah setelah dibuat';
+COMMENT ON COLUMN portfolio_revisions.revision_number IS 'Nomor revisi berurutan per portfolio, mulai dari 1';
+COMMENT ON COLUMN portfolio_revisions.reason IS 'Kejadian pemicu: submit, approve, assessment';
+COMMENT ON COLUMN portfolio_revisions.snapshot IS 'Judul, slug, thumbnail, status, series, tags dan content blocks terurut';
+
+-- ============================================================================
+-- PORTFOLIO DRAFTS
+-- ============================================================================
+
+-- Working draft portfolio published; konten live tidak berubah sampai draft disetujui
+CREATE TABLE portfolio_drafts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    portfolio_id UUID NOT NULL UNIQUE REFERENCES portfolios(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'editing' CHECK (status IN ('editing', 'pending_review', 'rejected')),
+    content JSONB NOT NULL,
+    admin_review_note TEXT,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ,
+    submitted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_portfolio_drafts_pending ON portfolio_drafts(submitted_at) WHERE status = 'pending_review';
+
+CREATE TRIGGER trg_portfolio_drafts_updated_at BEFORE UPDATE ON portfolio_drafts FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE portfolio_drafts IS 'Perubahan pada portfolio published yang menunggu review';
+COMMENT ON COLUMN portfolio_drafts.status IS 'editing: masih diedit, pending_review: diajukan, rejected: ditolak reviewer';
+COMMENT ON COLUMN portfolio_drafts.content IS 'Judul, thumbnail, series, tags dan content blocks versi draft (format sama dengan snapshot revisi)';
+
+-- ============================================================================
+-- PORTFOLIO COLLABORATORS
+-- ============================================================================
+
+-- Co-author portfolio tim; owner tetap portfolios.user_id
+CREATE TABLE portfolio_collaborators (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    portfolio_id UUID NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('editor', 'contributor')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined')),
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    responded_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (portfolio_id, user_id)
+);
+
+CREATE INDEX idx_portfolio_collaborators_user ON portfolio_collaborators(user_id) WHERE status = 'accepted';
+
+COMMENT ON TABLE portfolio_collaborators IS 'Anggota tim portfolio selain owner';
+COMMENT ON COLUMN portfolio_collaborators.role IS 'editor: boleh mengedit konten, contributor: hanya dikreditkan';
+COMMENT ON COLUMN portfolio_collaborators.status IS 'Undangan dikirim lewat notifikasi; berlaku setelah accepted';
+
+-- ============================================================================
+-- PORTFOLIO SHARE LINKS
+-- ============================================================================
+
+-- Link rahasia untuk membuka portfolio (termasuk draft/pending_review) tanpa login
+CREATE TABLE portfolio_share_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    portfolio_id UUID NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    label VARCHAR(100),
+    allow_comments BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    view_count BIGINT NOT NULL DEFAULT 0,
+    last_viewed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_portfolio_share_links_portfolio ON portfolio_share_links(portfolio_id, created_at DESC);
+
+COMMENT ON TABLE portfolio_share_links IS 'Share link privat portfolio dengan masa berlaku, bisa dicabut';
+COMMENT ON COLUMN portfolio_share_links.token_hash IS 'SHA-256 dari token; token asli hanya diberikan sekali saat link dibuat';
+COMMENT ON COLUMN portfolio_share_links.allow_comments IS 'Jika aktif, tamu yang membuka link boleh meninggalkan komentar';
+COMMENT ON COLUMN portfolio_share_links.view_count IS 'Jumlah kunjungan lewat link ini';
+
+-- Komentar tamu lewat share link, terpisah dari komentar publik yang butuh akun
+CREATE TABLE share_link_comments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    share_link_id UUID NOT NULL REFERENCES portfolio_share_links(id) ON DELETE CASCADE,
+    portfolio_id UUID NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    author_name VARCHAR(100) NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_share_link_comments_portfolio ON share_link_comments(portfolio_id, created_at);
+CREATE INDEX idx_share_link_comments_link ON share_link_comments(share_link_id, created_at);
+
+COMMENT ON TABLE share_link_comments IS 'Masukan dari orang tua, mentor atau mitra industri yang membuka share link';
+
+-- ============================================================================
+-- BOOKMARK COLLECTIONS
+-- ============================================================================
+
+-- Koleksi bookmark bernama milik user, terpisah dari like yang ikut memengaruhi ranking feed
+CREATE TABLE bookmark_collections (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    nama VARCHAR(100) NOT NULL,
+    deskripsi TEXT,
+    is_public BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_bookmark_collections_user ON bookmark_collections(user_id, created_at DESC);
+
+COMMENT ON TABLE bookmark_collections IS 'Koleksi portfolio yang disimpan user, misalnya "Inspirasi UI"';
+COMMENT ON COLUMN bookmark_collections.is_public IS 'Koleksi publik tampil di tab koleksi profil dan bisa dibuka lewat link-nya';
+
+CREATE TABLE bookmark_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    collection_id UUID NOT NULL REFERENCES bookmark_collections(id) ON DELETE CASCADE,
+    portfolio_id UUID NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    item_order INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (collection_id, portfolio_id)
+);
+
+CREATE INDEX idx_bookmark_items_collection ON bookmark_items(collection_id, item_order);
+CREATE INDEX idx_bookmark_items_portfolio ON bookmark_items(portfolio_id);
+
+COMMENT ON TABLE bookmark_items IS 'Portfolio di dalam koleksi; dihapus saat portfolio tidak lagi published atau dihapus';
+
+-- ============================================================================
+-- CONTENT REPORTS
+-- ============================================================================
+
+CREATE TYPE report_target_type AS ENUM ('portfolio', 'comment', 'user', 'message');
+CREATE TYPE report_reason AS ENUM ('spam', 'harassment', 'inappropriate', 'plagiarism', 'impersonation', 'other', 'auto_filter');
+CREATE TYPE report_status AS ENUM ('pending', 'resolved', 'dismissed');
+
+-- Laporan user atas portfolio, komentar, profil, atau pesan yang melanggar aturan
+CREATE TABLE content_reports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    reporter_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    target_type report_target_type NOT NULL,
+    target_id UUID NOT NULL,
+    target_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason report_reason NOT NULL,
+    details TEXT,
+    status report_status NOT NULL DEFAULT 'pending',
+    action VARCHAR(20),
+    resolution_note TEXT,
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_content_reports_pending_unique ON content_reports(reporter_id, target_type, target_id) WHERE status = 'pending';
+CREATE INDEX idx_content_reports_queue ON content_reports(status, target_type, target_id);
+CREATE INDEX idx_content_reports_target_user ON content_reports(target_user_id);
+
+COMMENT ON TABLE content_reports IS 'Laporan konten; laporan atas target yang sama dikelompokkan jadi satu kasus di antrean moderasi';
+COMMENT ON COLUMN content_reports.reporter_id IS 'Pelapor; NULL untuk laporan dari filter teks otomatis';
+COMMENT ON COLUMN content_reports.target_id IS 'ID portfolio, komentar, user, atau pesan sesuai target_type';
+COMMENT ON COLUMN content_reports.target_user_id IS 'Pemilik konten yang dilaporkan, sasaran tindakan deactivate_user';
+COMMENT ON COLUMN content_reports.action IS 'Tindakan moderator: dismiss, hide_content, unpublish, deactivate_user';
+
+CREATE TRIGGER trg_content_reports_updated_at
+    BEFORE UPDATE ON content_reports
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
+
+-- ============================================================================
+-- REVIEW CLAIMS
+-- ============================================================================
+
+-- Portfolio yang sedang ditinjau seorang moderator
+CREATE TABLE review_claims (
+    portfolio_id UUID PRIMARY KEY REFERENCES portfolios(id) ON DELETE CASCADE,
+    reviewer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    is_assigned BOOLEAN NOT NULL DEFAULT FALSE,
+    claimed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_review_claims_reviewer ON review_claims(reviewer_id, expires_at);
+
+COMMENT ON TABLE review_claims IS 'Portfolio yang sedang ditinjau seorang moderator; moderator lain tidak bisa approve/reject selama klaim berlaku';
+COMMENT ON COLUMN review_claims.is_assigned IS 'TRUE jika dibagikan otomatis dari reviewer pool, FALSE jika diklaim sendiri';
+COMMENT ON COLUMN review_claims.expires_at IS 'Klaim kedaluwarsa setelah waktu ini dan portfolio kembali ke antrean';
+
+-- Moderator per jurusan untuk pembagian review otomatis
+CREATE TABLE reviewer_pools (
+    jurusan_id UUID NOT NULL REFERENCES jurusan(id) ON DELETE CASCADE,
+    reviewer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    last_assigned_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (jurusan_id, reviewer_id)
+);
+
+COMMENT ON TABLE reviewer_pools IS 'Moderator yang menerima portfolio baru dari satu jurusan secara bergiliran';
+COMMENT ON COLUMN reviewer_pools.last_assigned_at IS 'Waktu pembagian terakhir; yang paling lama tidak mendapat bagian dipilih berikutnya';
+
+-- ============================================================================
+-- REJECTION REASONS
+-- ============================================================================
+
+-- Template alasan penolakan
+CREATE TABLE rejection_reasons (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    nama VARCHAR(100) NOT NULL,
+    deskripsi TEXT,
+    urutan INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_rejection_reasons_urutan ON rejection_reasons(urutan) WHERE deleted_at IS NULL;
+
+COMMENT ON TABLE rejection_reasons IS 'Template alasan penolakan portfolio yang dipilih moderator';
+COMMENT ON COLUMN rejection_reasons.deskripsi IS 'Penjelasan yang ditampilkan ke siswa bersama nama alasan';
+
+CREATE TRIGGER trg_rejection_reasons_updated_at
+    BEFORE UPDATE ON rejection_reasons
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
+
+-- Penolakan portfolio atau perubahan portfolio
+CREATE TABLE portfolio_rejections (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    portfolio_id UUID NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    is_draft BOOLEAN NOT NULL DEFAULT FALSE,
+    rejected_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    note TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_portfolio_rejections_portfolio ON portfolio_rejections(portfolio_id, created_at DESC);
+CREATE INDEX idx_portfolio_rejections_created ON portfolio_rejections(created_at);
+
+COMMENT ON TABLE portfolio_rejections IS 'Riwayat penolakan terstruktur; admin_review_note tetap diisi dengan ringkasan teksnya';
+COMMENT ON COLUMN portfolio_rejections.is_draft IS 'TRUE jika yang ditolak adalah perubahan (draft) atas portfolio yang sudah tayang';
+COMMENT ON COLUMN portfolio_rejections.note IS 'Catatan tambahan moderator di luar alasan yang dipilih';
+
+CREATE TABLE portfolio_rejection_reasons (
+    rejection_id UUID NOT NULL REFERENCES portfolio_rejections(id) ON DELETE CASCADE,
+    reason_id UUID NOT NULL REFERENCES rejection_reasons(id) ON DELETE RESTRICT,
+    PRIMARY KEY (rejection_id, reason_id)
+);
+
+CREATE INDEX idx_portfolio_rejection_reasons_reason ON portfolio_rejection_reasons(reason_id);
+
+CREATE TABLE portfolio_rejection_blocks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    rejection_id UUID NOT NULL REFERENCES portfolio_rejections(id) ON DELETE CASCADE,
+    block_id UUID NOT NULL,
+    note TEXT NOT NULL
+);
+
+CREATE INDEX idx_portfolio_rejection_blocks_rejection ON portfolio_rejection_blocks(rejection_id);
+
+COMMENT ON TABLE portfolio_rejection_blocks IS 'Block konten yang perlu diperbaiki siswa beserta catatannya';
+COMMENT ON COLUMN portfolio_rejection_blocks.block_id IS 'ID content block, atau ID block pada snapshot draft bila is_draft; sengaja tanpa foreign key';
+
+-- Checklist review per series
+CREATE TABLE series_checklist_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    series_id UUID NOT NULL REFERENCES series(id) ON DELETE CASCADE,
+    label VARCHAR(200) NOT NULL,
+    urutan INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_series_checklist_items_series ON series_checklist_items(series_id, urutan);
+
+COMMENT ON TABLE series_checklist_items IS 'Poin yang diperiksa moderator saat meninjau portfolio dari suatu series';
+
+INSERT INTO rejection_reasons (nama, deskripsi, urutan) VALUES
+('Konten tidak lengkap', 'Portfolio belum memuat informasi atau karya yang cukup untuk ditampilkan', 1),
+('Kualitas gambar rendah', 'Gambar buram, pecah, atau terlalu kecil sehingga karya tidak terlihat jelas', 2),
+('Tidak sesuai series', 'Isi portfolio tidak mengikuti ketentuan series yang dipilih', 3),
+('Bukan karya sendiri', 'Karya terindikasi milik orang lain atau tidak mencantumkan sumber', 4),
+('Melanggar aturan konten', 'Konten mengandung unsur yang tidak pantas untuk lingkungan sekolah', 5);
+
+-- ============================================================================
+-- TEXT FILTER
+-- ============================================================================
+
+CREATE TYPE text_filter_match AS ENUM ('word', 'regex');
+CREATE TYPE text_filter_action AS ENUM ('block', 'mask', 'flag');
+
+-- Kata terlarang dan pola regex yang diperiksa saat konten teks disimpan
+CREATE TABLE text_filter_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    pattern VARCHAR(200) NOT NULL,
+    match_type text_filter_match NOT NULL DEFAULT 'word',
+    action text_filter_action NOT NULL,
+    bahasa VARCHAR(5),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_text_filter_rules_active ON text_filter_rules(is_active);
+
+CREATE TRIGGER trg_text_filter_rules_updated_at
+    BEFORE UPDATE ON text_filter_rules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE text_filter_rules IS 'Kata terlarang dan pola regex untuk filter teks otomatis';
+COMMENT ON COLUMN text_filter_rules.pattern IS 'Kata/frasa (dicocokkan per kata utuh) atau regex, tidak membedakan huruf besar';
+COMMENT ON COLUMN text_filter_rules.action IS 'block menolak simpan, mask mengganti dengan bintang, flag masuk antrean moderasi';
+COMMENT ON COLUMN text_filter_rules.bahasa IS 'id atau en untuk pengelompokan daftar kata; NULL untuk pola umum';
+
+-- ============================================================================
+-- PORTFOLIO ANALYTICS
+-- ============================================================================
+
+CREATE TYPE view_source AS ENUM ('feed', 'search', 'profile', 'direct', 'share_link');
+
+-- Setiap kunjungan portfolio; portfolio_views hanya menyimpan satu baris per penonton
+CREATE TABLE portfolio_view_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    portfolio_id UUID NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    session_id VARCHAR(64),
+    source view_source NOT NULL DEFAULT 'direct',
+    viewed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_portfolio_view_events_portfolio ON portfolio_view_events(portfolio_id, viewed_at);
+
+COMMENT ON TABLE portfolio_view_events IS 'Setiap kunjungan portfolio untuk statistik pemilik; kunjungan oleh pemilik dan editor tidak dicatat';
+COMMENT ON COLUMN portfolio_view_events.source IS 'Sumber kunjungan yang dilaporkan klien; share_link dicatat oleh endpoint link berbagi';
+
+-- ============================================================================
+-- SLUG HISTORY
+-- ============================================================================
+
+CREATE TABLE portfolio_slug_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    portfolio_id UUID NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    slug VARCHAR(250) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT portfolio_slug_history_unique UNIQUE (user_id, slug)
+);
+
+CREATE INDEX idx_portfolio_slug_history_slug ON portfolio_slug_history(slug);
+CREATE INDEX idx_portfolio_slug_history_portfolio ON portfolio_slug_history(portfolio_id);
+
+COMMENT ON TABLE portfolio_slug_history IS 'Slug lama portfolio; slug yang sedang dipakai portfolio lain milik user yang sama tidak disimpan';
+
+-- Function: Keep the previous slug when generate_portfolio_slug rewrites it. The slug is
+-- changed by a BEFORE trigger, which UPDATE OF slug would not notice, so every update is checked.
+CREATE OR REPLACE FUNCTION record_portfolio_slug_history()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD.slug IS NOT DISTINCT FROM NEW.slug THEN
+        RETURN NEW;
+    END IF;
+
+    -- A live slug wins over an old one
+    DELETE FROM portfolio_slug_history WHERE user_id = NEW.user_id AND slug = NEW.slug;
+
+    IF TG_OP = 'UPDATE' THEN
+        INSERT INTO portfolio_slug_history (portfolio_id, user_id, slug)
+        VALUES (NEW.id, NEW.user_id, OLD.slug)
+        ON CONFLICT (user_id, slug) DO UPDATE
+            SET portfolio_id = EXCLUDED.portfolio_id, created_at = NOW();
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_portfolio_slug_history
+    AFTER INSERT OR UPDATE ON portfolios
+    FOR EACH ROW
+    EXECUTE FUNCTION record_portfolio_slug_history();
+
+CREATE TABLE username_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    username VARCHAR(30) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_username_history_user ON username_history(user_id);
+
+COMMENT ON TABLE username_history IS 'Username lama user; tetap dipesan untuk pemiliknya dan bisa diambil kembali olehnya';
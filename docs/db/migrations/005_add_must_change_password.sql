@@ -0,0 +1,9 @@
+-- ============================================================================
+-- Migration: Add must_change_password to users
+-- Description: Akun siswa hasil import dengan password acak wajib mengganti
+--              password saat login pertama
+-- ============================================================================
+
+ALTER TABLE users ADD COLUMN must_change_password BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN users.must_change_password IS 'TRUE jika user wajib mengganti password awal sebelum memakai aplikasi';
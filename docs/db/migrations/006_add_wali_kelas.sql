@@ -0,0 +1,12 @@
+-- ============================================================================
+-- Migration: Add teacher role and wali kelas
+-- Description: Role teacher untuk guru dan relasi wali kelas pada tabel kelas
+-- ============================================================================
+
+ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'teacher';
+
+ALTER TABLE kelas ADD COLUMN wali_kelas_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_kelas_wali_kelas ON kelas(wali_kelas_id) WHERE wali_kelas_id IS NOT NULL AND deleted_at IS NULL;
+
+COMMENT ON COLUMN kelas.wali_kelas_id IS 'User dengan role teacher yang menjadi wali kelas';
@@ -0,0 +1,60 @@
+-- ============================================================================
+-- Migration: Add class transfer details to student_class_history
+-- Description: Tanggal efektif, tanggal selesai, alasan dan pelaku perpindahan kelas
+-- ============================================================================
+
+ALTER TABLE student_class_history
+    ADD COLUMN effective_date DATE,
+    ADD COLUMN end_date DATE,
+    ADD COLUMN reason TEXT,
+    ADD COLUMN moved_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- Backfill: tanggal efektif dari created_at, riwayat lama yang tidak aktif ditutup di awal riwayat berikutnya
+UPDATE student_class_history SET effective_date = created_at::date;
+
+UPDATE student_class_history h
+SET end_date = GREATEST(h.effective_date, (
+    SELECT MIN(n.created_at)::date FROM student_class_history n
+    WHERE n.user_id = h.user_id AND n.created_at > h.created_at
+))
+WHERE h.is_current = FALSE;
+
+ALTER TABLE student_class_history
+    ALTER COLUMN effective_date SET NOT NULL,
+    ALTER COLUMN effective_date SET DEFAULT CURRENT_DATE,
+    ADD CONSTRAINT student_class_history_dates CHECK (end_date IS NULL OR end_date >= effective_date);
+
+CREATE INDEX idx_student_class_history_kelas ON student_class_history(kelas_id, effective_date);
+
+COMMENT ON COLUMN student_class_history.effective_date IS 'Tanggal siswa mulai berada di kelas ini';
+COMMENT ON COLUMN student_class_history.end_date IS 'Tanggal siswa pindah keluar (eksklusif), NULL jika masih di kelas';
+COMMENT ON COLUMN student_class_history.reason IS 'Alasan perpindahan kelas';
+COMMENT ON COLUMN student_class_history.moved_by IS 'Admin yang memindahkan siswa';
+
+CREATE OR REPLACE FUNCTION sync_student_class_history()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_tahun_ajaran_id UUID;
+BEGIN
+    IF NEW.kelas_id IS NOT NULL AND NEW.role IN ('student', 'alumni') THEN
+        -- Get tahun_ajaran_id from kelas
+        SELECT tahun_ajaran_id INTO v_tahun_ajaran_id FROM kelas WHERE id = NEW.kelas_id;
+        
+        -- Close previous history (keeps an end_date already set by a dated transfer)
+        UPDATE student_class_history
+        SET is_current = FALSE, end_date = COALESCE(end_date, CURRENT_DATE)
+        WHERE user_id = NEW.id AND kelas_id <> NEW.kelas_id;
+        
+        -- Insert or update history; re-entering a closed kelas starts a new stay
+        INSERT INTO student_class_history (user_id, kelas_id, tahun_ajaran_id, is_current)
+        VALUES (NEW.id, NEW.kelas_id, v_tahun_ajaran_id, TRUE)
+        ON CONFLICT (user_id, kelas_id, tahun_ajaran_id) 
+        DO UPDATE SET
+            is_current = TRUE,
+            effective_date = CASE WHEN student_class_history.end_date IS NULL
+                THEN student_class_history.effective_date ELSE CURRENT_DATE END,
+            end_date = NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,23 @@
+-- ============================================================================
+-- Migration: Add portfolio revisions
+-- Description: Snapshot portfolio pada submit, approval dan penilaian
+-- ============================================================================
+
+CREATE TABLE portfolio_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    portfolio_id UUID NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    revision_number INTEGER NOT NULL,
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('submit', 'approve', 'assessment')),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT portfolio_revisions_unique UNIQUE (portfolio_id, revision_number)
+);
+
+CREATE INDEX idx_portfolio_revisions_portfolio ON portfolio_revisions(portfolio_id, revision_number DESC);
+
+COMMENT ON TABLE portfolio_revisions IS 'Riwayat revisi portfolio, tidak pernah diubah setelah dibuat';
+COMMENT ON COLUMN portfolio_revisions.revision_number IS 'Nomor revisi berurutan per portfolio, mulai dari 1';
+COMMENT ON COLUMN portfolio_revisions.reason IS 'Kejadian pemicu: submit, approve, assessment';
+COMMENT ON COLUMN portfolio_revisions.snapshot IS 'Judul, slug, thumbnail, status, series, tags dan content blocks terurut';
@@ -0,0 +1,25 @@
+-- ============================================================================
+-- Migration: Add portfolio drafts
+-- Description: Edit portfolio published lewat working draft yang direview ulang
+-- ============================================================================
+
+CREATE TABLE portfolio_drafts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    portfolio_id UUID NOT NULL UNIQUE REFERENCES portfolios(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'editing' CHECK (status IN ('editing', 'pending_review', 'rejected')),
+    content JSONB NOT NULL,
+    admin_review_note TEXT,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ,
+    submitted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_portfolio_drafts_pending ON portfolio_drafts(submitted_at) WHERE status = 'pending_review';
+
+CREATE TRIGGER trg_portfolio_drafts_updated_at BEFORE UPDATE ON portfolio_drafts FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+COMMENT ON TABLE portfolio_drafts IS 'Perubahan pada portfolio published yang menunggu review';
+COMMENT ON COLUMN portfolio_drafts.status IS 'editing: masih diedit, pending_review: diajukan, rejected: ditolak reviewer';
+COMMENT ON COLUMN portfolio_drafts.content IS 'Judul, thumbnail, series, tags dan content blocks versi draft (format sama dengan snapshot revisi)';
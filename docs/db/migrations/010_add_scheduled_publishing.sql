@@ -0,0 +1,14 @@
+-- ============================================================================
+-- Migration: Add scheduled publishing
+-- Description: Portfolio yang disetujui bisa dijadwalkan publish pada waktu tertentu
+-- ============================================================================
+
+-- The new enum value must be committed before the partial index below can use it,
+-- so run this file without a wrapping transaction
+ALTER TYPE portfolio_status ADD VALUE IF NOT EXISTS 'scheduled';
+
+ALTER TABLE portfolios ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_portfolios_scheduled ON portfolios(publish_at) WHERE status = 'scheduled' AND deleted_at IS NULL;
+
+COMMENT ON COLUMN portfolios.publish_at IS 'Jadwal publish yang diminta saat submit/approve; status scheduled sampai waktu ini tiba';
@@ -0,0 +1,25 @@
+-- ============================================================================
+-- Migration: Add portfolio collaborators
+-- Description: Portfolio tim dengan co-author (editor/contributor) yang diundang lewat notifikasi
+-- ============================================================================
+
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'collab_invite';
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'collab_responded';
+
+CREATE TABLE IF NOT EXISTS portfolio_collaborators (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    portfolio_id UUID NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('editor', 'contributor')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined')),
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    responded_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (portfolio_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_portfolio_collaborators_user ON portfolio_collaborators(user_id) WHERE status = 'accepted';
+
+COMMENT ON TABLE portfolio_collaborators IS 'Anggota tim portfolio selain owner';
+COMMENT ON COLUMN portfolio_collaborators.role IS 'editor: boleh mengedit konten, contributor: hanya dikreditkan';
+COMMENT ON COLUMN portfolio_collaborators.status IS 'Undangan dikirim lewat notifikasi; berlaku setelah accepted';
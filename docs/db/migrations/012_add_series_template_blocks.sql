@@ -0,0 +1,13 @@
+-- ============================================================================
+-- Migration: Add series template blocks
+-- Description: Portfolio yang dibuat dari series mendapat placeholder block yang tertaut ke template
+-- ============================================================================
+
+ALTER TABLE series_blocks ADD COLUMN IF NOT EXISTS is_required BOOLEAN NOT NULL DEFAULT true;
+
+ALTER TABLE content_blocks ADD COLUMN IF NOT EXISTS series_block_id UUID REFERENCES series_blocks(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_content_blocks_series_block ON content_blocks(series_block_id) WHERE series_block_id IS NOT NULL;
+
+COMMENT ON COLUMN series_blocks.is_required IS 'Block wajib tidak bisa dihapus dari portfolio dan harus diisi sebelum submit';
+COMMENT ON COLUMN content_blocks.series_block_id IS 'Block template series asal block ini, dibuat saat portfolio dibuat dari series';
@@ -0,0 +1,40 @@
+-- ============================================================================
+-- Migration: Add series assignments
+-- Description: Series bisa ditugaskan ke kelas/jurusan dengan jadwal buka-tutup,
+--              penanda terlambat, dan pengingat otomatis sebelum deadline
+-- ============================================================================
+
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'series_reminder';
+
+ALTER TABLE series ADD COLUMN IF NOT EXISTS opens_at TIMESTAMPTZ;
+ALTER TABLE series ADD COLUMN IF NOT EXISTS closes_at TIMESTAMPTZ;
+ALTER TABLE series ADD COLUMN IF NOT EXISTS reminder_sent_at TIMESTAMPTZ;
+ALTER TABLE series ADD CONSTRAINT series_window_valid CHECK (opens_at IS NULL OR closes_at IS NULL OR closes_at > opens_at);
+
+CREATE TABLE IF NOT EXISTS series_targets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    series_id UUID NOT NULL REFERENCES series(id) ON DELETE CASCADE,
+    kelas_id UUID REFERENCES kelas(id) ON DELETE CASCADE,
+    jurusan_id UUID REFERENCES jurusan(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT series_targets_one_target CHECK ((kelas_id IS NULL) <> (jurusan_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_series_targets_series ON series_targets(series_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_series_targets_kelas ON series_targets(series_id, kelas_id) WHERE kelas_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_series_targets_jurusan ON series_targets(series_id, jurusan_id) WHERE jurusan_id IS NOT NULL;
+
+ALTER TABLE portfolios ADD COLUMN IF NOT EXISTS submitted_at TIMESTAMPTZ;
+
+-- Portfolio yang sudah pernah diajukan dianggap diajukan saat terakhir di-review
+UPDATE portfolios SET submitted_at = COALESCE(reviewed_at, updated_at)
+WHERE submitted_at IS NULL AND status IN ('pending_review', 'rejected', 'published', 'scheduled', 'archived');
+
+COMMENT ON COLUMN series.opens_at IS 'Mulai tugas; portfolio baru untuk series ini ditolak sebelum waktu ini';
+COMMENT ON COLUMN series.closes_at IS 'Deadline tugas; submit setelah waktu ini ditandai terlambat';
+COMMENT ON COLUMN series.reminder_sent_at IS 'Waktu pengingat deadline dikirim, dikosongkan lagi saat deadline diubah';
+COMMENT ON TABLE series_targets IS 'Target tugas series: seluruh siswa aktif di kelas atau jurusan ini';
+COMMENT ON COLUMN series_targets.kelas_id IS 'Kelas target; diisi salah satu dengan jurusan_id';
+COMMENT ON COLUMN series_targets.jurusan_id IS 'Jurusan target, mencakup semua kelas di jurusan tersebut';
+COMMENT ON COLUMN portfolios.submitted_at IS 'Waktu pertama kali diajukan untuk review, dibandingkan dengan deadline series';
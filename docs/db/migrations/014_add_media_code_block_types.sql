@@ -0,0 +1,12 @@
+-- ============================================================================
+-- Migration: Add code, gallery, audio, 3D model and markdown block types
+-- Description: Tipe block baru untuk snippet kode, galeri gambar, audio, model 3D (glTF/GLB) dan markdown
+-- ============================================================================
+
+ALTER TYPE content_block_type ADD VALUE IF NOT EXISTS 'code';
+ALTER TYPE content_block_type ADD VALUE IF NOT EXISTS 'gallery';
+ALTER TYPE content_block_type ADD VALUE IF NOT EXISTS 'audio';
+ALTER TYPE content_block_type ADD VALUE IF NOT EXISTS 'model_3d';
+ALTER TYPE content_block_type ADD VALUE IF NOT EXISTS 'markdown';
+
+COMMENT ON COLUMN content_blocks.block_type IS 'Tipe block: text, image, table, youtube, button, embed, figma, canva, ppt, pdf, doc, code, gallery, audio, model_3d, markdown';
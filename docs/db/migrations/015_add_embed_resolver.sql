@@ -0,0 +1,21 @@
+-- ============================================================================
+-- Migration: Add embed resolver cache
+-- Description: Link eksternal content block (YouTube, Figma, Canva, embed, dokumen)
+--              dicek saat disimpan dan dicek ulang berkala; metadatanya disimpan di block
+-- ============================================================================
+
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'embed_broken';
+
+CREATE TYPE embed_status AS ENUM ('ok', 'dead', 'private');
+
+ALTER TABLE content_blocks ADD COLUMN IF NOT EXISTS embed_status embed_status;
+ALTER TABLE content_blocks ADD COLUMN IF NOT EXISTS embed_meta JSONB;
+ALTER TABLE content_blocks ADD COLUMN IF NOT EXISTS embed_checked_at TIMESTAMPTZ;
+
+-- Existing blocks start unchecked, so the background job resolves them on its first ticks
+CREATE INDEX IF NOT EXISTS idx_content_blocks_embed_check ON content_blocks(embed_checked_at NULLS FIRST)
+    WHERE block_type IN ('youtube', 'figma', 'canva', 'embed', 'ppt', 'pdf', 'doc');
+
+COMMENT ON COLUMN content_blocks.embed_status IS 'Hasil pengecekan terakhir link eksternal block: ok, dead (hilang), private (tidak publik/tidak bisa di-embed)';
+COMMENT ON COLUMN content_blocks.embed_meta IS 'Cache metadata link: provider, canonical_url, title, thumbnail_url, aspect_ratio';
+COMMENT ON COLUMN content_blocks.embed_checked_at IS 'Waktu pengecekan link terakhir, NULL berarti belum dicek dan akan diambil job berikutnya';
@@ -0,0 +1,44 @@
+-- ============================================================================
+-- Migration: Add portfolio share links
+-- Description: Link privat dengan masa berlaku untuk membuka portfolio yang belum publish
+--              tanpa login, dengan penghitung kunjungan dan komentar tamu opsional
+-- ============================================================================
+
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'share_comment';
+
+-- Link rahasia untuk membuka portfolio (termasuk draft/pending_review) tanpa login
+CREATE TABLE IF NOT EXISTS portfolio_share_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    portfolio_id UUID NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    label VARCHAR(100),
+    allow_comments BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    view_count BIGINT NOT NULL DEFAULT 0,
+    last_viewed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_portfolio_share_links_portfolio ON portfolio_share_links(portfolio_id, created_at DESC);
+
+COMMENT ON TABLE portfolio_share_links IS 'Share link privat portfolio dengan masa berlaku, bisa dicabut';
+COMMENT ON COLUMN portfolio_share_links.token_hash IS 'SHA-256 dari token; token asli hanya diberikan sekali saat link dibuat';
+COMMENT ON COLUMN portfolio_share_links.allow_comments IS 'Jika aktif, tamu yang membuka link boleh meninggalkan komentar';
+COMMENT ON COLUMN portfolio_share_links.view_count IS 'Jumlah kunjungan lewat link ini';
+
+-- Komentar tamu lewat share link, terpisah dari komentar publik yang butuh akun
+CREATE TABLE IF NOT EXISTS share_link_comments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    share_link_id UUID NOT NULL REFERENCES portfolio_share_links(id) ON DELETE CASCADE,
+    portfolio_id UUID NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    author_name VARCHAR(100) NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_share_link_comments_portfolio ON share_link_comments(portfolio_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_share_link_comments_link ON share_link_comments(share_link_id, created_at);
+
+COMMENT ON TABLE share_link_comments IS 'Masukan dari orang tua, mentor atau mitra industri yang membuka share link';
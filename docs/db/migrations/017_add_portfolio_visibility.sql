@@ -0,0 +1,13 @@
+-- ============================================================================
+-- Migration: Add portfolio visibility
+-- Description: Tingkat visibility untuk portfolio published: public, khusus user login,
+--              khusus jurusan yang sama, atau unlisted (hanya lewat link)
+-- ============================================================================
+
+CREATE TYPE portfolio_visibility AS ENUM ('public', 'logged_in', 'jurusan', 'unlisted');
+
+ALTER TABLE portfolios ADD COLUMN IF NOT EXISTS visibility portfolio_visibility NOT NULL DEFAULT 'public';
+
+CREATE INDEX IF NOT EXISTS idx_portfolios_visibility ON portfolios(visibility) WHERE status = 'published' AND deleted_at IS NULL;
+
+COMMENT ON COLUMN portfolios.visibility IS 'Siapa yang boleh melihat portfolio published: public, logged_in (user login), jurusan (jurusan yang sama dengan pemilik), unlisted (hanya lewat link, tidak tampil di daftar)';
@@ -0,0 +1,35 @@
+-- ============================================================================
+-- Migration: Add bookmark collections
+-- Description: Koleksi bookmark bernama (privat atau publik) berisi portfolio published
+--              dengan urutan, tampil di profil dan bisa dibagikan
+-- ============================================================================
+
+-- Koleksi bookmark bernama milik user, terpisah dari like yang ikut memengaruhi ranking feed
+CREATE TABLE IF NOT EXISTS bookmark_collections (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    nama VARCHAR(100) NOT NULL,
+    deskripsi TEXT,
+    is_public BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_bookmark_collections_user ON bookmark_collections(user_id, created_at DESC);
+
+COMMENT ON TABLE bookmark_collections IS 'Koleksi portfolio yang disimpan user, misalnya "Inspirasi UI"';
+COMMENT ON COLUMN bookmark_collections.is_public IS 'Koleksi publik tampil di tab koleksi profil dan bisa dibuka lewat link-nya';
+
+CREATE TABLE IF NOT EXISTS bookmark_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    collection_id UUID NOT NULL REFERENCES bookmark_collections(id) ON DELETE CASCADE,
+    portfolio_id UUID NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    item_order INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (collection_id, portfolio_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bookmark_items_collection ON bookmark_items(collection_id, item_order);
+CREATE INDEX IF NOT EXISTS idx_bookmark_items_portfolio ON bookmark_items(portfolio_id);
+
+COMMENT ON TABLE bookmark_items IS 'Portfolio di dalam koleksi; dihapus saat portfolio tidak lagi published atau dihapus';
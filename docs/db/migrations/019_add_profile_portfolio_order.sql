@@ -0,0 +1,20 @@
+-- ============================================================================
+-- Migration: Add profile portfolio pins and order
+-- Description: Pin portfolio terbaik di atas profil dan atur urutan tampil secara manual
+-- ============================================================================
+
+-- Pin dan urutan tampil portfolio di profil (per user, termasuk co-author)
+CREATE TABLE IF NOT EXISTS profile_portfolios (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    portfolio_id UUID NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    is_pinned BOOLEAN NOT NULL DEFAULT FALSE,
+    display_order INT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (user_id, portfolio_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_profile_portfolios_pinned ON profile_portfolios(user_id) WHERE is_pinned = TRUE;
+
+COMMENT ON TABLE profile_portfolios IS 'Pengaturan tampilan portfolio di profil: pin (maksimal 6) dan urutan manual';
+COMMENT ON COLUMN profile_portfolios.display_order IS 'Urutan manual di profil; NULL tampil setelah yang diurutkan, terbaru dulu';
@@ -0,0 +1,48 @@
+-- ============================================================================
+-- Migration: Add content reports
+-- Description: Laporan user atas portfolio, komentar, profil, dan pesan dengan antrean
+--              moderasi per target, penyembunyian komentar, dan notifikasi ke pelapor
+-- ============================================================================
+
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'report_resolved';
+
+CREATE TYPE report_target_type AS ENUM ('portfolio', 'comment', 'user', 'message');
+CREATE TYPE report_reason AS ENUM ('spam', 'harassment', 'inappropriate', 'plagiarism', 'impersonation', 'other');
+CREATE TYPE report_status AS ENUM ('pending', 'resolved', 'dismissed');
+
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS is_hidden BOOLEAN NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN comments.is_hidden IS 'Disembunyikan moderator karena laporan; isi diganti placeholder, balasan tetap tampil';
+
+CREATE TABLE IF NOT EXISTS content_reports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    reporter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    target_type report_target_type NOT NULL,
+    target_id UUID NOT NULL,
+    target_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason report_reason NOT NULL,
+    details TEXT,
+    status report_status NOT NULL DEFAULT 'pending',
+    action VARCHAR(20),
+    resolution_note TEXT,
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Satu laporan terbuka per pelapor per target
+CREATE UNIQUE INDEX IF NOT EXISTS idx_content_reports_pending_unique ON content_reports(reporter_id, target_type, target_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_content_reports_queue ON content_reports(status, target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_content_reports_target_user ON content_reports(target_user_id);
+
+COMMENT ON TABLE content_reports IS 'Laporan konten; laporan atas target yang sama dikelompokkan jadi satu kasus di antrean moderasi';
+COMMENT ON COLUMN content_reports.target_id IS 'ID portfolio, komentar, user, atau pesan sesuai target_type';
+COMMENT ON COLUMN content_reports.target_user_id IS 'Pemilik konten yang dilaporkan, sasaran tindakan deactivate_user';
+COMMENT ON COLUMN content_reports.action IS 'Tindakan moderator: dismiss, hide_content, unpublish, deactivate_user';
+
+DROP TRIGGER IF EXISTS trg_content_reports_updated_at ON content_reports;
+CREATE TRIGGER trg_content_reports_updated_at
+    BEFORE UPDATE ON content_reports
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
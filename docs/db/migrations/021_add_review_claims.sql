@@ -0,0 +1,30 @@
+-- ============================================================================
+-- Migration: Add review claims and reviewer pools
+-- Description: Kunci moderator atas portfolio yang menunggu review dengan batas waktu,
+--              dan pembagian review otomatis bergiliran per jurusan
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS review_claims (
+    portfolio_id UUID PRIMARY KEY REFERENCES portfolios(id) ON DELETE CASCADE,
+    reviewer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    is_assigned BOOLEAN NOT NULL DEFAULT FALSE,
+    claimed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_claims_reviewer ON review_claims(reviewer_id, expires_at);
+
+COMMENT ON TABLE review_claims IS 'Portfolio yang sedang ditinjau seorang moderator; moderator lain tidak bisa approve/reject selama klaim berlaku';
+COMMENT ON COLUMN review_claims.is_assigned IS 'TRUE jika dibagikan otomatis dari reviewer pool, FALSE jika diklaim sendiri';
+COMMENT ON COLUMN review_claims.expires_at IS 'Klaim kedaluwarsa setelah waktu ini dan portfolio kembali ke antrean';
+
+CREATE TABLE IF NOT EXISTS reviewer_pools (
+    jurusan_id UUID NOT NULL REFERENCES jurusan(id) ON DELETE CASCADE,
+    reviewer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    last_assigned_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (jurusan_id, reviewer_id)
+);
+
+COMMENT ON TABLE reviewer_pools IS 'Moderator yang menerima portfolio baru dari satu jurusan secara bergiliran';
+COMMENT ON COLUMN reviewer_pools.last_assigned_at IS 'Waktu pembagian terakhir; yang paling lama tidak mendapat bagian dipilih berikutnya';
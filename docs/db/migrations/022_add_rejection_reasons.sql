@@ -0,0 +1,85 @@
+-- ============================================================================
+-- Migration: Add rejection reasons and series review checklists
+-- Description: Template alasan penolakan, riwayat penolakan terstruktur dengan penunjuk
+--              block konten, dan checklist review per series
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS rejection_reasons (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    nama VARCHAR(100) NOT NULL,
+    deskripsi TEXT,
+    urutan INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_rejection_reasons_urutan ON rejection_reasons(urutan) WHERE deleted_at IS NULL;
+
+COMMENT ON TABLE rejection_reasons IS 'Template alasan penolakan portfolio yang dipilih moderator';
+COMMENT ON COLUMN rejection_reasons.deskripsi IS 'Penjelasan yang ditampilkan ke siswa bersama nama alasan';
+
+DROP TRIGGER IF EXISTS trg_rejection_reasons_updated_at ON rejection_reasons;
+CREATE TRIGGER trg_rejection_reasons_updated_at
+    BEFORE UPDATE ON rejection_reasons
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
+
+CREATE TABLE IF NOT EXISTS portfolio_rejections (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    portfolio_id UUID NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    is_draft BOOLEAN NOT NULL DEFAULT FALSE,
+    rejected_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    note TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_portfolio_rejections_portfolio ON portfolio_rejections(portfolio_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_portfolio_rejections_created ON portfolio_rejections(created_at);
+
+COMMENT ON TABLE portfolio_rejections IS 'Riwayat penolakan terstruktur; admin_review_note tetap diisi dengan ringkasan teksnya';
+COMMENT ON COLUMN portfolio_rejections.is_draft IS 'TRUE jika yang ditolak adalah perubahan (draft) atas portfolio yang sudah tayang';
+COMMENT ON COLUMN portfolio_rejections.note IS 'Catatan tambahan moderator di luar alasan yang dipilih';
+
+CREATE TABLE IF NOT EXISTS portfolio_rejection_reasons (
+    rejection_id UUID NOT NULL REFERENCES portfolio_rejections(id) ON DELETE CASCADE,
+    reason_id UUID NOT NULL REFERENCES rejection_reasons(id) ON DELETE RESTRICT,
+    PRIMARY KEY (rejection_id, reason_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_portfolio_rejection_reasons_reason ON portfolio_rejection_reasons(reason_id);
+
+CREATE TABLE IF NOT EXISTS portfolio_rejection_blocks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    rejection_id UUID NOT NULL REFERENCES portfolio_rejections(id) ON DELETE CASCADE,
+    block_id UUID NOT NULL,
+    note TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_portfolio_rejection_blocks_rejection ON portfolio_rejection_blocks(rejection_id);
+
+COMMENT ON TABLE portfolio_rejection_blocks IS 'Block konten yang perlu diperbaiki siswa beserta catatannya';
+COMMENT ON COLUMN portfolio_rejection_blocks.block_id IS 'ID content block, atau ID block pada snapshot draft bila is_draft; sengaja tanpa foreign key';
+
+CREATE TABLE IF NOT EXISTS series_checklist_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    series_id UUID NOT NULL REFERENCES series(id) ON DELETE CASCADE,
+    label VARCHAR(200) NOT NULL,
+    urutan INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_series_checklist_items_series ON series_checklist_items(series_id, urutan);
+
+COMMENT ON TABLE series_checklist_items IS 'Poin yang diperiksa moderator saat meninjau portfolio dari suatu series';
+
+INSERT INTO rejection_reasons (nama, deskripsi, urutan)
+SELECT v.nama, v.deskripsi, v.urutan FROM (VALUES
+('Konten tidak lengkap', 'Portfolio belum memuat informasi atau karya yang cukup untuk ditampilkan', 1),
+('Kualitas gambar rendah', 'Gambar buram, pecah, atau terlalu kecil sehingga karya tidak terlihat jelas', 2),
+('Tidak sesuai series', 'Isi portfolio tidak mengikuti ketentuan series yang dipilih', 3),
+('Bukan karya sendiri', 'Karya terindikasi milik orang lain atau tidak mencantumkan sumber', 4),
+('Melanggar aturan konten', 'Konten mengandung unsur yang tidak pantas untuk lingkungan sekolah', 5)
+) AS v(nama, deskripsi, urutan)
+WHERE NOT EXISTS (SELECT 1 FROM rejection_reasons);
@@ -0,0 +1,40 @@
+-- ============================================================================
+-- Migration: Add text filter rules
+-- Description: Daftar kata terlarang dan pola regex yang diperiksa saat komentar, pesan,
+--              portfolio, content block, dan profil disimpan; konten yang cocok dengan
+--              aturan flag masuk antrean moderasi sebagai laporan tanpa pelapor
+-- ============================================================================
+
+ALTER TYPE report_reason ADD VALUE IF NOT EXISTS 'auto_filter';
+
+ALTER TABLE content_reports ALTER COLUMN reporter_id DROP NOT NULL;
+
+COMMENT ON COLUMN content_reports.reporter_id IS 'Pelapor; NULL untuk laporan dari filter teks otomatis';
+
+CREATE TYPE text_filter_match AS ENUM ('word', 'regex');
+CREATE TYPE text_filter_action AS ENUM ('block', 'mask', 'flag');
+
+CREATE TABLE IF NOT EXISTS text_filter_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    pattern VARCHAR(200) NOT NULL,
+    match_type text_filter_match NOT NULL DEFAULT 'word',
+    action text_filter_action NOT NULL,
+    bahasa VARCHAR(5),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_text_filter_rules_active ON text_filter_rules(is_active);
+
+COMMENT ON TABLE text_filter_rules IS 'Kata terlarang dan pola regex untuk filter teks otomatis';
+COMMENT ON COLUMN text_filter_rules.pattern IS 'Kata/frasa (dicocokkan per kata utuh) atau regex, tidak membedakan huruf besar';
+COMMENT ON COLUMN text_filter_rules.action IS 'block menolak simpan, mask mengganti dengan bintang, flag masuk antrean moderasi';
+COMMENT ON COLUMN text_filter_rules.bahasa IS 'id atau en untuk pengelompokan daftar kata; NULL untuk pola umum';
+
+DROP TRIGGER IF EXISTS trg_text_filter_rules_updated_at ON text_filter_rules;
+CREATE TRIGGER trg_text_filter_rules_updated_at
+    BEFORE UPDATE ON text_filter_rules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
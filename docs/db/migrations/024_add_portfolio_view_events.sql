@@ -0,0 +1,22 @@
+-- ============================================================================
+-- Migration: Add portfolio view events
+-- Description: Log setiap kunjungan portfolio beserta sumbernya untuk statistik
+--              pemilik (kunjungan per hari, penonton unik, sumber trafik)
+-- ============================================================================
+
+CREATE TYPE view_source AS ENUM ('feed', 'search', 'profile', 'direct', 'share_link');
+
+CREATE TABLE IF NOT EXISTS portfolio_view_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    portfolio_id UUID NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    session_id VARCHAR(64),
+    source view_source NOT NULL DEFAULT 'direct',
+    viewed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_portfolio_view_events_portfolio ON portfolio_view_events(portfolio_id, viewed_at);
+CREATE INDEX IF NOT EXISTS idx_portfolio_likes_portfolio_created ON portfolio_likes(portfolio_id, created_at);
+
+COMMENT ON TABLE portfolio_view_events IS 'Setiap kunjungan portfolio untuk statistik pemilik; kunjungan oleh pemilik dan editor tidak dicatat';
+COMMENT ON COLUMN portfolio_view_events.source IS 'Sumber kunjungan yang dilaporkan klien; share_link dicatat oleh endpoint link berbagi';
@@ -0,0 +1,60 @@
+-- ============================================================================
+-- Migration: Add slug and username history
+-- Description: Slug lama portfolio (dicatat trigger saat judul berubah) dan username
+--              lama user, agar link yang sudah dibagikan tetap bisa diarahkan ke
+--              alamat terkini
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS portfolio_slug_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    portfolio_id UUID NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    slug VARCHAR(250) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT portfolio_slug_history_unique UNIQUE (user_id, slug)
+);
+
+CREATE INDEX IF NOT EXISTS idx_portfolio_slug_history_slug ON portfolio_slug_history(slug);
+CREATE INDEX IF NOT EXISTS idx_portfolio_slug_history_portfolio ON portfolio_slug_history(portfolio_id);
+
+COMMENT ON TABLE portfolio_slug_history IS 'Slug lama portfolio; slug yang sedang dipakai portfolio lain milik user yang sama tidak disimpan';
+
+-- Function: Keep the previous slug when generate_portfolio_slug rewrites it. The slug is
+-- changed by a BEFORE trigger, which UPDATE OF slug would not notice, so every update is checked.
+CREATE OR REPLACE FUNCTION record_portfolio_slug_history()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD.slug IS NOT DISTINCT FROM NEW.slug THEN
+        RETURN NEW;
+    END IF;
+
+    -- A live slug wins over an old one
+    DELETE FROM portfolio_slug_history WHERE user_id = NEW.user_id AND slug = NEW.slug;
+
+    IF TG_OP = 'UPDATE' THEN
+        INSERT INTO portfolio_slug_history (portfolio_id, user_id, slug)
+        VALUES (NEW.id, NEW.user_id, OLD.slug)
+        ON CONFLICT (user_id, slug) DO UPDATE
+            SET portfolio_id = EXCLUDED.portfolio_id, created_at = NOW();
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_portfolio_slug_history ON portfolios;
+CREATE TRIGGER trg_portfolio_slug_history
+    AFTER INSERT OR UPDATE ON portfolios
+    FOR EACH ROW
+    EXECUTE FUNCTION record_portfolio_slug_history();
+
+CREATE TABLE IF NOT EXISTS username_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    username VARCHAR(30) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_username_history_user ON username_history(user_id);
+
+COMMENT ON TABLE username_history IS 'Username lama user; tetap dipesan untuk pemiliknya dan bisa diambil kembali olehnya';
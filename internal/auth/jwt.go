@@ -22,6 +22,9 @@ type AccessTokenClaims struct {
 	Sub  string `json:"sub"`
 	Role string `json:"role"`
 	JTI  string `json:"jti"`
+	// MustChangePassword keeps the token to the password change routes; a new token is issued
+	// once the password is changed
+	MustChangePassword bool `json:"must_change_password,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -41,15 +44,16 @@ func NewJWTService(cfg *config.Config) *JWTService {
 	}
 }
 
-func (j *JWTService) GenerateAccessToken(userID uuid.UUID, role string) (string, string, error) {
+func (j *JWTService) GenerateAccessToken(userID uuid.UUID, role string, mustChangePassword bool) (string, string, error) {
 	jti := uuid.New().String()
 	now := time.Now()
 	expiresAt := now.Add(j.accessExpiry)
 
 	claims := AccessTokenClaims{
-		Sub:  userID.String(),
-		Role: role,
-		JTI:  jti,
+		Sub:                userID.String(),
+		Role:               role,
+		JTI:                jti,
+		MustChangePassword: mustChangePassword,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    "grafikarsa",
 			Audience:  jwt.ClaimStrings{"grafikarsa-api"},
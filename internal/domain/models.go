@@ -16,6 +16,7 @@ const (
 	RoleStudent UserRole = "student"
 	RoleAlumni  UserRole = "alumni"
 	RoleAdmin   UserRole = "admin"
+	RoleTeacher UserRole = "teacher"
 )
 
 type PortfolioStatus string
@@ -26,6 +27,19 @@ const (
 	StatusRejected      PortfolioStatus = "rejected"
 	StatusPublished     PortfolioStatus = "published"
 	StatusArchived      PortfolioStatus = "archived"
+	// StatusScheduled is approved content waiting for its publish_at time
+	StatusScheduled PortfolioStatus = "scheduled"
+)
+
+// PortfolioVisibility - siapa yang boleh melihat portfolio yang sudah published
+type PortfolioVisibility string
+
+const (
+	VisibilityPublic   PortfolioVisibility = "public"
+	VisibilityLoggedIn PortfolioVisibility = "logged_in"
+	VisibilityJurusan  PortfolioVisibility = "jurusan"
+	// VisibilityUnlisted is reachable through its link but never listed
+	VisibilityUnlisted PortfolioVisibility = "unlisted"
 )
 
 type ContentBlockType string
@@ -43,6 +57,12 @@ const (
 	BlockPPT   ContentBlockType = "ppt"
 	BlockPDF   ContentBlockType = "pdf"
 	BlockDoc   ContentBlockType = "doc"
+	// Block types for code, media and 3D work
+	BlockCode     ContentBlockType = "code"
+	BlockGallery  ContentBlockType = "gallery"
+	BlockAudio    ContentBlockType = "audio"
+	BlockModel3D  ContentBlockType = "model_3d"
+	BlockMarkdown ContentBlockType = "markdown"
 )
 
 type SocialPlatform string
@@ -119,8 +139,10 @@ type Kelas struct {
 	Tingkat       int          `gorm:"type:smallint;not null" json:"tingkat"`
 	Rombel        string       `gorm:"type:char(1);not null" json:"rombel"`
 	Nama          string       `gorm:"type:varchar(20);not null" json:"nama"`
+	WaliKelasID   *uuid.UUID   `gorm:"type:uuid" json:"wali_kelas_id,omitempty"`
 	TahunAjaran   *TahunAjaran `gorm:"foreignKey:TahunAjaranID" json:"tahun_ajaran,omitempty"`
 	Jurusan       *Jurusan     `gorm:"foreignKey:JurusanID" json:"jurusan,omitempty"`
+	WaliKelas     *User        `gorm:"foreignKey:WaliKelasID" json:"wali_kelas,omitempty"`
 }
 
 func (Kelas) TableName() string { return "kelas" }
@@ -133,17 +155,54 @@ type Tag struct {
 
 func (Tag) TableName() string { return "tags" }
 
-// Series - Template portofolio dengan block konten yang sudah ditentukan
+// Series - Template portofolio dengan block konten yang sudah ditentukan.
+// Series dengan target kelas/jurusan berfungsi sebagai tugas: OpensAt/ClosesAt membatasi
+// waktu pengerjaan dan ReminderSentAt menandai pengingat deadline yang sudah dikirim.
 type Series struct {
 	BaseModel
-	Nama      string        `gorm:"type:varchar(100);not null;uniqueIndex" json:"nama"`
-	Deskripsi *string       `gorm:"type:text" json:"deskripsi,omitempty"`
-	IsActive  bool          `gorm:"not null;default:true" json:"is_active"`
-	Blocks    []SeriesBlock `gorm:"foreignKey:SeriesID" json:"blocks,omitempty"`
+	Nama           string         `gorm:"type:varchar(100);not null;uniqueIndex" json:"nama"`
+	Deskripsi      *string        `gorm:"type:text" json:"deskripsi,omitempty"`
+	IsActive       bool           `gorm:"not null;default:true" json:"is_active"`
+	OpensAt        *time.Time     `json:"opens_at,omitempty"`
+	ClosesAt       *time.Time     `json:"closes_at,omitempty"`
+	ReminderSentAt *time.Time     `json:"reminder_sent_at,omitempty"`
+	Blocks         []SeriesBlock  `gorm:"foreignKey:SeriesID" json:"blocks,omitempty"`
+	Targets        []SeriesTarget `gorm:"foreignKey:SeriesID" json:"targets,omitempty"`
 }
 
 func (Series) TableName() string { return "series" }
 
+// IsOpen reports whether work on the series may start at t
+func (s *Series) IsOpen(t time.Time) bool {
+	return s.OpensAt == nil || !t.Before(*s.OpensAt)
+}
+
+// IsLate reports whether a submission at submittedAt missed the series deadline.
+// Work that was never submitted is late once the deadline has passed at now.
+func (s *Series) IsLate(submittedAt *time.Time, now time.Time) bool {
+	if s.ClosesAt == nil {
+		return false
+	}
+	if submittedAt == nil {
+		return now.After(*s.ClosesAt)
+	}
+	return submittedAt.After(*s.ClosesAt)
+}
+
+// SeriesTarget - Kelas atau jurusan yang ditugaskan mengerjakan series.
+// Tepat satu dari KelasID dan JurusanID terisi.
+type SeriesTarget struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	SeriesID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"series_id"`
+	KelasID   *uuid.UUID `gorm:"type:uuid" json:"kelas_id,omitempty"`
+	JurusanID *uuid.UUID `gorm:"type:uuid" json:"jurusan_id,omitempty"`
+	CreatedAt time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	Kelas     *Kelas     `gorm:"foreignKey:KelasID" json:"kelas,omitempty"`
+	Jurusan   *Jurusan   `gorm:"foreignKey:JurusanID" json:"jurusan,omitempty"`
+}
+
+func (SeriesTarget) TableName() string { return "series_targets" }
+
 // SeriesBlock - Template block konten untuk series
 type SeriesBlock struct {
 	ID         uuid.UUID        `gorm:"type:uuid;primaryKey" json:"id"`
@@ -151,6 +210,7 @@ type SeriesBlock struct {
 	BlockType  ContentBlockType `gorm:"type:content_block_type;not null" json:"block_type"`
 	BlockOrder int              `gorm:"not null" json:"block_order"`
 	Instruksi  string           `gorm:"type:text;not null" json:"instruksi"`
+	IsRequired bool             `gorm:"not null" json:"is_required"`
 	CreatedAt  time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt  time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
 }
@@ -160,23 +220,24 @@ func (SeriesBlock) TableName() string { return "series_blocks" }
 // User
 type User struct {
 	BaseModel
-	Username     string           `gorm:"type:varchar(30);not null;uniqueIndex" json:"username"`
-	Email        string           `gorm:"type:varchar(255);not null;uniqueIndex" json:"email"`
-	PasswordHash string           `gorm:"type:varchar(255);not null" json:"-"`
-	Nama         string           `gorm:"type:varchar(100);not null" json:"nama"`
-	Bio          *string          `gorm:"type:text" json:"bio,omitempty"`
-	AvatarURL    *string          `gorm:"type:text" json:"avatar_url,omitempty"`
-	BannerURL    *string          `gorm:"type:text" json:"banner_url,omitempty"`
-	Role         UserRole         `gorm:"type:user_role;not null;default:'student'" json:"role"`
-	NISN         *string          `gorm:"type:varchar(20)" json:"nisn,omitempty"`
-	NIS          *string          `gorm:"type:varchar(30)" json:"nis,omitempty"`
-	KelasID      *uuid.UUID       `gorm:"type:uuid" json:"kelas_id,omitempty"`
-	TahunMasuk   *int             `gorm:"type:integer" json:"tahun_masuk,omitempty"`
-	TahunLulus   *int             `gorm:"type:integer" json:"tahun_lulus,omitempty"`
-	IsActive     bool             `gorm:"not null;default:true" json:"is_active"`
-	LastLoginAt  *time.Time       `json:"last_login_at,omitempty"`
-	Kelas        *Kelas           `gorm:"foreignKey:KelasID" json:"kelas,omitempty"`
-	SocialLinks  []UserSocialLink `gorm:"foreignKey:UserID" json:"social_links,omitempty"`
+	Username           string           `gorm:"type:varchar(30);not null;uniqueIndex" json:"username"`
+	Email              string           `gorm:"type:varchar(255);not null;uniqueIndex" json:"email"`
+	PasswordHash       string           `gorm:"type:varchar(255);not null" json:"-"`
+	Nama               string           `gorm:"type:varchar(100);not null" json:"nama"`
+	Bio                *string          `gorm:"type:text" json:"bio,omitempty"`
+	AvatarURL          *string          `gorm:"type:text" json:"avatar_url,omitempty"`
+	BannerURL          *string          `gorm:"type:text" json:"banner_url,omitempty"`
+	Role               UserRole         `gorm:"type:user_role;not null;default:'student'" json:"role"`
+	NISN               *string          `gorm:"type:varchar(20)" json:"nisn,omitempty"`
+	NIS                *string          `gorm:"type:varchar(30)" json:"nis,omitempty"`
+	KelasID            *uuid.UUID       `gorm:"type:uuid" json:"kelas_id,omitempty"`
+	TahunMasuk         *int             `gorm:"type:integer" json:"tahun_masuk,omitempty"`
+	TahunLulus         *int             `gorm:"type:integer" json:"tahun_lulus,omitempty"`
+	IsActive           bool             `gorm:"not null;default:true" json:"is_active"`
+	MustChangePassword bool             `gorm:"not null;default:false" json:"must_change_password"`
+	LastLoginAt        *time.Time       `json:"last_login_at,omitempty"`
+	Kelas              *Kelas           `gorm:"foreignKey:KelasID" json:"kelas,omitempty"`
+	SocialLinks        []UserSocialLink `gorm:"foreignKey:UserID" json:"social_links,omitempty"`
 }
 
 func (User) TableName() string { return "users" }
@@ -200,7 +261,12 @@ type StudentClassHistory struct {
 	KelasID       uuid.UUID    `gorm:"type:uuid;not null" json:"kelas_id"`
 	TahunAjaranID uuid.UUID    `gorm:"type:uuid;not null" json:"tahun_ajaran_id"`
 	IsCurrent     bool         `gorm:"not null;default:false" json:"is_current"`
+	EffectiveDate time.Time    `gorm:"type:date;not null;default:CURRENT_DATE" json:"effective_date"`
+	EndDate       *time.Time   `gorm:"type:date" json:"end_date,omitempty"`
+	Reason        *string      `gorm:"type:text" json:"reason,omitempty"`
+	MovedBy       *uuid.UUID   `gorm:"type:uuid" json:"moved_by,omitempty"`
 	CreatedAt     time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	User          *User        `gorm:"foreignKey:UserID" json:"user,omitempty"`
 	Kelas         *Kelas       `gorm:"foreignKey:KelasID" json:"kelas,omitempty"`
 	TahunAjaran   *TahunAjaran `gorm:"foreignKey:TahunAjaranID" json:"tahun_ajaran,omitempty"`
 }
@@ -253,25 +319,46 @@ func (Follow) TableName() string { return "follows" }
 // Portfolio
 type Portfolio struct {
 	BaseModel
-	UserID          uuid.UUID       `gorm:"type:uuid;not null" json:"user_id"`
-	Judul           string          `gorm:"type:varchar(200);not null" json:"judul"`
-	Slug            string          `gorm:"type:varchar(250);not null" json:"slug"`
-	ThumbnailURL    *string         `gorm:"type:text" json:"thumbnail_url,omitempty"`
-	Status          PortfolioStatus `gorm:"type:portfolio_status;not null;default:'draft'" json:"status"`
-	AdminReviewNote *string         `gorm:"type:text" json:"admin_review_note,omitempty"`
-	ReviewedBy      *uuid.UUID      `gorm:"type:uuid" json:"reviewed_by,omitempty"`
-	ReviewedAt      *time.Time      `json:"reviewed_at,omitempty"`
-	PublishedAt     *time.Time      `json:"published_at,omitempty"`
-	SeriesID        *uuid.UUID      `gorm:"type:uuid" json:"series_id,omitempty"`
-	User            *User           `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	Reviewer        *User           `gorm:"foreignKey:ReviewedBy" json:"reviewer,omitempty"`
-	Series          *Series         `gorm:"foreignKey:SeriesID" json:"series,omitempty"`
-	Tags            []Tag           `gorm:"many2many:portfolio_tags" json:"tags,omitempty"`
-	ContentBlocks   []ContentBlock  `gorm:"foreignKey:PortfolioID" json:"content_blocks,omitempty"`
+	UserID          uuid.UUID           `gorm:"type:uuid;not null" json:"user_id"`
+	Judul           string              `gorm:"type:varchar(200);not null" json:"judul"`
+	Slug            string              `gorm:"type:varchar(250);not null" json:"slug"`
+	ThumbnailURL    *string             `gorm:"type:text" json:"thumbnail_url,omitempty"`
+	Status          PortfolioStatus     `gorm:"type:portfolio_status;not null;default:'draft'" json:"status"`
+	Visibility      PortfolioVisibility `gorm:"type:portfolio_visibility;not null;default:'public'" json:"visibility"`
+	AdminReviewNote *string             `gorm:"type:text" json:"admin_review_note,omitempty"`
+	ReviewedBy      *uuid.UUID          `gorm:"type:uuid" json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time          `json:"reviewed_at,omitempty"`
+	PublishedAt     *time.Time          `json:"published_at,omitempty"`
+	PublishAt       *time.Time          `json:"publish_at,omitempty"`
+	SubmittedAt     *time.Time          `json:"submitted_at,omitempty"`
+	SeriesID        *uuid.UUID          `gorm:"type:uuid" json:"series_id,omitempty"`
+	User            *User               `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Reviewer        *User               `gorm:"foreignKey:ReviewedBy" json:"reviewer,omitempty"`
+	Series          *Series             `gorm:"foreignKey:SeriesID" json:"series,omitempty"`
+	Tags            []Tag               `gorm:"many2many:portfolio_tags" json:"tags,omitempty"`
+	ContentBlocks   []ContentBlock      `gorm:"foreignKey:PortfolioID" json:"content_blocks,omitempty"`
 }
 
 func (Portfolio) TableName() string { return "portfolios" }
 
+// IsApproved reports whether the portfolio passed review, live or scheduled.
+// Edits to approved portfolios go through a working draft.
+func (p *Portfolio) IsApproved() bool {
+	return p.Status == StatusPublished || p.Status == StatusScheduled
+}
+
+// ProfilePortfolio - Pin dan urutan tampil portfolio di profil seorang user. Disimpan per user
+// karena portfolio tim tampil di profil owner dan setiap co-author.
+type ProfilePortfolio struct {
+	UserID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	PortfolioID  uuid.UUID `gorm:"type:uuid;primaryKey" json:"portfolio_id"`
+	IsPinned     bool      `gorm:"not null;default:false" json:"is_pinned"`
+	DisplayOrder *int      `json:"display_order,omitempty"`
+	UpdatedAt    time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (ProfilePortfolio) TableName() string { return "profile_portfolios" }
+
 // PortfolioTag (junction table)
 type PortfolioTag struct {
 	PortfolioID uuid.UUID `gorm:"type:uuid;primaryKey" json:"portfolio_id"`
@@ -283,17 +370,30 @@ func (PortfolioTag) TableName() string { return "portfolio_tags" }
 
 // ContentBlock
 type ContentBlock struct {
-	ID          uuid.UUID        `gorm:"type:uuid;primaryKey" json:"id"`
-	PortfolioID uuid.UUID        `gorm:"type:uuid;not null" json:"portfolio_id"`
-	BlockType   ContentBlockType `gorm:"type:content_block_type;not null" json:"block_type"`
-	BlockOrder  int              `gorm:"not null" json:"block_order"`
-	Payload     JSONB            `gorm:"type:jsonb;not null;default:'{}'" json:"payload"`
-	CreatedAt   time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt   time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	ID             uuid.UUID        `gorm:"type:uuid;primaryKey" json:"id"`
+	PortfolioID    uuid.UUID        `gorm:"type:uuid;not null" json:"portfolio_id"`
+	SeriesBlockID  *uuid.UUID       `gorm:"type:uuid" json:"series_block_id,omitempty"`
+	BlockType      ContentBlockType `gorm:"type:content_block_type;not null" json:"block_type"`
+	BlockOrder     int              `gorm:"not null" json:"block_order"`
+	Payload        JSONB            `gorm:"type:jsonb;not null;default:'{}'" json:"payload"`
+	EmbedStatus    *EmbedStatus     `gorm:"type:embed_status" json:"embed_status,omitempty"`
+	EmbedMeta      JSONB            `gorm:"type:jsonb" json:"embed_meta,omitempty"`
+	EmbedCheckedAt *time.Time       `json:"embed_checked_at,omitempty"`
+	CreatedAt      time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt      time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
 }
 
 func (ContentBlock) TableName() string { return "content_blocks" }
 
+// EmbedStatus is the outcome of the last check of a block's external URL
+type EmbedStatus string
+
+const (
+	EmbedOK      EmbedStatus = "ok"
+	EmbedDead    EmbedStatus = "dead"
+	EmbedPrivate EmbedStatus = "private"
+)
+
 // PortfolioLike
 type PortfolioLike struct {
 	UserID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
@@ -349,6 +449,195 @@ type Feedback struct {
 
 func (Feedback) TableName() string { return "feedback" }
 
+// ============================================================================
+// CONTENT REPORT MODELS
+// ============================================================================
+
+// ReportTargetType enum
+type ReportTargetType string
+
+const (
+	ReportTargetPortfolio ReportTargetType = "portfolio"
+	ReportTargetComment   ReportTargetType = "comment"
+	ReportTargetUser      ReportTargetType = "user"
+	ReportTargetMessage   ReportTargetType = "message"
+)
+
+// ReportReason enum
+type ReportReason string
+
+const (
+	ReportReasonSpam          ReportReason = "spam"
+	ReportReasonHarassment    ReportReason = "harassment"
+	ReportReasonInappropriate ReportReason = "inappropriate"
+	ReportReasonPlagiarism    ReportReason = "plagiarism"
+	ReportReasonImpersonation ReportReason = "impersonation"
+	ReportReasonOther         ReportReason = "other"
+	// ReportReasonAutoFilter is set by the text filter, never chosen by users
+	ReportReasonAutoFilter ReportReason = "auto_filter"
+)
+
+// ReportStatus enum
+type ReportStatus string
+
+const (
+	ReportStatusPending   ReportStatus = "pending"
+	ReportStatusResolved  ReportStatus = "resolved"
+	ReportStatusDismissed ReportStatus = "dismissed"
+)
+
+// ReportAction enum
+type ReportAction string
+
+const (
+	ReportActionDismiss        ReportAction = "dismiss"
+	ReportActionHideContent    ReportAction = "hide_content"
+	ReportActionUnpublish      ReportAction = "unpublish"
+	ReportActionDeactivateUser ReportAction = "deactivate_user"
+)
+
+// ContentReport - Laporan pengguna atas portfolio, komentar, profil, atau pesan yang melanggar aturan
+type ContentReport struct {
+	ID             uuid.UUID        `gorm:"type:uuid;primaryKey" json:"id"`
+	ReporterID     *uuid.UUID       `gorm:"type:uuid" json:"reporter_id,omitempty"` // kosong untuk laporan otomatis dari filter teks
+	TargetType     ReportTargetType `gorm:"type:report_target_type;not null" json:"target_type"`
+	TargetID       uuid.UUID        `gorm:"type:uuid;not null" json:"target_id"`
+	TargetUserID   uuid.UUID        `gorm:"type:uuid;not null" json:"target_user_id"`
+	Reason         ReportReason     `gorm:"type:report_reason;not null" json:"reason"`
+	Details        *string          `gorm:"type:text" json:"details,omitempty"`
+	Status         ReportStatus     `gorm:"type:report_status;not null;default:'pending'" json:"status"`
+	Action         *ReportAction    `gorm:"type:varchar(20)" json:"action,omitempty"`
+	ResolutionNote *string          `gorm:"type:text" json:"resolution_note,omitempty"`
+	ResolvedBy     *uuid.UUID       `gorm:"type:uuid" json:"resolved_by,omitempty"`
+	ResolvedAt     *time.Time       `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt      time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	Reporter       *User            `gorm:"foreignKey:ReporterID" json:"reporter,omitempty"`
+}
+
+func (ContentReport) TableName() string { return "content_reports" }
+
+// ============================================================================
+// TEXT FILTER MODELS
+// ============================================================================
+
+// TextFilterMatch enum
+type TextFilterMatch string
+
+const (
+	TextFilterWord  TextFilterMatch = "word"
+	TextFilterRegex TextFilterMatch = "regex"
+)
+
+// TextFilterAction enum
+type TextFilterAction string
+
+const (
+	TextFilterBlock TextFilterAction = "block"
+	TextFilterMask  TextFilterAction = "mask"
+	TextFilterFlag  TextFilterAction = "flag"
+)
+
+// TextFilterRule - Kata terlarang atau pola regex yang diperiksa saat konten teks disimpan
+type TextFilterRule struct {
+	ID        uuid.UUID        `gorm:"type:uuid;primaryKey" json:"id"`
+	Pattern   string           `gorm:"type:varchar(200);not null" json:"pattern"`
+	MatchType TextFilterMatch  `gorm:"type:text_filter_match;not null;default:'word'" json:"match_type"`
+	Action    TextFilterAction `gorm:"type:text_filter_action;not null" json:"action"`
+	Bahasa    *string          `gorm:"type:varchar(5)" json:"bahasa,omitempty"` // id atau en, kosong untuk pola umum
+	IsActive  bool             `gorm:"not null;default:true" json:"is_active"`
+	CreatedBy *uuid.UUID       `gorm:"type:uuid" json:"created_by,omitempty"`
+	CreatedAt time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (TextFilterRule) TableName() string { return "text_filter_rules" }
+
+// ============================================================================
+// REVIEW ASSIGNMENT MODELS
+// ============================================================================
+
+// ReviewClaim - Kunci moderator atas portfolio yang menunggu review; tidak berlaku lagi setelah expires_at
+type ReviewClaim struct {
+	PortfolioID uuid.UUID `gorm:"type:uuid;primaryKey" json:"portfolio_id"`
+	ReviewerID  uuid.UUID `gorm:"type:uuid;not null" json:"reviewer_id"`
+	IsAssigned  bool      `gorm:"not null;default:false" json:"is_assigned"`
+	ClaimedAt   time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"claimed_at"`
+	ExpiresAt   time.Time `gorm:"not null" json:"expires_at"`
+	Reviewer    *User     `gorm:"foreignKey:ReviewerID" json:"reviewer,omitempty"`
+}
+
+func (ReviewClaim) TableName() string { return "review_claims" }
+
+// ReviewerPool - Moderator yang menerima pembagian review otomatis untuk satu jurusan
+type ReviewerPool struct {
+	JurusanID      uuid.UUID  `gorm:"type:uuid;primaryKey" json:"jurusan_id"`
+	ReviewerID     uuid.UUID  `gorm:"type:uuid;primaryKey" json:"reviewer_id"`
+	LastAssignedAt *time.Time `json:"last_assigned_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	Reviewer       *User      `gorm:"foreignKey:ReviewerID" json:"reviewer,omitempty"`
+}
+
+func (ReviewerPool) TableName() string { return "reviewer_pools" }
+
+// ============================================================================
+// REJECTION REASON MODELS
+// ============================================================================
+
+// RejectionReason - Template alasan penolakan portfolio yang dikelola admin
+type RejectionReason struct {
+	BaseModel
+	Nama      string  `gorm:"type:varchar(100);not null" json:"nama"`
+	Deskripsi *string `gorm:"type:text" json:"deskripsi,omitempty"`
+	Urutan    int     `gorm:"not null;default:0" json:"urutan"`
+	IsActive  bool    `gorm:"not null;default:true" json:"is_active"`
+}
+
+func (RejectionReason) TableName() string { return "rejection_reasons" }
+
+// SeriesChecklistItem - Poin yang diperiksa moderator saat meninjau portfolio dari suatu series
+type SeriesChecklistItem struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	SeriesID  uuid.UUID `gorm:"type:uuid;not null" json:"series_id"`
+	Label     string    `gorm:"type:varchar(200);not null" json:"label"`
+	Urutan    int       `gorm:"not null;default:0" json:"urutan"`
+	CreatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (SeriesChecklistItem) TableName() string { return "series_checklist_items" }
+
+// PortfolioRejection - Catatan penolakan portfolio atau perubahan (draft) portfolio beserta alasannya
+type PortfolioRejection struct {
+	ID          uuid.UUID                 `gorm:"type:uuid;primaryKey" json:"id"`
+	PortfolioID uuid.UUID                 `gorm:"type:uuid;not null" json:"portfolio_id"`
+	IsDraft     bool                      `gorm:"not null;default:false" json:"is_draft"`
+	RejectedBy  *uuid.UUID                `gorm:"type:uuid" json:"rejected_by,omitempty"`
+	Note        *string                   `gorm:"type:text" json:"note,omitempty"`
+	CreatedAt   time.Time                 `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	Reasons     []RejectionReason         `gorm:"many2many:portfolio_rejection_reasons;joinForeignKey:RejectionID;joinReferences:ReasonID" json:"reasons,omitempty"`
+	Blocks      []PortfolioRejectionBlock `gorm:"foreignKey:RejectionID" json:"blocks,omitempty"`
+}
+
+func (PortfolioRejection) TableName() string { return "portfolio_rejections" }
+
+// PortfolioRejectionReason - Alasan yang dipilih pada suatu penolakan
+type PortfolioRejectionReason struct {
+	RejectionID uuid.UUID `gorm:"type:uuid;primaryKey" json:"rejection_id"`
+	ReasonID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"reason_id"`
+}
+
+func (PortfolioRejectionReason) TableName() string { return "portfolio_rejection_reasons" }
+
+// PortfolioRejectionBlock - Penunjuk block konten yang perlu diperbaiki siswa
+type PortfolioRejectionBlock struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	RejectionID uuid.UUID `gorm:"type:uuid;not null" json:"rejection_id"`
+	BlockID     uuid.UUID `gorm:"type:uuid;not null" json:"block_id"`
+	Note        string    `gorm:"type:text;not null" json:"note"`
+}
+
+func (PortfolioRejectionBlock) TableName() string { return "portfolio_rejection_blocks" }
+
 // ============================================================================
 // ASSESSMENT MODELS
 // ============================================================================
@@ -409,6 +698,12 @@ const (
 	NotifFeedbackUpdated   NotificationType = "feedback_updated"
 	NotifNewComment        NotificationType = "new_comment"
 	NotifReplyComment      NotificationType = "reply_comment"
+	NotifCollabInvite      NotificationType = "collab_invite"
+	NotifCollabResponded   NotificationType = "collab_responded"
+	NotifSeriesReminder    NotificationType = "series_reminder"
+	NotifEmbedBroken       NotificationType = "embed_broken"
+	NotifShareComment      NotificationType = "share_comment"
+	NotifReportResolved    NotificationType = "report_resolved"
 )
 
 // Comment
@@ -419,6 +714,7 @@ type Comment struct {
 	ParentID    *uuid.UUID `gorm:"type:uuid" json:"parent_id,omitempty"`
 	Content     string     `gorm:"type:text;not null" json:"content"`
 	IsEdited    bool       `gorm:"default:false" json:"is_edited"`
+	IsHidden    bool       `gorm:"not null;default:false" json:"is_hidden"`
 	User        *User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
 	Portfolio   *Portfolio `gorm:"foreignKey:PortfolioID" json:"portfolio,omitempty"`
 	Parent      *Comment   `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
@@ -574,6 +870,29 @@ type PortfolioView struct {
 
 func (PortfolioView) TableName() string { return "portfolio_views" }
 
+// ViewSource enum - dari mana penonton membuka portfolio
+type ViewSource string
+
+const (
+	ViewSourceFeed      ViewSource = "feed"
+	ViewSourceSearch    ViewSource = "search"
+	ViewSourceProfile   ViewSource = "profile"
+	ViewSourceDirect    ViewSource = "direct"
+	ViewSourceShareLink ViewSource = "share_link"
+)
+
+// PortfolioViewEvent - Setiap kunjungan ke portfolio, untuk analytics pemilik; portfolio_views hanya menyimpan satu baris per penonton
+type PortfolioViewEvent struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	PortfolioID uuid.UUID  `gorm:"type:uuid;not null" json:"portfolio_id"`
+	UserID      *uuid.UUID `gorm:"type:uuid" json:"user_id,omitempty"`
+	SessionID   *string    `gorm:"type:varchar(64)" json:"session_id,omitempty"`
+	Source      ViewSource `gorm:"type:view_source;not null;default:'direct'" json:"source"`
+	ViewedAt    time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"viewed_at"`
+}
+
+func (PortfolioViewEvent) TableName() string { return "portfolio_view_events" }
+
 // UserInterest - Profil interest user dari aktivitas like
 type UserInterest struct {
 	UserID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
@@ -885,6 +1204,255 @@ func (m *UserBlock) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// ============================================================================
+// PORTFOLIO REVISION MODELS
+// ============================================================================
+
+// RevisionReason records which event produced a revision
+type RevisionReason string
+
+const (
+	RevisionReasonSubmit     RevisionReason = "submit"
+	RevisionReasonApprove    RevisionReason = "approve"
+	RevisionReasonAssessment RevisionReason = "assessment"
+)
+
+// SnapshotTag is a tag as it was attached at snapshot time
+type SnapshotTag struct {
+	ID   uuid.UUID `json:"id"`
+	Nama string    `json:"nama"`
+}
+
+// SnapshotBlock is a content block as it was at snapshot time
+type SnapshotBlock struct {
+	ID            uuid.UUID        `json:"id"`
+	SeriesBlockID *uuid.UUID       `json:"series_block_id,omitempty"`
+	BlockType     ContentBlockType `json:"block_type"`
+	BlockOrder    int              `json:"block_order"`
+	Payload       JSONB            `json:"payload"`
+}
+
+// PortfolioSnapshot is the frozen metadata, tags and ordered blocks of a portfolio
+type PortfolioSnapshot struct {
+	Judul        string          `json:"judul"`
+	Slug         string          `json:"slug"`
+	ThumbnailURL *string         `json:"thumbnail_url,omitempty"`
+	Status       PortfolioStatus `json:"status"`
+	SeriesID     *uuid.UUID      `json:"series_id,omitempty"`
+	Tags         []SnapshotTag   `json:"tags"`
+	Blocks       []SnapshotBlock `json:"blocks"`
+}
+
+func (s PortfolioSnapshot) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+func (s *PortfolioSnapshot) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	}
+	return nil
+}
+
+// NewPortfolioSnapshot captures p; tags and content blocks must be preloaded
+func NewPortfolioSnapshot(p *Portfolio) PortfolioSnapshot {
+	snapshot := PortfolioSnapshot{
+		Judul:        p.Judul,
+		Slug:         p.Slug,
+		ThumbnailURL: p.ThumbnailURL,
+		Status:       p.Status,
+		SeriesID:     p.SeriesID,
+		Tags:         make([]SnapshotTag, 0, len(p.Tags)),
+		Blocks:       make([]SnapshotBlock, 0, len(p.ContentBlocks)),
+	}
+	for _, t := range p.Tags {
+		snapshot.Tags = append(snapshot.Tags, SnapshotTag{ID: t.ID, Nama: t.Nama})
+	}
+	for _, b := range p.ContentBlocks {
+		snapshot.Blocks = append(snapshot.Blocks, SnapshotBlock{
+			ID: b.ID, SeriesBlockID: b.SeriesBlockID, BlockType: b.BlockType, BlockOrder: b.BlockOrder, Payload: b.Payload,
+		})
+	}
+	return snapshot
+}
+
+// PortfolioRevision - Snapshot immutable portfolio pada submit, approval dan penilaian
+type PortfolioRevision struct {
+	ID             uuid.UUID         `gorm:"type:uuid;primaryKey" json:"id"`
+	PortfolioID    uuid.UUID         `gorm:"type:uuid;not null" json:"portfolio_id"`
+	RevisionNumber int               `gorm:"not null" json:"revision_number"`
+	Reason         RevisionReason    `gorm:"type:varchar(20);not null" json:"reason"`
+	CreatedBy      *uuid.UUID        `gorm:"type:uuid" json:"created_by,omitempty"`
+	Snapshot       PortfolioSnapshot `gorm:"type:jsonb;not null" json:"snapshot"`
+	CreatedAt      time.Time         `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	Creator        *User             `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
+}
+
+func (PortfolioRevision) TableName() string { return "portfolio_revisions" }
+
+// ============================================================================
+// PORTFOLIO DRAFT MODELS
+// ============================================================================
+
+// DraftStatus is the review state of a published portfolio's working draft
+type DraftStatus string
+
+const (
+	DraftEditing       DraftStatus = "editing"
+	DraftPendingReview DraftStatus = "pending_review"
+	DraftRejected      DraftStatus = "rejected"
+)
+
+// PortfolioDraft - Salinan kerja portfolio yang sudah published. Konten live tetap tampil
+// sampai draft disetujui, lalu isi draft menggantikan konten live.
+type PortfolioDraft struct {
+	ID              uuid.UUID         `gorm:"type:uuid;primaryKey" json:"id"`
+	PortfolioID     uuid.UUID         `gorm:"type:uuid;not null;uniqueIndex" json:"portfolio_id"`
+	Status          DraftStatus       `gorm:"type:varchar(20);not null;default:'editing'" json:"status"`
+	Content         PortfolioSnapshot `gorm:"type:jsonb;not null" json:"content"`
+	AdminReviewNote *string           `gorm:"type:text" json:"admin_review_note,omitempty"`
+	ReviewedBy      *uuid.UUID        `gorm:"type:uuid" json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time        `json:"reviewed_at,omitempty"`
+	SubmittedAt     *time.Time        `json:"submitted_at,omitempty"`
+	CreatedAt       time.Time         `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt       time.Time         `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (PortfolioDraft) TableName() string { return "portfolio_drafts" }
+
+// ============================================================================
+// PORTFOLIO COLLABORATOR MODELS
+// ============================================================================
+
+// CollaboratorRole enum. The owner is Portfolio.UserID and never stored as a collaborator row.
+type CollaboratorRole string
+
+const (
+	CollaboratorOwner       CollaboratorRole = "owner"
+	CollaboratorEditor      CollaboratorRole = "editor"
+	CollaboratorContributor CollaboratorRole = "contributor"
+)
+
+// CollaboratorStatus enum
+type CollaboratorStatus string
+
+const (
+	CollaboratorPending  CollaboratorStatus = "pending"
+	CollaboratorAccepted CollaboratorStatus = "accepted"
+	CollaboratorDeclined CollaboratorStatus = "declined"
+)
+
+// PortfolioCollaborator - Co-author portfolio tim. Editor boleh mengedit konten,
+// contributor hanya dikreditkan. Undangan berlaku setelah diterima.
+type PortfolioCollaborator struct {
+	ID          uuid.UUID          `gorm:"type:uuid;primaryKey" json:"id"`
+	PortfolioID uuid.UUID          `gorm:"type:uuid;not null;uniqueIndex:idx_portfolio_collaborator" json:"portfolio_id"`
+	UserID      uuid.UUID          `gorm:"type:uuid;not null;uniqueIndex:idx_portfolio_collaborator" json:"user_id"`
+	Role        CollaboratorRole   `gorm:"type:varchar(20);not null" json:"role"`
+	Status      CollaboratorStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	InvitedBy   *uuid.UUID         `gorm:"type:uuid" json:"invited_by,omitempty"`
+	RespondedAt *time.Time         `json:"responded_at,omitempty"`
+	CreatedAt   time.Time          `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	User        *User              `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Portfolio   *Portfolio         `gorm:"foreignKey:PortfolioID" json:"portfolio,omitempty"`
+}
+
+func (PortfolioCollaborator) TableName() string { return "portfolio_collaborators" }
+
+// PortfolioShareLink - Link rahasia untuk membuka portfolio yang belum publish tanpa login.
+// Hanya hash token yang disimpan; token asli diberikan sekali saat link dibuat.
+type PortfolioShareLink struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	PortfolioID   uuid.UUID  `gorm:"type:uuid;not null" json:"portfolio_id"`
+	CreatedBy     uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	TokenHash     string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	Label         *string    `gorm:"type:varchar(100)" json:"label,omitempty"`
+	AllowComments bool       `gorm:"not null;default:false" json:"allow_comments"`
+	ExpiresAt     time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	ViewCount     int64      `gorm:"not null;default:0" json:"view_count"`
+	LastViewedAt  *time.Time `json:"last_viewed_at,omitempty"`
+	CreatedAt     time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (PortfolioShareLink) TableName() string { return "portfolio_share_links" }
+
+// IsActive reports whether the link still opens the portfolio at t
+func (l *PortfolioShareLink) IsActive(t time.Time) bool {
+	return l.RevokedAt == nil && t.Before(l.ExpiresAt)
+}
+
+// ShareLinkComment - Komentar tamu yang membuka portfolio lewat share link
+type ShareLinkComment struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	ShareLinkID uuid.UUID `gorm:"type:uuid;not null" json:"share_link_id"`
+	PortfolioID uuid.UUID `gorm:"type:uuid;not null" json:"portfolio_id"`
+	AuthorName  string    `gorm:"type:varchar(100);not null" json:"author_name"`
+	Content     string    `gorm:"type:text;not null" json:"content"`
+	CreatedAt   time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (ShareLinkComment) TableName() string { return "share_link_comments" }
+
+// ============================================================================
+// BOOKMARK COLLECTION MODELS
+// ============================================================================
+
+// BookmarkCollection - Koleksi bernama berisi portfolio yang disimpan user, privat atau publik.
+// Koleksi publik tampil di profil dan bisa dibagikan lewat link-nya.
+type BookmarkCollection struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
+	Nama      string    `gorm:"type:varchar(100);not null" json:"nama"`
+	Deskripsi *string   `gorm:"type:text" json:"deskripsi,omitempty"`
+	IsPublic  bool      `gorm:"not null;default:false" json:"is_public"`
+	CreatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	User      *User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (BookmarkCollection) TableName() string { return "bookmark_collections" }
+
+// BookmarkItem - Portfolio di dalam koleksi bookmark, diurutkan dengan item_order
+type BookmarkItem struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	CollectionID uuid.UUID  `gorm:"type:uuid;not null" json:"collection_id"`
+	PortfolioID  uuid.UUID  `gorm:"type:uuid;not null" json:"portfolio_id"`
+	ItemOrder    int        `gorm:"not null;default:0" json:"item_order"`
+	CreatedAt    time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	Portfolio    *Portfolio `gorm:"foreignKey:PortfolioID" json:"portfolio,omitempty"`
+}
+
+func (BookmarkItem) TableName() string { return "bookmark_items" }
+
+// ============================================================================
+// SLUG HISTORY MODELS
+// ============================================================================
+
+// PortfolioSlugHistory - Slug lama portfolio, diisi trigger saat judul berubah agar link lama tetap bisa diarahkan
+type PortfolioSlugHistory struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	PortfolioID uuid.UUID `gorm:"type:uuid;not null" json:"portfolio_id"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
+	Slug        string    `gorm:"type:varchar(250);not null" json:"slug"`
+	CreatedAt   time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (PortfolioSlugHistory) TableName() string { return "portfolio_slug_history" }
+
+// UsernameHistory - Username lama user; tetap dipesan untuk pemiliknya agar link profil lama tidak diambil orang lain
+type UsernameHistory struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
+	Username  string    `gorm:"type:varchar(30);not null;uniqueIndex" json:"username"`
+	CreatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (UsernameHistory) TableName() string { return "username_history" }
+
 // ============================================================================
 // HOOKS FOR UUID GENERATION
 // ============================================================================
@@ -997,3 +1565,99 @@ func (m *ChangelogRead) BeforeCreate(tx *gorm.DB) error {
 	setUUIDIfEmpty(&m.ID)
 	return nil
 }
+
+// PortfolioRevision Hook
+func (m *PortfolioRevision) BeforeCreate(tx *gorm.DB) error {
+	setUUIDIfEmpty(&m.ID)
+	return nil
+}
+
+// PortfolioDraft Hook
+func (m *PortfolioDraft) BeforeCreate(tx *gorm.DB) error {
+	setUUIDIfEmpty(&m.ID)
+	return nil
+}
+
+// PortfolioCollaborator Hook
+func (m *PortfolioCollaborator) BeforeCreate(tx *gorm.DB) error {
+	setUUIDIfEmpty(&m.ID)
+	return nil
+}
+
+// SeriesTarget Hook
+func (m *SeriesTarget) BeforeCreate(tx *gorm.DB) error {
+	setUUIDIfEmpty(&m.ID)
+	return nil
+}
+
+// PortfolioShareLink Hook
+func (m *PortfolioShareLink) BeforeCreate(tx *gorm.DB) error {
+	setUUIDIfEmpty(&m.ID)
+	return nil
+}
+
+// ShareLinkComment Hook
+func (m *ShareLinkComment) BeforeCreate(tx *gorm.DB) error {
+	setUUIDIfEmpty(&m.ID)
+	return nil
+}
+
+// BookmarkCollection Hook
+func (m *BookmarkCollection) BeforeCreate(tx *gorm.DB) error {
+	setUUIDIfEmpty(&m.ID)
+	return nil
+}
+
+// BookmarkItem Hook
+func (m *BookmarkItem) BeforeCreate(tx *gorm.DB) error {
+	setUUIDIfEmpty(&m.ID)
+	return nil
+}
+
+// ContentReport Hook
+func (m *ContentReport) BeforeCreate(tx *gorm.DB) error {
+	setUUIDIfEmpty(&m.ID)
+	return nil
+}
+
+// SeriesChecklistItem Hook
+func (m *SeriesChecklistItem) BeforeCreate(tx *gorm.DB) error {
+	setUUIDIfEmpty(&m.ID)
+	return nil
+}
+
+// PortfolioRejection Hook
+func (m *PortfolioRejection) BeforeCreate(tx *gorm.DB) error {
+	setUUIDIfEmpty(&m.ID)
+	return nil
+}
+
+// PortfolioRejectionBlock Hook
+func (m *PortfolioRejectionBlock) BeforeCreate(tx *gorm.DB) error {
+	setUUIDIfEmpty(&m.ID)
+	return nil
+}
+
+// TextFilterRule Hook
+func (m *TextFilterRule) BeforeCreate(tx *gorm.DB) error {
+	setUUIDIfEmpty(&m.ID)
+	return nil
+}
+
+// PortfolioViewEvent Hook
+func (m *PortfolioViewEvent) BeforeCreate(tx *gorm.DB) error {
+	setUUIDIfEmpty(&m.ID)
+	return nil
+}
+
+// PortfolioSlugHistory Hook
+func (m *PortfolioSlugHistory) BeforeCreate(tx *gorm.DB) error {
+	setUUIDIfEmpty(&m.ID)
+	return nil
+}
+
+// UsernameHistory Hook
+func (m *UsernameHistory) BeforeCreate(tx *gorm.DB) error {
+	setUUIDIfEmpty(&m.ID)
+	return nil
+}
@@ -67,19 +67,68 @@ type KelasDetailDTO struct {
 	Rombel       string          `json:"rombel"`
 	TahunAjaran  *TahunAjaranDTO `json:"tahun_ajaran,omitempty"`
 	Jurusan      *JurusanDTO     `json:"jurusan,omitempty"`
+	WaliKelas    *UserBriefDTO   `json:"wali_kelas,omitempty"`
 	StudentCount int64           `json:"student_count,omitempty"`
 	CreatedAt    time.Time       `json:"created_at"`
 }
 
 type CreateKelasRequest struct {
-	TahunAjaranID uuid.UUID `json:"tahun_ajaran_id" validate:"required"`
-	JurusanID     uuid.UUID `json:"jurusan_id" validate:"required"`
-	Tingkat       int       `json:"tingkat" validate:"required"`
-	Rombel        string    `json:"rombel" validate:"required"`
+	TahunAjaranID uuid.UUID  `json:"tahun_ajaran_id" validate:"required"`
+	JurusanID     uuid.UUID  `json:"jurusan_id" validate:"required"`
+	Tingkat       int        `json:"tingkat" validate:"required"`
+	Rombel        string     `json:"rombel" validate:"required"`
+	WaliKelasID   *uuid.UUID `json:"wali_kelas_id,omitempty"`
 }
 
 type UpdateKelasRequest struct {
-	Rombel *string `json:"rombel,omitempty"`
+	Rombel          *string    `json:"rombel,omitempty"`
+	WaliKelasID     *uuid.UUID `json:"wali_kelas_id,omitempty"`
+	RemoveWaliKelas bool       `json:"remove_wali_kelas,omitempty"`
+}
+
+// Class Transfer
+type TransferStudentsRequest struct {
+	UserIDs       []uuid.UUID `json:"user_ids" validate:"required"`
+	KelasID       uuid.UUID   `json:"kelas_id" validate:"required"`
+	EffectiveDate string      `json:"effective_date" validate:"required"`
+	Reason        *string     `json:"reason,omitempty"`
+}
+
+type TransferSkippedDTO struct {
+	UserID uuid.UUID `json:"user_id"`
+	Reason string    `json:"reason"`
+}
+
+type TransferStudentsResponse struct {
+	Kelas         KelasDTO             `json:"kelas"`
+	EffectiveDate string               `json:"effective_date"`
+	Moved         int                  `json:"moved"`
+	Skipped       []TransferSkippedDTO `json:"skipped"`
+}
+
+type AdminClassHistoryDTO struct {
+	ClassHistoryDTO
+	KelasID       uuid.UUID `json:"kelas_id"`
+	IsCurrent     bool      `json:"is_current"`
+	EffectiveDate string    `json:"effective_date"`
+	EndDate       *string   `json:"end_date,omitempty"`
+	Reason        *string   `json:"reason,omitempty"`
+}
+
+type KelasRosterEntryDTO struct {
+	Student       UserBriefDTO `json:"student"`
+	NIS           *string      `json:"nis,omitempty"`
+	NISN          *string      `json:"nisn,omitempty"`
+	IsCurrent     bool         `json:"is_current"`
+	EffectiveDate string       `json:"effective_date"`
+	EndDate       *string      `json:"end_date,omitempty"`
+	Reason        *string      `json:"reason,omitempty"`
+}
+
+type KelasRosterResponse struct {
+	Kelas    KelasDTO              `json:"kelas"`
+	Date     *string               `json:"date,omitempty"`
+	Students []KelasRosterEntryDTO `json:"students"`
 }
 
 // Tags
@@ -124,11 +173,11 @@ type AdminUserDTO struct {
 
 type AdminUserDetailDTO struct {
 	AdminUserDTO
-	Bio          *string           `json:"bio,omitempty"`
-	BannerURL    *string           `json:"banner_url,omitempty"`
-	ClassHistory []ClassHistoryDTO `json:"class_history,omitempty"`
-	SocialLinks  []SocialLinkDTO   `json:"social_links,omitempty"`
-	UpdatedAt    time.Time         `json:"updated_at"`
+	Bio          *string                `json:"bio,omitempty"`
+	BannerURL    *string                `json:"banner_url,omitempty"`
+	ClassHistory []AdminClassHistoryDTO `json:"class_history,omitempty"`
+	SocialLinks  []SocialLinkDTO        `json:"social_links,omitempty"`
+	UpdatedAt    time.Time              `json:"updated_at"`
 }
 
 type CreateUserRequest struct {
@@ -171,6 +220,32 @@ type AdminPortfolioDTO struct {
 	Status       string            `json:"status"`
 	CreatedAt    time.Time         `json:"created_at"`
 	User         *PortfolioUserDTO `json:"user,omitempty"`
+	// HasPendingEdit marks a published portfolio whose working draft waits for review
+	HasPendingEdit bool `json:"has_pending_edit,omitempty"`
+	// Claim is the reviewer currently holding a pending portfolio
+	Claim *ReviewClaimDTO `json:"claim,omitempty"`
+}
+
+type ReviewClaimDTO struct {
+	Reviewer   *UserBriefDTO `json:"reviewer,omitempty"`
+	IsMine     bool          `json:"is_mine"`
+	IsAssigned bool          `json:"is_assigned"`
+	ClaimedAt  time.Time     `json:"claimed_at"`
+	ExpiresAt  time.Time     `json:"expires_at"`
+}
+
+type ReviewerPoolDTO struct {
+	JurusanID uuid.UUID               `json:"jurusan_id"`
+	Reviewers []ReviewerPoolMemberDTO `json:"reviewers"`
+}
+
+type ReviewerPoolMemberDTO struct {
+	Reviewer       *UserBriefDTO `json:"reviewer,omitempty"`
+	LastAssignedAt *time.Time    `json:"last_assigned_at,omitempty"`
+}
+
+type SetReviewerPoolRequest struct {
+	ReviewerIDs []uuid.UUID `json:"reviewer_ids"`
 }
 
 type AdminPortfolioDetailDTO struct {
@@ -180,6 +255,11 @@ type AdminPortfolioDetailDTO struct {
 
 type ModeratePortfolioRequest struct {
 	Note string `json:"note,omitempty"`
+	// ReasonIDs and Blocks are only read on rejection
+	ReasonIDs []uuid.UUID             `json:"reason_ids,omitempty"`
+	Blocks    []RejectionBlockRequest `json:"blocks,omitempty"`
+	// PublishAt overrides the schedule requested by the student on approval
+	PublishAt *time.Time `json:"publish_at,omitempty"`
 }
 
 type AdminUpdatePortfolioRequest struct {
@@ -196,6 +276,7 @@ type DashboardStatsDTO struct {
 	Kelas                   KelasStatsDTO               `json:"kelas"`
 	RecentUsers             []RecentUserDTO             `json:"recent_users"`
 	RecentPendingPortfolios []RecentPendingPortfolioDTO `json:"recent_pending_portfolios"`
+	RejectionReasons        []RejectionReasonStatDTO    `json:"rejection_reasons"`
 }
 
 type UserStatsDTO struct {
@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PortfolioAnalyticsDTO - statistik portfolio untuk pemiliknya dalam rentang beberapa hari terakhir
+type PortfolioAnalyticsDTO struct {
+	PortfolioID uuid.UUID              `json:"portfolio_id"`
+	Days        int                    `json:"days"`
+	From        time.Time              `json:"from"`
+	To          time.Time              `json:"to"`
+	Summary     AnalyticsSummaryDTO    `json:"summary"`
+	Daily       []AnalyticsDayDTO      `json:"daily"`
+	Sources     []ViewSourceCountDTO   `json:"sources"`
+	Audience    AudienceDTO            `json:"audience"`
+	Comparison  AnalyticsComparisonDTO `json:"comparison"`
+}
+
+// AnalyticsSummaryDTO - total dalam rentang; views menghitung setiap kunjungan, unique_viewers
+// menghitung setiap penonton sekali, dan total_likes dihitung sejak portfolio dibuat
+type AnalyticsSummaryDTO struct {
+	Views         int64 `json:"views"`
+	UniqueViewers int64 `json:"unique_viewers"`
+	Likes         int64 `json:"likes"`
+	TotalLikes    int64 `json:"total_likes"`
+}
+
+// AnalyticsDayDTO - angka per hari; total_likes adalah jumlah like sampai akhir hari tersebut
+type AnalyticsDayDTO struct {
+	Date          string `json:"date"`
+	Views         int64  `json:"views"`
+	UniqueViewers int64  `json:"unique_viewers"`
+	Likes         int64  `json:"likes"`
+	TotalLikes    int64  `json:"total_likes"`
+}
+
+// ViewSourceCountDTO - jumlah kunjungan dari satu sumber (feed, search, profile, direct, share_link)
+type ViewSourceCountDTO struct {
+	Source string `json:"source"`
+	Views  int64  `json:"views"`
+}
+
+// AudienceDTO - sebaran penonton tanpa identitas; kelompok yang terlalu kecil digabung ke "lainnya",
+// yang juga disembunyikan selama jumlahnya masih terlalu kecil
+type AudienceDTO struct {
+	ByRole    []AudienceGroupDTO `json:"by_role"`
+	ByJurusan []AudienceGroupDTO `json:"by_jurusan"`
+	Guests    int64              `json:"guests"`
+}
+
+// AudienceGroupDTO - jumlah penonton unik dalam satu kelompok
+type AudienceGroupDTO struct {
+	Label   string `json:"label"`
+	Viewers int64  `json:"viewers"`
+}
+
+// AnalyticsComparisonDTO - perbandingan dengan portfolio lain milik siswa yang sama
+type AnalyticsComparisonDTO struct {
+	Portfolios   []PortfolioEngagementDTO `json:"portfolios"`
+	ViewsRank    int                      `json:"views_rank"`
+	AverageViews float64                  `json:"average_views"`
+	AverageLikes float64                  `json:"average_likes"`
+}
+
+// PortfolioEngagementDTO - kunjungan dan like satu portfolio dalam rentang yang sama
+type PortfolioEngagementDTO struct {
+	ID        uuid.UUID `json:"id"`
+	Judul     string    `json:"judul"`
+	Slug      string    `json:"slug"`
+	Views     int64     `json:"views"`
+	Likes     int64     `json:"likes"`
+	IsCurrent bool      `json:"is_current"`
+}
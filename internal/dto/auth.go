@@ -9,10 +9,11 @@ type LoginRequest struct {
 }
 
 type LoginResponse struct {
-	AccessToken string       `json:"access_token"`
-	TokenType   string       `json:"token_type"`
-	ExpiresIn   int64        `json:"expires_in"`
-	User        UserBriefDTO `json:"user"`
+	AccessToken        string       `json:"access_token"`
+	TokenType          string       `json:"token_type"`
+	ExpiresIn          int64        `json:"expires_in"`
+	User               UserBriefDTO `json:"user"`
+	MustChangePassword bool         `json:"must_change_password"`
 }
 
 type UserBriefDTO struct {
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BookmarkCollectionDTO adalah ringkasan koleksi bookmark
+type BookmarkCollectionDTO struct {
+	ID        uuid.UUID         `json:"id"`
+	Nama      string            `json:"nama"`
+	Deskripsi *string           `json:"deskripsi,omitempty"`
+	IsPublic  bool              `json:"is_public"`
+	ItemCount int64             `json:"item_count"`
+	User      *PortfolioUserDTO `json:"user,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// BookmarkCollectionDetailDTO untuk GET /collections/:id
+type BookmarkCollectionDetailDTO struct {
+	BookmarkCollectionDTO
+	Items []BookmarkItemDTO `json:"items"`
+}
+
+// BookmarkItemDTO adalah portfolio di dalam koleksi beserta urutannya
+type BookmarkItemDTO struct {
+	Portfolio PortfolioListDTO `json:"portfolio"`
+	ItemOrder int              `json:"item_order"`
+	AddedAt   time.Time        `json:"added_at"`
+}
+
+type CreateBookmarkCollectionRequest struct {
+	Nama      string  `json:"nama"`
+	Deskripsi *string `json:"deskripsi,omitempty"`
+	IsPublic  bool    `json:"is_public"`
+}
+
+type UpdateBookmarkCollectionRequest struct {
+	Nama      *string `json:"nama,omitempty"`
+	Deskripsi *string `json:"deskripsi,omitempty"`
+	IsPublic  *bool   `json:"is_public,omitempty"`
+}
+
+type AddBookmarkItemRequest struct {
+	PortfolioID uuid.UUID `json:"portfolio_id"`
+}
+
+// ReorderBookmarkItemsRequest lists portfolio IDs in their new order
+type ReorderBookmarkItemsRequest struct {
+	PortfolioIDs []uuid.UUID `json:"portfolio_ids"`
+}
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+)
+
+// CollaboratorDTO adalah anggota tim portfolio selain owner
+type CollaboratorDTO struct {
+	ID          uuid.UUID         `json:"id"`
+	Role        string            `json:"role"`
+	Status      string            `json:"status"`
+	User        *PortfolioUserDTO `json:"user,omitempty"`
+	RespondedAt *time.Time        `json:"responded_at,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// InviteCollaboratorRequest untuk POST /portfolios/:id/collaborators
+type InviteCollaboratorRequest struct {
+	UserID uuid.UUID `json:"user_id"`
+	Role   string    `json:"role"`
+}
+
+// UpdateCollaboratorRequest untuk PATCH /portfolios/:id/collaborators/:collaboratorId
+type UpdateCollaboratorRequest struct {
+	Role string `json:"role"`
+}
+
+func CollaboratorToDTO(c domain.PortfolioCollaborator) CollaboratorDTO {
+	result := CollaboratorDTO{
+		ID:          c.ID,
+		Role:        string(c.Role),
+		Status:      string(c.Status),
+		RespondedAt: c.RespondedAt,
+		CreatedAt:   c.CreatedAt,
+	}
+	if c.User != nil {
+		var kelasNama *string
+		if c.User.Kelas != nil {
+			kelasNama = &c.User.Kelas.Nama
+		}
+		result.User = &PortfolioUserDTO{
+			ID:        c.User.ID,
+			Username:  c.User.Username,
+			Nama:      c.User.Nama,
+			AvatarURL: c.User.AvatarURL,
+			Role:      string(c.User.Role),
+			KelasNama: kelasNama,
+		}
+	}
+	return result
+}
+
+func CollaboratorsToDTO(collaborators []domain.PortfolioCollaborator) []CollaboratorDTO {
+	result := make([]CollaboratorDTO, 0, len(collaborators))
+	for _, c := range collaborators {
+		result = append(result, CollaboratorToDTO(c))
+	}
+	return result
+}
@@ -15,6 +15,7 @@ type CreateCommentRequest struct {
 type CommentResponse struct {
 	ID        uuid.UUID          `json:"id"`
 	Content   string             `json:"content"`
+	IsHidden  bool               `json:"is_hidden"`
 	CreatedAt time.Time          `json:"created_at"`
 	UpdatedAt time.Time          `json:"updated_at"`
 	User      UserBriefDTO       `json:"user"`
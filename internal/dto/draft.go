@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+)
+
+// PortfolioDraftDTO adalah working draft portfolio published yang belum menggantikan versi live
+type PortfolioDraftDTO struct {
+	Status          string            `json:"status"`
+	Judul           string            `json:"judul"`
+	ThumbnailURL    *string           `json:"thumbnail_url,omitempty"`
+	SeriesID        *uuid.UUID        `json:"series_id,omitempty"`
+	Tags            []TagDTO          `json:"tags"`
+	ContentBlocks   []ContentBlockDTO `json:"content_blocks"`
+	AdminReviewNote *string           `json:"admin_review_note,omitempty"`
+	ReviewedAt      *time.Time        `json:"reviewed_at,omitempty"`
+	SubmittedAt     *time.Time        `json:"submitted_at,omitempty"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+}
+
+// DraftStatusResponse untuk response submit/discard draft
+type DraftStatusResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Status      string    `json:"status"`
+	DraftStatus *string   `json:"draft_status"`
+}
+
+func DraftToDTO(d *domain.PortfolioDraft) *PortfolioDraftDTO {
+	result := &PortfolioDraftDTO{
+		Status:          string(d.Status),
+		Judul:           d.Content.Judul,
+		ThumbnailURL:    d.Content.ThumbnailURL,
+		SeriesID:        d.Content.SeriesID,
+		Tags:            make([]TagDTO, 0, len(d.Content.Tags)),
+		ContentBlocks:   make([]ContentBlockDTO, 0, len(d.Content.Blocks)),
+		AdminReviewNote: d.AdminReviewNote,
+		ReviewedAt:      d.ReviewedAt,
+		SubmittedAt:     d.SubmittedAt,
+		UpdatedAt:       d.UpdatedAt,
+	}
+	for _, t := range d.Content.Tags {
+		result.Tags = append(result.Tags, TagDTO{ID: t.ID, Nama: t.Nama})
+	}
+	for _, b := range d.Content.Blocks {
+		result.ContentBlocks = append(result.ContentBlocks, SnapshotBlockToDTO(b))
+	}
+	return result
+}
+
+func SnapshotBlockToDTO(b domain.SnapshotBlock) ContentBlockDTO {
+	return ContentBlockDTO{ID: b.ID, SeriesBlockID: b.SeriesBlockID, BlockType: string(b.BlockType), BlockOrder: b.BlockOrder, Payload: b.Payload}
+}
@@ -1,5 +1,11 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
 // StudentImportRow represents a single row from import file
 type StudentImportRow struct {
 	Row         int    `json:"row"`
@@ -8,9 +14,12 @@ type StudentImportRow struct {
 	Rombel      string `json:"rombel"`
 	Nama        string `json:"nama"`
 	NIS         string `json:"nis"`
+	NISN        string `json:"nisn,omitempty"`
+	Email       string `json:"email,omitempty"`
 }
 
-// StudentImportError represents an error for a specific row
+// StudentImportError represents an error for a specific row.
+// Entity imports leave NIS empty and put the row's label in Nama.
 type StudentImportError struct {
 	Row   int    `json:"row"`
 	NIS   string `json:"nis,omitempty"`
@@ -41,4 +50,76 @@ type StudentImportResponse struct {
 	CreatedStudents int                  `json:"created_students"`
 	Skipped         int                  `json:"skipped"`
 	Errors          []StudentImportError `json:"errors"`
+	CredentialSlips *CredentialSlipBatch `json:"credential_slips,omitempty"`
+}
+
+// StudentUpsertDryRunResponse is the response for an upsert-mode dry run
+type StudentUpsertDryRunResponse struct {
+	TotalRows            int                  `json:"total_rows"`
+	ClassesToCreate      []ClassToCreate      `json:"classes_to_create"`
+	StudentsToCreate     int                  `json:"students_to_create"`
+	StudentsToUpdate     int                  `json:"students_to_update"`
+	StudentsUnchanged    int                  `json:"students_unchanged"`
+	StudentsToDeactivate int64                `json:"students_to_deactivate"`
+	DeactivationBlocked  bool                 `json:"deactivation_blocked,omitempty"` // row errors would stop the real run from deactivating
+	ValidationErrors     []StudentImportError `json:"validation_errors"`
+}
+
+// StudentUpsertResponse is the response for an upsert-mode import
+type StudentUpsertResponse struct {
+	TotalRows           int                  `json:"total_rows"`
+	CreatedClasses      int                  `json:"created_classes"`
+	Created             int                  `json:"created"`
+	Updated             int                  `json:"updated"`
+	Unchanged           int                  `json:"unchanged"`
+	Deactivated         int64                `json:"deactivated"`
+	DeactivationBlocked bool                 `json:"deactivation_blocked,omitempty"` // nobody was deactivated because of row errors
+	Skipped             int                  `json:"skipped"`
+	Errors              []StudentImportError `json:"errors"`
+	CredentialSlips     *CredentialSlipBatch `json:"credential_slips,omitempty"`
+}
+
+// ImportJobResponse is the status of a background import job
+type ImportJobResponse struct {
+	ID            uuid.UUID            `json:"id"`
+	Mode          string               `json:"mode"`
+	Status        string               `json:"status"`
+	TotalRows     int                  `json:"total_rows"`
+	ProcessedRows int                  `json:"processed_rows"`
+	ErrorCount    int                  `json:"error_count"`
+	Errors        []StudentImportError `json:"errors"`
+	Result        interface{}          `json:"result,omitempty"`
+	Failure       string               `json:"failure,omitempty"`
+	CreatedAt     time.Time            `json:"created_at"`
+	StartedAt     *time.Time           `json:"started_at,omitempty"`
+	FinishedAt    *time.Time           `json:"finished_at,omitempty"`
+}
+
+// CredentialSlipBatch describes login slips generated by an import, downloadable once per kelas
+type CredentialSlipBatch struct {
+	ID        uuid.UUID `json:"id"`
+	Kelas     []string  `json:"kelas"`
+	Total     int       `json:"total"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// EntityImportRowResult is what an entity import did (or would do) with one row
+type EntityImportRowResult struct {
+	Row    int    `json:"row"`
+	Label  string `json:"label"`
+	Action string `json:"action"`
+}
+
+// EntityImportResponse is the report of a teacher, tag, jurusan or kelas import.
+// On a dry run the counters describe what would happen and nothing is written.
+type EntityImportResponse struct {
+	Entity          string                  `json:"entity"`
+	DryRun          bool                    `json:"dry_run"`
+	TotalRows       int                     `json:"total_rows"`
+	Created         int                     `json:"created"`
+	Updated         int                     `json:"updated"`
+	Unchanged       int                     `json:"unchanged"`
+	Rows            []EntityImportRowResult `json:"rows"`
+	Errors          []StudentImportError    `json:"errors"`
+	CredentialSlips *CredentialSlipBatch    `json:"credential_slips,omitempty"`
 }
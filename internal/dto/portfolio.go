@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
 )
 
 // Portfolio List Item
@@ -16,6 +17,7 @@ type PortfolioListDTO struct {
 	CreatedAt    time.Time           `json:"created_at"`
 	LikeCount    int64               `json:"like_count"`
 	IsLiked      bool                `json:"is_liked,omitempty"`
+	IsPinned     bool                `json:"is_pinned,omitempty"`
 	User         *PortfolioUserDTO   `json:"user,omitempty"`
 	Tags         []TagDTO            `json:"tags,omitempty"`
 	Series       *PortfolioSeriesDTO `json:"series,omitempty"`
@@ -23,8 +25,9 @@ type PortfolioListDTO struct {
 
 // PortfolioSeriesDTO - series info dalam portfolio
 type PortfolioSeriesDTO struct {
-	ID   uuid.UUID `json:"id"`
-	Nama string    `json:"nama"`
+	ID       uuid.UUID  `json:"id"`
+	Nama     string     `json:"nama"`
+	ClosesAt *time.Time `json:"closes_at,omitempty"`
 }
 
 type PortfolioUserDTO struct {
@@ -44,9 +47,13 @@ type PortfolioDetailDTO struct {
 	Slug            string              `json:"slug"`
 	ThumbnailURL    *string             `json:"thumbnail_url,omitempty"`
 	Status          string              `json:"status"`
+	Visibility      string              `json:"visibility"`
 	AdminReviewNote *string             `json:"admin_review_note,omitempty"`
 	ReviewedAt      *time.Time          `json:"reviewed_at,omitempty"`
 	PublishedAt     *time.Time          `json:"published_at,omitempty"`
+	PublishAt       *time.Time          `json:"publish_at,omitempty"`
+	SubmittedAt     *time.Time          `json:"submitted_at,omitempty"`
+	IsLate          bool                `json:"is_late,omitempty"`
 	CreatedAt       time.Time           `json:"created_at"`
 	UpdatedAt       time.Time           `json:"updated_at"`
 	LikeCount       int64               `json:"like_count"`
@@ -55,6 +62,18 @@ type PortfolioDetailDTO struct {
 	Tags            []TagDTO            `json:"tags,omitempty"`
 	Series          *PortfolioSeriesDTO `json:"series,omitempty"`
 	ContentBlocks   []ContentBlockDTO   `json:"content_blocks,omitempty"`
+	Draft           *PortfolioDraftDTO  `json:"draft,omitempty"`
+	Collaborators   []CollaboratorDTO   `json:"collaborators,omitempty"`
+	Rejection       *RejectionDTO       `json:"rejection,omitempty"`
+	ReviewChecklist []ChecklistItemDTO  `json:"review_checklist,omitempty"`
+	Redirect        *RedirectDTO        `json:"redirect,omitempty"`
+}
+
+// RedirectDTO - alamat terkini saat halaman dibuka lewat slug atau username lama;
+// klien sebaiknya mengganti URL secara permanen
+type RedirectDTO struct {
+	Username string `json:"username"`
+	Slug     string `json:"slug,omitempty"`
 }
 
 // My Portfolio List Item
@@ -64,20 +83,27 @@ type MyPortfolioDTO struct {
 	Slug            string     `json:"slug"`
 	ThumbnailURL    *string    `json:"thumbnail_url,omitempty"`
 	Status          string     `json:"status"`
+	Visibility      string     `json:"visibility"`
 	AdminReviewNote *string    `json:"admin_review_note,omitempty"`
 	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
 	PublishedAt     *time.Time `json:"published_at,omitempty"`
+	PublishAt       *time.Time `json:"publish_at,omitempty"`
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
 	LikeCount       int64      `json:"like_count"`
+	DraftStatus     *string    `json:"draft_status,omitempty"`
+	MyRole          string     `json:"my_role"`
+	IsPinned        bool       `json:"is_pinned"`
+	DisplayOrder    *int       `json:"display_order,omitempty"`
 }
 
 // Create/Update Portfolio
 type CreatePortfolioRequest struct {
-	Judul    string      `json:"judul" validate:"required"`
-	UserID   *uuid.UUID  `json:"user_id,omitempty"` // Admin can assign to another user
-	TagIDs   []uuid.UUID `json:"tag_ids,omitempty"`
-	SeriesID *uuid.UUID  `json:"series_id,omitempty"`
+	Judul      string      `json:"judul" validate:"required"`
+	UserID     *uuid.UUID  `json:"user_id,omitempty"` // Admin can assign to another user
+	TagIDs     []uuid.UUID `json:"tag_ids,omitempty"`
+	SeriesID   *uuid.UUID  `json:"series_id,omitempty"`
+	Visibility *string     `json:"visibility,omitempty"`
 }
 
 type UpdatePortfolioRequest struct {
@@ -85,16 +111,48 @@ type UpdatePortfolioRequest struct {
 	ThumbnailURL *string     `json:"thumbnail_url,omitempty"`
 	TagIDs       []uuid.UUID `json:"tag_ids,omitempty"`
 	SeriesID     *uuid.UUID  `json:"series_id,omitempty"`
+	// Visibility applies right away, also on published portfolios, without review
+	Visibility *string `json:"visibility,omitempty"`
+}
+
+// ReorderProfilePortfoliosRequest lists portfolio IDs in their display order on the profile
+type ReorderProfilePortfoliosRequest struct {
+	PortfolioIDs []uuid.UUID `json:"portfolio_ids"`
 }
 
 // Content Block
 type ContentBlockDTO struct {
-	ID         uuid.UUID              `json:"id"`
-	BlockType  string                 `json:"block_type"`
-	BlockOrder int                    `json:"block_order"`
-	Payload    map[string]interface{} `json:"payload"`
-	CreatedAt  time.Time              `json:"created_at,omitempty"`
-	UpdatedAt  time.Time              `json:"updated_at,omitempty"`
+	ID            uuid.UUID              `json:"id"`
+	SeriesBlockID *uuid.UUID             `json:"series_block_id,omitempty"`
+	BlockType     string                 `json:"block_type"`
+	BlockOrder    int                    `json:"block_order"`
+	Payload       map[string]interface{} `json:"payload"`
+	Embed         *EmbedDTO              `json:"embed,omitempty"`
+	CreatedAt     time.Time              `json:"created_at,omitempty"`
+	UpdatedAt     time.Time              `json:"updated_at,omitempty"`
+}
+
+// EmbedDTO is the cached check of the external URL of a block
+type EmbedDTO struct {
+	Status    string                 `json:"status"`
+	Meta      map[string]interface{} `json:"meta,omitempty"`
+	CheckedAt *time.Time             `json:"checked_at,omitempty"`
+}
+
+func ContentBlockToDTO(b domain.ContentBlock) ContentBlockDTO {
+	result := ContentBlockDTO{
+		ID:            b.ID,
+		SeriesBlockID: b.SeriesBlockID,
+		BlockType:     string(b.BlockType),
+		BlockOrder:    b.BlockOrder,
+		Payload:       b.Payload,
+		CreatedAt:     b.CreatedAt,
+		UpdatedAt:     b.UpdatedAt,
+	}
+	if b.EmbedStatus != nil {
+		result.Embed = &EmbedDTO{Status: string(*b.EmbedStatus), Meta: b.EmbedMeta, CheckedAt: b.EmbedCheckedAt}
+	}
+	return result
 }
 
 type CreateContentBlockRequest struct {
@@ -117,6 +175,11 @@ type BlockOrderItem struct {
 }
 
 // Portfolio Status Response
+// SubmitPortfolioRequest; publish_at optionally schedules the go-live time after approval
+type SubmitPortfolioRequest struct {
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+}
+
 type PortfolioStatusResponse struct {
 	ID     uuid.UUID `json:"id"`
 	Status string    `json:"status"`
@@ -0,0 +1,108 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+)
+
+// CreateRejectionReasonRequest - request untuk membuat template alasan penolakan
+type CreateRejectionReasonRequest struct {
+	Nama      string  `json:"nama"`
+	Deskripsi *string `json:"deskripsi,omitempty"`
+}
+
+// UpdateRejectionReasonRequest - request untuk mengubah template alasan penolakan
+type UpdateRejectionReasonRequest struct {
+	Nama      *string `json:"nama,omitempty"`
+	Deskripsi *string `json:"deskripsi,omitempty"`
+	IsActive  *bool   `json:"is_active,omitempty"`
+}
+
+// ReorderRejectionReasonsRequest - request untuk mengubah urutan alasan penolakan
+type ReorderRejectionReasonsRequest struct {
+	Orders []MetricOrder `json:"orders"`
+}
+
+// RejectionReasonDTO - template alasan penolakan
+type RejectionReasonDTO struct {
+	ID        uuid.UUID `json:"id"`
+	Nama      string    `json:"nama"`
+	Deskripsi *string   `json:"deskripsi,omitempty"`
+	Urutan    int       `json:"urutan"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RejectionBlockRequest - penunjuk block konten yang perlu diperbaiki saat menolak portfolio
+type RejectionBlockRequest struct {
+	BlockID uuid.UUID `json:"block_id"`
+	Note    string    `json:"note"`
+}
+
+// RejectionDTO - penolakan terakhir portfolio beserta alasan dan block yang perlu diperbaiki
+type RejectionDTO struct {
+	ID        uuid.UUID              `json:"id"`
+	IsDraft   bool                   `json:"is_draft"`
+	Reasons   []RejectionReasonBrief `json:"reasons"`
+	Note      *string                `json:"note,omitempty"`
+	Blocks    []RejectionBlockDTO    `json:"blocks"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// RejectionReasonBrief - alasan penolakan seperti yang ditampilkan ke siswa
+type RejectionReasonBrief struct {
+	ID        uuid.UUID `json:"id"`
+	Nama      string    `json:"nama"`
+	Deskripsi *string   `json:"deskripsi,omitempty"`
+}
+
+// RejectionBlockDTO - catatan moderator untuk satu block konten
+type RejectionBlockDTO struct {
+	BlockID uuid.UUID `json:"block_id"`
+	Note    string    `json:"note"`
+}
+
+// RejectionReasonStatDTO - jumlah penolakan per alasan untuk dashboard
+type RejectionReasonStatDTO struct {
+	ID        uuid.UUID `json:"id"`
+	Nama      string    `json:"nama"`
+	Total     int64     `json:"total"`
+	ThisMonth int64     `json:"this_month"`
+}
+
+// ChecklistItemDTO - poin checklist review suatu series
+type ChecklistItemDTO struct {
+	ID     uuid.UUID `json:"id"`
+	Label  string    `json:"label"`
+	Urutan int       `json:"urutan"`
+}
+
+// UpdateChecklistRequest - request untuk mengganti checklist review suatu series
+type UpdateChecklistRequest struct {
+	Items []string `json:"items"`
+}
+
+// RejectionToDTO converts a recorded rejection, nil stays nil
+func RejectionToDTO(r *domain.PortfolioRejection) *RejectionDTO {
+	if r == nil {
+		return nil
+	}
+	result := &RejectionDTO{
+		ID:        r.ID,
+		IsDraft:   r.IsDraft,
+		Reasons:   make([]RejectionReasonBrief, 0, len(r.Reasons)),
+		Note:      r.Note,
+		Blocks:    make([]RejectionBlockDTO, 0, len(r.Blocks)),
+		CreatedAt: r.CreatedAt,
+	}
+	for _, reason := range r.Reasons {
+		result.Reasons = append(result.Reasons, RejectionReasonBrief{ID: reason.ID, Nama: reason.Nama, Deskripsi: reason.Deskripsi})
+	}
+	for _, b := range r.Blocks {
+		result.Blocks = append(result.Blocks, RejectionBlockDTO{BlockID: b.BlockID, Note: b.Note})
+	}
+	return result
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateReportRequest - request untuk melaporkan portfolio, komentar, profil, atau pesan
+type CreateReportRequest struct {
+	TargetType string    `json:"target_type"`
+	TargetID   uuid.UUID `json:"target_id"`
+	Reason     string    `json:"reason"`
+	Details    *string   `json:"details,omitempty"`
+}
+
+// ResolveReportRequest - request moderator untuk menindaklanjuti laporan atas satu target
+type ResolveReportRequest struct {
+	Action string  `json:"action"`
+	Note   *string `json:"note,omitempty"`
+}
+
+// ReportTargetDTO - ringkasan konten yang dilaporkan untuk moderator
+type ReportTargetDTO struct {
+	Type      string    `json:"type"`
+	ID        uuid.UUID `json:"id"`
+	Available bool      `json:"available"`
+	Title     string    `json:"title,omitempty"`
+	Excerpt   *string   `json:"excerpt,omitempty"`
+	Slug      *string   `json:"slug,omitempty"`
+	Status    *string   `json:"status,omitempty"`
+}
+
+// ReportCaseDTO - satu target di antrean laporan beserta jumlah laporannya
+type ReportCaseDTO struct {
+	TargetType  string           `json:"target_type"`
+	TargetID    uuid.UUID        `json:"target_id"`
+	TargetUser  *UserBriefDTO    `json:"target_user,omitempty"`
+	ReportCount int64            `json:"report_count"`
+	Reasons     map[string]int64 `json:"reasons"`
+}
+
+// ReportDTO - satu laporan dari seorang pelapor
+type ReportDTO struct {
+	ID             uuid.UUID     `json:"id"`
+	Reporter       *UserBriefDTO `json:"reporter,omitempty"`
+	Reason         string        `json:"reason"`
+	Details        *string       `json:"details,omitempty"`
+	Status         string        `json:"status"`
+	Action         *string       `json:"action,omitempty"`
+	ResolutionNote *string       `json:"resolution_note,omitempty"`
+	ResolvedAt     *time.Time    `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+// ReportCaseDetailDTO - detail target yang dilaporkan beserta seluruh laporannya
+type ReportCaseDetailDTO struct {
+	Target     ReportTargetDTO `json:"target"`
+	TargetUser *UserBriefDTO   `json:"target_user,omitempty"`
+	Reports    []ReportDTO     `json:"reports"`
+}
@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+)
+
+// RevisionSummaryDTO untuk response list revisi
+type RevisionSummaryDTO struct {
+	RevisionNumber int           `json:"revision_number"`
+	Reason         string        `json:"reason"`
+	Judul          string        `json:"judul"`
+	Status         string        `json:"status"`
+	BlockCount     int           `json:"block_count"`
+	CreatedBy      *UserBriefDTO `json:"created_by,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+// RevisionDetailDTO untuk response detail revisi dengan snapshot lengkap
+type RevisionDetailDTO struct {
+	RevisionSummaryDTO
+	Snapshot domain.PortfolioSnapshot `json:"snapshot"`
+}
+
+// RevisionFieldChange adalah perubahan satu field metadata
+type RevisionFieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// RevisionBlockChange adalah perubahan satu content block (added, removed, modified, moved)
+type RevisionBlockChange struct {
+	BlockID     uuid.UUID              `json:"block_id"`
+	BlockType   string                 `json:"block_type"`
+	Change      string                 `json:"change"`
+	FromOrder   *int                   `json:"from_order,omitempty"`
+	ToOrder     *int                   `json:"to_order,omitempty"`
+	ChangedKeys []string               `json:"changed_keys,omitempty"`
+	FromPayload map[string]interface{} `json:"from_payload,omitempty"`
+	ToPayload   map[string]interface{} `json:"to_payload,omitempty"`
+}
+
+// RevisionDiffDTO adalah diff struktural antara dua revisi
+type RevisionDiffDTO struct {
+	From        RevisionSummaryDTO    `json:"from"`
+	To          RevisionSummaryDTO    `json:"to"`
+	Fields      []RevisionFieldChange `json:"fields"`
+	TagsAdded   []TagDTO              `json:"tags_added"`
+	TagsRemoved []TagDTO              `json:"tags_removed"`
+	Blocks      []RevisionBlockChange `json:"blocks"`
+}
+
+// RevisionToSummaryDTO converts a revision to its list representation
+func RevisionToSummaryDTO(r *domain.PortfolioRevision) RevisionSummaryDTO {
+	summary := RevisionSummaryDTO{
+		RevisionNumber: r.RevisionNumber,
+		Reason:         string(r.Reason),
+		Judul:          r.Snapshot.Judul,
+		Status:         string(r.Snapshot.Status),
+		BlockCount:     len(r.Snapshot.Blocks),
+		CreatedAt:      r.CreatedAt,
+	}
+	if r.Creator != nil {
+		summary.CreatedBy = &UserBriefDTO{
+			ID: r.Creator.ID, Username: r.Creator.Username, Nama: r.Creator.Nama,
+			Role: string(r.Creator.Role), AvatarURL: r.Creator.AvatarURL,
+		}
+	}
+	return summary
+}
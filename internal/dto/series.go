@@ -9,24 +9,36 @@ import (
 
 // SeriesDTO untuk response list
 type SeriesDTO struct {
-	ID             uuid.UUID `json:"id"`
-	Nama           string    `json:"nama"`
-	Deskripsi      *string   `json:"deskripsi,omitempty"`
-	IsActive       bool      `json:"is_active"`
-	BlockCount     int       `json:"block_count"`
-	PortfolioCount int64     `json:"portfolio_count"`
-	CreatedAt      time.Time `json:"created_at"`
+	ID             uuid.UUID  `json:"id"`
+	Nama           string     `json:"nama"`
+	Deskripsi      *string    `json:"deskripsi,omitempty"`
+	IsActive       bool       `json:"is_active"`
+	OpensAt        *time.Time `json:"opens_at,omitempty"`
+	ClosesAt       *time.Time `json:"closes_at,omitempty"`
+	BlockCount     int        `json:"block_count"`
+	PortfolioCount int64      `json:"portfolio_count"`
+	CreatedAt      time.Time  `json:"created_at"`
 }
 
 // SeriesDetailDTO untuk response detail dengan blocks
 type SeriesDetailDTO struct {
-	ID             uuid.UUID        `json:"id"`
-	Nama           string           `json:"nama"`
-	Deskripsi      *string          `json:"deskripsi,omitempty"`
-	IsActive       bool             `json:"is_active"`
-	Blocks         []SeriesBlockDTO `json:"blocks"`
-	PortfolioCount int64            `json:"portfolio_count"`
-	CreatedAt      time.Time        `json:"created_at"`
+	ID             uuid.UUID         `json:"id"`
+	Nama           string            `json:"nama"`
+	Deskripsi      *string           `json:"deskripsi,omitempty"`
+	IsActive       bool              `json:"is_active"`
+	OpensAt        *time.Time        `json:"opens_at,omitempty"`
+	ClosesAt       *time.Time        `json:"closes_at,omitempty"`
+	Targets        []SeriesTargetDTO `json:"targets"`
+	Blocks         []SeriesBlockDTO  `json:"blocks"`
+	PortfolioCount int64             `json:"portfolio_count"`
+	CreatedAt      time.Time         `json:"created_at"`
+}
+
+// SeriesTargetDTO - kelas atau jurusan yang ditugaskan mengerjakan series
+type SeriesTargetDTO struct {
+	ID      uuid.UUID   `json:"id"`
+	Kelas   *KelasDTO   `json:"kelas,omitempty"`
+	Jurusan *JurusanDTO `json:"jurusan,omitempty"`
 }
 
 // SeriesBlockDTO untuk response block template
@@ -35,6 +47,7 @@ type SeriesBlockDTO struct {
 	BlockType  string    `json:"block_type"`
 	BlockOrder int       `json:"block_order"`
 	Instruksi  string    `json:"instruksi"`
+	IsRequired bool      `json:"is_required"`
 }
 
 // SeriesBriefDTO untuk dropdown/select (minimal info)
@@ -42,30 +55,47 @@ type SeriesBriefDTO struct {
 	ID         uuid.UUID        `json:"id"`
 	Nama       string           `json:"nama"`
 	Deskripsi  *string          `json:"deskripsi,omitempty"`
+	OpensAt    *time.Time       `json:"opens_at,omitempty"`
+	ClosesAt   *time.Time       `json:"closes_at,omitempty"`
 	BlockCount int              `json:"block_count"`
 	Blocks     []SeriesBlockDTO `json:"blocks,omitempty"`
 }
 
 // CreateSeriesRequest untuk admin create
+// Target kelas/jurusan dan jadwal opsional; tanpa target series tidak menjadi tugas
 type CreateSeriesRequest struct {
-	Nama      string                     `json:"nama" validate:"required,max=100"`
-	Deskripsi *string                    `json:"deskripsi,omitempty"`
-	IsActive  *bool                      `json:"is_active,omitempty"`
-	Blocks    []CreateSeriesBlockRequest `json:"blocks" validate:"required,min=1"`
+	Nama             string                     `json:"nama" validate:"required,max=100"`
+	Deskripsi        *string                    `json:"deskripsi,omitempty"`
+	IsActive         *bool                      `json:"is_active,omitempty"`
+	OpensAt          *time.Time                 `json:"opens_at,omitempty"`
+	ClosesAt         *time.Time                 `json:"closes_at,omitempty"`
+	TargetKelasIDs   []uuid.UUID                `json:"target_kelas_ids,omitempty"`
+	TargetJurusanIDs []uuid.UUID                `json:"target_jurusan_ids,omitempty"`
+	Blocks           []CreateSeriesBlockRequest `json:"blocks" validate:"required,min=1"`
 }
 
 // CreateSeriesBlockRequest untuk block dalam create series
+// ID diisi saat update untuk mempertahankan block lama beserta tautannya ke portfolio;
+// IsRequired default true
 type CreateSeriesBlockRequest struct {
-	BlockType string `json:"block_type" validate:"required"`
-	Instruksi string `json:"instruksi" validate:"required"`
+	ID         *uuid.UUID `json:"id,omitempty"`
+	BlockType  string     `json:"block_type" validate:"required"`
+	Instruksi  string     `json:"instruksi" validate:"required"`
+	IsRequired *bool      `json:"is_required,omitempty"`
 }
 
 // UpdateSeriesRequest untuk admin update
+// Target diganti seluruhnya jika salah satu list target dikirim; ClearSchedule menghapus jadwal
 type UpdateSeriesRequest struct {
-	Nama      *string                    `json:"nama,omitempty" validate:"omitempty,max=100"`
-	Deskripsi *string                    `json:"deskripsi,omitempty"`
-	IsActive  *bool                      `json:"is_active,omitempty"`
-	Blocks    []CreateSeriesBlockRequest `json:"blocks,omitempty"`
+	Nama             *string                    `json:"nama,omitempty" validate:"omitempty,max=100"`
+	Deskripsi        *string                    `json:"deskripsi,omitempty"`
+	IsActive         *bool                      `json:"is_active,omitempty"`
+	OpensAt          *time.Time                 `json:"opens_at,omitempty"`
+	ClosesAt         *time.Time                 `json:"closes_at,omitempty"`
+	ClearSchedule    bool                       `json:"clear_schedule,omitempty"`
+	TargetKelasIDs   []uuid.UUID                `json:"target_kelas_ids,omitempty"`
+	TargetJurusanIDs []uuid.UUID                `json:"target_jurusan_ids,omitempty"`
+	Blocks           []CreateSeriesBlockRequest `json:"blocks,omitempty"`
 }
 
 // Helper function to convert domain.SeriesBlock to DTO
@@ -75,6 +105,7 @@ func SeriesBlockToDTO(block domain.SeriesBlock) SeriesBlockDTO {
 		BlockType:  string(block.BlockType),
 		BlockOrder: block.BlockOrder,
 		Instruksi:  block.Instruksi,
+		IsRequired: block.IsRequired,
 	}
 }
 
@@ -87,6 +118,61 @@ func SeriesBlocksToDTOs(blocks []domain.SeriesBlock) []SeriesBlockDTO {
 	return result
 }
 
+// SeriesTargetsToDTOs converts series targets with their kelas/jurusan preloaded
+func SeriesTargetsToDTOs(targets []domain.SeriesTarget) []SeriesTargetDTO {
+	result := make([]SeriesTargetDTO, 0, len(targets))
+	for _, t := range targets {
+		tDTO := SeriesTargetDTO{ID: t.ID}
+		if t.Kelas != nil {
+			tDTO.Kelas = &KelasDTO{ID: t.Kelas.ID, Nama: t.Kelas.Nama}
+		}
+		if t.Jurusan != nil {
+			tDTO.Jurusan = &JurusanDTO{ID: t.Jurusan.ID, Nama: t.Jurusan.Nama, Kode: t.Jurusan.Kode}
+		}
+		result = append(result, tDTO)
+	}
+	return result
+}
+
+// ============================================================================
+// ASSIGNMENT REPORT DTOs
+// ============================================================================
+
+// SeriesReportDTO - progres pengerjaan tugas series per siswa target
+type SeriesReportDTO struct {
+	SeriesID uuid.UUID              `json:"series_id"`
+	Nama     string                 `json:"nama"`
+	OpensAt  *time.Time             `json:"opens_at,omitempty"`
+	ClosesAt *time.Time             `json:"closes_at,omitempty"`
+	Summary  SeriesReportSummaryDTO `json:"summary"`
+	Students []AssignmentStudentDTO `json:"students"`
+}
+
+// SeriesReportSummaryDTO - jumlah siswa per status pengerjaan
+type SeriesReportSummaryDTO struct {
+	Total      int `json:"total"`
+	NotStarted int `json:"not_started"`
+	Draft      int `json:"draft"`
+	Pending    int `json:"pending"`
+	Published  int `json:"published"`
+	Late       int `json:"late"`
+}
+
+// AssignmentStudentDTO - status tugas satu siswa
+// Status: not_started, draft, pending, published; portfolio yang paling jauh progresnya yang dipakai
+type AssignmentStudentDTO struct {
+	ID             uuid.UUID  `json:"id"`
+	Username       string     `json:"username"`
+	Nama           string     `json:"nama"`
+	AvatarURL      *string    `json:"avatar_url,omitempty"`
+	KelasNama      *string    `json:"kelas_nama,omitempty"`
+	Status         string     `json:"status"`
+	PortfolioID    *uuid.UUID `json:"portfolio_id,omitempty"`
+	PortfolioJudul *string    `json:"portfolio_judul,omitempty"`
+	SubmittedAt    *time.Time `json:"submitted_at,omitempty"`
+	IsLate         bool       `json:"is_late"`
+}
+
 // ============================================================================
 // EXPORT DTOs
 // ============================================================================
@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+)
+
+// ShareLinkDTO adalah share link portfolio seperti yang dilihat author
+type ShareLinkDTO struct {
+	ID            uuid.UUID  `json:"id"`
+	Label         *string    `json:"label,omitempty"`
+	AllowComments bool       `json:"allow_comments"`
+	IsActive      bool       `json:"is_active"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	ViewCount     int64      `json:"view_count"`
+	LastViewedAt  *time.Time `json:"last_viewed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// CreatedShareLinkDTO membawa token share link, yang hanya ditampilkan sekali saat dibuat
+type CreatedShareLinkDTO struct {
+	ShareLinkDTO
+	Token string `json:"token"`
+}
+
+// CreateShareLinkRequest untuk POST /portfolios/:id/share-links
+type CreateShareLinkRequest struct {
+	Label         *string `json:"label,omitempty"`
+	AllowComments bool    `json:"allow_comments"`
+	ExpiresInDays int     `json:"expires_in_days"`
+}
+
+// SharedPortfolioDTO untuk GET /shared/:token
+type SharedPortfolioDTO struct {
+	Portfolio     PortfolioDetailDTO `json:"portfolio"`
+	Label         *string            `json:"label,omitempty"`
+	AllowComments bool               `json:"allow_comments"`
+	ExpiresAt     time.Time          `json:"expires_at"`
+}
+
+// ShareCommentDTO adalah komentar tamu lewat share link
+type ShareCommentDTO struct {
+	ID          uuid.UUID `json:"id"`
+	ShareLinkID uuid.UUID `json:"share_link_id"`
+	AuthorName  string    `json:"author_name"`
+	Content     string    `json:"content"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CreateShareCommentRequest untuk POST /shared/:token/comments
+type CreateShareCommentRequest struct {
+	AuthorName string `json:"author_name"`
+	Content    string `json:"content"`
+}
+
+func ShareLinkToDTO(l domain.PortfolioShareLink, now time.Time) ShareLinkDTO {
+	return ShareLinkDTO{
+		ID:            l.ID,
+		Label:         l.Label,
+		AllowComments: l.AllowComments,
+		IsActive:      l.IsActive(now),
+		ExpiresAt:     l.ExpiresAt,
+		RevokedAt:     l.RevokedAt,
+		ViewCount:     l.ViewCount,
+		LastViewedAt:  l.LastViewedAt,
+		CreatedAt:     l.CreatedAt,
+	}
+}
+
+func ShareCommentToDTO(c domain.ShareLinkComment) ShareCommentDTO {
+	return ShareCommentDTO{
+		ID:          c.ID,
+		ShareLinkID: c.ShareLinkID,
+		AuthorName:  c.AuthorName,
+		Content:     c.Content,
+		CreatedAt:   c.CreatedAt,
+	}
+}
+
+func ShareCommentsToDTO(comments []domain.ShareLinkComment) []ShareCommentDTO {
+	result := make([]ShareCommentDTO, 0, len(comments))
+	for _, c := range comments {
+		result = append(result, ShareCommentToDTO(c))
+	}
+	return result
+}
@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TeacherStudentDTO - siswa di kelas wali dengan ringkasan portfolio per status
+type TeacherStudentDTO struct {
+	ID              uuid.UUID        `json:"id"`
+	Username        string           `json:"username"`
+	Nama            string           `json:"nama"`
+	NIS             *string          `json:"nis,omitempty"`
+	NISN            *string          `json:"nisn,omitempty"`
+	AvatarURL       *string          `json:"avatar_url,omitempty"`
+	IsActive        bool             `json:"is_active"`
+	LastLoginAt     *time.Time       `json:"last_login_at,omitempty"`
+	PortfolioCounts map[string]int64 `json:"portfolio_counts"`
+}
+
+// ClassAssessmentSummaryDTO - ringkasan penilaian satu kelas
+type ClassAssessmentSummaryDTO struct {
+	Published    int64    `json:"published"`
+	Assessed     int64    `json:"assessed"`
+	Pending      int64    `json:"pending"`
+	AverageScore *float64 `json:"average_score,omitempty"`
+}
+
+// ClassAssessmentsResponse - hasil penilaian portfolio satu kelas
+type ClassAssessmentsResponse struct {
+	Summary    ClassAssessmentSummaryDTO `json:"summary"`
+	Portfolios []PortfolioForAssessment  `json:"portfolios"`
+}
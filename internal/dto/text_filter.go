@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateTextFilterRuleRequest - request untuk menambah kata terlarang atau pola regex
+type CreateTextFilterRuleRequest struct {
+	Pattern   string  `json:"pattern"`
+	MatchType string  `json:"match_type"`
+	Action    string  `json:"action"`
+	Bahasa    *string `json:"bahasa,omitempty"`
+}
+
+// UpdateTextFilterRuleRequest - request untuk mengubah aturan filter teks
+type UpdateTextFilterRuleRequest struct {
+	Pattern   *string `json:"pattern,omitempty"`
+	MatchType *string `json:"match_type,omitempty"`
+	Action    *string `json:"action,omitempty"`
+	Bahasa    *string `json:"bahasa,omitempty"`
+	IsActive  *bool   `json:"is_active,omitempty"`
+}
+
+// TestTextFilterRequest - request untuk mencoba filter pada contoh teks
+type TestTextFilterRequest struct {
+	Text string `json:"text"`
+}
+
+// TextFilterRuleDTO - aturan filter teks
+type TextFilterRuleDTO struct {
+	ID        uuid.UUID `json:"id"`
+	Pattern   string    `json:"pattern"`
+	MatchType string    `json:"match_type"`
+	Action    string    `json:"action"`
+	Bahasa    *string   `json:"bahasa,omitempty"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TextFilterTestDTO - hasil filter atas contoh teks
+type TextFilterTestDTO struct {
+	Text    string   `json:"text"`
+	Blocked bool     `json:"blocked"`
+	Flagged bool     `json:"flagged"`
+	Matches []string `json:"matches"`
+}
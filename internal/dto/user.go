@@ -43,6 +43,7 @@ type UserDetailDTO struct {
 	PortfolioCount int64                `json:"portfolio_count"`
 	IsFollowing    bool                 `json:"is_following"`
 	CreatedAt      time.Time            `json:"created_at"`
+	Redirect       *RedirectDTO         `json:"redirect,omitempty"`
 }
 
 // UserSpecialRoleDTO for public profile special roles
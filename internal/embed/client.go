@@ -0,0 +1,67 @@
+package embed
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// maxRedirects bounds the hops followed while looking a URL up
+const maxRedirects = 5
+
+// ErrUnreachable is returned for URLs that do not lead to a public address, including ones
+// that redirect there. Callers show it without detail so lookups cannot map internal hosts.
+var ErrUnreachable = errors.New("embed: url is not publicly reachable")
+
+// sharedAddressSpace is the carrier-grade NAT range, which the net.IP helpers treat as public
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// NewHTTPClient returns the client lookups must use. Student-supplied URLs are fetched from
+// the server, so every connection is checked after DNS resolution and refused unless it goes
+// to a public address; this also covers redirects and names that re-resolve between checks.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: refusePrivateAddress}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("embed: more than %d redirects: %w", maxRedirects, ErrUnreachable)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return ErrUnreachable
+			}
+			if addr, err := netip.ParseAddr(req.URL.Hostname()); err == nil && !isPublicAddr(addr) {
+				return ErrUnreachable
+			}
+			return nil
+		},
+	}
+}
+
+// refusePrivateAddress runs right before each connect, on the resolved address
+func refusePrivateAddress(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil || !isPublicAddr(addrPort.Addr()) {
+		return ErrUnreachable
+	}
+	return nil
+}
+
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() &&
+		!addr.IsPrivate() &&
+		!addr.IsLoopback() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsUnspecified() &&
+		!sharedAddressSpace.Contains(addr)
+}
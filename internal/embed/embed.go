@@ -0,0 +1,92 @@
+// Package embed resolves the external URLs pasted into content blocks: it rewrites them to
+// the canonical form of their provider, checks that the content is public and embeddable,
+// and fetches the metadata used to render a preview.
+package embed
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+var (
+	// ErrUnsupported is returned for URLs no provider handles, e.g. non-http schemes
+	ErrUnsupported = errors.New("embed: unsupported url")
+	// ErrNotFound is returned when the content no longer exists
+	ErrNotFound = errors.New("embed: content not found")
+	// ErrNotEmbeddable is returned when the content is private or refuses to be embedded
+	ErrNotEmbeddable = errors.New("embed: content is private or not embeddable")
+)
+
+// Metadata is what a provider knows about a piece of embedded content
+type Metadata struct {
+	Provider     string  `json:"provider"`
+	Title        string  `json:"title,omitempty"`
+	ThumbnailURL string  `json:"thumbnail_url,omitempty"`
+	AspectRatio  float64 `json:"aspect_ratio,omitempty"`
+}
+
+// Provider knows the URLs and the lookup API of one embed source
+type Provider interface {
+	Name() string
+	// Canonicalize returns the canonical form of u, or false when u does not belong to the provider
+	Canonicalize(u *url.URL) (string, bool)
+	// Fetch looks up a canonical URL. Dead content yields ErrNotFound and private content
+	// ErrNotEmbeddable; any other error is treated as temporary.
+	Fetch(ctx context.Context, canonicalURL string) (*Metadata, error)
+}
+
+// Result is a resolved URL
+type Result struct {
+	CanonicalURL string
+	Metadata     Metadata
+}
+
+// Resolver tries its providers in order; the first one that accepts a URL owns it
+type Resolver struct {
+	providers []Provider
+}
+
+func NewResolver(providers ...Provider) *Resolver {
+	return &Resolver{providers: providers}
+}
+
+// Canonicalize returns the canonical form of rawURL and the provider it belongs to
+func (r *Resolver) Canonicalize(rawURL string) (string, Provider, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", nil, ErrUnsupported
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+
+	for _, p := range r.providers {
+		if canonical, ok := p.Canonicalize(u); ok {
+			return canonical, p, nil
+		}
+	}
+	return "", nil, ErrUnsupported
+}
+
+// Resolve canonicalizes rawURL and fetches its metadata
+func (r *Resolver) Resolve(ctx context.Context, rawURL string) (*Result, error) {
+	canonical, provider, err := r.Canonicalize(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := provider.Fetch(ctx, canonical)
+	if err != nil {
+		return nil, err
+	}
+	if meta.Provider == "" {
+		meta.Provider = provider.Name()
+	}
+	return &Result{CanonicalURL: canonical, Metadata: *meta}, nil
+}
+
+// IsPermanent reports whether err says the content itself is unusable, as opposed to
+// the provider being unreachable for a moment
+func IsPermanent(err error) bool {
+	return errors.Is(err, ErrNotFound) || errors.Is(err, ErrNotEmbeddable) || errors.Is(err, ErrUnsupported) || errors.Is(err, ErrUnreachable)
+}
@@ -0,0 +1,100 @@
+package embed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalize(t *testing.T) {
+	r := NewResolver(DefaultProviders(http.DefaultClient)...)
+
+	cases := map[string]string{
+		"https://youtu.be/dQw4w9WgXcQ?si=abc":                                    "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		"https://m.youtube.com/watch?v=dQw4w9WgXcQ&t=42":                         "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		"https://www.youtube.com/shorts/dQw4w9WgXcQ":                             "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		"https://www.figma.com/design/AbC123/Poster-Final?node-id=1-2&t=x":       "https://www.figma.com/design/AbC123?node-id=1-2",
+		"https://www.canva.com/design/DAF1/tok3n/edit?utm_content=DAF1":          "https://www.canva.com/design/DAF1/tok3n/view",
+		"https://canva.com/design/DAF1/view":                                     "https://www.canva.com/design/DAF1/view",
+		"HTTPS://Docs.Example.com:443/slides/deck.pptx?utm_source=wa&page=2#top": "https://docs.example.com/slides/deck.pptx?page=2",
+		"http://[2001:db8::1]:80/deck.pdf":                                       "http://[2001:db8::1]/deck.pdf",
+		"https://[2001:db8::1]:8443/deck.pdf":                                    "https://[2001:db8::1]:8443/deck.pdf",
+	}
+	for raw, want := range cases {
+		got, _, err := r.Canonicalize(raw)
+		require.NoError(t, err, raw)
+		assert.Equal(t, want, got, raw)
+	}
+
+	_, _, err := r.Canonicalize("javascript:alert(1)")
+	assert.ErrorIs(t, err, ErrUnsupported)
+
+	// A YouTube URL without a usable video ID falls through to the link provider
+	_, provider, err := r.Canonicalize("https://www.youtube.com/@grafikarsa")
+	require.NoError(t, err)
+	assert.Equal(t, "link", provider.Name())
+}
+
+func TestResolve_FakeProvider(t *testing.T) {
+	fake := NewFakeProvider()
+	r := NewResolver(fake)
+	fake.Set("https://www.youtube.com/watch?v=dQw4w9WgXcQ", Metadata{Title: "Karya Akhir", AspectRatio: 16.0 / 9.0})
+	fake.Fail("https://www.canva.com/design/DAF1/view", ErrNotEmbeddable)
+	fake.Fail("https://figma.com/file/slow", errors.New("timeout"))
+
+	res, err := r.Resolve(context.Background(), "https://youtu.be/dQw4w9WgXcQ")
+	require.NoError(t, err)
+	assert.Equal(t, "https://www.youtube.com/watch?v=dQw4w9WgXcQ", res.CanonicalURL)
+	assert.Equal(t, "fake", res.Metadata.Provider)
+	assert.Equal(t, "Karya Akhir", res.Metadata.Title)
+
+	_, err = r.Resolve(context.Background(), "https://www.canva.com/design/DAF1/view")
+	assert.True(t, IsPermanent(err))
+
+	_, err = r.Resolve(context.Background(), "https://figma.com/file/slow")
+	assert.False(t, IsPermanent(err))
+
+	_, err = r.Resolve(context.Background(), "https://example.com/hilang")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, 1, fake.Calls("https://example.com/hilang"))
+}
+
+// Lookups run on the server, so they must never reach loopback, private or metadata addresses
+func TestHTTPClient_RefusesInternalAddresses(t *testing.T) {
+	hits := 0
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer internal.Close()
+
+	r := NewResolver(DefaultProviders(NewHTTPClient(2 * time.Second))...)
+	for _, target := range []string{
+		internal.URL + "/slides.pdf",
+		"http://127.0.0.1/admin",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.1/",
+		"http://[::1]/",
+	} {
+		_, err := r.Resolve(context.Background(), target)
+		assert.ErrorIs(t, err, ErrUnreachable, target)
+		assert.True(t, IsPermanent(err), target)
+	}
+	assert.Zero(t, hits)
+
+	// A public page redirecting inward is stopped at the hop
+	redirector := &http.Request{URL: mustParseURL(t, "http://127.0.0.1/")}
+	assert.ErrorIs(t, NewHTTPClient(time.Second).CheckRedirect(redirector, []*http.Request{{}}), ErrUnreachable)
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	return u
+}
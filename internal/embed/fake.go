@@ -0,0 +1,72 @@
+package embed
+
+import (
+	"context"
+	"net/url"
+	"sync"
+)
+
+// FakeProvider answers from an in-memory table instead of the network. It accepts every
+// http(s) URL, so it is meant to be the only provider of a Resolver in tests and local setups.
+type FakeProvider struct {
+	mu      sync.Mutex
+	results map[string]Metadata
+	errors  map[string]error
+	calls   map[string]int
+}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{
+		results: make(map[string]Metadata),
+		errors:  make(map[string]error),
+		calls:   make(map[string]int),
+	}
+}
+
+// Set makes canonicalURL resolve to meta
+func (f *FakeProvider) Set(canonicalURL string, meta Metadata) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.errors, canonicalURL)
+	f.results[canonicalURL] = meta
+}
+
+// Fail makes lookups of canonicalURL return err
+func (f *FakeProvider) Fail(canonicalURL string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.results, canonicalURL)
+	f.errors[canonicalURL] = err
+}
+
+// Calls returns how many times canonicalURL was fetched
+func (f *FakeProvider) Calls(canonicalURL string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[canonicalURL]
+}
+
+func (f *FakeProvider) Name() string { return "fake" }
+
+func (f *FakeProvider) Canonicalize(u *url.URL) (string, bool) {
+	if canonical, ok := canonicalYouTube(u); ok {
+		return canonical, true
+	}
+	return canonicalLink(u), true
+}
+
+// Fetch returns the configured result; unknown URLs are treated as dead
+func (f *FakeProvider) Fetch(_ context.Context, canonicalURL string) (*Metadata, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[canonicalURL]++
+
+	if err, ok := f.errors[canonicalURL]; ok {
+		return nil, err
+	}
+	meta, ok := f.results[canonicalURL]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &meta, nil
+}
@@ -0,0 +1,234 @@
+package embed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var youtubeIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+// trackingParams are dropped from every canonical URL
+var trackingParams = []string{"fbclid", "gclid", "si", "feature"}
+
+// DefaultProviders returns the real providers, most specific first, with the generic link
+// provider last so any reachable http(s) URL is still accepted
+func DefaultProviders(client *http.Client) []Provider {
+	return []Provider{
+		&oEmbedProvider{name: "youtube", endpoint: "https://www.youtube.com/oembed", client: client, canonicalize: canonicalYouTube},
+		&oEmbedProvider{name: "figma", endpoint: "https://www.figma.com/api/oembed", client: client, canonicalize: canonicalFigma},
+		&oEmbedProvider{name: "canva", endpoint: "https://www.canva.com/_oembed", client: client, canonicalize: canonicalCanva},
+		&linkProvider{client: client},
+	}
+}
+
+// YouTubeURL is the canonical watch URL of a video ID
+func YouTubeURL(videoID string) string {
+	return "https://www.youtube.com/watch?v=" + videoID
+}
+
+// oEmbedProvider looks content up through a provider's oEmbed endpoint
+type oEmbedProvider struct {
+	name         string
+	endpoint     string
+	client       *http.Client
+	canonicalize func(u *url.URL) (string, bool)
+}
+
+func (p *oEmbedProvider) Name() string { return p.name }
+
+func (p *oEmbedProvider) Canonicalize(u *url.URL) (string, bool) { return p.canonicalize(u) }
+
+func (p *oEmbedProvider) Fetch(ctx context.Context, canonicalURL string) (*Metadata, error) {
+	query := url.Values{"url": {canonicalURL}, "format": {"json"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := statusError(resp.StatusCode); err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		Title        string  `json:"title"`
+		ThumbnailURL string  `json:"thumbnail_url"`
+		Width        float64 `json:"width"`
+		Height       float64 `json:"height"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("embed: decode %s oembed: %w", p.name, err)
+	}
+
+	meta := &Metadata{Provider: p.name, Title: body.Title, ThumbnailURL: body.ThumbnailURL}
+	if body.Width > 0 && body.Height > 0 {
+		meta.AspectRatio = body.Width / body.Height
+	}
+	return meta, nil
+}
+
+// linkProvider accepts any http(s) URL and only checks that it can still be reached. Its
+// client must come from NewHTTPClient, which keeps the probe off internal addresses.
+type linkProvider struct {
+	client *http.Client
+}
+
+func (p *linkProvider) Name() string { return "link" }
+
+func (p *linkProvider) Canonicalize(u *url.URL) (string, bool) { return canonicalLink(u), true }
+
+func (p *linkProvider) Fetch(ctx context.Context, canonicalURL string) (*Metadata, error) {
+	status, err := p.probe(ctx, http.MethodHead, canonicalURL)
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented) {
+		status, err = p.probe(ctx, http.MethodGet, canonicalURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The URL is arbitrary, so what the host answered is not passed on: a missing or
+	// refused resource is just unreachable
+	if err := statusError(status); err != nil {
+		if IsPermanent(err) {
+			return nil, ErrUnreachable
+		}
+		return nil, err
+	}
+
+	meta := &Metadata{Provider: p.Name()}
+	if u, err := url.Parse(canonicalURL); err == nil {
+		if base := path.Base(u.Path); base != "/" && base != "." {
+			meta.Title = base
+		}
+	}
+	return meta, nil
+}
+
+func (p *linkProvider) probe(ctx context.Context, method, target string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// statusError maps the HTTP status of a lookup to the package errors
+func statusError(status int) error {
+	switch {
+	case status == http.StatusNotFound || status == http.StatusGone:
+		return ErrNotFound
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrNotEmbeddable
+	case status >= 400:
+		return fmt.Errorf("embed: unexpected status %d", status)
+	}
+	return nil
+}
+
+func canonicalYouTube(u *url.URL) (string, bool) {
+	host := strings.TrimPrefix(strings.TrimPrefix(u.Hostname(), "www."), "m.")
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	var id string
+	switch host {
+	case "youtu.be":
+		id = segments[0]
+	case "youtube.com", "music.youtube.com", "youtube-nocookie.com":
+		switch segments[0] {
+		case "watch":
+			id = u.Query().Get("v")
+		case "embed", "shorts", "live", "v":
+			if len(segments) > 1 {
+				id = segments[1]
+			}
+		}
+	default:
+		return "", false
+	}
+
+	if !youtubeIDPattern.MatchString(id) {
+		return "", false
+	}
+	return YouTubeURL(id), true
+}
+
+func canonicalFigma(u *url.URL) (string, bool) {
+	host := u.Hostname()
+	if host != "figma.com" && !strings.HasSuffix(host, ".figma.com") {
+		return "", false
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 || segments[1] == "" {
+		return "", false
+	}
+	switch segments[0] {
+	case "file", "design", "proto", "board", "slides":
+	default:
+		return "", false
+	}
+
+	canonical := "https://www.figma.com/" + segments[0] + "/" + segments[1]
+	if node := u.Query().Get("node-id"); node != "" {
+		canonical += "?" + url.Values{"node-id": {node}}.Encode()
+	}
+	return canonical, true
+}
+
+func canonicalCanva(u *url.URL) (string, bool) {
+	host := u.Hostname()
+	if host != "canva.com" && host != "www.canva.com" {
+		return "", false
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 || segments[0] != "design" || segments[1] == "" {
+		return "", false
+	}
+
+	// Edit links only open for collaborators, so every design is embedded through its view link
+	parts := []string{"design", segments[1]}
+	if len(segments) > 2 {
+		switch segments[2] {
+		case "view", "edit", "watch":
+		default:
+			parts = append(parts, segments[2])
+		}
+	}
+	return "https://www.canva.com/" + strings.Join(parts, "/") + "/view", true
+}
+
+// canonicalLink drops the fragment, default port and tracking parameters of u
+func canonicalLink(u *url.URL) string {
+	c := *u
+	c.Fragment = ""
+	c.RawFragment = ""
+	if (c.Scheme == "http" && c.Port() == "80") || (c.Scheme == "https" && c.Port() == "443") {
+		// Cut the port off rather than using Hostname, which loses the brackets of IPv6 hosts
+		c.Host = strings.TrimSuffix(c.Host, ":"+c.Port())
+	}
+
+	query := c.Query()
+	for key := range query {
+		if strings.HasPrefix(key, "utm_") {
+			query.Del(key)
+		}
+	}
+	for _, key := range trackingParams {
+		query.Del(key)
+	}
+	c.RawQuery = query.Encode()
+	return c.String()
+}
@@ -0,0 +1,72 @@
+package embed
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ObjectChecker reports whether an object exists in our own storage bucket
+type ObjectChecker interface {
+	ObjectExists(objectKey string) (bool, error)
+}
+
+// storageProvider owns the files students upload to our storage. Their public URL may sit
+// on an internal address, so they are checked in the bucket instead of over HTTP.
+type storageProvider struct {
+	base    *url.URL
+	objects ObjectChecker
+}
+
+// NewStorageProvider returns the provider for URLs under publicURL, the public base URL of
+// the storage bucket. It must come before the generic link provider.
+func NewStorageProvider(publicURL string, objects ObjectChecker) Provider {
+	base, err := url.Parse(strings.TrimSuffix(strings.TrimSpace(publicURL), "/"))
+	if err != nil || base.Host == "" {
+		return &storageProvider{objects: objects}
+	}
+	base.Scheme = strings.ToLower(base.Scheme)
+	base.Host = strings.ToLower(base.Host)
+	return &storageProvider{base: base, objects: objects}
+}
+
+func (p *storageProvider) Name() string { return "storage" }
+
+func (p *storageProvider) Canonicalize(u *url.URL) (string, bool) {
+	if _, ok := p.objectKey(u); !ok {
+		return "", false
+	}
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}).String(), true
+}
+
+func (p *storageProvider) Fetch(ctx context.Context, canonicalURL string) (*Metadata, error) {
+	u, err := url.Parse(canonicalURL)
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+	key, ok := p.objectKey(u)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	exists, err := p.objects.ObjectExists(key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+	return &Metadata{Provider: p.Name(), Title: path.Base(key)}, nil
+}
+
+// objectKey returns the bucket key of u when u points into the bucket
+func (p *storageProvider) objectKey(u *url.URL) (string, bool) {
+	if p.base == nil || u.Scheme != p.base.Scheme || u.Host != p.base.Host {
+		return "", false
+	}
+	key, ok := strings.CutPrefix(u.Path, p.base.Path+"/")
+	if !ok || key == "" || strings.Contains("/"+key+"/", "/../") {
+		return "", false
+	}
+	return key, true
+}
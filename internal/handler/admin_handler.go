@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"errors"
+	"log"
 	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -15,18 +19,34 @@ import (
 )
 
 type AdminHandler struct {
-	adminRepo     *repository.AdminRepository
-	userRepo      *repository.UserRepository
-	portfolioRepo *repository.PortfolioRepository
-	notifService  *service.NotificationService
+	adminRepo         *repository.AdminRepository
+	userRepo          *repository.UserRepository
+	portfolioRepo     *repository.PortfolioRepository
+	notifService      *service.NotificationService
+	revisionService   *service.RevisionService
+	draftService      *service.DraftService
+	assignmentRepo    *repository.SeriesAssignmentRepository
+	assignmentService *service.SeriesAssignmentService
+	reviewRepo        *repository.ReviewRepository
+	reviewClaims      *service.ReviewClaimService
+	rejectionRepo     *repository.RejectionRepository
+	rejections        *service.RejectionService
 }
 
-func NewAdminHandler(adminRepo *repository.AdminRepository, userRepo *repository.UserRepository, portfolioRepo *repository.PortfolioRepository, notifService *service.NotificationService) *AdminHandler {
+func NewAdminHandler(adminRepo *repository.AdminRepository, userRepo *repository.UserRepository, portfolioRepo *repository.PortfolioRepository, notifService *service.NotificationService, revisionService *service.RevisionService, draftService *service.DraftService, assignmentRepo *repository.SeriesAssignmentRepository, assignmentService *service.SeriesAssignmentService, reviewRepo *repository.ReviewRepository, reviewClaims *service.ReviewClaimService, rejectionRepo *repository.RejectionRepository, rejections *service.RejectionService) *AdminHandler {
 	return &AdminHandler{
-		adminRepo:     adminRepo,
-		userRepo:      userRepo,
-		portfolioRepo: portfolioRepo,
-		notifService:  notifService,
+		adminRepo:         adminRepo,
+		userRepo:          userRepo,
+		portfolioRepo:     portfolioRepo,
+		notifService:      notifService,
+		revisionService:   revisionService,
+		draftService:      draftService,
+		assignmentRepo:    assignmentRepo,
+		assignmentService: assignmentService,
+		reviewRepo:        reviewRepo,
+		reviewClaims:      reviewClaims,
+		rejectionRepo:     rejectionRepo,
+		rejections:        rejections,
 	}
 }
 
@@ -288,6 +308,7 @@ func (h *AdminHandler) ListKelas(c *fiber.Ctx) error {
 		if k.Jurusan != nil {
 			kDTO.Jurusan = &dto.JurusanDTO{ID: k.Jurusan.ID, Nama: k.Jurusan.Nama, Kode: k.Jurusan.Kode}
 		}
+		kDTO.WaliKelas = toWaliKelasDTO(k.WaliKelas)
 		kDTO.StudentCount, _ = h.adminRepo.GetKelasStudentCount(k.ID)
 		result = append(result, kDTO)
 	}
@@ -326,12 +347,18 @@ func (h *AdminHandler) CreateKelas(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Jurusan tidak ditemukan"))
 	}
 
+	if req.WaliKelasID != nil && !h.isTeacher(*req.WaliKelasID) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal",
+			dto.ErrorDetail{Field: "wali_kelas_id", Message: "Wali kelas harus user dengan role teacher"},
+		))
+	}
+
 	tingkatRomawi := map[int]string{10: "X", 11: "XI", 12: "XII"}
 	nama := tingkatRomawi[req.Tingkat] + "-" + jurusan.Kode + "-" + req.Rombel
 
 	kelas := &domain.Kelas{
 		TahunAjaranID: req.TahunAjaranID, JurusanID: req.JurusanID,
-		Tingkat: req.Tingkat, Rombel: req.Rombel, Nama: nama,
+		Tingkat: req.Tingkat, Rombel: req.Rombel, Nama: nama, WaliKelasID: req.WaliKelasID,
 	}
 
 	if err := h.adminRepo.CreateKelas(kelas); err != nil {
@@ -346,6 +373,7 @@ func (h *AdminHandler) CreateKelas(c *fiber.Ctx) error {
 	if kelas.Jurusan != nil {
 		result.Jurusan = &dto.JurusanDTO{ID: kelas.Jurusan.ID, Nama: kelas.Jurusan.Nama, Kode: kelas.Jurusan.Kode}
 	}
+	result.WaliKelas = toWaliKelasDTO(kelas.WaliKelas)
 
 	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse(result, "Kelas berhasil dibuat"))
 }
@@ -376,15 +404,42 @@ func (h *AdminHandler) UpdateKelas(c *fiber.Ctx) error {
 		kelas.Nama = tingkatRomawi[kelas.Tingkat] + "-" + kelas.Jurusan.Kode + "-" + kelas.Rombel
 	}
 
+	if req.RemoveWaliKelas {
+		kelas.WaliKelasID = nil
+	} else if req.WaliKelasID != nil {
+		if !h.isTeacher(*req.WaliKelasID) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal",
+				dto.ErrorDetail{Field: "wali_kelas_id", Message: "Wali kelas harus user dengan role teacher"},
+			))
+		}
+		kelas.WaliKelasID = req.WaliKelasID
+	}
+	// Drop the preloaded association so Save doesn't write the old wali kelas back
+	kelas.WaliKelas = nil
+
 	if err := h.adminRepo.UpdateKelas(kelas); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal memperbarui kelas"))
 	}
 
 	return c.JSON(dto.SuccessResponse(map[string]interface{}{
-		"id": kelas.ID, "nama": kelas.Nama, "tingkat": kelas.Tingkat, "rombel": kelas.Rombel, "updated_at": kelas.UpdatedAt,
+		"id": kelas.ID, "nama": kelas.Nama, "tingkat": kelas.Tingkat, "rombel": kelas.Rombel,
+		"wali_kelas_id": kelas.WaliKelasID, "updated_at": kelas.UpdatedAt,
 	}, "Kelas berhasil diperbarui"))
 }
 
+// isTeacher reports whether userID is an active user with the teacher role
+func (h *AdminHandler) isTeacher(userID uuid.UUID) bool {
+	user, err := h.userRepo.FindByID(userID)
+	return err == nil && user.Role == domain.RoleTeacher && user.IsActive
+}
+
+func toWaliKelasDTO(u *domain.User) *dto.UserBriefDTO {
+	if u == nil {
+		return nil
+	}
+	return &dto.UserBriefDTO{ID: u.ID, Username: u.Username, Nama: u.Nama, Role: string(u.Role), AvatarURL: u.AvatarURL}
+}
+
 func (h *AdminHandler) DeleteKelas(c *fiber.Ctx) error {
 	id, err := uuid.Parse(c.Params("id"))
 	if err != nil {
@@ -425,6 +480,180 @@ func (h *AdminHandler) GetKelasStudents(c *fiber.Ctx) error {
 	return c.JSON(dto.SuccessWithMeta(result, &dto.Meta{TotalCount: total}))
 }
 
+// TransferStudents moves one or more students into another kelas as of an effective date
+func (h *AdminHandler) TransferStudents(c *fiber.Ctx) error {
+	var req dto.TransferStudentsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Request body tidak valid"))
+	}
+
+	if len(req.UserIDs) == 0 {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal",
+			dto.ErrorDetail{Field: "user_ids", Message: "Pilih minimal satu siswa"},
+		))
+	}
+
+	effectiveDate, err := time.Parse(dateLayout, req.EffectiveDate)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal",
+			dto.ErrorDetail{Field: "effective_date", Message: "Format tanggal harus YYYY-MM-DD"},
+		))
+	}
+	if effectiveDate.After(time.Now()) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal",
+			dto.ErrorDetail{Field: "effective_date", Message: "Tanggal efektif tidak boleh di masa depan"},
+		))
+	}
+
+	kelas, err := h.adminRepo.FindKelasByID(req.KelasID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("NOT_FOUND", "Kelas tidak ditemukan"))
+	}
+
+	// The effective date has to fall inside the target kelas' academic year
+	if ta := kelas.TahunAjaran; ta != nil {
+		start := time.Date(ta.TahunMulai, time.Month(ta.PromotionMonth), ta.PromotionDay, 0, 0, 0, 0, time.UTC)
+		if effectiveDate.Before(start) || !effectiveDate.Before(start.AddDate(1, 0, 0)) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal",
+				dto.ErrorDetail{Field: "effective_date", Message: "Tanggal efektif harus berada dalam tahun ajaran kelas tujuan"},
+			))
+		}
+	}
+
+	users, err := h.adminRepo.FindUsersByIDs(req.UserIDs)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil data siswa"))
+	}
+	usersByID := make(map[uuid.UUID]domain.User, len(users))
+	for _, u := range users {
+		usersByID[u.ID] = u
+	}
+
+	openHistory, err := h.adminRepo.FindOpenClassHistory(req.UserIDs)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil riwayat kelas"))
+	}
+
+	var reason *string
+	if req.Reason != nil && strings.TrimSpace(*req.Reason) != "" {
+		trimmed := strings.TrimSpace(*req.Reason)
+		reason = &trimmed
+	}
+	movedBy := middleware.GetUserID(c)
+
+	var moves []repository.ClassMove
+	skipped := []dto.TransferSkippedDTO{}
+	seen := make(map[uuid.UUID]bool, len(req.UserIDs))
+	for _, userID := range req.UserIDs {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		user, ok := usersByID[userID]
+		switch {
+		case !ok:
+			skipped = append(skipped, dto.TransferSkippedDTO{UserID: userID, Reason: "Siswa tidak ditemukan"})
+			continue
+		case user.Role != domain.RoleStudent:
+			skipped = append(skipped, dto.TransferSkippedDTO{UserID: userID, Reason: "User bukan siswa"})
+			continue
+		case user.KelasID != nil && *user.KelasID == kelas.ID:
+			skipped = append(skipped, dto.TransferSkippedDTO{UserID: userID, Reason: "Siswa sudah berada di kelas tujuan"})
+			continue
+		}
+		if current, ok := openHistory[userID]; ok && effectiveDate.Before(current.EffectiveDate) {
+			skipped = append(skipped, dto.TransferSkippedDTO{UserID: userID, Reason: "Tanggal efektif sebelum siswa masuk kelas saat ini"})
+			continue
+		}
+
+		moves = append(moves, repository.ClassMove{
+			UserID:        userID,
+			KelasID:       kelas.ID,
+			TahunAjaranID: kelas.TahunAjaranID,
+			EffectiveDate: effectiveDate,
+			Reason:        reason,
+			MovedBy:       movedBy,
+		})
+	}
+
+	if len(moves) > 0 {
+		if err := h.adminRepo.TransferStudents(moves); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal memindahkan siswa"))
+		}
+	}
+
+	return c.JSON(dto.SuccessResponse(dto.TransferStudentsResponse{
+		Kelas:         dto.KelasDTO{ID: kelas.ID, Nama: kelas.Nama},
+		EffectiveDate: effectiveDate.Format(dateLayout),
+		Moved:         len(moves),
+		Skipped:       skipped,
+	}, "Siswa berhasil dipindahkan"))
+}
+
+// GetKelasRoster lists who was in a kelas, optionally on a given date
+func (h *AdminHandler) GetKelasRoster(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "ID tidak valid"))
+	}
+
+	var at *time.Time
+	if date := c.Query("date"); date != "" {
+		parsed, err := time.Parse(dateLayout, date)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Format tanggal harus YYYY-MM-DD"))
+		}
+		at = &parsed
+	}
+
+	kelas, err := h.adminRepo.FindKelasByID(id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("NOT_FOUND", "Kelas tidak ditemukan"))
+	}
+
+	rows, err := h.adminRepo.GetKelasRoster(id, at)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil data siswa"))
+	}
+
+	result := dto.KelasRosterResponse{
+		Kelas:    dto.KelasDTO{ID: kelas.ID, Nama: kelas.Nama},
+		Students: make([]dto.KelasRosterEntryDTO, 0, len(rows)),
+	}
+	if at != nil {
+		date := at.Format(dateLayout)
+		result.Date = &date
+	}
+	for _, row := range rows {
+		entry := dto.KelasRosterEntryDTO{
+			IsCurrent:     row.IsCurrent,
+			EffectiveDate: row.EffectiveDate.Format(dateLayout),
+			EndDate:       formatOptionalDate(row.EndDate),
+			Reason:        row.Reason,
+		}
+		if u := row.User; u != nil {
+			entry.Student = dto.UserBriefDTO{ID: u.ID, Username: u.Username, Nama: u.Nama, Role: string(u.Role), AvatarURL: u.AvatarURL}
+			entry.NIS = u.NIS
+			entry.NISN = u.NISN
+		}
+		result.Students = append(result.Students, entry)
+	}
+
+	return c.JSON(dto.SuccessResponse(result, ""))
+}
+
+// dateLayout is the wire format for DATE columns
+const dateLayout = "2006-01-02"
+
+func formatOptionalDate(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format(dateLayout)
+	return &s
+}
+
 // Tags Handlers
 func (h *AdminHandler) ListTags(c *fiber.Ctx) error {
 	search := c.Query("search")
@@ -530,6 +759,8 @@ func (h *AdminHandler) ListSeries(c *fiber.Ctx) error {
 			Nama:           s.Nama,
 			Deskripsi:      s.Deskripsi,
 			IsActive:       s.IsActive,
+			OpensAt:        s.OpensAt,
+			ClosesAt:       s.ClosesAt,
 			BlockCount:     len(s.Blocks),
 			PortfolioCount: portfolioCount,
 			CreatedAt:      s.CreatedAt,
@@ -562,15 +793,7 @@ func (h *AdminHandler) GetSeries(c *fiber.Ctx) error {
 
 	portfolioCount, _ := h.adminRepo.GetSeriesPortfolioCount(series.ID)
 
-	return c.JSON(dto.SuccessResponse(dto.SeriesDetailDTO{
-		ID:             series.ID,
-		Nama:           series.Nama,
-		Deskripsi:      series.Deskripsi,
-		IsActive:       series.IsActive,
-		Blocks:         dto.SeriesBlocksToDTOs(series.Blocks),
-		PortfolioCount: portfolioCount,
-		CreatedAt:      series.CreatedAt,
-	}, ""))
+	return c.JSON(dto.SuccessResponse(h.toSeriesDetailDTO(series, portfolioCount), ""))
 }
 
 func (h *AdminHandler) CreateSeries(c *fiber.Ctx) error {
@@ -597,6 +820,16 @@ func (h *AdminHandler) CreateSeries(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse("DUPLICATE_ERROR", "Series dengan nama tersebut sudah ada"))
 	}
 
+	if details := validateSeriesBlockTypes(req.Blocks); len(details) > 0 {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal", details...))
+	}
+	if detail := validateSeriesSchedule(req.OpensAt, req.ClosesAt); detail != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal", *detail))
+	}
+	if ok := h.checkSeriesTargets(c, req.TargetKelasIDs, req.TargetJurusanIDs); !ok {
+		return nil
+	}
+
 	isActive := true
 	if req.IsActive != nil {
 		isActive = *req.IsActive
@@ -609,6 +842,7 @@ func (h *AdminHandler) CreateSeries(c *fiber.Ctx) error {
 			BlockType:  domain.ContentBlockType(b.BlockType),
 			BlockOrder: i,
 			Instruksi:  b.Instruksi,
+			IsRequired: b.IsRequired == nil || *b.IsRequired,
 		})
 	}
 
@@ -616,6 +850,8 @@ func (h *AdminHandler) CreateSeries(c *fiber.Ctx) error {
 		Nama:      req.Nama,
 		Deskripsi: req.Deskripsi,
 		IsActive:  isActive,
+		OpensAt:   req.OpensAt,
+		ClosesAt:  req.ClosesAt,
 		Blocks:    blocks,
 	}
 
@@ -623,15 +859,13 @@ func (h *AdminHandler) CreateSeries(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal membuat series"))
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse(dto.SeriesDetailDTO{
-		ID:             series.ID,
-		Nama:           series.Nama,
-		Deskripsi:      series.Deskripsi,
-		IsActive:       series.IsActive,
-		Blocks:         dto.SeriesBlocksToDTOs(series.Blocks),
-		PortfolioCount: 0,
-		CreatedAt:      series.CreatedAt,
-	}, "Series berhasil dibuat"))
+	if len(req.TargetKelasIDs) > 0 || len(req.TargetJurusanIDs) > 0 {
+		if err := h.assignmentRepo.ReplaceTargets(series.ID, uniqueIDs(req.TargetKelasIDs), uniqueIDs(req.TargetJurusanIDs)); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal menyimpan target series"))
+		}
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse(h.toSeriesDetailDTO(series, 0), "Series berhasil dibuat"))
 }
 
 func (h *AdminHandler) UpdateSeries(c *fiber.Ctx) error {
@@ -665,6 +899,32 @@ func (h *AdminHandler) UpdateSeries(c *fiber.Ctx) error {
 		series.IsActive = *req.IsActive
 	}
 
+	previousClose := series.ClosesAt
+	if req.ClearSchedule {
+		series.OpensAt = nil
+		series.ClosesAt = nil
+	}
+	if req.OpensAt != nil {
+		series.OpensAt = req.OpensAt
+	}
+	if req.ClosesAt != nil {
+		series.ClosesAt = req.ClosesAt
+	}
+	if detail := validateSeriesSchedule(series.OpensAt, series.ClosesAt); detail != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal", *detail))
+	}
+	// A moved deadline gets its own reminder
+	if !sameTime(previousClose, series.ClosesAt) {
+		series.ReminderSentAt = nil
+	}
+
+	retarget := req.TargetKelasIDs != nil || req.TargetJurusanIDs != nil
+	if retarget {
+		if ok := h.checkSeriesTargets(c, req.TargetKelasIDs, req.TargetJurusanIDs); !ok {
+			return nil
+		}
+	}
+
 	if err := h.adminRepo.UpdateSeries(series); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal memperbarui series"))
 	}
@@ -676,32 +936,38 @@ func (h *AdminHandler) UpdateSeries(c *fiber.Ctx) error {
 				dto.ErrorDetail{Field: "blocks", Message: "Series harus memiliki minimal 1 block"},
 			))
 		}
+		if details := validateSeriesBlockTypes(req.Blocks); len(details) > 0 {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal", details...))
+		}
 		var blocks []domain.SeriesBlock
 		for i, b := range req.Blocks {
-			blocks = append(blocks, domain.SeriesBlock{
+			block := domain.SeriesBlock{
 				BlockType:  domain.ContentBlockType(b.BlockType),
 				BlockOrder: i,
 				Instruksi:  b.Instruksi,
-			})
+				IsRequired: b.IsRequired == nil || *b.IsRequired,
+			}
+			if b.ID != nil {
+				block.ID = *b.ID
+			}
+			blocks = append(blocks, block)
 		}
 		if err := h.adminRepo.UpdateSeriesBlocks(id, blocks); err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal memperbarui blocks"))
 		}
 	}
 
+	if retarget {
+		if err := h.assignmentRepo.ReplaceTargets(id, uniqueIDs(req.TargetKelasIDs), uniqueIDs(req.TargetJurusanIDs)); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal menyimpan target series"))
+		}
+	}
+
 	// Reload series with blocks
 	series, _ = h.adminRepo.FindSeriesByID(id)
 	portfolioCount, _ := h.adminRepo.GetSeriesPortfolioCount(series.ID)
 
-	return c.JSON(dto.SuccessResponse(dto.SeriesDetailDTO{
-		ID:             series.ID,
-		Nama:           series.Nama,
-		Deskripsi:      series.Deskripsi,
-		IsActive:       series.IsActive,
-		Blocks:         dto.SeriesBlocksToDTOs(series.Blocks),
-		PortfolioCount: portfolioCount,
-		CreatedAt:      series.CreatedAt,
-	}, "Series berhasil diperbarui"))
+	return c.JSON(dto.SuccessResponse(h.toSeriesDetailDTO(series, portfolioCount), "Series berhasil diperbarui"))
 }
 
 func (h *AdminHandler) DeleteSeries(c *fiber.Ctx) error {
@@ -809,7 +1075,14 @@ func (h *AdminHandler) GetUser(c *fiber.Ctx) error {
 		if ch.TahunAjaran != nil {
 			tahunAjaran = ch.TahunAjaran.TahunMulai
 		}
-		result.ClassHistory = append(result.ClassHistory, dto.ClassHistoryDTO{KelasNama: kelasNama, TahunAjaran: tahunAjaran})
+		result.ClassHistory = append(result.ClassHistory, dto.AdminClassHistoryDTO{
+			ClassHistoryDTO: dto.ClassHistoryDTO{KelasNama: kelasNama, TahunAjaran: tahunAjaran},
+			KelasID:         ch.KelasID,
+			IsCurrent:       ch.IsCurrent,
+			EffectiveDate:   ch.EffectiveDate.Format(dateLayout),
+			EndDate:         formatOptionalDate(ch.EndDate),
+			Reason:          ch.Reason,
+		})
 	}
 	for _, sl := range user.SocialLinks {
 		result.SocialLinks = append(result.SocialLinks, dto.SocialLinkDTO{Platform: string(sl.Platform), URL: sl.URL})
@@ -1097,27 +1370,7 @@ func (h *AdminHandler) ListPendingPortfolios(c *fiber.Ctx) error {
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil data portfolio"))
 	}
-
-	var result []dto.AdminPortfolioDTO
-	for _, p := range portfolios {
-		pDTO := dto.AdminPortfolioDTO{
-			ID: p.ID, Judul: p.Judul, Slug: p.Slug, ThumbnailURL: p.ThumbnailURL, Status: string(p.Status), CreatedAt: p.CreatedAt,
-		}
-		if p.User != nil {
-			var kelasNama, jurusanNama *string
-			if p.User.Kelas != nil {
-				kelasNama = &p.User.Kelas.Nama
-				if p.User.Kelas.Jurusan != nil {
-					jurusanNama = &p.User.Kelas.Jurusan.Nama
-				}
-			}
-			pDTO.User = &dto.PortfolioUserDTO{
-				ID: p.User.ID, Username: p.User.Username, Nama: p.User.Nama, AvatarURL: p.User.AvatarURL,
-				Role: string(p.User.Role), KelasNama: kelasNama, JurusanNama: jurusanNama,
-			}
-		}
-		result = append(result, pDTO)
-	}
+	result := h.pendingPortfolioDTOs(portfolios, *middleware.GetUserID(c))
 
 	totalPages := int(total) / limit
 	if int(total)%limit > 0 {
@@ -1189,7 +1442,7 @@ func (h *AdminHandler) GetPortfolio(c *fiber.Ctx) error {
 	result := dto.PortfolioDetailDTO{
 		ID: portfolio.ID, Judul: portfolio.Judul, Slug: portfolio.Slug, ThumbnailURL: portfolio.ThumbnailURL,
 		Status: string(portfolio.Status), AdminReviewNote: portfolio.AdminReviewNote, ReviewedAt: portfolio.ReviewedAt,
-		PublishedAt: portfolio.PublishedAt, CreatedAt: portfolio.CreatedAt, UpdatedAt: portfolio.UpdatedAt, LikeCount: likeCount,
+		PublishedAt: portfolio.PublishedAt, PublishAt: portfolio.PublishAt, CreatedAt: portfolio.CreatedAt, UpdatedAt: portfolio.UpdatedAt, LikeCount: likeCount,
 	}
 
 	if portfolio.User != nil {
@@ -1210,9 +1463,17 @@ func (h *AdminHandler) GetPortfolio(c *fiber.Ctx) error {
 		result.Tags = append(result.Tags, dto.TagDTO{ID: t.ID, Nama: t.Nama})
 	}
 	for _, b := range portfolio.ContentBlocks {
-		result.ContentBlocks = append(result.ContentBlocks, dto.ContentBlockDTO{
-			ID: b.ID, BlockType: string(b.BlockType), BlockOrder: b.BlockOrder, Payload: b.Payload,
-		})
+		result.ContentBlocks = append(result.ContentBlocks, dto.ContentBlockToDTO(b))
+	}
+	if draft, _ := h.draftService.Find(portfolio.ID); draft != nil {
+		result.Draft = dto.DraftToDTO(draft)
+	}
+	if rejection, _ := h.rejectionRepo.LatestRejection(portfolio.ID); rejection != nil {
+		result.Rejection = dto.RejectionToDTO(rejection)
+	}
+	if portfolio.SeriesID != nil {
+		items, _ := h.rejectionRepo.ListChecklist(*portfolio.SeriesID)
+		result.ReviewChecklist = toChecklistDTOs(items)
 	}
 
 	return c.JSON(dto.SuccessResponse(result, ""))
@@ -1229,31 +1490,72 @@ func (h *AdminHandler) ApprovePortfolio(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("PORTFOLIO_NOT_FOUND", "Portfolio tidak ditemukan"))
 	}
 
+	if !h.checkClaim(c, portfolio.ID) {
+		return nil
+	}
+
 	var req dto.ModeratePortfolioRequest
 	c.BodyParser(&req)
 
 	adminID := middleware.GetUserID(c)
+
+	if draft, ok := h.pendingDraft(c, portfolio); !ok {
+		return nil
+	} else if draft != nil {
+		if req.PublishAt != nil {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal",
+				dto.ErrorDetail{Field: "publish_at", Message: "Perubahan portfolio yang sudah disetujui tidak bisa dijadwalkan"},
+			))
+		}
+		return h.approveDraft(c, portfolio, draft, req.Note, adminID)
+	}
+
 	now := time.Now()
 
-	portfolio.Status = domain.StatusPublished
+	// The schedule set by the admin wins over the one requested on submit;
+	// a requested time that has already passed publishes immediately
+	publishAt := portfolio.PublishAt
+	if req.PublishAt != nil {
+		if detail := validatePublishAt(req.PublishAt, now); detail != nil {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal", *detail))
+		}
+		publishAt = req.PublishAt
+	}
+	scheduled := publishAt != nil && publishAt.After(now)
+
 	portfolio.AdminReviewNote = &req.Note
 	portfolio.ReviewedBy = adminID
 	portfolio.ReviewedAt = &now
-	portfolio.PublishedAt = &now
+	portfolio.PublishAt = publishAt
+	if scheduled {
+		portfolio.Status = domain.StatusScheduled
+		portfolio.PublishedAt = nil
+	} else {
+		portfolio.Status = domain.StatusPublished
+		portfolio.PublishedAt = &now
+	}
 
 	if err := h.portfolioRepo.Update(portfolio); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal menyetujui portfolio"))
 	}
+	h.reviewClaims.Done(portfolio.ID)
+	recordRevision(h.revisionService, portfolio, domain.RevisionReasonApprove, adminID)
+
+	response := map[string]interface{}{
+		"id": portfolio.ID, "status": portfolio.Status, "admin_review_note": portfolio.AdminReviewNote,
+		"reviewed_at": portfolio.ReviewedAt, "published_at": portfolio.PublishedAt, "publish_at": portfolio.PublishAt,
+	}
+	if scheduled {
+		// The owner is notified by the publish scheduler once the portfolio goes live
+		return c.JSON(dto.SuccessResponse(response, "Portfolio disetujui dan dijadwalkan untuk dipublish"))
+	}
 
 	// Send notification to portfolio owner
 	if h.notifService != nil {
 		_ = h.notifService.NotifyPortfolioApproved(portfolio)
 	}
 
-	return c.JSON(dto.SuccessResponse(map[string]interface{}{
-		"id": portfolio.ID, "status": portfolio.Status, "admin_review_note": portfolio.AdminReviewNote,
-		"reviewed_at": portfolio.ReviewedAt, "published_at": portfolio.PublishedAt,
-	}, "Portfolio berhasil disetujui dan dipublish"))
+	return c.JSON(dto.SuccessResponse(response, "Portfolio berhasil disetujui dan dipublish"))
 }
 
 func (h *AdminHandler) RejectPortfolio(c *fiber.Ctx) error {
@@ -1272,34 +1574,137 @@ func (h *AdminHandler) RejectPortfolio(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Request body tidak valid"))
 	}
 
-	if req.Note == "" {
+	var details []dto.ErrorDetail
+	if len(req.ReasonIDs) == 0 {
+		details = append(details, dto.ErrorDetail{Field: "reason_ids", Message: "Pilih minimal satu alasan penolakan"})
+	}
+	if utf8.RuneCountInString(req.Note) > 2000 {
+		details = append(details, dto.ErrorDetail{Field: "note", Message: "Catatan maksimal 2000 karakter"})
+	}
+	blocks := make([]service.RejectionBlockNote, 0, len(req.Blocks))
+	for _, b := range req.Blocks {
+		note := strings.TrimSpace(b.Note)
+		if note == "" || utf8.RuneCountInString(note) > 500 {
+			details = append(details, dto.ErrorDetail{Field: "blocks", Message: "Catatan per block wajib diisi, maksimal 500 karakter"})
+			break
+		}
+		blocks = append(blocks, service.RejectionBlockNote{BlockID: b.BlockID, Note: note})
+	}
+	if len(details) > 0 {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal", details...))
+	}
+	if !h.checkClaim(c, portfolio.ID) {
+		return nil
+	}
+
+	adminID := middleware.GetUserID(c)
+
+	draft, ok := h.pendingDraft(c, portfolio)
+	if !ok {
+		return nil
+	}
+	rejection, reviewNote, err := h.rejections.Prepare(portfolio, draft, req.ReasonIDs, req.Note, blocks)
+	switch {
+	case errors.Is(err, service.ErrRejectionReasonRequired), errors.Is(err, service.ErrRejectionReasonInvalid):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal",
+			dto.ErrorDetail{Field: "reason_ids", Message: "Alasan penolakan tidak ditemukan atau sudah tidak aktif"},
+		))
+	case errors.Is(err, service.ErrRejectionBlockInvalid):
 		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal",
-			dto.ErrorDetail{Field: "note", Message: "Alasan penolakan wajib diisi"},
+			dto.ErrorDetail{Field: "blocks", Message: "Block tidak termasuk konten yang sedang ditinjau"},
 		))
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal memeriksa alasan penolakan"))
+	}
+
+	if draft != nil {
+		if err := h.draftService.Reject(draft, adminID, reviewNote); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal menolak perubahan portfolio"))
+		}
+		h.recordRejection(rejection, adminID)
+		h.reviewClaims.Done(portfolio.ID)
+		if h.notifService != nil {
+			_ = h.notifService.NotifyPortfolioRejected(portfolio, reviewNote)
+		}
+		return c.JSON(dto.SuccessResponse(map[string]interface{}{
+			"id": portfolio.ID, "status": portfolio.Status, "draft_status": draft.Status,
+			"admin_review_note": draft.AdminReviewNote, "reviewed_at": draft.ReviewedAt,
+			"rejection": dto.RejectionToDTO(rejection),
+		}, "Perubahan portfolio ditolak, versi live tetap dipertahankan"))
 	}
 
-	adminID := middleware.GetUserID(c)
 	now := time.Now()
 
 	portfolio.Status = domain.StatusRejected
-	portfolio.AdminReviewNote = &req.Note
+	portfolio.AdminReviewNote = &reviewNote
 	portfolio.ReviewedBy = adminID
 	portfolio.ReviewedAt = &now
 
 	if err := h.portfolioRepo.Update(portfolio); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal menolak portfolio"))
 	}
+	h.recordRejection(rejection, adminID)
+	h.reviewClaims.Done(portfolio.ID)
 
 	// Send notification to portfolio owner
 	if h.notifService != nil {
-		_ = h.notifService.NotifyPortfolioRejected(portfolio, req.Note)
+		_ = h.notifService.NotifyPortfolioRejected(portfolio, reviewNote)
 	}
 
 	return c.JSON(dto.SuccessResponse(map[string]interface{}{
 		"id": portfolio.ID, "status": portfolio.Status, "admin_review_note": portfolio.AdminReviewNote, "reviewed_at": portfolio.ReviewedAt,
+		"rejection": dto.RejectionToDTO(rejection),
 	}, "Portfolio ditolak"))
 }
 
+// recordRejection stores the structured rejection once the status change went through; the
+// composed review note already reached the student, so a failure here is only logged
+func (h *AdminHandler) recordRejection(rejection *domain.PortfolioRejection, adminID *uuid.UUID) {
+	if err := h.rejections.Record(rejection, adminID); err != nil {
+		log.Printf("[REVIEW] Failed to record rejection of portfolio %s: %v", rejection.PortfolioID, err)
+	}
+}
+
+// pendingDraft returns the working draft awaiting review when portfolio is a published
+// portfolio under re-review, or nil when the moderation applies to the portfolio itself
+func (h *AdminHandler) pendingDraft(c *fiber.Ctx, portfolio *domain.Portfolio) (*domain.PortfolioDraft, bool) {
+	if !portfolio.IsApproved() {
+		return nil, true
+	}
+	draft, err := h.draftService.Find(portfolio.ID)
+	if err != nil {
+		c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil draft portfolio"))
+		return nil, false
+	}
+	if draft == nil || draft.Status != domain.DraftPendingReview {
+		return nil, true
+	}
+	return draft, true
+}
+
+// approveDraft replaces the live content with the approved draft
+func (h *AdminHandler) approveDraft(c *fiber.Ctx, portfolio *domain.Portfolio, draft *domain.PortfolioDraft, note string, adminID *uuid.UUID) error {
+	if err := h.draftService.Approve(draft, adminID, &note); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal menyetujui perubahan portfolio"))
+	}
+	h.reviewClaims.Done(portfolio.ID)
+
+	updated, err := h.portfolioRepo.FindByID(portfolio.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal memuat portfolio"))
+	}
+	recordRevision(h.revisionService, updated, domain.RevisionReasonApprove, adminID)
+
+	if h.notifService != nil {
+		_ = h.notifService.NotifyPortfolioApproved(updated)
+	}
+
+	return c.JSON(dto.SuccessResponse(map[string]interface{}{
+		"id": updated.ID, "status": updated.Status, "admin_review_note": updated.AdminReviewNote,
+		"reviewed_at": updated.ReviewedAt, "published_at": updated.PublishedAt,
+	}, "Perubahan portfolio disetujui dan dipublish"))
+}
+
 func (h *AdminHandler) UpdatePortfolio(c *fiber.Ctx) error {
 	id, err := uuid.Parse(c.Params("id"))
 	if err != nil {
@@ -1397,6 +1802,14 @@ func (h *AdminHandler) GetDashboardStats(c *fiber.Ctx) error {
 		recentPendingDTO = append(recentPendingDTO, pDTO)
 	}
 
+	// Rejection reasons, most used first
+	now := time.Now()
+	reasonStats, _ := h.rejectionRepo.ReasonStats(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()))
+	reasonStatsDTO := make([]dto.RejectionReasonStatDTO, 0, len(reasonStats))
+	for _, st := range reasonStats {
+		reasonStatsDTO = append(reasonStatsDTO, dto.RejectionReasonStatDTO{ID: st.ReasonID, Nama: st.Nama, Total: st.Total, ThisMonth: st.Recent})
+	}
+
 	return c.JSON(dto.SuccessResponse(dto.DashboardStatsDTO{
 		Users: dto.UserStatsDTO{
 			Total: userTotal, Students: students, Alumni: alumni, Admins: admins, NewThisMonth: userNewMonth,
@@ -1409,6 +1822,7 @@ func (h *AdminHandler) GetDashboardStats(c *fiber.Ctx) error {
 		Kelas:                   dto.KelasStatsDTO{Total: kelasTotal, ActiveTahunAjaran: kelasActive},
 		RecentUsers:             recentUsersDTO,
 		RecentPendingPortfolios: recentPendingDTO,
+		RejectionReasons:        reasonStatsDTO,
 	}, ""))
 }
 
@@ -7,17 +7,20 @@ import (
 	"github.com/grafikarsa/backend/internal/dto"
 	"github.com/grafikarsa/backend/internal/middleware"
 	"github.com/grafikarsa/backend/internal/repository"
+	"github.com/grafikarsa/backend/internal/service"
 )
 
 type AssessmentHandler struct {
-	assessmentRepo *repository.AssessmentRepository
-	portfolioRepo  *repository.PortfolioRepository
+	assessmentRepo  *repository.AssessmentRepository
+	portfolioRepo   *repository.PortfolioRepository
+	revisionService *service.RevisionService
 }
 
-func NewAssessmentHandler(assessmentRepo *repository.AssessmentRepository, portfolioRepo *repository.PortfolioRepository) *AssessmentHandler {
+func NewAssessmentHandler(assessmentRepo *repository.AssessmentRepository, portfolioRepo *repository.PortfolioRepository, revisionService *service.RevisionService) *AssessmentHandler {
 	return &AssessmentHandler{
-		assessmentRepo: assessmentRepo,
-		portfolioRepo:  portfolioRepo,
+		assessmentRepo:  assessmentRepo,
+		portfolioRepo:   portfolioRepo,
+		revisionService: revisionService,
 	}
 }
 
@@ -288,7 +291,7 @@ func (h *AssessmentHandler) GetAssessment(c *fiber.Ctx) error {
 			Slug:         portfolio.Slug,
 			ThumbnailURL: portfolio.ThumbnailURL,
 		},
-		"assessment": h.toAssessmentResponse(assessment),
+		"assessment": toAssessmentResponse(assessment),
 	}, ""))
 }
 
@@ -353,10 +356,11 @@ func (h *AssessmentHandler) CreateOrUpdateAssessment(c *fiber.Ctx) error {
 		if err := h.assessmentRepo.ReplaceScores(existingAssessment.ID, scores); err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("UPDATE_FAILED", "Gagal mengupdate nilai"))
 		}
+		recordRevision(h.revisionService, portfolio, domain.RevisionReasonAssessment, adminID)
 
 		// Reload assessment
 		assessment, _ := h.assessmentRepo.FindAssessmentByPortfolioID(portfolioID)
-		return c.JSON(dto.SuccessResponse(h.toAssessmentResponse(assessment), "Penilaian berhasil diupdate"))
+		return c.JSON(dto.SuccessResponse(toAssessmentResponse(assessment), "Penilaian berhasil diupdate"))
 	}
 
 	// Create new assessment
@@ -385,11 +389,12 @@ func (h *AssessmentHandler) CreateOrUpdateAssessment(c *fiber.Ctx) error {
 	if err := h.assessmentRepo.CreateScores(scores); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("CREATE_FAILED", "Gagal menyimpan nilai"))
 	}
+	recordRevision(h.revisionService, portfolio, domain.RevisionReasonAssessment, adminID)
 
 	// Reload assessment with scores
 	assessment, _ = h.assessmentRepo.FindAssessmentByPortfolioID(portfolioID)
 
-	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse(h.toAssessmentResponse(assessment), "Penilaian berhasil disimpan"))
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse(toAssessmentResponse(assessment), "Penilaian berhasil disimpan"))
 }
 
 // DeleteAssessment - DELETE /admin/assessments/:portfolio_id
@@ -427,7 +432,7 @@ func (h *AssessmentHandler) toMetricResponse(m *domain.AssessmentMetric) dto.Met
 	}
 }
 
-func (h *AssessmentHandler) toAssessmentResponse(a *domain.PortfolioAssessment) dto.AssessmentResponse {
+func toAssessmentResponse(a *domain.PortfolioAssessment) dto.AssessmentResponse {
 	resp := dto.AssessmentResponse{
 		ID:           a.ID.String(),
 		PortfolioID:  a.PortfolioID.String(),
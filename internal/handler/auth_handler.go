@@ -55,7 +55,7 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	}
 
 	// Generate tokens
-	accessToken, _, err := h.jwt.GenerateAccessToken(user.ID, string(user.Role))
+	accessToken, _, err := h.jwt.GenerateAccessToken(user.ID, string(user.Role), user.MustChangePassword)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
 			"INTERNAL_ERROR", "Gagal membuat token",
@@ -114,6 +114,7 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 			Role:      string(user.Role),
 			AvatarURL: user.AvatarURL,
 		},
+		MustChangePassword: user.MustChangePassword,
 	}, ""))
 }
 
@@ -161,7 +162,7 @@ func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
 	h.authRepo.RevokeRefreshToken(storedToken.ID, "rotated")
 
 	// Generate new tokens
-	accessToken, _, err := h.jwt.GenerateAccessToken(user.ID, string(user.Role))
+	accessToken, _, err := h.jwt.GenerateAccessToken(user.ID, string(user.Role), user.MustChangePassword)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
 			"INTERNAL_ERROR", "Gagal membuat token",
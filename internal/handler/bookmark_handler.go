@@ -0,0 +1,356 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/middleware"
+	"github.com/grafikarsa/backend/internal/repository"
+	"github.com/grafikarsa/backend/internal/service"
+	"gorm.io/gorm"
+)
+
+type BookmarkHandler struct {
+	bookmarkService *service.BookmarkService
+	bookmarkRepo    *repository.BookmarkRepository
+	portfolioRepo   *repository.PortfolioRepository
+	userRepo        *repository.UserRepository
+}
+
+func NewBookmarkHandler(bookmarkService *service.BookmarkService, bookmarkRepo *repository.BookmarkRepository, portfolioRepo *repository.PortfolioRepository, userRepo *repository.UserRepository) *BookmarkHandler {
+	return &BookmarkHandler{
+		bookmarkService: bookmarkService,
+		bookmarkRepo:    bookmarkRepo,
+		portfolioRepo:   portfolioRepo,
+		userRepo:        userRepo,
+	}
+}
+
+// ListMine - GET /me/collections
+func (h *BookmarkHandler) ListMine(c *fiber.Ctx) error {
+	collections, err := h.bookmarkRepo.ListCollections(*middleware.GetUserID(c), false)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil koleksi"))
+	}
+	return h.respondCollections(c, collections)
+}
+
+// ListByUser - GET /users/:username/collections
+// Profile tab; visitors only see public collections.
+func (h *BookmarkHandler) ListByUser(c *fiber.Ctx) error {
+	user, err := h.userRepo.FindByUsername(c.Params("username"))
+	if err != nil || (!user.IsActive && middleware.GetUserRole(c) != "admin") {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("USER_NOT_FOUND", "User tidak ditemukan"))
+	}
+
+	currentUserID := middleware.GetUserID(c)
+	isOwner := currentUserID != nil && *currentUserID == user.ID
+	collections, err := h.bookmarkRepo.ListCollections(user.ID, !isOwner)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil koleksi"))
+	}
+	return h.respondCollections(c, collections)
+}
+
+// Create - POST /collections
+func (h *BookmarkHandler) Create(c *fiber.Ctx) error {
+	var req dto.CreateBookmarkCollectionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Request body tidak valid"))
+	}
+
+	deskripsi := normalizeDeskripsi(req.Deskripsi)
+	if details := validateCollection(&req.Nama, deskripsi); len(details) > 0 {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal", details...))
+	}
+
+	collection, err := h.bookmarkService.CreateCollection(*middleware.GetUserID(c), req.Nama, deskripsi, req.IsPublic)
+	if errors.Is(err, service.ErrCollectionLimit) {
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse("COLLECTION_LIMIT", "Jumlah koleksi sudah maksimal"))
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal membuat koleksi"))
+	}
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse(toCollectionDTO(*collection, 0), "Koleksi berhasil dibuat"))
+}
+
+// Get - GET /collections/:id
+// Public collections can be opened by anyone holding the link, private ones only by their owner.
+func (h *BookmarkHandler) Get(c *fiber.Ctx) error {
+	collection, ok := h.findCollection(c, false)
+	if !ok {
+		return nil
+	}
+
+	items, err := h.bookmarkRepo.ListItems(collection, currentViewer(c, h.userRepo))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil isi koleksi"))
+	}
+
+	result := dto.BookmarkCollectionDetailDTO{
+		BookmarkCollectionDTO: toCollectionDTO(*collection, int64(len(items))),
+		Items:                 make([]dto.BookmarkItemDTO, 0, len(items)),
+	}
+	for _, item := range items {
+		if item.Portfolio == nil {
+			continue
+		}
+		likeCount, _ := h.portfolioRepo.GetLikeCount(item.PortfolioID)
+		result.Items = append(result.Items, dto.BookmarkItemDTO{
+			Portfolio: toPortfolioListDTO(item.Portfolio, likeCount),
+			ItemOrder: item.ItemOrder,
+			AddedAt:   item.CreatedAt,
+		})
+	}
+	return c.JSON(dto.SuccessResponse(result, ""))
+}
+
+// Update - PATCH /collections/:id
+func (h *BookmarkHandler) Update(c *fiber.Ctx) error {
+	collection, ok := h.findCollection(c, true)
+	if !ok {
+		return nil
+	}
+
+	var req dto.UpdateBookmarkCollectionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Request body tidak valid"))
+	}
+
+	deskripsi := normalizeDeskripsi(req.Deskripsi)
+	if details := validateCollection(req.Nama, deskripsi); len(details) > 0 {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal", details...))
+	}
+
+	if req.Nama != nil {
+		collection.Nama = strings.TrimSpace(*req.Nama)
+	}
+	if req.Deskripsi != nil {
+		collection.Deskripsi = deskripsi
+	}
+	if req.IsPublic != nil {
+		collection.IsPublic = *req.IsPublic
+	}
+	if err := h.bookmarkRepo.UpdateCollection(collection); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal memperbarui koleksi"))
+	}
+
+	counts, _ := h.bookmarkRepo.CountItems([]uuid.UUID{collection.ID})
+	return c.JSON(dto.SuccessResponse(toCollectionDTO(*collection, counts[collection.ID]), "Koleksi berhasil diperbarui"))
+}
+
+// Delete - DELETE /collections/:id
+func (h *BookmarkHandler) Delete(c *fiber.Ctx) error {
+	collection, ok := h.findCollection(c, true)
+	if !ok {
+		return nil
+	}
+	if err := h.bookmarkRepo.DeleteCollection(collection.ID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal menghapus koleksi"))
+	}
+	return c.JSON(dto.SuccessResponse(nil, "Koleksi berhasil dihapus"))
+}
+
+// AddItem - POST /collections/:id/items
+func (h *BookmarkHandler) AddItem(c *fiber.Ctx) error {
+	collection, ok := h.findCollection(c, true)
+	if !ok {
+		return nil
+	}
+
+	var req dto.AddBookmarkItemRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Request body tidak valid"))
+	}
+	if req.PortfolioID == uuid.Nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse(
+			"VALIDATION_ERROR", "Validasi gagal",
+			dto.ErrorDetail{Field: "portfolio_id", Message: "Portfolio wajib dipilih"},
+		))
+	}
+
+	item, err := h.bookmarkService.AddItem(collection, req.PortfolioID, currentViewer(c, h.userRepo))
+	switch {
+	case errors.Is(err, service.ErrNotBookmarkable):
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("PORTFOLIO_NOT_FOUND", "Portfolio tidak ditemukan"))
+	case errors.Is(err, service.ErrAlreadyBookmarked):
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse("ALREADY_BOOKMARKED", "Portfolio sudah ada di koleksi ini"))
+	case errors.Is(err, service.ErrCollectionFull):
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse("COLLECTION_FULL", "Koleksi sudah penuh"))
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal menyimpan portfolio ke koleksi"))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse(map[string]interface{}{
+		"collection_id": item.CollectionID,
+		"portfolio_id":  item.PortfolioID,
+		"item_order":    item.ItemOrder,
+	}, "Portfolio berhasil disimpan ke koleksi"))
+}
+
+// RemoveItem - DELETE /collections/:id/items/:portfolio_id
+func (h *BookmarkHandler) RemoveItem(c *fiber.Ctx) error {
+	collection, ok := h.findCollection(c, true)
+	if !ok {
+		return nil
+	}
+	portfolioID, err := uuid.Parse(c.Params("portfolio_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "ID portfolio tidak valid"))
+	}
+
+	removed, err := h.bookmarkRepo.RemoveItem(collection.ID, portfolioID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal menghapus portfolio dari koleksi"))
+	}
+	if !removed {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("BOOKMARK_NOT_FOUND", "Portfolio tidak ada di koleksi ini"))
+	}
+	return c.JSON(dto.SuccessResponse(nil, "Portfolio berhasil dihapus dari koleksi"))
+}
+
+// ReorderItems - PUT /collections/:id/items/reorder
+func (h *BookmarkHandler) ReorderItems(c *fiber.Ctx) error {
+	collection, ok := h.findCollection(c, true)
+	if !ok {
+		return nil
+	}
+
+	var req dto.ReorderBookmarkItemsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Request body tidak valid"))
+	}
+	if len(req.PortfolioIDs) == 0 {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse(
+			"VALIDATION_ERROR", "Validasi gagal",
+			dto.ErrorDetail{Field: "portfolio_ids", Message: "Urutan portfolio wajib diisi"},
+		))
+	}
+
+	if err := h.bookmarkRepo.ReorderItems(collection.ID, req.PortfolioIDs); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengubah urutan koleksi"))
+	}
+	return c.JSON(dto.SuccessResponse(nil, "Urutan koleksi berhasil diperbarui"))
+}
+
+// findCollection loads the :id collection; owned requires the requester to own it, otherwise
+// private collections are hidden from everyone but their owner
+func (h *BookmarkHandler) findCollection(c *fiber.Ctx, owned bool) (*domain.BookmarkCollection, bool) {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "ID tidak valid"))
+		return nil, false
+	}
+	collection, err := h.bookmarkRepo.FindCollection(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("COLLECTION_NOT_FOUND", "Koleksi tidak ditemukan"))
+		return nil, false
+	}
+	if err != nil {
+		c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil koleksi"))
+		return nil, false
+	}
+
+	currentUserID := middleware.GetUserID(c)
+	isOwner := currentUserID != nil && *currentUserID == collection.UserID
+	if !isOwner && (owned || !collection.IsPublic) {
+		c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("COLLECTION_NOT_FOUND", "Koleksi tidak ditemukan"))
+		return nil, false
+	}
+	return collection, true
+}
+
+func (h *BookmarkHandler) respondCollections(c *fiber.Ctx, collections []domain.BookmarkCollection) error {
+	ids := make([]uuid.UUID, 0, len(collections))
+	for _, col := range collections {
+		ids = append(ids, col.ID)
+	}
+	counts, _ := h.bookmarkRepo.CountItems(ids)
+
+	result := make([]dto.BookmarkCollectionDTO, 0, len(collections))
+	for _, col := range collections {
+		result = append(result, toCollectionDTO(col, counts[col.ID]))
+	}
+	return c.JSON(dto.SuccessResponse(result, ""))
+}
+
+func toCollectionDTO(col domain.BookmarkCollection, itemCount int64) dto.BookmarkCollectionDTO {
+	result := dto.BookmarkCollectionDTO{
+		ID:        col.ID,
+		Nama:      col.Nama,
+		Deskripsi: col.Deskripsi,
+		IsPublic:  col.IsPublic,
+		ItemCount: itemCount,
+		CreatedAt: col.CreatedAt,
+		UpdatedAt: col.UpdatedAt,
+	}
+	if col.User != nil {
+		result.User = toPortfolioUserDTO(col.User)
+	}
+	return result
+}
+
+func toPortfolioListDTO(p *domain.Portfolio, likeCount int64) dto.PortfolioListDTO {
+	result := dto.PortfolioListDTO{
+		ID:           p.ID,
+		Judul:        p.Judul,
+		Slug:         p.Slug,
+		ThumbnailURL: p.ThumbnailURL,
+		PublishedAt:  p.PublishedAt,
+		CreatedAt:    p.CreatedAt,
+		LikeCount:    likeCount,
+	}
+	if p.User != nil {
+		result.User = toPortfolioUserDTO(p.User)
+	}
+	for _, t := range p.Tags {
+		result.Tags = append(result.Tags, dto.TagDTO{ID: t.ID, Nama: t.Nama})
+	}
+	return result
+}
+
+func toPortfolioUserDTO(u *domain.User) *dto.PortfolioUserDTO {
+	var kelasNama *string
+	if u.Kelas != nil {
+		kelasNama = &u.Kelas.Nama
+	}
+	return &dto.PortfolioUserDTO{
+		ID:        u.ID,
+		Username:  u.Username,
+		Nama:      u.Nama,
+		AvatarURL: u.AvatarURL,
+		Role:      string(u.Role),
+		KelasNama: kelasNama,
+	}
+}
+
+// normalizeDeskripsi trims the description; a blank one clears it
+func normalizeDeskripsi(deskripsi *string) *string {
+	if deskripsi == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*deskripsi)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+func validateCollection(nama *string, deskripsi *string) []dto.ErrorDetail {
+	var details []dto.ErrorDetail
+	if nama != nil {
+		trimmed := strings.TrimSpace(*nama)
+		if trimmed == "" || utf8.RuneCountInString(trimmed) > 100 {
+			details = append(details, dto.ErrorDetail{Field: "nama", Message: "Nama koleksi wajib diisi, maksimal 100 karakter"})
+		}
+	}
+	if deskripsi != nil && utf8.RuneCountInString(*deskripsi) > 500 {
+		details = append(details, dto.ErrorDetail{Field: "deskripsi", Message: "Deskripsi maksimal 500 karakter"})
+	}
+	return details
+}
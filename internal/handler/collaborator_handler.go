@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/middleware"
+	"github.com/grafikarsa/backend/internal/repository"
+	"github.com/grafikarsa/backend/internal/service"
+	"gorm.io/gorm"
+)
+
+type CollaboratorHandler struct {
+	collabService *service.CollaborationService
+	collabRepo    *repository.CollaboratorRepository
+	portfolioRepo *repository.PortfolioRepository
+	userRepo      *repository.UserRepository
+}
+
+func NewCollaboratorHandler(collabService *service.CollaborationService, collabRepo *repository.CollaboratorRepository, portfolioRepo *repository.PortfolioRepository, userRepo *repository.UserRepository) *CollaboratorHandler {
+	return &CollaboratorHandler{
+		collabService: collabService,
+		collabRepo:    collabRepo,
+		portfolioRepo: portfolioRepo,
+		userRepo:      userRepo,
+	}
+}
+
+// List - GET /portfolios/:id/collaborators
+// Authors and admins also see pending and declined invitations.
+func (h *CollaboratorHandler) List(c *fiber.Ctx) error {
+	portfolio, ok := h.findPortfolio(c)
+	if !ok {
+		return nil
+	}
+
+	currentUserID := middleware.GetUserID(c)
+	includePending := h.collabService.CanEdit(portfolio, currentUserID) || middleware.GetUserRole(c) == "admin"
+	if portfolio.Status != domain.StatusPublished && !includePending {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("PORTFOLIO_NOT_FOUND", "Portfolio tidak ditemukan"))
+	}
+
+	collaborators, err := h.collabService.List(portfolio.ID, includePending)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil anggota tim"))
+	}
+	return c.JSON(dto.SuccessResponse(dto.CollaboratorsToDTO(collaborators), ""))
+}
+
+// Invite - POST /portfolios/:id/collaborators
+func (h *CollaboratorHandler) Invite(c *fiber.Ctx) error {
+	portfolio, ok := h.findManagedPortfolio(c)
+	if !ok {
+		return nil
+	}
+
+	var req dto.InviteCollaboratorRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Request body tidak valid"))
+	}
+
+	role := domain.CollaboratorRole(req.Role)
+	if !service.IsValidInviteRole(role) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse(
+			"VALIDATION_ERROR", "Validasi gagal",
+			dto.ErrorDetail{Field: "role", Message: "Role harus editor atau contributor"},
+		))
+	}
+
+	invitee, err := h.userRepo.FindByID(req.UserID)
+	if err != nil || !invitee.IsActive {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("USER_NOT_FOUND", "User tidak ditemukan"))
+	}
+	if invitee.Role != domain.RoleStudent && invitee.Role != domain.RoleAlumni {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse(
+			"VALIDATION_ERROR", "Validasi gagal",
+			dto.ErrorDetail{Field: "user_id", Message: "Hanya siswa atau alumni yang bisa menjadi anggota tim"},
+		))
+	}
+
+	inviter, err := h.userRepo.FindByID(*middleware.GetUserID(c))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengirim undangan"))
+	}
+
+	collaborator, err := h.collabService.Invite(portfolio, inviter, invitee.ID, role)
+	switch {
+	case errors.Is(err, service.ErrCollaboratorIsOwner):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse(
+			"VALIDATION_ERROR", "Validasi gagal",
+			dto.ErrorDetail{Field: "user_id", Message: "Owner portfolio tidak perlu diundang"},
+		))
+	case errors.Is(err, service.ErrCollaboratorExists):
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse("ALREADY_INVITED", "User sudah diundang ke portfolio ini"))
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengirim undangan"))
+	}
+
+	collaborator.User = invitee
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse(dto.CollaboratorToDTO(*collaborator), "Undangan berhasil dikirim"))
+}
+
+// UpdateRole - PATCH /portfolios/:id/collaborators/:collaborator_id
+func (h *CollaboratorHandler) UpdateRole(c *fiber.Ctx) error {
+	portfolio, ok := h.findManagedPortfolio(c)
+	if !ok {
+		return nil
+	}
+	collaborator, ok := h.findCollaborator(c, portfolio)
+	if !ok {
+		return nil
+	}
+
+	var req dto.UpdateCollaboratorRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Request body tidak valid"))
+	}
+
+	err := h.collabService.UpdateRole(collaborator, domain.CollaboratorRole(req.Role))
+	if errors.Is(err, service.ErrInvalidCollaboratorRole) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse(
+			"VALIDATION_ERROR", "Validasi gagal",
+			dto.ErrorDetail{Field: "role", Message: "Role harus editor atau contributor"},
+		))
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengubah role anggota tim"))
+	}
+
+	return c.JSON(dto.SuccessResponse(dto.CollaboratorToDTO(*collaborator), "Role anggota tim berhasil diubah"))
+}
+
+// Remove - DELETE /portfolios/:id/collaborators/:collaborator_id
+// The owner removes anyone; a co-author may remove themselves to leave the team.
+func (h *CollaboratorHandler) Remove(c *fiber.Ctx) error {
+	portfolio, ok := h.findPortfolio(c)
+	if !ok {
+		return nil
+	}
+	collaborator, ok := h.findCollaborator(c, portfolio)
+	if !ok {
+		return nil
+	}
+
+	currentUserID := middleware.GetUserID(c)
+	isOwner := currentUserID != nil && *currentUserID == portfolio.UserID
+	isSelf := currentUserID != nil && *currentUserID == collaborator.UserID
+	isAdmin := middleware.GetUserRole(c) == "admin"
+	if !isOwner && !isSelf && !isAdmin {
+		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse("FORBIDDEN", "Anda tidak memiliki akses"))
+	}
+
+	if err := h.collabService.Remove(collaborator.ID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal menghapus anggota tim"))
+	}
+	return c.JSON(dto.SuccessResponse(nil, "Anggota tim berhasil dihapus"))
+}
+
+func (h *CollaboratorHandler) findPortfolio(c *fiber.Ctx) (*domain.Portfolio, bool) {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "ID tidak valid"))
+		return nil, false
+	}
+	portfolio, err := h.portfolioRepo.FindByID(id)
+	if err != nil {
+		c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("PORTFOLIO_NOT_FOUND", "Portfolio tidak ditemukan"))
+		return nil, false
+	}
+	return portfolio, true
+}
+
+// findManagedPortfolio loads the portfolio for team management, which only its owner or an admin may do
+func (h *CollaboratorHandler) findManagedPortfolio(c *fiber.Ctx) (*domain.Portfolio, bool) {
+	portfolio, ok := h.findPortfolio(c)
+	if !ok {
+		return nil, false
+	}
+	currentUserID := middleware.GetUserID(c)
+	isOwner := currentUserID != nil && *currentUserID == portfolio.UserID
+	isAdmin := middleware.GetUserRole(c) == "admin"
+	if !isOwner && !isAdmin {
+		c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse("FORBIDDEN", "Hanya owner yang dapat mengatur anggota tim"))
+		return nil, false
+	}
+	return portfolio, true
+}
+
+func (h *CollaboratorHandler) findCollaborator(c *fiber.Ctx, portfolio *domain.Portfolio) (*domain.PortfolioCollaborator, bool) {
+	id, err := uuid.Parse(c.Params("collaborator_id"))
+	if err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "ID anggota tim tidak valid"))
+		return nil, false
+	}
+	collaborator, err := h.collabRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && collaborator.PortfolioID != portfolio.ID) {
+		c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("COLLABORATOR_NOT_FOUND", "Anggota tim tidak ditemukan"))
+		return nil, false
+	}
+	if err != nil {
+		c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil anggota tim"))
+		return nil, false
+	}
+	return collaborator, true
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/grafikarsa/backend/internal/domain"
@@ -40,6 +42,9 @@ func (h *CommentHandler) Create(c *fiber.Ctx) error {
 	}
 
 	comment, err := h.service.Create(userID, req)
+	if errors.Is(err, service.ErrContentBlocked) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("CONTENT_BLOCKED", "Komentar mengandung kata yang tidak diperbolehkan"))
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", err.Error()))
 	}
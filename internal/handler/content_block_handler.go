@@ -1,20 +1,37 @@
 package handler
 
 import (
+	"errors"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/grafikarsa/backend/internal/domain"
 	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/embed"
 	"github.com/grafikarsa/backend/internal/middleware"
 	"github.com/grafikarsa/backend/internal/repository"
+	"github.com/grafikarsa/backend/internal/service"
 )
 
 type ContentBlockHandler struct {
-	portfolioRepo *repository.PortfolioRepository
+	portfolioRepo   *repository.PortfolioRepository
+	draftService    *service.DraftService
+	collabService   *service.CollaborationService
+	templateService *service.SeriesTemplateService
+	embedService    *service.EmbedService
+	textFilter      *service.TextFilterService
 }
 
-func NewContentBlockHandler(portfolioRepo *repository.PortfolioRepository) *ContentBlockHandler {
-	return &ContentBlockHandler{portfolioRepo: portfolioRepo}
+func NewContentBlockHandler(portfolioRepo *repository.PortfolioRepository, draftService *service.DraftService, collabService *service.CollaborationService, templateService *service.SeriesTemplateService, embedService *service.EmbedService, textFilter *service.TextFilterService) *ContentBlockHandler {
+	return &ContentBlockHandler{
+		portfolioRepo:   portfolioRepo,
+		draftService:    draftService,
+		collabService:   collabService,
+		templateService: templateService,
+		embedService:    embedService,
+		textFilter:      textFilter,
+	}
 }
 
 func (h *ContentBlockHandler) Create(c *fiber.Ctx) error {
@@ -33,9 +50,9 @@ func (h *ContentBlockHandler) Create(c *fiber.Ctx) error {
 		))
 	}
 
-	isOwner := currentUserID != nil && *currentUserID == portfolio.UserID
+	canEdit := h.collabService.CanEdit(portfolio, currentUserID)
 	isAdmin := middleware.GetUserRole(c) == "admin"
-	if !isOwner && !isAdmin {
+	if !canEdit && !isAdmin {
 		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse(
 			"FORBIDDEN", "Anda tidak memiliki akses",
 		))
@@ -48,6 +65,33 @@ func (h *ContentBlockHandler) Create(c *fiber.Ctx) error {
 		))
 	}
 
+	if rejectInvalidPayload(c, domain.ContentBlockType(req.BlockType), req.Payload) {
+		return nil
+	}
+	embedResult, ok := h.resolveEmbed(c, domain.ContentBlockType(req.BlockType), req.Payload)
+	if !ok {
+		return nil
+	}
+	filtered := h.textFilter.ApplyPayload(domain.ContentBlockType(req.BlockType), req.Payload)
+	if filtered.Blocked {
+		return contentBlocked(c, "payload")
+	}
+
+	if portfolio.IsApproved() {
+		draft, ok := openDraft(c, h.draftService, portfolio)
+		if !ok {
+			return nil
+		}
+		block, err := h.draftService.AddBlock(draft, domain.ContentBlockType(req.BlockType), req.BlockOrder, req.Payload)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
+				"INTERNAL_ERROR", "Gagal membuat content block",
+			))
+		}
+		h.textFilter.Flag(domain.ReportTargetPortfolio, portfolio.ID, portfolio.UserID, filtered)
+		return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse(dto.SnapshotBlockToDTO(block), "Content block ditambahkan ke draft"))
+	}
+
 	// Get max order
 	maxOrder, _ := h.portfolioRepo.GetMaxBlockOrder(portfolioID)
 	blockOrder := maxOrder + 1
@@ -61,20 +105,16 @@ func (h *ContentBlockHandler) Create(c *fiber.Ctx) error {
 		BlockOrder:  blockOrder,
 		Payload:     req.Payload,
 	}
+	service.CacheEmbed(block, embedResult, time.Now())
 
 	if err := h.portfolioRepo.CreateContentBlock(block); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
 			"INTERNAL_ERROR", "Gagal membuat content block",
 		))
 	}
+	h.textFilter.Flag(domain.ReportTargetPortfolio, portfolio.ID, portfolio.UserID, filtered)
 
-	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse(dto.ContentBlockDTO{
-		ID:         block.ID,
-		BlockType:  string(block.BlockType),
-		BlockOrder: block.BlockOrder,
-		Payload:    block.Payload,
-		CreatedAt:  block.CreatedAt,
-	}, "Content block berhasil ditambahkan"))
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse(dto.ContentBlockToDTO(*block), "Content block berhasil ditambahkan"))
 }
 
 func (h *ContentBlockHandler) Update(c *fiber.Ctx) error {
@@ -100,14 +140,18 @@ func (h *ContentBlockHandler) Update(c *fiber.Ctx) error {
 		))
 	}
 
-	isOwner := currentUserID != nil && *currentUserID == portfolio.UserID
+	canEdit := h.collabService.CanEdit(portfolio, currentUserID)
 	isAdmin := middleware.GetUserRole(c) == "admin"
-	if !isOwner && !isAdmin {
+	if !canEdit && !isAdmin {
 		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse(
 			"FORBIDDEN", "Anda tidak memiliki akses",
 		))
 	}
 
+	if portfolio.IsApproved() {
+		return h.updateDraftBlock(c, portfolio, blockID)
+	}
+
 	block, err := h.portfolioRepo.FindContentBlockByID(blockID)
 	if err != nil || block.PortfolioID != portfolioID {
 		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse(
@@ -122,8 +166,21 @@ func (h *ContentBlockHandler) Update(c *fiber.Ctx) error {
 		))
 	}
 
+	var filtered service.TextFilterResult
 	if req.Payload != nil {
+		if rejectInvalidPayload(c, block.BlockType, req.Payload) {
+			return nil
+		}
+		embedResult, ok := h.resolveEmbed(c, block.BlockType, req.Payload)
+		if !ok {
+			return nil
+		}
+		filtered = h.textFilter.ApplyPayload(block.BlockType, req.Payload)
+		if filtered.Blocked {
+			return contentBlocked(c, "payload")
+		}
 		block.Payload = req.Payload
+		service.CacheEmbed(block, embedResult, time.Now())
 	}
 
 	if err := h.portfolioRepo.UpdateContentBlock(block); err != nil {
@@ -131,14 +188,9 @@ func (h *ContentBlockHandler) Update(c *fiber.Ctx) error {
 			"INTERNAL_ERROR", "Gagal memperbarui content block",
 		))
 	}
+	h.textFilter.Flag(domain.ReportTargetPortfolio, portfolio.ID, portfolio.UserID, filtered)
 
-	return c.JSON(dto.SuccessResponse(dto.ContentBlockDTO{
-		ID:         block.ID,
-		BlockType:  string(block.BlockType),
-		BlockOrder: block.BlockOrder,
-		Payload:    block.Payload,
-		UpdatedAt:  block.UpdatedAt,
-	}, "Content block berhasil diperbarui"))
+	return c.JSON(dto.SuccessResponse(dto.ContentBlockToDTO(*block), "Content block berhasil diperbarui"))
 }
 
 func (h *ContentBlockHandler) Delete(c *fiber.Ctx) error {
@@ -164,14 +216,30 @@ func (h *ContentBlockHandler) Delete(c *fiber.Ctx) error {
 		))
 	}
 
-	isOwner := currentUserID != nil && *currentUserID == portfolio.UserID
+	canEdit := h.collabService.CanEdit(portfolio, currentUserID)
 	isAdmin := middleware.GetUserRole(c) == "admin"
-	if !isOwner && !isAdmin {
+	if !canEdit && !isAdmin {
 		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse(
 			"FORBIDDEN", "Anda tidak memiliki akses",
 		))
 	}
 
+	if portfolio.IsApproved() {
+		draft, ok := openDraft(c, h.draftService, portfolio)
+		if !ok {
+			return nil
+		}
+		for _, b := range draft.Content.Blocks {
+			if b.ID == blockID && h.rejectRequiredBlock(c, draft.Content.SeriesID, b.SeriesBlockID) {
+				return nil
+			}
+		}
+		if err := h.draftService.DeleteBlock(draft, blockID); err != nil {
+			return draftBlockError(c, err, "Gagal menghapus content block")
+		}
+		return c.JSON(dto.SuccessResponse(nil, "Content block dihapus dari draft"))
+	}
+
 	block, err := h.portfolioRepo.FindContentBlockByID(blockID)
 	if err != nil || block.PortfolioID != portfolioID {
 		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse(
@@ -179,6 +247,10 @@ func (h *ContentBlockHandler) Delete(c *fiber.Ctx) error {
 		))
 	}
 
+	if h.rejectRequiredBlock(c, portfolio.SeriesID, block.SeriesBlockID) {
+		return nil
+	}
+
 	if err := h.portfolioRepo.DeleteContentBlock(blockID); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
 			"INTERNAL_ERROR", "Gagal menghapus content block",
@@ -204,9 +276,9 @@ func (h *ContentBlockHandler) Reorder(c *fiber.Ctx) error {
 		))
 	}
 
-	isOwner := currentUserID != nil && *currentUserID == portfolio.UserID
+	canEdit := h.collabService.CanEdit(portfolio, currentUserID)
 	isAdmin := middleware.GetUserRole(c) == "admin"
-	if !isOwner && !isAdmin {
+	if !canEdit && !isAdmin {
 		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse(
 			"FORBIDDEN", "Anda tidak memiliki akses",
 		))
@@ -224,6 +296,19 @@ func (h *ContentBlockHandler) Reorder(c *fiber.Ctx) error {
 		orders[item.ID] = item.Order
 	}
 
+	if portfolio.IsApproved() {
+		draft, ok := openDraft(c, h.draftService, portfolio)
+		if !ok {
+			return nil
+		}
+		if err := h.draftService.ReorderBlocks(draft, orders); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
+				"INTERNAL_ERROR", "Gagal mengubah urutan content blocks",
+			))
+		}
+		return c.JSON(dto.SuccessResponse(nil, "Urutan content blocks pada draft berhasil diperbarui"))
+	}
+
 	if err := h.portfolioRepo.ReorderContentBlocks(portfolioID, orders); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
 			"INTERNAL_ERROR", "Gagal mengubah urutan content blocks",
@@ -232,3 +317,96 @@ func (h *ContentBlockHandler) Reorder(c *fiber.Ctx) error {
 
 	return c.JSON(dto.SuccessResponse(nil, "Urutan content blocks berhasil diperbarui"))
 }
+
+// updateDraftBlock applies a block update to the working draft of a published portfolio
+func (h *ContentBlockHandler) updateDraftBlock(c *fiber.Ctx, portfolio *domain.Portfolio, blockID uuid.UUID) error {
+	var req dto.UpdateContentBlockRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse(
+			"VALIDATION_ERROR", "Request body tidak valid",
+		))
+	}
+
+	draft, ok := openDraft(c, h.draftService, portfolio)
+	if !ok {
+		return nil
+	}
+	var filtered service.TextFilterResult
+	if req.Payload != nil {
+		for _, b := range draft.Content.Blocks {
+			if b.ID != blockID {
+				continue
+			}
+			if rejectInvalidPayload(c, b.BlockType, req.Payload) {
+				return nil
+			}
+			if _, ok := h.resolveEmbed(c, b.BlockType, req.Payload); !ok {
+				return nil
+			}
+			if filtered = h.textFilter.ApplyPayload(b.BlockType, req.Payload); filtered.Blocked {
+				return contentBlocked(c, "payload")
+			}
+		}
+	}
+	block, err := h.draftService.UpdateBlock(draft, blockID, req.Payload)
+	if err != nil {
+		return draftBlockError(c, err, "Gagal memperbarui content block")
+	}
+	h.textFilter.Flag(domain.ReportTargetPortfolio, portfolio.ID, portfolio.UserID, filtered)
+
+	return c.JSON(dto.SuccessResponse(dto.SnapshotBlockToDTO(block), "Content block pada draft berhasil diperbarui"))
+}
+
+// rejectRequiredBlock answers 409 when the block fills a required block of the series template
+func (h *ContentBlockHandler) rejectRequiredBlock(c *fiber.Ctx, seriesID, seriesBlockID *uuid.UUID) bool {
+	required, err := h.templateService.IsRequired(seriesID, seriesBlockID)
+	if err != nil {
+		c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
+			"INTERNAL_ERROR", "Gagal menghapus content block",
+		))
+		return true
+	}
+	if required {
+		c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse(
+			"REQUIRED_TEMPLATE_BLOCK", "Block ini wajib dari template series dan tidak bisa dihapus",
+		))
+		return true
+	}
+	return false
+}
+
+// rejectInvalidPayload answers 422 with one detail per problem when payload does not match
+// the definition of blockType
+func rejectInvalidPayload(c *fiber.Ctx, blockType domain.ContentBlockType, payload domain.JSONB) bool {
+	details := service.ValidateBlockPayload(blockType, payload)
+	if len(details) == 0 {
+		return false
+	}
+	c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse(
+		"VALIDATION_ERROR", "Payload block tidak valid", details...,
+	))
+	return true
+}
+
+// resolveEmbed canonicalizes the external URL of payload and answers 422 when it is dead or private
+func (h *ContentBlockHandler) resolveEmbed(c *fiber.Ctx, blockType domain.ContentBlockType, payload domain.JSONB) (*embed.Result, bool) {
+	result, detail := h.embedService.ResolvePayload(c.UserContext(), blockType, payload)
+	if detail != nil {
+		c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse(
+			"EMBED_UNAVAILABLE", "Link pada block tidak bisa ditampilkan", *detail,
+		))
+		return nil, false
+	}
+	return result, true
+}
+
+func draftBlockError(c *fiber.Ctx, err error, message string) error {
+	if errors.Is(err, service.ErrDraftBlockNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse(
+			"NOT_FOUND", "Content block tidak ditemukan",
+		))
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
+		"INTERNAL_ERROR", message,
+	))
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"crypto/rand"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/xuri/excelize/v2"
+)
+
+// ============================================================================
+// CREDENTIAL SLIPS
+// ============================================================================
+
+// credentialSlipRetention bounds how long undownloaded initial passwords stay in memory
+const credentialSlipRetention = 24 * time.Hour
+
+// initialPasswordAlphabet leaves out characters that are easy to misread on paper (0/O, 1/l/I)
+const initialPasswordAlphabet = "abcdefghjkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+const initialPasswordLength = 10
+
+// generateInitialPassword returns a random password suitable for printing on a slip
+func generateInitialPassword() (string, error) {
+	max := big.NewInt(int64(len(initialPasswordAlphabet)))
+	b := make([]byte, initialPasswordLength)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = initialPasswordAlphabet[n.Int64()]
+	}
+	return string(b), nil
+}
+
+// credentialSlip is one user's login slip. Kelas groups the slips into sheets;
+// teacher slips leave NIS empty and are grouped under teacherSlipGroup.
+type credentialSlip struct {
+	Kelas    string
+	Nama     string
+	NIS      string
+	Username string
+	Password string
+}
+
+// teacherSlipGroup is the sheet name used for teacher slips
+const teacherSlipGroup = "Guru"
+
+type credentialBatch struct {
+	createdAt time.Time
+	byKelas   map[string][]credentialSlip
+}
+
+// CredentialSlipStore holds generated initial passwords until they are downloaded.
+// Each kelas can be downloaded exactly once; nothing is written to the database.
+type CredentialSlipStore struct {
+	mu      sync.Mutex
+	batches map[uuid.UUID]*credentialBatch
+}
+
+// NewCredentialSlipStore creates an empty store
+func NewCredentialSlipStore() *CredentialSlipStore {
+	return &CredentialSlipStore{batches: make(map[uuid.UUID]*credentialBatch)}
+}
+
+// Put stores slips as a new batch and returns its summary
+func (s *CredentialSlipStore) Put(slips []credentialSlip) *dto.CredentialSlipBatch {
+	if len(slips) == 0 {
+		return nil
+	}
+
+	batch := &credentialBatch{
+		createdAt: time.Now(),
+		byKelas:   make(map[string][]credentialSlip),
+	}
+	for _, slip := range slips {
+		batch.byKelas[slip.Kelas] = append(batch.byKelas[slip.Kelas], slip)
+	}
+
+	id := uuid.New()
+	s.mu.Lock()
+	s.pruneLocked()
+	s.batches[id] = batch
+	s.mu.Unlock()
+
+	return &dto.CredentialSlipBatch{
+		ID:        id,
+		Kelas:     sortedKelas(batch.byKelas),
+		Total:     len(slips),
+		ExpiresAt: batch.createdAt.Add(credentialSlipRetention),
+	}
+}
+
+// Take removes and returns the slips of one kelas, or of every remaining kelas when kelas is empty
+func (s *CredentialSlipStore) Take(id uuid.UUID, kelas string) (map[string][]credentialSlip, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pruneLocked()
+
+	batch, ok := s.batches[id]
+	if !ok {
+		return nil, false
+	}
+
+	taken := batch.byKelas
+	if kelas != "" {
+		slips, ok := batch.byKelas[kelas]
+		if !ok {
+			return nil, false
+		}
+		taken = map[string][]credentialSlip{kelas: slips}
+		delete(batch.byKelas, kelas)
+	} else {
+		batch.byKelas = nil
+	}
+
+	if len(batch.byKelas) == 0 {
+		delete(s.batches, id)
+	}
+	return taken, true
+}
+
+// pruneLocked drops batches past the retention window; caller holds s.mu
+func (s *CredentialSlipStore) pruneLocked() {
+	cutoff := time.Now().Add(-credentialSlipRetention)
+	for id, batch := range s.batches {
+		if batch.createdAt.Before(cutoff) {
+			delete(s.batches, id)
+		}
+	}
+}
+
+func sortedKelas(byKelas map[string][]credentialSlip) []string {
+	kelas := make([]string, 0, len(byKelas))
+	for k := range byKelas {
+		kelas = append(kelas, k)
+	}
+	sort.Strings(kelas)
+	return kelas
+}
+
+// buildCredentialSlipsXLSX lays out one printable sheet per kelas, one bordered slip per student
+func buildCredentialSlipsXLSX(byKelas map[string][]credentialSlip) (*excelize.File, error) {
+	xlsx := excelize.NewFile()
+
+	border := []excelize.Border{
+		{Type: "left", Color: "000000", Style: 1},
+		{Type: "right", Color: "000000", Style: 1},
+		{Type: "top", Color: "000000", Style: 1},
+		{Type: "bottom", Color: "000000", Style: 1},
+	}
+	titleStyle, err := xlsx.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}, Border: border})
+	if err != nil {
+		return nil, err
+	}
+	cellStyle, err := xlsx.NewStyle(&excelize.Style{Border: border})
+	if err != nil {
+		return nil, err
+	}
+	passwordStyle, err := xlsx.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Family: "Courier New"}, Border: border})
+	if err != nil {
+		return nil, err
+	}
+
+	defaultSheet := xlsx.GetSheetName(0)
+	for i, kelas := range sortedKelas(byKelas) {
+		sheet := kelas
+		if i == 0 {
+			if err := xlsx.SetSheetName(defaultSheet, sheet); err != nil {
+				return nil, err
+			}
+		} else if _, err := xlsx.NewSheet(sheet); err != nil {
+			return nil, err
+		}
+		_ = xlsx.SetColWidth(sheet, "A", "A", 18)
+		_ = xlsx.SetColWidth(sheet, "B", "B", 36)
+
+		slips := byKelas[kelas]
+		sort.Slice(slips, func(a, b int) bool { return slips[a].Nama < slips[b].Nama })
+
+		row := 1
+		for _, slip := range slips {
+			var lines [][]interface{}
+			if slip.NIS == "" {
+				lines = [][]interface{}{
+					{"GRAFIKARSA", "Slip Akun Guru"},
+					{"Nama", slip.Nama},
+				}
+			} else {
+				lines = [][]interface{}{
+					{"GRAFIKARSA", "Slip Akun Siswa"},
+					{"Kelas", slip.Kelas},
+					{"Nama", slip.Nama},
+					{"NIS", slip.NIS},
+				}
+			}
+			passwordRow := row + len(lines) + 1
+			lines = append(lines,
+				[]interface{}{"Username", slip.Username},
+				[]interface{}{"Password awal", slip.Password},
+				[]interface{}{"Catatan", "Wajib ganti password saat login pertama"},
+			)
+			for j, line := range lines {
+				cell, _ := excelize.CoordinatesToCellName(1, row+j)
+				_ = xlsx.SetSheetRow(sheet, cell, &line)
+			}
+
+			first, _ := excelize.CoordinatesToCellName(1, row)
+			last, _ := excelize.CoordinatesToCellName(2, row+len(lines)-1)
+			_ = xlsx.SetCellStyle(sheet, first, last, cellStyle)
+			titleEnd, _ := excelize.CoordinatesToCellName(2, row)
+			_ = xlsx.SetCellStyle(sheet, first, titleEnd, titleStyle)
+			passwordCell, _ := excelize.CoordinatesToCellName(2, passwordRow)
+			_ = xlsx.SetCellStyle(sheet, passwordCell, passwordCell, passwordStyle)
+
+			// Leave a blank row between slips as a cutting line
+			row += len(lines) + 1
+		}
+	}
+
+	return xlsx, nil
+}
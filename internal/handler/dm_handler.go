@@ -145,6 +145,15 @@ func (h *DMHandler) StartConversation(c *fiber.Ctx) error {
 				},
 			})
 		}
+		if err == service.ErrContentBlocked {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"success": false,
+				"error": fiber.Map{
+					"code":    "CONTENT_BLOCKED",
+					"message": "Pesan mengandung kata yang tidak diperbolehkan",
+				},
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
 			"error": fiber.Map{
@@ -526,6 +535,15 @@ func (h *DMHandler) SendMessage(c *fiber.Ctx) error {
 				},
 			})
 		}
+		if err == service.ErrContentBlocked {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"success": false,
+				"error": fiber.Map{
+					"code":    "CONTENT_BLOCKED",
+					"message": "Pesan mengandung kata yang tidak diperbolehkan",
+				},
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
 			"error": fiber.Map{
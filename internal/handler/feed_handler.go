@@ -61,13 +61,14 @@ func (h *FeedHandler) GetFeed(c *fiber.Ctx) error {
 	var total int64
 	var err error
 
+	viewer := currentViewer(c, h.userRepo)
 	switch algorithm {
 	case "recent":
-		feedItems, total, err = h.getRecentFeed(userID, page, limit)
+		feedItems, total, err = h.getRecentFeed(viewer, userID, page, limit)
 	case "following":
-		feedItems, total, err = h.getFollowingFeed(*userID, page, limit)
+		feedItems, total, err = h.getFollowingFeed(viewer, *userID, page, limit)
 	case "smart":
-		feedItems, total, err = h.getSmartFeed(*userID, page, limit)
+		feedItems, total, err = h.getSmartFeed(viewer, *userID, page, limit)
 	}
 
 	if err != nil {
@@ -90,7 +91,7 @@ func (h *FeedHandler) GetFeed(c *fiber.Ctx) error {
 }
 
 // getSmartFeed returns feed ranked by smart algorithm
-func (h *FeedHandler) getSmartFeed(userID uuid.UUID, page, limit int) ([]dto.FeedItemDTO, int64, error) {
+func (h *FeedHandler) getSmartFeed(viewer repository.Viewer, userID uuid.UUID, page, limit int) ([]dto.FeedItemDTO, int64, error) {
 	// Get user info for relevance calculation
 	user, _ := h.userRepo.FindByID(userID)
 	var userJurusanID, userKelasID *uuid.UUID
@@ -110,7 +111,7 @@ func (h *FeedHandler) getSmartFeed(userID uuid.UUID, page, limit int) ([]dto.Fee
 	if batchSize < 100 {
 		batchSize = 100
 	}
-	portfolios, err := h.feedRepo.GetPortfoliosForSmartFeed(userID, batchSize)
+	portfolios, err := h.feedRepo.GetPortfoliosForSmartFeed(viewer, userID, batchSize)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -138,8 +139,8 @@ func (h *FeedHandler) getSmartFeed(userID uuid.UUID, page, limit int) ([]dto.Fee
 }
 
 // getRecentFeed returns feed sorted by published_at
-func (h *FeedHandler) getRecentFeed(userID *uuid.UUID, page, limit int) ([]dto.FeedItemDTO, int64, error) {
-	portfolios, total, err := h.feedRepo.GetRecentFeed(page, limit)
+func (h *FeedHandler) getRecentFeed(viewer repository.Viewer, userID *uuid.UUID, page, limit int) ([]dto.FeedItemDTO, int64, error) {
+	portfolios, total, err := h.feedRepo.GetRecentFeed(viewer, page, limit)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -157,8 +158,8 @@ func (h *FeedHandler) getRecentFeed(userID *uuid.UUID, page, limit int) ([]dto.F
 }
 
 // getFollowingFeed returns feed from followed users
-func (h *FeedHandler) getFollowingFeed(userID uuid.UUID, page, limit int) ([]dto.FeedItemDTO, int64, error) {
-	portfolios, total, err := h.feedRepo.GetFollowingFeed(userID, page, limit)
+func (h *FeedHandler) getFollowingFeed(viewer repository.Viewer, userID uuid.UUID, page, limit int) ([]dto.FeedItemDTO, int64, error) {
+	portfolios, total, err := h.feedRepo.GetFollowingFeed(viewer, userID, page, limit)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/grafikarsa/backend/internal/dto"
+)
+
+// ============================================================================
+// ENTITY IMPORTS
+// ============================================================================
+
+type importAction string
+
+const (
+	importActionCreate    importAction = "create"
+	importActionUpdate    importAction = "update"
+	importActionUnchanged importAction = "unchanged"
+)
+
+// importStep is a validated row and the write it performs; apply is nil for unchanged rows
+type importStep struct {
+	Row    int
+	Label  string
+	Action importAction
+	apply  func() error
+}
+
+// importRun holds state shared by the steps of one import
+type importRun struct {
+	slips []credentialSlip
+}
+
+// entityImporter plugs one entity type into the generic import endpoints.
+// Plan must not write anything; all writes happen in the returned steps.
+type entityImporter interface {
+	// Columns is the template header; a first row starting with Columns()[0] is skipped
+	Columns() []string
+	// Example returns sample rows for the template
+	Example() [][]string
+	// Plan validates the rows and decides what each one will do
+	Plan(run *importRun, records []importRecord) ([]importStep, []dto.StudentImportError)
+}
+
+// importError builds a row error for entity imports
+func importError(row int, label, message string) dto.StudentImportError {
+	return dto.StudentImportError{Row: row, Nama: label, Error: message}
+}
+
+// duplicateRowError reports a row whose key already appeared earlier in the file
+func duplicateRowError(row int, label string, firstRow int) dto.StudentImportError {
+	return importError(row, label, "Duplikat dengan baris "+strconv.Itoa(firstRow))
+}
+
+// ImportEntity returns the upload handler for an entity import registered under entity.
+// Form fields: file (CSV/XLSX), dry_run=true to only validate and report.
+func (h *ImportHandler) ImportEntity(entity string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		importer, ok := h.importers[entity]
+		if !ok {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("UNKNOWN_ENTITY", "Jenis import tidak dikenal"))
+		}
+		dryRun := c.FormValue("dry_run") == "true"
+
+		records, readErrors, ok := h.readImportFile(c)
+		if !ok {
+			return nil
+		}
+		columns := importer.Columns()
+		if len(records) > 0 && records[0].Row == 1 && records[0].isHeader(columns[0]) {
+			records = records[1:]
+		}
+		if len(records) == 0 && len(readErrors) == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("EMPTY_FILE", "File tidak memiliki data"))
+		}
+
+		run := &importRun{}
+		steps, planErrors := importer.Plan(run, records)
+
+		result := dto.EntityImportResponse{
+			Entity:    entity,
+			DryRun:    dryRun,
+			TotalRows: len(records),
+			Rows:      make([]dto.EntityImportRowResult, 0, len(steps)),
+			Errors:    append(readErrors, planErrors...),
+		}
+		for _, step := range steps {
+			if !dryRun && step.apply != nil {
+				if err := step.apply(); err != nil {
+					result.Errors = append(result.Errors, importError(step.Row, step.Label, "Gagal menyimpan: "+err.Error()))
+					continue
+				}
+			}
+			switch step.Action {
+			case importActionCreate:
+				result.Created++
+			case importActionUpdate:
+				result.Updated++
+			default:
+				result.Unchanged++
+			}
+			result.Rows = append(result.Rows, dto.EntityImportRowResult{Row: step.Row, Label: step.Label, Action: string(step.Action)})
+		}
+		if result.Errors == nil {
+			result.Errors = []dto.StudentImportError{}
+		}
+
+		if dryRun {
+			return c.JSON(dto.SuccessResponse(result, "Dry run selesai"))
+		}
+		result.CredentialSlips = h.slips.Put(run.slips)
+		return c.JSON(dto.SuccessResponse(result, "Import selesai"))
+	}
+}
+
+// DownloadEntityTemplate returns the CSV template of an entity import
+func (h *ImportHandler) DownloadEntityTemplate(entity string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		importer, ok := h.importers[entity]
+		if !ok {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("UNKNOWN_ENTITY", "Jenis import tidak dikenal"))
+		}
+
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		_ = w.Write(importer.Columns())
+		_ = w.WriteAll(importer.Example())
+
+		c.Set("Content-Type", "text/csv")
+		c.Set("Content-Disposition", "attachment; filename=template_import_"+strings.ToLower(entity)+".csv")
+		return c.Send(buf.Bytes())
+	}
+}
@@ -0,0 +1,346 @@
+package handler
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	importUsernamePattern = regexp.MustCompile(`^[a-z0-9_.]{3,30}$`)
+	importRombelPattern   = regexp.MustCompile(`^[A-Z]$`)
+	importKodePattern     = regexp.MustCompile(`^[a-z0-9]{1,10}$`)
+)
+
+// ----------------------------------------------------------------------------
+// Jurusan: keyed by kode, nama is updated when it differs
+// ----------------------------------------------------------------------------
+
+type jurusanImporter struct {
+	adminRepo *repository.AdminRepository
+}
+
+func (i *jurusanImporter) Columns() []string { return []string{"kode", "nama"} }
+
+func (i *jurusanImporter) Example() [][]string {
+	return [][]string{
+		{"rpl", "Rekayasa Perangkat Lunak"},
+		{"dkv", "Desain Komunikasi Visual"},
+	}
+}
+
+func (i *jurusanImporter) Plan(run *importRun, records []importRecord) ([]importStep, []dto.StudentImportError) {
+	var steps []importStep
+	var errs []dto.StudentImportError
+	seen := make(map[string]int)
+
+	for _, rec := range records {
+		kode := strings.ToLower(rec.field(0))
+		nama := rec.field(1)
+		label := kode
+
+		switch {
+		case !importKodePattern.MatchString(kode):
+			errs = append(errs, importError(rec.Row, label, "Kode harus huruf kecil/angka, maksimal 10 karakter"))
+			continue
+		case nama == "" || utf8.RuneCountInString(nama) > 100:
+			errs = append(errs, importError(rec.Row, label, "Nama wajib diisi, maksimal 100 karakter"))
+			continue
+		}
+		if first, ok := seen[kode]; ok {
+			errs = append(errs, duplicateRowError(rec.Row, label, first))
+			continue
+		}
+		seen[kode] = rec.Row
+
+		existing, err := i.adminRepo.FindJurusanByKode(kode)
+		if err != nil {
+			steps = append(steps, importStep{Row: rec.Row, Label: label, Action: importActionCreate, apply: func() error {
+				return i.adminRepo.CreateJurusan(&domain.Jurusan{Kode: kode, Nama: nama})
+			}})
+			continue
+		}
+		if existing.Nama == nama {
+			steps = append(steps, importStep{Row: rec.Row, Label: label, Action: importActionUnchanged})
+			continue
+		}
+		steps = append(steps, importStep{Row: rec.Row, Label: label, Action: importActionUpdate, apply: func() error {
+			existing.Nama = nama
+			return i.adminRepo.UpdateJurusan(existing)
+		}})
+	}
+
+	return steps, errs
+}
+
+// ----------------------------------------------------------------------------
+// Tags: keyed by nama (case-insensitive), existing tags are left alone
+// ----------------------------------------------------------------------------
+
+type tagImporter struct {
+	adminRepo *repository.AdminRepository
+}
+
+func (i *tagImporter) Columns() []string { return []string{"nama"} }
+
+func (i *tagImporter) Example() [][]string {
+	return [][]string{{"UI/UX"}, {"Ilustrasi"}, {"Web Development"}}
+}
+
+func (i *tagImporter) Plan(run *importRun, records []importRecord) ([]importStep, []dto.StudentImportError) {
+	var steps []importStep
+	var errs []dto.StudentImportError
+	seen := make(map[string]int)
+
+	for _, rec := range records {
+		nama := rec.field(0)
+		if nama == "" || utf8.RuneCountInString(nama) > 50 {
+			errs = append(errs, importError(rec.Row, nama, "Nama tag wajib diisi, maksimal 50 karakter"))
+			continue
+		}
+		key := strings.ToLower(nama)
+		if first, ok := seen[key]; ok {
+			errs = append(errs, duplicateRowError(rec.Row, nama, first))
+			continue
+		}
+		seen[key] = rec.Row
+
+		if _, err := i.adminRepo.FindTagByNama(nama); err == nil {
+			steps = append(steps, importStep{Row: rec.Row, Label: nama, Action: importActionUnchanged})
+			continue
+		}
+		steps = append(steps, importStep{Row: rec.Row, Label: nama, Action: importActionCreate, apply: func() error {
+			return i.adminRepo.CreateTag(&domain.Tag{Nama: nama})
+		}})
+	}
+
+	return steps, errs
+}
+
+// ----------------------------------------------------------------------------
+// Kelas: keyed by tingkat/jurusan/rombel in the active tahun ajaran; an optional
+// wali kelas username is assigned to new classes and updated on existing ones
+// ----------------------------------------------------------------------------
+
+type kelasImporter struct {
+	adminRepo *repository.AdminRepository
+	userRepo  *repository.UserRepository
+}
+
+func (i *kelasImporter) Columns() []string {
+	return []string{"tingkat", "kode_jurusan", "rombel", "wali_kelas"}
+}
+
+func (i *kelasImporter) Example() [][]string {
+	return [][]string{
+		{"10", "rpl", "A", "budi.guru"},
+		{"10", "rpl", "B", ""},
+		{"11", "dkv", "A", "siti.guru"},
+	}
+}
+
+func (i *kelasImporter) Plan(run *importRun, records []importRecord) ([]importStep, []dto.StudentImportError) {
+	var steps []importStep
+	var errs []dto.StudentImportError
+
+	tahunAjaran, err := i.adminRepo.GetActiveTahunAjaran()
+	if err != nil {
+		return nil, []dto.StudentImportError{importError(0, "", "Tidak ada tahun ajaran aktif")}
+	}
+
+	seen := make(map[string]int)
+	for _, rec := range records {
+		tingkat, err := strconv.Atoi(rec.field(0))
+		kode := strings.ToLower(rec.field(1))
+		rombel := strings.ToUpper(rec.field(2))
+		waliUsername := strings.ToLower(rec.field(3))
+		label := generateKelasNama(tingkat, kode, rombel)
+
+		switch {
+		case err != nil || (tingkat != 10 && tingkat != 11 && tingkat != 12):
+			errs = append(errs, importError(rec.Row, label, "Tingkat harus 10, 11, atau 12"))
+			continue
+		case kode == "":
+			errs = append(errs, importError(rec.Row, label, "Kode jurusan tidak boleh kosong"))
+			continue
+		case !importRombelPattern.MatchString(rombel):
+			errs = append(errs, importError(rec.Row, label, "Rombel harus huruf A-Z"))
+			continue
+		}
+		key := kelasKey(tingkat, kode, rombel)
+		if first, ok := seen[key]; ok {
+			errs = append(errs, duplicateRowError(rec.Row, label, first))
+			continue
+		}
+		seen[key] = rec.Row
+
+		jurusan, err := i.adminRepo.FindJurusanByKode(kode)
+		if err != nil {
+			errs = append(errs, importError(rec.Row, label, "Kode jurusan '"+kode+"' tidak ditemukan"))
+			continue
+		}
+
+		var waliKelasID *uuid.UUID
+		if waliUsername != "" {
+			wali, err := i.userRepo.FindByUsername(waliUsername)
+			if err != nil || wali.Role != domain.RoleTeacher || !wali.IsActive {
+				errs = append(errs, importError(rec.Row, label, "Wali kelas '"+waliUsername+"' bukan guru aktif"))
+				continue
+			}
+			waliKelasID = &wali.ID
+		}
+
+		existing, err := i.adminRepo.FindKelasByTingkatJurusanRombel(tahunAjaran.ID, jurusan.ID, tingkat, rombel)
+		if err != nil {
+			kelas := &domain.Kelas{
+				TahunAjaranID: tahunAjaran.ID,
+				JurusanID:     jurusan.ID,
+				Tingkat:       tingkat,
+				Rombel:        rombel,
+				Nama:          label,
+				WaliKelasID:   waliKelasID,
+			}
+			steps = append(steps, importStep{Row: rec.Row, Label: label, Action: importActionCreate, apply: func() error {
+				return i.adminRepo.CreateKelas(kelas)
+			}})
+			continue
+		}
+
+		// An empty wali_kelas column never removes an existing wali kelas
+		if waliKelasID == nil || (existing.WaliKelasID != nil && *existing.WaliKelasID == *waliKelasID) {
+			steps = append(steps, importStep{Row: rec.Row, Label: existing.Nama, Action: importActionUnchanged})
+			continue
+		}
+		steps = append(steps, importStep{Row: rec.Row, Label: existing.Nama, Action: importActionUpdate, apply: func() error {
+			existing.WaliKelasID = waliKelasID
+			return i.adminRepo.UpdateKelas(existing)
+		}})
+	}
+
+	return steps, errs
+}
+
+// ----------------------------------------------------------------------------
+// Teachers: keyed by username; new accounts get a random initial password on a
+// credential slip, existing teachers have nama and email updated
+// ----------------------------------------------------------------------------
+
+type teacherImporter struct {
+	userRepo *repository.UserRepository
+}
+
+func (i *teacherImporter) Columns() []string { return []string{"nama", "username", "email"} }
+
+func (i *teacherImporter) Example() [][]string {
+	return [][]string{
+		{"Budi Hartono, S.Kom.", "budi.guru", "budi.hartono@smk.sch.id"},
+		{"Siti Rahma, S.Pd.", "siti.guru", "siti.rahma@smk.sch.id"},
+	}
+}
+
+func (i *teacherImporter) Plan(run *importRun, records []importRecord) ([]importStep, []dto.StudentImportError) {
+	var steps []importStep
+	var errs []dto.StudentImportError
+	seenUsername := make(map[string]int)
+	seenEmail := make(map[string]int)
+
+	for _, rec := range records {
+		nama := rec.field(0)
+		username := strings.ToLower(rec.field(1))
+		email := strings.ToLower(rec.field(2))
+		label := username
+
+		switch {
+		case nama == "" || utf8.RuneCountInString(nama) > 100:
+			errs = append(errs, importError(rec.Row, label, "Nama wajib diisi, maksimal 100 karakter"))
+			continue
+		case !importUsernamePattern.MatchString(username):
+			errs = append(errs, importError(rec.Row, label, "Username 3-30 karakter: huruf kecil, angka, titik atau garis bawah"))
+			continue
+		case !strings.Contains(email, "@"):
+			errs = append(errs, importError(rec.Row, label, "Format email tidak valid"))
+			continue
+		}
+		if first, ok := seenUsername[username]; ok {
+			errs = append(errs, duplicateRowError(rec.Row, label, first))
+			continue
+		}
+		if first, ok := seenEmail[email]; ok {
+			errs = append(errs, duplicateRowError(rec.Row, label, first))
+			continue
+		}
+		seenUsername[username] = rec.Row
+		seenEmail[email] = rec.Row
+
+		existing, err := i.userRepo.FindByUsername(username)
+		if err != nil {
+			if taken, _ := i.userRepo.EmailExists(email, nil); taken {
+				errs = append(errs, importError(rec.Row, label, "Email sudah terdaftar"))
+				continue
+			}
+			steps = append(steps, importStep{Row: rec.Row, Label: label, Action: importActionCreate, apply: func() error {
+				return i.createTeacher(run, nama, username, email)
+			}})
+			continue
+		}
+
+		if existing.Role != domain.RoleTeacher {
+			errs = append(errs, importError(rec.Row, label, "Username sudah dipakai oleh user yang bukan guru"))
+			continue
+		}
+		if existing.Nama == nama && existing.Email == email {
+			steps = append(steps, importStep{Row: rec.Row, Label: label, Action: importActionUnchanged})
+			continue
+		}
+		if taken, _ := i.userRepo.EmailExists(email, &existing.ID); taken {
+			errs = append(errs, importError(rec.Row, label, "Email sudah terdaftar"))
+			continue
+		}
+		userID := existing.ID
+		steps = append(steps, importStep{Row: rec.Row, Label: label, Action: importActionUpdate, apply: func() error {
+			return i.userRepo.UpdateFields(userID, map[string]interface{}{"nama": nama, "email": email})
+		}})
+	}
+
+	return steps, errs
+}
+
+func (i *teacherImporter) createTeacher(run *importRun, nama, username, email string) error {
+	password, err := generateInitialPassword()
+	if err != nil {
+		return errors.New("gagal membuat password")
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.New("gagal membuat password")
+	}
+
+	teacher := &domain.User{
+		Username:           username,
+		Email:              email,
+		PasswordHash:       string(hashed),
+		Nama:               nama,
+		Role:               domain.RoleTeacher,
+		IsActive:           true,
+		MustChangePassword: true,
+	}
+	if err := i.userRepo.Create(teacher); err != nil {
+		return err
+	}
+
+	run.slips = append(run.slips, credentialSlip{
+		Kelas:    teacherSlipGroup,
+		Nama:     nama,
+		Username: username,
+		Password: password,
+	})
+	return nil
+}
@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"context"
 	"encoding/csv"
 	"io"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/grafikarsa/backend/internal/domain"
 	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/middleware"
 	"github.com/grafikarsa/backend/internal/repository"
 	"github.com/xuri/excelize/v2"
 	"golang.org/x/crypto/bcrypt"
@@ -19,15 +22,47 @@ import (
 type ImportHandler struct {
 	adminRepo *repository.AdminRepository
 	userRepo  *repository.UserRepository
+	jobs      *ImportJobManager
+	slips     *CredentialSlipStore
+	importers map[string]entityImporter
 }
 
 func NewImportHandler(adminRepo *repository.AdminRepository, userRepo *repository.UserRepository) *ImportHandler {
 	return &ImportHandler{
 		adminRepo: adminRepo,
 		userRepo:  userRepo,
+		jobs:      NewImportJobManager(),
+		slips:     NewCredentialSlipStore(),
+		importers: map[string]entityImporter{
+			"jurusan":  &jurusanImporter{adminRepo: adminRepo},
+			"tags":     &tagImporter{adminRepo: adminRepo},
+			"kelas":    &kelasImporter{adminRepo: adminRepo, userRepo: userRepo},
+			"teachers": &teacherImporter{userRepo: userRepo},
+		},
 	}
 }
 
+const (
+	importModeCreate = "create"
+	importModeUpsert = "upsert"
+
+	// deactivate_scope values: which students a roster may deactivate
+	deactivateScopeKelas   = "kelas"
+	deactivateScopeJurusan = "jurusan"
+)
+
+// studentImportOptions carries the form options of an import run
+type studentImportOptions struct {
+	TahunAjaranID     uuid.UUID
+	DeactivateMissing bool
+	// DeactivateScope is deactivateScopeKelas (only the kelas named in the file) or
+	// deactivateScopeJurusan (every kelas of the jurusan named in the file)
+	DeactivateScope string
+	// DeactivateDespiteErrors lets a roster with row errors still deactivate missing students
+	DeactivateDespiteErrors bool
+	RandomPassword          bool
+}
+
 // StudentImportRow represents parsed row data
 type studentImportRow struct {
 	Row         int
@@ -36,6 +71,8 @@ type studentImportRow struct {
 	Rombel      string
 	Nama        string
 	NIS         string
+	NISN        string
+	Email       string
 }
 
 // ImportStudents handles student import from CSV/XLSX
@@ -43,42 +80,20 @@ func (h *ImportHandler) ImportStudents(c *fiber.Ctx) error {
 	// Check dry_run parameter
 	dryRun := c.FormValue("dry_run") == "true"
 
-	// Get uploaded file
-	file, err := c.FormFile("file")
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("INVALID_FILE", "File tidak ditemukan"))
-	}
-
-	// Check file size (max 5MB)
-	if file.Size > 5*1024*1024 {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("FILE_TOO_LARGE", "Ukuran file maksimal 5MB"))
-	}
-
-	// Detect file type
-	filename := strings.ToLower(file.Filename)
-	isCSV := strings.HasSuffix(filename, ".csv")
-	isXLSX := strings.HasSuffix(filename, ".xlsx")
-
-	if !isCSV && !isXLSX {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("INVALID_FILE_TYPE", "File harus berformat CSV atau XLSX"))
+	// mode=create (default) rejects known NIS, mode=upsert updates matching students
+	mode := c.FormValue("mode", importModeCreate)
+	if mode != importModeCreate && mode != importModeUpsert {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("INVALID_MODE", "Mode import harus create atau upsert"))
 	}
 
-	// Open file
-	f, err := file.Open()
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal membuka file"))
+	records, readErrors, ok := h.readImportFile(c)
+	if !ok {
+		return nil
 	}
-	defer f.Close()
 
 	// Parse rows
-	var rows []studentImportRow
-	var parseErrors []dto.StudentImportError
-
-	if isCSV {
-		rows, parseErrors = h.parseCSV(f)
-	} else {
-		rows, parseErrors = h.parseXLSX(f)
-	}
+	rows, parseErrors := h.parseStudentRecords(records)
+	parseErrors = slices.Concat(readErrors, parseErrors)
 
 	if len(rows) == 0 && len(parseErrors) == 0 {
 		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("EMPTY_FILE", "File tidak memiliki data"))
@@ -90,142 +105,197 @@ func (h *ImportHandler) ImportStudents(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("NO_ACTIVE_TAHUN_AJARAN", "Tidak ada tahun ajaran aktif"))
 	}
 
-	// Validate and process rows
-	validationErrors, classesToCreate, studentsToCreate := h.validateAndPrepare(rows, tahunAjaran.ID)
-
-	// Combine parse errors and validation errors
-	allErrors := append(parseErrors, validationErrors...)
+	opts := studentImportOptions{
+		TahunAjaranID:           tahunAjaran.ID,
+		DeactivateMissing:       c.FormValue("deactivate_missing") == "true",
+		DeactivateScope:         c.FormValue("deactivate_scope", deactivateScopeKelas),
+		DeactivateDespiteErrors: c.FormValue("deactivate_despite_errors") == "true",
+		RandomPassword:          c.FormValue("random_password") == "true",
+	}
+	if opts.DeactivateScope != deactivateScopeKelas && opts.DeactivateScope != deactivateScopeJurusan {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("INVALID_SCOPE", "Cakupan nonaktifkan harus kelas atau jurusan"))
+	}
 
 	if dryRun {
+		if mode == importModeUpsert {
+			return c.JSON(dto.SuccessResponse(h.dryRunUpsert(rows, parseErrors, opts), "Dry run selesai"))
+		}
+
+		// Validate and process rows
+		validationErrors, classesToCreate, studentsToCreate := h.validateAndPrepare(rows, tahunAjaran.ID)
+
 		// Return dry run response
 		return c.JSON(dto.SuccessResponse(dto.StudentImportDryRunResponse{
 			TotalRows:        len(rows),
 			ClassesToCreate:  classesToCreate,
 			StudentsToCreate: len(studentsToCreate),
-			ValidationErrors: allErrors,
+			ValidationErrors: slices.Concat(parseErrors, validationErrors),
 		}, "Dry run selesai"))
 	}
 
-	// Actual import
-	createdClasses := 0
-	createdStudents := 0
+	run := func(ctx context.Context, job *ImportJob) (interface{}, error) {
+		if mode == importModeUpsert {
+			return h.runUpsert(ctx, job, rows, parseErrors, opts), nil
+		}
+		return h.runCreate(ctx, job, rows, parseErrors, opts), nil
+	}
 
-	// Create classes first
-	classMap := make(map[string]uuid.UUID) // key: "tingkat-jurusan-rombel" -> kelas_id
+	// Imports always run as a background job, which also keeps them from racing each other;
+	// poll GET /admin/import/jobs/:id for the result
+	var createdBy uuid.UUID
+	if userID := middleware.GetUserID(c); userID != nil {
+		createdBy = *userID
+	}
+	job := h.jobs.Start(createdBy, mode, len(rows), parseErrors, run)
+	return c.Status(fiber.StatusAccepted).JSON(dto.SuccessResponse(job.ToResponse(), "Import sedang diproses"))
+}
 
-	for _, cls := range classesToCreate {
-		// Find jurusan
-		jurusan, err := h.adminRepo.FindJurusanByKode(cls.Jurusan)
-		if err != nil {
-			continue
-		}
+// runCreate imports rows as new students, rejecting any NIS or username already registered
+func (h *ImportHandler) runCreate(ctx context.Context, job *ImportJob, rows []studentImportRow, parseErrors []dto.StudentImportError, opts studentImportOptions) dto.StudentImportResponse {
+	// Validate and process rows
+	validationErrors, classesToCreate, studentsToCreate := h.validateAndPrepare(rows, opts.TahunAjaranID)
+	job.addProcessed(len(rows)-len(studentsToCreate), validationErrors...)
 
-		// Check if kelas already exists
-		existingKelas, err := h.adminRepo.FindKelasByTingkatJurusanRombel(tahunAjaran.ID, jurusan.ID, cls.Tingkat, cls.Rombel)
-		if err == nil && existingKelas != nil {
-			// Kelas already exists, use it
-			key := h.kelasKey(cls.Tingkat, cls.Jurusan, cls.Rombel)
-			classMap[key] = existingKelas.ID
-			continue
-		}
+	// Combine parse errors and validation errors
+	allErrors := slices.Concat(parseErrors, validationErrors)
 
-		// Create new kelas
-		newKelas := &domain.Kelas{
-			TahunAjaranID: tahunAjaran.ID,
-			JurusanID:     jurusan.ID,
-			Tingkat:       cls.Tingkat,
-			Rombel:        strings.ToUpper(cls.Rombel),
-		}
+	// Actual import
+	classMap, createdClasses := h.ensureClasses(classesToCreate, studentsToCreate, opts.TahunAjaranID)
+	createdStudents := 0
 
-		if err := h.adminRepo.CreateKelas(newKelas); err == nil {
-			key := h.kelasKey(cls.Tingkat, cls.Jurusan, cls.Rombel)
-			classMap[key] = newKelas.ID
-			createdClasses++
+	// Create students
+	var importErrors []dto.StudentImportError
+	var slips []credentialSlip
+	rowError := func(row studentImportRow, msg string) {
+		rowErr := dto.StudentImportError{
+			Row:   row.Row,
+			NIS:   row.NIS,
+			Nama:  row.Nama,
+			Error: msg,
 		}
+		importErrors = append(importErrors, rowErr)
+		job.addProcessed(1, rowErr)
 	}
 
-	// Also populate classMap with existing classes
 	for _, student := range studentsToCreate {
-		key := h.kelasKey(student.Tingkat, student.KodeJurusan, student.Rombel)
-		if _, exists := classMap[key]; !exists {
-			jurusan, err := h.adminRepo.FindJurusanByKode(student.KodeJurusan)
-			if err != nil {
-				continue
-			}
-			existingKelas, err := h.adminRepo.FindKelasByTingkatJurusanRombel(tahunAjaran.ID, jurusan.ID, student.Tingkat, student.Rombel)
-			if err == nil && existingKelas != nil {
-				classMap[key] = existingKelas.ID
-			}
+		if ctx.Err() != nil {
+			break
 		}
-	}
 
-	// Create students
-	var importErrors []dto.StudentImportError
-
-	for _, student := range studentsToCreate {
-		key := h.kelasKey(student.Tingkat, student.KodeJurusan, student.Rombel)
+		key := kelasKey(student.Tingkat, student.KodeJurusan, student.Rombel)
 		kelasID, exists := classMap[key]
 		if !exists {
-			importErrors = append(importErrors, dto.StudentImportError{
-				Row:   student.Row,
-				NIS:   student.NIS,
-				Nama:  student.Nama,
-				Error: "Kelas tidak ditemukan",
-			})
+			rowError(student, "Kelas tidak ditemukan")
 			continue
 		}
 
 		// Generate password hash
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(student.NIS), bcrypt.DefaultCost)
+		password, hashedPassword, err := h.initialPassword(student, opts.RandomPassword)
 		if err != nil {
-			importErrors = append(importErrors, dto.StudentImportError{
-				Row:   student.Row,
-				NIS:   student.NIS,
-				Nama:  student.Nama,
-				Error: "Gagal generate password",
-			})
+			rowError(student, "Gagal generate password")
 			continue
 		}
 
 		// Create user
-		newUser := &domain.User{
-			Username:     student.NIS,
-			Email:        student.NIS + "@grafikarsa.com",
-			PasswordHash: string(hashedPassword),
-			Nama:         student.Nama,
-			Role:         domain.RoleStudent,
-			NIS:          &student.NIS,
-			KelasID:      &kelasID,
-			IsActive:     true,
-		}
+		newUser := h.newStudent(student, kelasID, hashedPassword)
+		newUser.MustChangePassword = opts.RandomPassword
 
 		if err := h.userRepo.Create(newUser); err != nil {
-			importErrors = append(importErrors, dto.StudentImportError{
-				Row:   student.Row,
-				NIS:   student.NIS,
-				Nama:  student.Nama,
-				Error: "Gagal membuat user: " + err.Error(),
-			})
+			rowError(student, "Gagal membuat user: "+err.Error())
 			continue
 		}
 
+		if opts.RandomPassword {
+			slips = append(slips, h.credentialSlip(student, password))
+		}
 		createdStudents++
+		job.addProcessed(1)
 	}
 
 	// Combine all errors
 	allErrors = append(allErrors, importErrors...)
 
-	return c.JSON(dto.SuccessResponse(dto.StudentImportResponse{
+	return dto.StudentImportResponse{
 		TotalRows:       len(rows),
 		CreatedClasses:  createdClasses,
 		CreatedStudents: createdStudents,
 		Skipped:         len(allErrors),
 		Errors:          allErrors,
-	}, "Import selesai"))
+		CredentialSlips: h.slips.Put(slips),
+	}
 }
 
-func (h *ImportHandler) parseCSV(r io.Reader) ([]studentImportRow, []dto.StudentImportError) {
-	var rows []studentImportRow
+// importRecord is one raw data row of an uploaded CSV/XLSX file
+type importRecord struct {
+	Row    int
+	Fields []string
+}
+
+// field returns the trimmed value of column i, or "" when the row is shorter
+func (r importRecord) field(i int) string {
+	if i < len(r.Fields) {
+		return strings.TrimSpace(r.Fields[i])
+	}
+	return ""
+}
+
+// isHeader reports whether the record looks like a header row starting with one of the given column names
+func (r importRecord) isHeader(names ...string) bool {
+	first := strings.ToLower(r.field(0))
+	for _, name := range names {
+		if first == name {
+			return true
+		}
+	}
+	return false
+}
+
+// readImportFile validates the uploaded "file" form field and reads its rows.
+// It writes the error response itself and returns false when the upload is unusable.
+func (h *ImportHandler) readImportFile(c *fiber.Ctx) ([]importRecord, []dto.StudentImportError, bool) {
+	// Get uploaded file
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("INVALID_FILE", "File tidak ditemukan"))
+		return nil, nil, false
+	}
+
+	// Check file size (max 5MB)
+	if file.Size > 5*1024*1024 {
+		c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("FILE_TOO_LARGE", "Ukuran file maksimal 5MB"))
+		return nil, nil, false
+	}
+
+	// Detect file type
+	filename := strings.ToLower(file.Filename)
+	isCSV := strings.HasSuffix(filename, ".csv")
+	isXLSX := strings.HasSuffix(filename, ".xlsx")
+
+	if !isCSV && !isXLSX {
+		c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("INVALID_FILE_TYPE", "File harus berformat CSV atau XLSX"))
+		return nil, nil, false
+	}
+
+	// Open file
+	f, err := file.Open()
+	if err != nil {
+		c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal membuka file"))
+		return nil, nil, false
+	}
+	defer f.Close()
+
+	var records []importRecord
+	var readErrors []dto.StudentImportError
+	if isCSV {
+		records, readErrors = readCSVRecords(f)
+	} else {
+		records, readErrors = readXLSXRecords(f)
+	}
+	return records, readErrors, true
+}
+
+func readCSVRecords(r io.Reader) ([]importRecord, []dto.StudentImportError) {
+	var records []importRecord
 	var errors []dto.StudentImportError
 
 	reader := csv.NewReader(r)
@@ -247,25 +317,14 @@ func (h *ImportHandler) parseCSV(r io.Reader) ([]studentImportRow, []dto.Student
 			continue
 		}
 
-		// Skip header if detected
-		if rowNum == 1 && (strings.ToLower(record[0]) == "tingkat" || strings.ToLower(record[0]) == "kelas") {
-			continue
-		}
-
-		row, parseErr := h.parseRow(rowNum, record)
-		if parseErr != nil {
-			errors = append(errors, *parseErr)
-			continue
-		}
-
-		rows = append(rows, *row)
+		records = append(records, importRecord{Row: rowNum, Fields: record})
 	}
 
-	return rows, errors
+	return records, errors
 }
 
-func (h *ImportHandler) parseXLSX(r io.Reader) ([]studentImportRow, []dto.StudentImportError) {
-	var rows []studentImportRow
+func readXLSXRecords(r io.Reader) ([]importRecord, []dto.StudentImportError) {
+	var records []importRecord
 	var errors []dto.StudentImportError
 
 	xlsx, err := excelize.OpenReader(r)
@@ -274,7 +333,7 @@ func (h *ImportHandler) parseXLSX(r io.Reader) ([]studentImportRow, []dto.Studen
 			Row:   0,
 			Error: "Gagal membaca file XLSX",
 		})
-		return rows, errors
+		return records, errors
 	}
 	defer xlsx.Close()
 
@@ -285,7 +344,7 @@ func (h *ImportHandler) parseXLSX(r io.Reader) ([]studentImportRow, []dto.Studen
 			Row:   0,
 			Error: "File XLSX tidak memiliki sheet",
 		})
-		return rows, errors
+		return records, errors
 	}
 
 	xlsxRows, err := xlsx.GetRows(sheets[0])
@@ -294,18 +353,28 @@ func (h *ImportHandler) parseXLSX(r io.Reader) ([]studentImportRow, []dto.Studen
 			Row:   0,
 			Error: "Gagal membaca sheet",
 		})
-		return rows, errors
+		return records, errors
 	}
 
 	for i, record := range xlsxRows {
-		rowNum := i + 1
+		records = append(records, importRecord{Row: i + 1, Fields: record})
+	}
+
+	return records, errors
+}
+
+// parseStudentRecords converts raw records to student rows, skipping a leading header row
+func (h *ImportHandler) parseStudentRecords(records []importRecord) ([]studentImportRow, []dto.StudentImportError) {
+	var rows []studentImportRow
+	var errors []dto.StudentImportError
 
+	for i, record := range records {
 		// Skip header if detected
-		if rowNum == 1 && len(record) > 0 && (strings.ToLower(record[0]) == "tingkat" || strings.ToLower(record[0]) == "kelas") {
+		if i == 0 && record.Row == 1 && record.isHeader("tingkat", "kelas") {
 			continue
 		}
 
-		row, parseErr := h.parseRow(rowNum, record)
+		row, parseErr := h.parseRow(record.Row, record.Fields)
 		if parseErr != nil {
 			errors = append(errors, *parseErr)
 			continue
@@ -371,6 +440,32 @@ func (h *ImportHandler) parseRow(rowNum int, record []string) (*studentImportRow
 		}
 	}
 
+	// Parse optional NISN
+	var nisn string
+	if len(record) > 5 {
+		nisn = strings.TrimSpace(record[5])
+		if nisn != "" && !regexp.MustCompile(`^\d+$`).MatchString(nisn) {
+			return nil, &dto.StudentImportError{
+				Row:   rowNum,
+				NIS:   nis,
+				Error: "NISN harus berupa angka",
+			}
+		}
+	}
+
+	// Parse optional email
+	var email string
+	if len(record) > 6 {
+		email = strings.TrimSpace(strings.ToLower(record[6]))
+		if email != "" && !strings.Contains(email, "@") {
+			return nil, &dto.StudentImportError{
+				Row:   rowNum,
+				NIS:   nis,
+				Error: "Format email tidak valid",
+			}
+		}
+	}
+
 	return &studentImportRow{
 		Row:         rowNum,
 		Tingkat:     tingkat,
@@ -378,6 +473,8 @@ func (h *ImportHandler) parseRow(rowNum int, record []string) (*studentImportRow
 		Rombel:      rombel,
 		Nama:        nama,
 		NIS:         nis,
+		NISN:        nisn,
+		Email:       email,
 	}, nil
 }
 
@@ -468,10 +565,10 @@ func (h *ImportHandler) validateAndPrepare(rows []studentImportRow, tahunAjaranI
 		_, err = h.adminRepo.FindKelasByTingkatJurusanRombel(tahunAjaranID, jurusan.ID, row.Tingkat, row.Rombel)
 		if err != nil {
 			// Kelas doesn't exist, add to create list
-			key := h.kelasKey(row.Tingkat, row.KodeJurusan, row.Rombel)
+			key := kelasKey(row.Tingkat, row.KodeJurusan, row.Rombel)
 			if _, exists := classSet[key]; !exists {
 				classSet[key] = dto.ClassToCreate{
-					Nama:    h.generateKelasNama(row.Tingkat, row.KodeJurusan, row.Rombel),
+					Nama:    generateKelasNama(row.Tingkat, row.KodeJurusan, row.Rombel),
 					Tingkat: row.Tingkat,
 					Jurusan: row.KodeJurusan,
 					Rombel:  row.Rombel,
@@ -491,11 +588,469 @@ func (h *ImportHandler) validateAndPrepare(rows []studentImportRow, tahunAjaranI
 	return errors, classesToCreate, validStudents
 }
 
-func (h *ImportHandler) kelasKey(tingkat int, jurusan, rombel string) string {
+// ensureClasses creates missing classes and returns a kelasKey -> kelas_id map covering every student row
+func (h *ImportHandler) ensureClasses(classesToCreate []dto.ClassToCreate, students []studentImportRow, tahunAjaranID uuid.UUID) (map[string]uuid.UUID, int) {
+	createdClasses := 0
+	classMap := make(map[string]uuid.UUID) // key: "tingkat-jurusan-rombel" -> kelas_id
+
+	for _, cls := range classesToCreate {
+		// Find jurusan
+		jurusan, err := h.adminRepo.FindJurusanByKode(cls.Jurusan)
+		if err != nil {
+			continue
+		}
+
+		// Check if kelas already exists
+		existingKelas, err := h.adminRepo.FindKelasByTingkatJurusanRombel(tahunAjaranID, jurusan.ID, cls.Tingkat, cls.Rombel)
+		if err == nil && existingKelas != nil {
+			// Kelas already exists, use it
+			key := kelasKey(cls.Tingkat, cls.Jurusan, cls.Rombel)
+			classMap[key] = existingKelas.ID
+			continue
+		}
+
+		// Create new kelas
+		newKelas := &domain.Kelas{
+			TahunAjaranID: tahunAjaranID,
+			JurusanID:     jurusan.ID,
+			Tingkat:       cls.Tingkat,
+			Rombel:        strings.ToUpper(cls.Rombel),
+		}
+
+		if err := h.adminRepo.CreateKelas(newKelas); err == nil {
+			key := kelasKey(cls.Tingkat, cls.Jurusan, cls.Rombel)
+			classMap[key] = newKelas.ID
+			createdClasses++
+		}
+	}
+
+	// Also populate classMap with existing classes
+	for _, student := range students {
+		key := kelasKey(student.Tingkat, student.KodeJurusan, student.Rombel)
+		if _, exists := classMap[key]; !exists {
+			jurusan, err := h.adminRepo.FindJurusanByKode(student.KodeJurusan)
+			if err != nil {
+				continue
+			}
+			existingKelas, err := h.adminRepo.FindKelasByTingkatJurusanRombel(tahunAjaranID, jurusan.ID, student.Tingkat, student.Rombel)
+			if err == nil && existingKelas != nil {
+				classMap[key] = existingKelas.ID
+			}
+		}
+	}
+
+	return classMap, createdClasses
+}
+
+// newStudent builds a student user from an import row; username and default password are the NIS
+func (h *ImportHandler) newStudent(row studentImportRow, kelasID uuid.UUID, passwordHash string) *domain.User {
+	nis := row.NIS
+	email := row.Email
+	if email == "" {
+		email = nis + "@grafikarsa.com"
+	}
+
+	user := &domain.User{
+		Username:     nis,
+		Email:        email,
+		PasswordHash: passwordHash,
+		Nama:         row.Nama,
+		Role:         domain.RoleStudent,
+		NIS:          &nis,
+		KelasID:      &kelasID,
+		IsActive:     true,
+	}
+	if row.NISN != "" {
+		nisn := row.NISN
+		user.NISN = &nisn
+	}
+	return user
+}
+
+// initialPassword returns the plain and hashed initial password for a new student:
+// the NIS by default, or a random one printed on a credential slip
+func (h *ImportHandler) initialPassword(row studentImportRow, random bool) (string, string, error) {
+	password := row.NIS
+	if random {
+		var err error
+		if password, err = generateInitialPassword(); err != nil {
+			return "", "", err
+		}
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", "", err
+	}
+	return password, string(hashed), nil
+}
+
+func (h *ImportHandler) credentialSlip(row studentImportRow, password string) credentialSlip {
+	return credentialSlip{
+		Kelas:    generateKelasNama(row.Tingkat, row.KodeJurusan, row.Rombel),
+		Nama:     row.Nama,
+		NIS:      row.NIS,
+		Username: row.NIS,
+		Password: password,
+	}
+}
+
+// studentUpsertRow is a validated upsert row, matched to an existing student when Existing is set
+type studentUpsertRow struct {
+	studentImportRow
+	Existing *domain.User
+	KelasID  *uuid.UUID // nil when the kelas still has to be created
+}
+
+// countUpsert tallies prepared rows into new, changed and unchanged students
+func (h *ImportHandler) countUpsert(prepared []studentUpsertRow) (toCreate, toUpdate, unchanged int) {
+	for _, row := range prepared {
+		if row.Existing == nil {
+			toCreate++
+			continue
+		}
+		if len(h.diffImportedStudent(row.Existing, row.studentImportRow, row.KelasID)) > 0 {
+			toUpdate++
+		} else {
+			unchanged++
+		}
+	}
+	return toCreate, toUpdate, unchanged
+}
+
+// rosterSync works out whom a deactivate_missing run keeps and where it may deactivate.
+// Every row naming an existing student keeps that student, even a row that failed
+// validation, and only the kelas or jurusan named in the file are touched.
+func (h *ImportHandler) rosterSync(rows []studentImportRow, opts studentImportOptions) ([]uuid.UUID, repository.StudentScope) {
+	var nisList, nisnList []string
+	for _, row := range rows {
+		nisList = append(nisList, row.NIS)
+		if row.NISN != "" {
+			nisnList = append(nisnList, row.NISN)
+		}
+	}
+	var keepIDs []uuid.UUID
+	existing, _ := h.adminRepo.FindStudentsByNISOrNISN(nisList, nisnList)
+	for _, user := range existing {
+		if user.Role == domain.RoleStudent {
+			keepIDs = append(keepIDs, user.ID)
+		}
+	}
+
+	var scope repository.StudentScope
+	jurusanByKode := make(map[string]*domain.Jurusan)
+	seenKelas := make(map[uuid.UUID]bool)
+	for _, row := range rows {
+		jurusan, seen := jurusanByKode[row.KodeJurusan]
+		if !seen {
+			jurusan, _ = h.adminRepo.FindJurusanByKode(row.KodeJurusan)
+			jurusanByKode[row.KodeJurusan] = jurusan
+			if jurusan != nil && opts.DeactivateScope == deactivateScopeJurusan {
+				scope.JurusanIDs = append(scope.JurusanIDs, jurusan.ID)
+			}
+		}
+		if jurusan == nil || opts.DeactivateScope == deactivateScopeJurusan {
+			continue
+		}
+		kelas, err := h.adminRepo.FindKelasByTingkatJurusanRombel(opts.TahunAjaranID, jurusan.ID, row.Tingkat, row.Rombel)
+		if err == nil && kelas != nil && !seenKelas[kelas.ID] {
+			seenKelas[kelas.ID] = true
+			scope.KelasIDs = append(scope.KelasIDs, kelas.ID)
+		}
+	}
+	return keepIDs, scope
+}
+
+// dryRunUpsert reports what an upsert import would change without writing anything
+func (h *ImportHandler) dryRunUpsert(rows []studentImportRow, parseErrors []dto.StudentImportError, opts studentImportOptions) dto.StudentUpsertDryRunResponse {
+	validationErrors, classesToCreate, prepared := h.validateUpsert(rows, opts.TahunAjaranID)
+	toCreate, toUpdate, unchanged := h.countUpsert(prepared)
+	allErrors := slices.Concat(parseErrors, validationErrors)
+
+	var toDeactivate int64
+	var blocked bool
+	if opts.DeactivateMissing {
+		keepIDs, scope := h.rosterSync(rows, opts)
+		toDeactivate, _ = h.adminRepo.CountActiveStudentsExcept(keepIDs, scope)
+		blocked = len(allErrors) > 0 && !opts.DeactivateDespiteErrors
+	}
+
+	return dto.StudentUpsertDryRunResponse{
+		TotalRows:            len(rows),
+		ClassesToCreate:      classesToCreate,
+		StudentsToCreate:     toCreate,
+		StudentsToUpdate:     toUpdate,
+		StudentsUnchanged:    unchanged,
+		StudentsToDeactivate: toDeactivate,
+		DeactivationBlocked:  blocked,
+		ValidationErrors:     allErrors,
+	}
+}
+
+// runUpsert matches rows to existing students by NIS or NISN, updates them in place
+// and creates the rest, optionally deactivating active students missing from the roster
+func (h *ImportHandler) runUpsert(ctx context.Context, job *ImportJob, rows []studentImportRow, parseErrors []dto.StudentImportError, opts studentImportOptions) dto.StudentUpsertResponse {
+	validationErrors, classesToCreate, prepared := h.validateUpsert(rows, opts.TahunAjaranID)
+	job.addProcessed(len(rows)-len(prepared), validationErrors...)
+	allErrors := slices.Concat(parseErrors, validationErrors)
+	var keepIDs []uuid.UUID
+	var scope repository.StudentScope
+	if opts.DeactivateMissing {
+		keepIDs, scope = h.rosterSync(rows, opts)
+	}
+
+	students := make([]studentImportRow, 0, len(prepared))
+	for _, row := range prepared {
+		students = append(students, row.studentImportRow)
+	}
+	classMap, createdClasses := h.ensureClasses(classesToCreate, students, opts.TahunAjaranID)
+
+	result := dto.StudentUpsertResponse{
+		TotalRows:      len(rows),
+		CreatedClasses: createdClasses,
+	}
+	var importErrors []dto.StudentImportError
+	var slips []credentialSlip
+	rowError := func(row studentImportRow, msg string) {
+		rowErr := dto.StudentImportError{
+			Row:   row.Row,
+			NIS:   row.NIS,
+			Nama:  row.Nama,
+			Error: msg,
+		}
+		importErrors = append(importErrors, rowErr)
+		job.addProcessed(1, rowErr)
+	}
+
+	for _, row := range prepared {
+		if ctx.Err() != nil {
+			break
+		}
+
+		kelasID, exists := classMap[kelasKey(row.Tingkat, row.KodeJurusan, row.Rombel)]
+		if !exists {
+			rowError(row.studentImportRow, "Kelas tidak ditemukan")
+			continue
+		}
+
+		if row.Existing != nil {
+			updates := h.diffImportedStudent(row.Existing, row.studentImportRow, &kelasID)
+			if len(updates) == 0 {
+				result.Unchanged++
+				job.addProcessed(1)
+				continue
+			}
+
+			var movedTo *uuid.UUID
+			if _, ok := updates["kelas_id"]; ok {
+				movedTo = &kelasID
+			}
+			if err := h.adminRepo.UpdateImportedStudent(row.Existing.ID, updates, movedTo, opts.TahunAjaranID); err != nil {
+				rowError(row.studentImportRow, "Gagal memperbarui user: "+err.Error())
+				continue
+			}
+			result.Updated++
+			job.addProcessed(1)
+			continue
+		}
+
+		password, hashedPassword, err := h.initialPassword(row.studentImportRow, opts.RandomPassword)
+		if err != nil {
+			rowError(row.studentImportRow, "Gagal generate password")
+			continue
+		}
+
+		newUser := h.newStudent(row.studentImportRow, kelasID, hashedPassword)
+		newUser.MustChangePassword = opts.RandomPassword
+		if err := h.userRepo.Create(newUser); err != nil {
+			rowError(row.studentImportRow, "Gagal membuat user: "+err.Error())
+			continue
+		}
+		if opts.RandomPassword {
+			slips = append(slips, h.credentialSlip(row.studentImportRow, password))
+		}
+		keepIDs = append(keepIDs, newUser.ID)
+		result.Created++
+		job.addProcessed(1)
+	}
+
+	allErrors = append(allErrors, importErrors...)
+
+	// A cancelled run has not seen the whole roster, so nobody may be deactivated; neither
+	// may a roster with row errors unless the admin insists
+	if opts.DeactivateMissing && ctx.Err() == nil {
+		if len(allErrors) > 0 && !opts.DeactivateDespiteErrors {
+			result.DeactivationBlocked = true
+		} else {
+			result.Deactivated, _ = h.adminRepo.DeactivateStudentsExcept(keepIDs, scope)
+		}
+	}
+	result.Skipped = len(allErrors)
+	result.Errors = allErrors
+	result.CredentialSlips = h.slips.Put(slips)
+
+	return result
+}
+
+// validateUpsert validates upsert rows and matches them against existing students by NIS, then NISN
+func (h *ImportHandler) validateUpsert(rows []studentImportRow, tahunAjaranID uuid.UUID) ([]dto.StudentImportError, []dto.ClassToCreate, []studentUpsertRow) {
+	var errors []dto.StudentImportError
+	var valid []studentUpsertRow
+
+	rowError := func(row studentImportRow, msg string) {
+		errors = append(errors, dto.StudentImportError{
+			Row:   row.Row,
+			NIS:   row.NIS,
+			Nama:  row.Nama,
+			Error: msg,
+		})
+	}
+
+	// Check for duplicate NIS/NISN in file
+	var nisList, nisnList []string
+	nisSet := make(map[string]int)
+	nisnSet := make(map[string]int)
+	var unique []studentImportRow
+	for _, row := range rows {
+		if firstRow, exists := nisSet[row.NIS]; exists {
+			rowError(row, "NIS duplikat dengan baris "+strconv.Itoa(firstRow))
+			continue
+		}
+		if row.NISN != "" {
+			if firstRow, exists := nisnSet[row.NISN]; exists {
+				rowError(row, "NISN duplikat dengan baris "+strconv.Itoa(firstRow))
+				continue
+			}
+			nisnSet[row.NISN] = row.Row
+			nisnList = append(nisnList, row.NISN)
+		}
+		nisSet[row.NIS] = row.Row
+		nisList = append(nisList, row.NIS)
+		unique = append(unique, row)
+	}
+
+	// Index existing users by NIS and NISN
+	existing, _ := h.adminRepo.FindStudentsByNISOrNISN(nisList, nisnList)
+	byNIS := make(map[string]*domain.User)
+	byNISN := make(map[string]*domain.User)
+	for i := range existing {
+		user := &existing[i]
+		if user.NIS != nil {
+			byNIS[*user.NIS] = user
+		}
+		if user.NISN != nil {
+			byNISN[*user.NISN] = user
+		}
+	}
+
+	existingUsernames, _ := h.adminRepo.FindExistingUsernames(nisList)
+	existingUsernameSet := make(map[string]bool)
+	for _, username := range existingUsernames {
+		existingUsernameSet[username] = true
+	}
+
+	classSet := make(map[string]dto.ClassToCreate)
+	matched := make(map[uuid.UUID]int)
+
+	for _, row := range unique {
+		user := byNIS[row.NIS]
+		if user == nil && row.NISN != "" {
+			user = byNISN[row.NISN]
+		}
+
+		if user != nil {
+			if user.Role != domain.RoleStudent {
+				rowError(row, "NIS terdaftar pada user yang bukan siswa")
+				continue
+			}
+			if firstRow, exists := matched[user.ID]; exists {
+				rowError(row, "Siswa yang sama sudah dicocokkan oleh baris "+strconv.Itoa(firstRow))
+				continue
+			}
+		} else if existingUsernameSet[row.NIS] {
+			rowError(row, "Username sudah terdaftar")
+			continue
+		}
+
+		if row.Email != "" {
+			var excludeID *uuid.UUID
+			if user != nil {
+				excludeID = &user.ID
+			}
+			if taken, _ := h.userRepo.EmailExists(row.Email, excludeID); taken {
+				rowError(row, "Email sudah terdaftar")
+				continue
+			}
+		}
+
+		// Validate jurusan exists
+		jurusan, err := h.adminRepo.FindJurusanByKode(row.KodeJurusan)
+		if err != nil {
+			rowError(row, "Kode jurusan '"+row.KodeJurusan+"' tidak ditemukan")
+			continue
+		}
+
+		prepared := studentUpsertRow{studentImportRow: row, Existing: user}
+		kelas, err := h.adminRepo.FindKelasByTingkatJurusanRombel(tahunAjaranID, jurusan.ID, row.Tingkat, row.Rombel)
+		if err == nil && kelas != nil {
+			prepared.KelasID = &kelas.ID
+		} else {
+			key := kelasKey(row.Tingkat, row.KodeJurusan, row.Rombel)
+			if _, exists := classSet[key]; !exists {
+				classSet[key] = dto.ClassToCreate{
+					Nama:    generateKelasNama(row.Tingkat, row.KodeJurusan, row.Rombel),
+					Tingkat: row.Tingkat,
+					Jurusan: row.KodeJurusan,
+					Rombel:  row.Rombel,
+				}
+			}
+		}
+
+		if user != nil {
+			matched[user.ID] = row.Row
+		}
+		valid = append(valid, prepared)
+	}
+
+	var classesToCreate []dto.ClassToCreate
+	for _, cls := range classSet {
+		classesToCreate = append(classesToCreate, cls)
+	}
+
+	return errors, classesToCreate, valid
+}
+
+// diffImportedStudent returns the columns of user that differ from the import row.
+// A nil kelasID means the target kelas does not exist yet and therefore always differs.
+func (h *ImportHandler) diffImportedStudent(user *domain.User, row studentImportRow, kelasID *uuid.UUID) map[string]interface{} {
+	updates := make(map[string]interface{})
+
+	if user.Nama != row.Nama {
+		updates["nama"] = row.Nama
+	}
+	if user.NIS == nil || *user.NIS != row.NIS {
+		updates["nis"] = row.NIS
+	}
+	if row.NISN != "" && (user.NISN == nil || *user.NISN != row.NISN) {
+		updates["nisn"] = row.NISN
+	}
+	if row.Email != "" && user.Email != row.Email {
+		updates["email"] = row.Email
+	}
+	if kelasID == nil || user.KelasID == nil || *user.KelasID != *kelasID {
+		updates["kelas_id"] = kelasID
+	}
+	if !user.IsActive {
+		updates["is_active"] = true
+	}
+
+	return updates
+}
+
+func kelasKey(tingkat int, jurusan, rombel string) string {
 	return strconv.Itoa(tingkat) + "-" + strings.ToLower(jurusan) + "-" + strings.ToUpper(rombel)
 }
 
-func (h *ImportHandler) generateKelasNama(tingkat int, jurusan, rombel string) string {
+func generateKelasNama(tingkat int, jurusan, rombel string) string {
 	tingkatRomawi := map[int]string{10: "X", 11: "XI", 12: "XII"}
 	return tingkatRomawi[tingkat] + "-" + strings.ToUpper(jurusan) + "-" + strings.ToUpper(rombel)
 }
@@ -505,10 +1060,111 @@ func (h *ImportHandler) DownloadTemplate(c *fiber.Ctx) error {
 	c.Set("Content-Type", "text/csv")
 	c.Set("Content-Disposition", "attachment; filename=template_import_siswa.csv")
 
-	template := "tingkat,kode_jurusan,rombel,nama_lengkap,nis\n"
-	template += "10,rpl,A,Budi Santoso,25327004990001\n"
-	template += "10,rpl,A,Siti Aminah,25327004990002\n"
-	template += "11,dkv,B,Ahmad Rizki,24327004990001\n"
+	template := "tingkat,kode_jurusan,rombel,nama_lengkap,nis,nisn,email\n"
+	template += "10,rpl,A,Budi Santoso,25327004990001,0091234567,\n"
+	template += "10,rpl,A,Siti Aminah,25327004990002,,siti.aminah@gmail.com\n"
+	template += "11,dkv,B,Ahmad Rizki,24327004990001,,\n"
 
 	return c.SendString(template)
 }
+
+// GetImportJob returns the progress of a background import
+func (h *ImportHandler) GetImportJob(c *fiber.Ctx) error {
+	job, ok := h.findJob(c)
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("JOB_NOT_FOUND", "Job import tidak ditemukan"))
+	}
+	return c.JSON(dto.SuccessResponse(job.ToResponse(), ""))
+}
+
+// CancelImportJob stops a queued or running import; rows already written are kept
+func (h *ImportHandler) CancelImportJob(c *fiber.Ctx) error {
+	job, ok := h.findJob(c)
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("JOB_NOT_FOUND", "Job import tidak ditemukan"))
+	}
+	if !h.jobs.Cancel(job.ID) {
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse("JOB_FINISHED", "Job import sudah selesai"))
+	}
+	return c.JSON(dto.SuccessResponse(job.ToResponse(), "Pembatalan import diminta"))
+}
+
+// DownloadImportJobErrors returns the row errors of a finished import as XLSX
+func (h *ImportHandler) DownloadImportJobErrors(c *fiber.Ctx) error {
+	job, ok := h.findJob(c)
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("JOB_NOT_FOUND", "Job import tidak ditemukan"))
+	}
+	if !job.Finished() {
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse("JOB_RUNNING", "Job import belum selesai"))
+	}
+
+	xlsx := excelize.NewFile()
+	defer xlsx.Close()
+
+	sheet := xlsx.GetSheetName(0)
+	_ = xlsx.SetSheetRow(sheet, "A1", &[]interface{}{"baris", "nis", "nama", "error"})
+	for i, rowErr := range job.Errors() {
+		cell, _ := excelize.CoordinatesToCellName(1, i+2)
+		_ = xlsx.SetSheetRow(sheet, cell, &[]interface{}{rowErr.Row, rowErr.NIS, rowErr.Nama, rowErr.Error})
+	}
+
+	buf, err := xlsx.WriteToBuffer()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal membuat laporan error"))
+	}
+
+	c.Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Set("Content-Disposition", "attachment; filename=import_errors_"+job.ID.String()+".xlsx")
+	return c.Send(buf.Bytes())
+}
+
+// DownloadCredentialSlips returns printable login slips for an import batch.
+// Passing ?kelas= limits the file to one class; downloaded slips are purged immediately.
+func (h *ImportHandler) DownloadCredentialSlips(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("INVALID_ID", "ID batch tidak valid"))
+	}
+
+	byKelas, ok := h.slips.Take(id, c.Query("kelas"))
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("SLIPS_NOT_FOUND", "Slip akun tidak ditemukan atau sudah diunduh"))
+	}
+
+	xlsx, err := buildCredentialSlipsXLSX(byKelas)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal membuat slip akun"))
+	}
+	defer xlsx.Close()
+
+	buf, err := xlsx.WriteToBuffer()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal membuat slip akun"))
+	}
+
+	filename := "slip_akun_" + id.String() + ".xlsx"
+	if kelas := filenamePart(c.Query("kelas")); kelas != "" {
+		filename = "slip_akun_" + kelas + ".xlsx"
+	}
+	c.Set("Cache-Control", "no-store")
+	c.Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Set("Content-Disposition", "attachment; filename="+filename)
+	return c.Send(buf.Bytes())
+}
+
+// unsafeFilenameChars matches what may not go into a Content-Disposition filename unquoted
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// filenamePart turns user input into a filename fragment, e.g. "XII RPL 1" into "XII_RPL_1"
+func filenamePart(s string) string {
+	return strings.Trim(unsafeFilenameChars.ReplaceAllString(s, "_"), "_")
+}
+
+func (h *ImportHandler) findJob(c *fiber.Ctx) (*ImportJob, bool) {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, false
+	}
+	return h.jobs.Get(id)
+}
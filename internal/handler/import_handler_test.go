@@ -0,0 +1,14 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilenamePart(t *testing.T) {
+	assert.Equal(t, "XII_RPL_1", filenamePart("XII RPL 1"))
+	assert.Equal(t, "X_DKV-2", filenamePart(` X "DKV-2"; `))
+	assert.Equal(t, "Kelas_Tata_Boga", filenamePart("Kelas Tata Boga ☕"))
+	assert.Equal(t, "", filenamePart("☕"))
+}
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/dto"
+)
+
+// ============================================================================
+// IMPORT JOBS
+// ============================================================================
+
+// ImportJobStatus is the lifecycle state of a background import
+type ImportJobStatus string
+
+const (
+	ImportJobQueued    ImportJobStatus = "queued"
+	ImportJobRunning   ImportJobStatus = "running"
+	ImportJobCompleted ImportJobStatus = "completed"
+	ImportJobFailed    ImportJobStatus = "failed"
+	ImportJobCancelled ImportJobStatus = "cancelled"
+)
+
+// importJobRetention is how long finished jobs stay available for status and error reports
+const importJobRetention = 24 * time.Hour
+
+// ImportJob tracks a single background import
+type ImportJob struct {
+	ID        uuid.UUID
+	CreatedBy uuid.UUID
+	Mode      string
+	CreatedAt time.Time
+
+	mu            sync.RWMutex
+	status        ImportJobStatus
+	totalRows     int
+	processedRows int
+	errors        []dto.StudentImportError
+	result        interface{}
+	failure       string
+	startedAt     *time.Time
+	finishedAt    *time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// addProcessed records n handled rows and any row errors produced by them
+func (j *ImportJob) addProcessed(n int, errs ...dto.StudentImportError) {
+	j.mu.Lock()
+	j.processedRows += n
+	j.errors = append(j.errors, errs...)
+	j.mu.Unlock()
+}
+
+// Errors returns a copy of the row errors collected so far
+func (j *ImportJob) Errors() []dto.StudentImportError {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return append([]dto.StudentImportError(nil), j.errors...)
+}
+
+// Finished reports whether the job reached a terminal state
+func (j *ImportJob) Finished() bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.finishedAt != nil
+}
+
+// ToResponse converts the job state to its API representation
+func (j *ImportJob) ToResponse() dto.ImportJobResponse {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return dto.ImportJobResponse{
+		ID:            j.ID,
+		Mode:          j.Mode,
+		Status:        string(j.status),
+		TotalRows:     j.totalRows,
+		ProcessedRows: j.processedRows,
+		ErrorCount:    len(j.errors),
+		Errors:        append([]dto.StudentImportError(nil), j.errors...),
+		Result:        j.result,
+		Failure:       j.failure,
+		CreatedAt:     j.CreatedAt,
+		StartedAt:     j.startedAt,
+		FinishedAt:    j.finishedAt,
+	}
+}
+
+func (j *ImportJob) setRunning() {
+	now := time.Now()
+	j.mu.Lock()
+	j.status = ImportJobRunning
+	j.startedAt = &now
+	j.mu.Unlock()
+}
+
+func (j *ImportJob) finish(result interface{}, err error) {
+	now := time.Now()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.result = result
+	j.finishedAt = &now
+	switch {
+	case err != nil:
+		j.status = ImportJobFailed
+		j.failure = err.Error()
+	case j.ctx.Err() != nil:
+		j.status = ImportJobCancelled
+	default:
+		j.status = ImportJobCompleted
+	}
+}
+
+// ImportJobFunc runs the import body; it must stop early once ctx is cancelled
+type ImportJobFunc func(ctx context.Context, job *ImportJob) (interface{}, error)
+
+// ImportJobManager keeps background imports in memory and runs them one at a time,
+// so concurrent rosters never race on class creation
+type ImportJobManager struct {
+	mu   sync.RWMutex
+	jobs map[uuid.UUID]*ImportJob
+	slot chan struct{}
+}
+
+// NewImportJobManager creates an empty job manager
+func NewImportJobManager() *ImportJobManager {
+	return &ImportJobManager{
+		jobs: make(map[uuid.UUID]*ImportJob),
+		slot: make(chan struct{}, 1),
+	}
+}
+
+// Start registers a new job and runs fn in the background once no other import is running
+func (m *ImportJobManager) Start(createdBy uuid.UUID, mode string, totalRows int, initialErrors []dto.StudentImportError, fn ImportJobFunc) *ImportJob {
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &ImportJob{
+		ID:        uuid.New(),
+		CreatedBy: createdBy,
+		Mode:      mode,
+		CreatedAt: time.Now(),
+		status:    ImportJobQueued,
+		totalRows: totalRows,
+		errors:    append([]dto.StudentImportError(nil), initialErrors...),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+
+	m.mu.Lock()
+	m.pruneLocked()
+	m.jobs[job.ID] = job
+	m.mu.Unlock()
+
+	go func() {
+		defer cancel()
+
+		select {
+		case m.slot <- struct{}{}:
+		case <-ctx.Done():
+			job.finish(nil, nil)
+			return
+		}
+		defer func() { <-m.slot }()
+
+		job.setRunning()
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[IMPORT] Job %s panicked: %v", job.ID, r)
+				job.finish(nil, errors.New("import berhenti karena kesalahan internal"))
+			}
+		}()
+		result, err := fn(ctx, job)
+		job.finish(result, err)
+	}()
+
+	return job
+}
+
+// Get returns a job by ID
+func (m *ImportJobManager) Get(id uuid.UUID) (*ImportJob, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	job, ok := m.jobs[id]
+	return job, ok
+}
+
+// Cancel requests cancellation of a queued or running job
+func (m *ImportJobManager) Cancel(id uuid.UUID) bool {
+	job, ok := m.Get(id)
+	if !ok || job.Finished() {
+		return false
+	}
+	job.cancel()
+	return true
+}
+
+// pruneLocked drops finished jobs past the retention window; caller holds m.mu
+func (m *ImportJobManager) pruneLocked() {
+	cutoff := time.Now().Add(-importJobRetention)
+	for id, job := range m.jobs {
+		job.mu.RLock()
+		expired := job.finishedAt != nil && job.finishedAt.Before(cutoff)
+		job.mu.RUnlock()
+		if expired {
+			delete(m.jobs, id)
+		}
+	}
+}
@@ -1,22 +1,27 @@
 package handler
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/grafikarsa/backend/internal/domain"
 	"github.com/grafikarsa/backend/internal/dto"
 	"github.com/grafikarsa/backend/internal/middleware"
 	"github.com/grafikarsa/backend/internal/repository"
+	"github.com/grafikarsa/backend/internal/service"
+	"gorm.io/gorm"
 )
 
 type NotificationHandler struct {
-	repo       *repository.NotificationRepository
-	userRepo   *repository.UserRepository
-	followRepo *repository.FollowRepository
+	repo          *repository.NotificationRepository
+	userRepo      *repository.UserRepository
+	followRepo    *repository.FollowRepository
+	collabService *service.CollaborationService
 }
 
-func NewNotificationHandler(repo *repository.NotificationRepository, userRepo *repository.UserRepository, followRepo *repository.FollowRepository) *NotificationHandler {
-	return &NotificationHandler{repo: repo, userRepo: userRepo, followRepo: followRepo}
+func NewNotificationHandler(repo *repository.NotificationRepository, userRepo *repository.UserRepository, followRepo *repository.FollowRepository, collabService *service.CollaborationService) *NotificationHandler {
+	return &NotificationHandler{repo: repo, userRepo: userRepo, followRepo: followRepo, collabService: collabService}
 }
 
 // List - GET /notifications
@@ -257,3 +262,63 @@ func (h *NotificationHandler) Delete(c *fiber.Ctx) error {
 
 	return c.JSON(dto.SuccessResponse(nil, "Notifikasi berhasil dihapus"))
 }
+
+// AcceptInvite - POST /notifications/:id/accept
+func (h *NotificationHandler) AcceptInvite(c *fiber.Ctx) error {
+	return h.respondInvite(c, true)
+}
+
+// DeclineInvite - POST /notifications/:id/decline
+func (h *NotificationHandler) DeclineInvite(c *fiber.Ctx) error {
+	return h.respondInvite(c, false)
+}
+
+// respondInvite answers the team portfolio invitation carried by a collab_invite notification
+func (h *NotificationHandler) respondInvite(c *fiber.Ctx, accept bool) error {
+	userID := middleware.GetUserID(c)
+	if userID == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse("UNAUTHORIZED", "Unauthorized"))
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("INVALID_ID", "ID tidak valid"))
+	}
+
+	notification, err := h.repo.FindByID(id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("NOT_FOUND", "Notifikasi tidak ditemukan"))
+	}
+	if notification.UserID != *userID {
+		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse("FORBIDDEN", "Tidak memiliki akses"))
+	}
+	if notification.Type != domain.NotifCollabInvite {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("NOT_AN_INVITATION", "Notifikasi ini bukan undangan portfolio tim"))
+	}
+
+	idStr, _ := notification.Data["collaborator_id"].(string)
+	collaboratorID, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("NOT_AN_INVITATION", "Notifikasi ini bukan undangan portfolio tim"))
+	}
+
+	collaborator, err := h.collabService.Respond(collaboratorID, *userID, accept)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, service.ErrInvitationNotForUser):
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("INVITATION_NOT_FOUND", "Undangan sudah dibatalkan"))
+	case errors.Is(err, service.ErrInvitationNotPending):
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse("INVITATION_ANSWERED", "Undangan sudah dijawab"))
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("UPDATE_FAILED", "Gagal menjawab undangan"))
+	}
+
+	if !notification.IsRead {
+		h.repo.MarkAsRead(notification.ID)
+	}
+
+	message := "Undangan ditolak"
+	if accept {
+		message = "Undangan diterima. Portfolio tim kini tampil di profil kamu"
+	}
+	return c.JSON(dto.SuccessResponse(dto.CollaboratorToDTO(*collaborator), message))
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/middleware"
+	"github.com/grafikarsa/backend/internal/service"
+)
+
+// GetAnalytics - GET /portfolios/:id/analytics
+// Views, likes, traffic sources and audience of a portfolio over the last ?days days
+// (default 30, at most 90), for its owner. Collaborators do not see the owner's numbers.
+func (h *PortfolioHandler) GetAnalytics(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "ID tidak valid"))
+	}
+
+	portfolio, err := h.portfolioRepo.FindByID(id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("PORTFOLIO_NOT_FOUND", "Portfolio tidak ditemukan"))
+	}
+
+	userID := middleware.GetUserID(c)
+	isOwner := userID != nil && *userID == portfolio.UserID
+	if !isOwner && middleware.GetUserRole(c) != "admin" {
+		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse("FORBIDDEN", "Hanya pemilik portfolio yang dapat melihat statistik"))
+	}
+
+	days := c.QueryInt("days", service.DefaultAnalyticsDays)
+	if days < 1 || days > service.MaxAnalyticsDays {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal",
+			dto.ErrorDetail{Field: "days", Message: "Rentang hari harus 1-90"},
+		))
+	}
+
+	result, err := h.analytics.Build(portfolio, days, time.Now())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil statistik portfolio"))
+	}
+	return c.JSON(dto.SuccessResponse(result, ""))
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/middleware"
+	"github.com/grafikarsa/backend/internal/service"
+)
+
+// ============================================================================
+// WORKING DRAFTS OF PUBLISHED PORTFOLIOS
+// ============================================================================
+
+// openDraft returns the working draft of a published portfolio ready for editing
+func openDraft(c *fiber.Ctx, draftService *service.DraftService, portfolio *domain.Portfolio) (*domain.PortfolioDraft, bool) {
+	draft, err := draftService.Open(portfolio)
+	if errors.Is(err, service.ErrDraftUnderReview) {
+		c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse(
+			"DRAFT_UNDER_REVIEW", "Perubahan portfolio sedang direview dan belum bisa diedit",
+		))
+		return nil, false
+	}
+	if err != nil {
+		c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
+			"INTERNAL_ERROR", "Gagal membuka draft portfolio",
+		))
+		return nil, false
+	}
+	return draft, true
+}
+
+// updateDraft applies PATCH /portfolios/:id to the working draft of a published portfolio
+func (h *PortfolioHandler) updateDraft(c *fiber.Ctx, portfolio *domain.Portfolio, req dto.UpdatePortfolioRequest) error {
+	draft, ok := openDraft(c, h.draftService, portfolio)
+	if !ok {
+		return nil
+	}
+
+	if err := h.draftService.ApplyUpdate(draft, req); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
+			"INTERNAL_ERROR", "Gagal menyimpan draft portfolio",
+		))
+	}
+	if req.SeriesID != nil {
+		if err := h.templateService.ScaffoldDraft(draft); err != nil {
+			log.Printf("[TEMPLATE] Failed to scaffold draft of portfolio %s: %v", portfolio.ID, err)
+		} else if err := h.draftService.Save(draft); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
+				"INTERNAL_ERROR", "Gagal menyimpan draft portfolio",
+			))
+		}
+	}
+
+	return c.JSON(dto.SuccessResponse(map[string]interface{}{
+		"id":           portfolio.ID,
+		"judul":        portfolio.Judul,
+		"slug":         portfolio.Slug,
+		"status":       portfolio.Status,
+		"draft_status": draft.Status,
+		"draft":        dto.DraftToDTO(draft),
+	}, "Perubahan disimpan sebagai draft. Ajukan untuk review agar tampil di halaman publik"))
+}
+
+// submitDraft sends the working draft of a published portfolio to the review queue
+func (h *PortfolioHandler) submitDraft(c *fiber.Ctx, portfolio *domain.Portfolio, currentUserID *uuid.UUID) error {
+	draft, err := h.draftService.Find(portfolio.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
+			"INTERNAL_ERROR", "Gagal submit perubahan portfolio",
+		))
+	}
+	if draft == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse(
+			"NO_PENDING_CHANGES", "Tidak ada perubahan untuk diajukan",
+		))
+	}
+	if draft.Status == domain.DraftPendingReview {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse(
+			"INVALID_STATUS_TRANSITION", "Perubahan portfolio sudah diajukan untuk review",
+		))
+	}
+
+	var details []dto.ErrorDetail
+	if draft.Content.ThumbnailURL == nil {
+		details = append(details, dto.ErrorDetail{Field: "thumbnail", Message: "Thumbnail wajib diisi sebelum submit"})
+	}
+	if len(draft.Content.Blocks) == 0 {
+		details = append(details, dto.ErrorDetail{Field: "content_blocks", Message: "Portfolio harus memiliki minimal 1 content block"})
+	}
+	missing, err := h.templateService.MissingRequired(draft.Content.SeriesID, draft.Content.Blocks)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
+			"INTERNAL_ERROR", "Gagal submit perubahan portfolio",
+		))
+	}
+	details = append(details, missing...)
+	if len(details) > 0 {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse(
+			"INCOMPLETE_PORTFOLIO", "Portfolio belum lengkap", details...,
+		))
+	}
+
+	if err := h.draftService.Submit(draft); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
+			"INTERNAL_ERROR", "Gagal submit perubahan portfolio",
+		))
+	}
+
+	snapshot := draft.Content
+	snapshot.Status = domain.StatusPendingReview
+	recordSnapshot(h.revisionService, portfolio.ID, snapshot, domain.RevisionReasonSubmit, currentUserID)
+
+	draftStatus := string(draft.Status)
+	return c.JSON(dto.SuccessResponse(dto.DraftStatusResponse{
+		ID:          portfolio.ID,
+		Status:      string(portfolio.Status),
+		DraftStatus: &draftStatus,
+	}, "Perubahan portfolio berhasil diajukan untuk review. Versi saat ini tetap tampil sampai disetujui"))
+}
+
+// DiscardDraft - DELETE /portfolios/:id/draft
+func (h *PortfolioHandler) DiscardDraft(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse(
+			"VALIDATION_ERROR", "ID tidak valid",
+		))
+	}
+
+	currentUserID := middleware.GetUserID(c)
+	portfolio, err := h.portfolioRepo.FindByID(id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse(
+			"PORTFOLIO_NOT_FOUND", "Portfolio tidak ditemukan",
+		))
+	}
+
+	isOwner := currentUserID != nil && *currentUserID == portfolio.UserID
+	isAdmin := middleware.GetUserRole(c) == "admin"
+	if !isOwner && !isAdmin {
+		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse(
+			"FORBIDDEN", "Anda tidak memiliki akses",
+		))
+	}
+
+	if err := h.draftService.Discard(portfolio.ID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
+			"INTERNAL_ERROR", "Gagal membuang draft portfolio",
+		))
+	}
+
+	return c.JSON(dto.SuccessResponse(dto.DraftStatusResponse{
+		ID:     portfolio.ID,
+		Status: string(portfolio.Status),
+	}, "Draft perubahan dibuang"))
+}
@@ -1,8 +1,11 @@
 package handler
 
 import (
+	"errors"
+	"log"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -11,23 +14,44 @@ import (
 	"github.com/grafikarsa/backend/internal/middleware"
 	"github.com/grafikarsa/backend/internal/repository"
 	"github.com/grafikarsa/backend/internal/service"
+	"gorm.io/gorm"
 )
 
 type PortfolioHandler struct {
-	portfolioRepo *repository.PortfolioRepository
-	userRepo      *repository.UserRepository
-	viewRepo      *repository.ViewRepository
-	interestRepo  *repository.InterestRepository
-	notifService  *service.NotificationService
+	portfolioRepo    *repository.PortfolioRepository
+	userRepo         *repository.UserRepository
+	viewRepo         *repository.ViewRepository
+	interestRepo     *repository.InterestRepository
+	notifService     *service.NotificationService
+	revisionService  *service.RevisionService
+	draftService     *service.DraftService
+	collabService    *service.CollaborationService
+	templateService  *service.SeriesTemplateService
+	shareLinkRepo    *repository.ShareLinkRepository
+	shareLinkService *service.ShareLinkService
+	profileOrder     *service.ProfileOrderService
+	rejectionRepo    *repository.RejectionRepository
+	textFilter       *service.TextFilterService
+	analytics        *service.PortfolioAnalyticsService
 }
 
-func NewPortfolioHandler(portfolioRepo *repository.PortfolioRepository, userRepo *repository.UserRepository, viewRepo *repository.ViewRepository, interestRepo *repository.InterestRepository, notifService *service.NotificationService) *PortfolioHandler {
+func NewPortfolioHandler(portfolioRepo *repository.PortfolioRepository, userRepo *repository.UserRepository, viewRepo *repository.ViewRepository, interestRepo *repository.InterestRepository, notifService *service.NotificationService, revisionService *service.RevisionService, draftService *service.DraftService, collabService *service.CollaborationService, templateService *service.SeriesTemplateService, shareLinkRepo *repository.ShareLinkRepository, shareLinkService *service.ShareLinkService, profileOrder *service.ProfileOrderService, rejectionRepo *repository.RejectionRepository, textFilter *service.TextFilterService, analytics *service.PortfolioAnalyticsService) *PortfolioHandler {
 	return &PortfolioHandler{
-		portfolioRepo: portfolioRepo,
-		userRepo:      userRepo,
-		viewRepo:      viewRepo,
-		interestRepo:  interestRepo,
-		notifService:  notifService,
+		portfolioRepo:    portfolioRepo,
+		userRepo:         userRepo,
+		viewRepo:         viewRepo,
+		interestRepo:     interestRepo,
+		notifService:     notifService,
+		revisionService:  revisionService,
+		draftService:     draftService,
+		collabService:    collabService,
+		templateService:  templateService,
+		shareLinkRepo:    shareLinkRepo,
+		shareLinkService: shareLinkService,
+		profileOrder:     profileOrder,
+		rejectionRepo:    rejectionRepo,
+		textFilter:       textFilter,
+		analytics:        analytics,
 	}
 }
 
@@ -60,13 +84,19 @@ func (h *PortfolioHandler) List(c *fiber.Ctx) error {
 		userID = &parsed
 	}
 
-	portfolios, total, err := h.portfolioRepo.ListPublished(search, tagIDs, jurusanID, kelasID, userID, sort, page, limit)
+	portfolios, total, err := h.portfolioRepo.ListPublished(currentViewer(c, h.userRepo), search, tagIDs, jurusanID, kelasID, userID, sort, page, limit)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
 			"INTERNAL_ERROR", "Gagal mengambil data portfolio",
 		))
 	}
 
+	// Profile listings mark the portfolios pinned by that user
+	var profileOrder map[uuid.UUID]domain.ProfilePortfolio
+	if userID != nil {
+		profileOrder, _ = h.portfolioRepo.ProfileOrder(*userID)
+	}
+
 	var portfolioDTOs []dto.PortfolioListDTO
 	for _, p := range portfolios {
 		likeCount, _ := h.portfolioRepo.GetLikeCount(p.ID)
@@ -78,6 +108,7 @@ func (h *PortfolioHandler) List(c *fiber.Ctx) error {
 			PublishedAt:  p.PublishedAt,
 			CreatedAt:    p.CreatedAt,
 			LikeCount:    likeCount,
+			IsPinned:     profileOrder[p.ID].IsPinned,
 		}
 
 		if p.User != nil {
@@ -119,12 +150,16 @@ func (h *PortfolioHandler) GetBySlug(c *fiber.Ctx) error {
 	slug := c.Params("slug")
 	username := c.Query("username")
 	currentUserID := middleware.GetUserID(c)
+	viewer := currentViewer(c, h.userRepo)
 
 	var portfolio *domain.Portfolio
 	var err error
 
 	if username != "" {
 		user, userErr := h.userRepo.FindByUsername(username)
+		if userErr != nil {
+			user, userErr = h.userRepo.FindByPreviousUsername(username)
+		}
 		if userErr != nil {
 			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse(
 				"PORTFOLIO_NOT_FOUND", "Portfolio tidak ditemukan",
@@ -133,13 +168,7 @@ func (h *PortfolioHandler) GetBySlug(c *fiber.Ctx) error {
 		portfolio, err = h.portfolioRepo.FindBySlugAndUserID(slug, user.ID)
 	} else {
 		// Find by slug only (first match)
-		portfolios, _, _ := h.portfolioRepo.ListPublished("", nil, nil, nil, nil, "", 1, 100)
-		for _, p := range portfolios {
-			if p.Slug == slug {
-				portfolio, err = h.portfolioRepo.FindByID(p.ID)
-				break
-			}
-		}
+		portfolio, err = h.portfolioRepo.FindPublishedBySlugAndUsername(slug, "", viewer)
 	}
 
 	if err != nil || portfolio == nil {
@@ -148,29 +177,45 @@ func (h *PortfolioHandler) GetBySlug(c *fiber.Ctx) error {
 		))
 	}
 
-	// Check visibility
-	if portfolio.Status != domain.StatusPublished {
-		isOwner := currentUserID != nil && *currentUserID == portfolio.UserID
-		isAdmin := middleware.GetUserRole(c) == "admin"
-		if !isOwner && !isAdmin {
-			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse(
-				"PORTFOLIO_NOT_FOUND", "Portfolio tidak ditemukan",
-			))
-		}
+	// Check visibility; unpublished work is only for its editors, published work follows its visibility level
+	canEdit := h.collabService.CanEdit(portfolio, currentUserID)
+	isAdmin := middleware.GetUserRole(c) == "admin"
+	visible := canEdit || isAdmin
+	if !visible && portfolio.Status == domain.StatusPublished {
+		visible, _ = h.portfolioRepo.IsVisibleTo(portfolio.ID, viewer)
+	}
+	if !visible {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse(
+			"PORTFOLIO_NOT_FOUND", "Portfolio tidak ditemukan",
+		))
 	}
 
 	// Record view for published portfolios
 	if portfolio.Status == domain.StatusPublished && h.viewRepo != nil {
-		sessionID := c.Get("X-Session-ID")
+		// Copied: fiber reuses the header buffer once the handler returns, before the goroutines below run
+		sessionID := strings.Clone(c.Get("X-Session-ID"))
 		var sessionPtr *string
 		if sessionID != "" {
 			sessionPtr = &sessionID
 		}
 		// Record view asynchronously to not block response
 		go h.viewRepo.RecordView(portfolio.ID, currentUserID, sessionPtr)
+		// Visits by the portfolio's own editors stay out of its analytics
+		if !canEdit {
+			go h.analytics.RecordView(portfolio.ID, currentUserID, sessionPtr, service.ParseViewSource(c.Query("source")), time.Now())
+		}
+	}
+
+	result := h.toPortfolioDetailDTO(portfolio, currentUserID)
+	// Opened through an old slug or username: point the client at the current address
+	if portfolio.Slug != slug || (username != "" && portfolio.User != nil && portfolio.User.Username != username) {
+		result.Redirect = &dto.RedirectDTO{Slug: portfolio.Slug}
+		if portfolio.User != nil {
+			result.Redirect.Username = portfolio.User.Username
+		}
 	}
 
-	return c.JSON(dto.SuccessResponse(h.toPortfolioDetailDTO(portfolio, currentUserID), ""))
+	return c.JSON(dto.SuccessResponse(result, ""))
 }
 
 func (h *PortfolioHandler) GetByID(c *fiber.Ctx) error {
@@ -190,15 +235,40 @@ func (h *PortfolioHandler) GetByID(c *fiber.Ctx) error {
 	}
 
 	// Check access
-	isOwner := currentUserID != nil && *currentUserID == portfolio.UserID
+	canEdit := h.collabService.CanEdit(portfolio, currentUserID)
 	isAdmin := middleware.GetUserRole(c) == "admin"
-	if !isOwner && !isAdmin {
+	if !canEdit && !isAdmin {
 		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse(
 			"FORBIDDEN", "Anda tidak memiliki akses ke portfolio ini",
 		))
 	}
 
-	return c.JSON(dto.SuccessResponse(h.toPortfolioDetailDTO(portfolio, currentUserID), ""))
+	result := h.toPortfolioDetailDTO(portfolio, currentUserID)
+	draft, _ := h.draftService.Find(portfolio.ID)
+	if draft != nil {
+		result.Draft = dto.DraftToDTO(draft)
+	}
+	result.Rejection = h.currentRejection(portfolio, draft)
+
+	return c.JSON(dto.SuccessResponse(result, ""))
+}
+
+// currentRejection tells the student what to fix while a rejection still stands. Rejections
+// made without picked reasons, such as unpublishing after a report, have no record and yield nil.
+func (h *PortfolioHandler) currentRejection(portfolio *domain.Portfolio, draft *domain.PortfolioDraft) *dto.RejectionDTO {
+	reviewedAt := portfolio.ReviewedAt
+	isDraft := draft != nil && draft.Status == domain.DraftRejected
+	if isDraft {
+		reviewedAt = draft.ReviewedAt
+	} else if portfolio.Status != domain.StatusRejected {
+		return nil
+	}
+
+	rejection, _ := h.rejectionRepo.LatestRejection(portfolio.ID)
+	if rejection == nil || rejection.IsDraft != isDraft || (reviewedAt != nil && rejection.CreatedAt.Before(*reviewedAt)) {
+		return nil
+	}
+	return dto.RejectionToDTO(rejection)
 }
 
 func (h *PortfolioHandler) GetMyPortfolios(c *fiber.Ctx) error {
@@ -223,21 +293,44 @@ func (h *PortfolioHandler) GetMyPortfolios(c *fiber.Ctx) error {
 		))
 	}
 
+	ids := make([]uuid.UUID, 0, len(portfolios))
+	for _, p := range portfolios {
+		ids = append(ids, p.ID)
+	}
+	draftStatuses, _ := h.draftService.StatusesByPortfolioIDs(ids)
+	coAuthorRoles, _ := h.collabService.RolesByPortfolioIDs(*userID, ids)
+	profileOrder, _ := h.portfolioRepo.ProfileOrder(*userID)
+
 	var portfolioDTOs []dto.MyPortfolioDTO
 	for _, p := range portfolios {
 		likeCount, _ := h.portfolioRepo.GetLikeCount(p.ID)
+		var draftStatus *string
+		if st, ok := draftStatuses[p.ID]; ok {
+			s := string(st)
+			draftStatus = &s
+		}
+		myRole := domain.CollaboratorOwner
+		if p.UserID != *userID {
+			myRole = coAuthorRoles[p.ID]
+		}
 		portfolioDTOs = append(portfolioDTOs, dto.MyPortfolioDTO{
 			ID:              p.ID,
 			Judul:           p.Judul,
 			Slug:            p.Slug,
 			ThumbnailURL:    p.ThumbnailURL,
 			Status:          string(p.Status),
+			Visibility:      string(p.Visibility),
 			AdminReviewNote: p.AdminReviewNote,
 			ReviewedAt:      p.ReviewedAt,
 			PublishedAt:     p.PublishedAt,
+			PublishAt:       p.PublishAt,
 			CreatedAt:       p.CreatedAt,
 			UpdatedAt:       p.UpdatedAt,
 			LikeCount:       likeCount,
+			DraftStatus:     draftStatus,
+			MyRole:          string(myRole),
+			IsPinned:        profileOrder[p.ID].IsPinned,
+			DisplayOrder:    profileOrder[p.ID].DisplayOrder,
 		})
 	}
 
@@ -275,6 +368,25 @@ func (h *PortfolioHandler) Create(c *fiber.Ctx) error {
 			dto.ErrorDetail{Field: "judul", Message: "Judul wajib diisi"},
 		))
 	}
+	var filtered service.TextFilterResult
+	if !filterTextField(c, h.textFilter, "judul", &req.Judul, &filtered) {
+		return nil
+	}
+
+	visibility := domain.VisibilityPublic
+	if req.Visibility != nil {
+		visibility = domain.PortfolioVisibility(*req.Visibility)
+		if !isValidVisibility(visibility) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse(
+				"VALIDATION_ERROR", "Validasi gagal",
+				dto.ErrorDetail{Field: "visibility", Message: "Visibility harus salah satu dari: public, logged_in, jurusan, unlisted"},
+			))
+		}
+	}
+
+	if req.SeriesID != nil && !h.checkSeriesOpen(c, *req.SeriesID) {
+		return nil
+	}
 
 	// Determine owner and status based on who's creating
 	isAdmin := middleware.GetUserRole(c) == "admin"
@@ -288,9 +400,10 @@ func (h *PortfolioHandler) Create(c *fiber.Ctx) error {
 	}
 
 	portfolio := &domain.Portfolio{
-		UserID: ownerID,
-		Judul:  req.Judul,
-		Status: status,
+		UserID:     ownerID,
+		Judul:      req.Judul,
+		Status:     status,
+		Visibility: visibility,
 	}
 
 	if err := h.portfolioRepo.Create(portfolio); err != nil {
@@ -298,6 +411,7 @@ func (h *PortfolioHandler) Create(c *fiber.Ctx) error {
 			"INTERNAL_ERROR", "Gagal membuat portfolio",
 		))
 	}
+	h.textFilter.Flag(domain.ReportTargetPortfolio, portfolio.ID, ownerID, filtered)
 
 	if len(req.TagIDs) > 0 {
 		h.portfolioRepo.UpdateTags(portfolio.ID, req.TagIDs)
@@ -305,6 +419,10 @@ func (h *PortfolioHandler) Create(c *fiber.Ctx) error {
 
 	if req.SeriesID != nil {
 		h.portfolioRepo.UpdateSeriesID(portfolio.ID, req.SeriesID)
+		portfolio.SeriesID = req.SeriesID
+		if err := h.templateService.Scaffold(portfolio); err != nil {
+			log.Printf("[TEMPLATE] Failed to scaffold portfolio %s from series %s: %v", portfolio.ID, *req.SeriesID, err)
+		}
 	}
 
 	portfolio, _ = h.portfolioRepo.FindByID(portfolio.ID)
@@ -328,9 +446,9 @@ func (h *PortfolioHandler) Update(c *fiber.Ctx) error {
 		))
 	}
 
-	isOwner := currentUserID != nil && *currentUserID == portfolio.UserID
+	canEdit := h.collabService.CanEdit(portfolio, currentUserID)
 	isAdmin := middleware.GetUserRole(c) == "admin"
-	if !isOwner && !isAdmin {
+	if !canEdit && !isAdmin {
 		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse(
 			"FORBIDDEN", "Anda tidak memiliki akses untuk mengedit portfolio ini",
 		))
@@ -343,18 +461,61 @@ func (h *PortfolioHandler) Update(c *fiber.Ctx) error {
 		))
 	}
 
+	if req.Visibility != nil && !isValidVisibility(domain.PortfolioVisibility(*req.Visibility)) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse(
+			"VALIDATION_ERROR", "Validasi gagal",
+			dto.ErrorDetail{Field: "visibility", Message: "Visibility harus salah satu dari: public, logged_in, jurusan, unlisted"},
+		))
+	}
+
+	if req.SeriesID != nil && !sameSeries(portfolio.SeriesID, req.SeriesID) && !h.checkSeriesOpen(c, *req.SeriesID) {
+		return nil
+	}
+	var filtered service.TextFilterResult
+	if !filterTextField(c, h.textFilter, "judul", req.Judul, &filtered) {
+		return nil
+	}
+
+	// Approved portfolios keep their reviewed content; edits go to the working draft.
+	// Visibility is not reviewed content and changes on the live portfolio.
+	if portfolio.IsApproved() {
+		if req.Visibility != nil {
+			portfolio.Visibility = domain.PortfolioVisibility(*req.Visibility)
+			if err := h.portfolioRepo.UpdateVisibility(portfolio.ID, portfolio.Visibility); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
+					"INTERNAL_ERROR", "Gagal memperbarui portfolio",
+				))
+			}
+			if req.Judul == nil && req.ThumbnailURL == nil && req.TagIDs == nil && req.SeriesID == nil {
+				return c.JSON(dto.SuccessResponse(map[string]interface{}{
+					"id":         portfolio.ID,
+					"judul":      portfolio.Judul,
+					"slug":       portfolio.Slug,
+					"status":     portfolio.Status,
+					"visibility": portfolio.Visibility,
+				}, "Visibility portfolio berhasil diperbarui"))
+			}
+		}
+		h.textFilter.Flag(domain.ReportTargetPortfolio, portfolio.ID, portfolio.UserID, filtered)
+		return h.updateDraft(c, portfolio, req)
+	}
+
 	if req.Judul != nil {
 		portfolio.Judul = *req.Judul
 	}
 	if req.ThumbnailURL != nil {
 		portfolio.ThumbnailURL = req.ThumbnailURL
 	}
+	if req.Visibility != nil {
+		portfolio.Visibility = domain.PortfolioVisibility(*req.Visibility)
+	}
 
 	if err := h.portfolioRepo.Update(portfolio); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
 			"INTERNAL_ERROR", "Gagal memperbarui portfolio",
 		))
 	}
+	h.textFilter.Flag(domain.ReportTargetPortfolio, portfolio.ID, portfolio.UserID, filtered)
 
 	if req.TagIDs != nil {
 		h.portfolioRepo.UpdateTags(portfolio.ID, req.TagIDs)
@@ -362,6 +523,10 @@ func (h *PortfolioHandler) Update(c *fiber.Ctx) error {
 
 	if req.SeriesID != nil {
 		h.portfolioRepo.UpdateSeriesID(portfolio.ID, req.SeriesID)
+		portfolio.SeriesID = req.SeriesID
+		if err := h.templateService.Scaffold(portfolio); err != nil {
+			log.Printf("[TEMPLATE] Failed to scaffold portfolio %s from series %s: %v", portfolio.ID, *req.SeriesID, err)
+		}
 	}
 
 	return c.JSON(dto.SuccessResponse(map[string]interface{}{
@@ -369,6 +534,7 @@ func (h *PortfolioHandler) Update(c *fiber.Ctx) error {
 		"judul":      portfolio.Judul,
 		"slug":       portfolio.Slug,
 		"status":     portfolio.Status,
+		"visibility": portfolio.Visibility,
 		"updated_at": portfolio.UpdatedAt,
 	}, "Portfolio berhasil diperbarui"))
 }
@@ -428,6 +594,25 @@ func (h *PortfolioHandler) Submit(c *fiber.Ctx) error {
 		))
 	}
 
+	var req dto.SubmitPortfolioRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse(
+				"VALIDATION_ERROR", "Request body tidak valid",
+			))
+		}
+	}
+
+	if portfolio.IsApproved() {
+		if req.PublishAt != nil {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse(
+				"VALIDATION_ERROR", "Validasi gagal",
+				dto.ErrorDetail{Field: "publish_at", Message: "Jadwal publish hanya untuk portfolio yang belum pernah dipublish"},
+			))
+		}
+		return h.submitDraft(c, portfolio, currentUserID)
+	}
+
 	if portfolio.Status != domain.StatusDraft && portfolio.Status != domain.StatusRejected {
 		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse(
 			"INVALID_STATUS_TRANSITION", "Portfolio hanya bisa disubmit dari status draft atau rejected",
@@ -442,6 +627,16 @@ func (h *PortfolioHandler) Submit(c *fiber.Ctx) error {
 	if len(portfolio.ContentBlocks) == 0 {
 		details = append(details, dto.ErrorDetail{Field: "content_blocks", Message: "Portfolio harus memiliki minimal 1 content block"})
 	}
+	if detail := validatePublishAt(req.PublishAt, time.Now()); detail != nil {
+		details = append(details, *detail)
+	}
+	missing, err := h.templateService.MissingRequired(portfolio.SeriesID, domain.NewPortfolioSnapshot(portfolio).Blocks)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
+			"INTERNAL_ERROR", "Gagal submit portfolio",
+		))
+	}
+	details = append(details, missing...)
 	if len(details) > 0 {
 		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse(
 			"INCOMPLETE_PORTFOLIO", "Portfolio belum lengkap", details...,
@@ -449,11 +644,18 @@ func (h *PortfolioHandler) Submit(c *fiber.Ctx) error {
 	}
 
 	portfolio.Status = domain.StatusPendingReview
+	portfolio.PublishAt = req.PublishAt
+	// Lateness is judged on the first submission, not on resubmits after a rejection
+	if portfolio.SubmittedAt == nil {
+		now := time.Now()
+		portfolio.SubmittedAt = &now
+	}
 	if err := h.portfolioRepo.Update(portfolio); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
 			"INTERNAL_ERROR", "Gagal submit portfolio",
 		))
 	}
+	recordRevision(h.revisionService, portfolio, domain.RevisionReasonSubmit, currentUserID)
 
 	return c.JSON(dto.SuccessResponse(dto.PortfolioStatusResponse{
 		ID:     portfolio.ID,
@@ -461,6 +663,56 @@ func (h *PortfolioHandler) Submit(c *fiber.Ctx) error {
 	}, "Portfolio berhasil diajukan untuk review"))
 }
 
+// checkSeriesOpen rejects work on a series assignment that has not opened yet
+func (h *PortfolioHandler) checkSeriesOpen(c *fiber.Ctx, seriesID uuid.UUID) bool {
+	series, err := h.portfolioRepo.FindSeries(seriesID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse(
+			"VALIDATION_ERROR", "Validasi gagal",
+			dto.ErrorDetail{Field: "series_id", Message: "Series tidak ditemukan"},
+		))
+		return false
+	}
+	if err != nil {
+		c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
+			"INTERNAL_ERROR", "Gagal memeriksa series",
+		))
+		return false
+	}
+	if !series.IsOpen(time.Now()) {
+		c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse(
+			"VALIDATION_ERROR", "Validasi gagal",
+			dto.ErrorDetail{Field: "series_id", Message: "Series belum dibuka, mulai " + series.OpensAt.Format("02 Jan 2006 15:04")},
+		))
+		return false
+	}
+	return true
+}
+
+func sameSeries(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// maxPublishDelay caps how far ahead a portfolio can be scheduled
+const maxPublishDelay = 365 * 24 * time.Hour
+
+// validatePublishAt checks an optional scheduled publish time; nil means publish on approval
+func validatePublishAt(publishAt *time.Time, now time.Time) *dto.ErrorDetail {
+	if publishAt == nil {
+		return nil
+	}
+	if !publishAt.After(now) {
+		return &dto.ErrorDetail{Field: "publish_at", Message: "Jadwal publish harus di masa depan"}
+	}
+	if publishAt.After(now.Add(maxPublishDelay)) {
+		return &dto.ErrorDetail{Field: "publish_at", Message: "Jadwal publish maksimal 1 tahun ke depan"}
+	}
+	return nil
+}
+
 func (h *PortfolioHandler) Archive(c *fiber.Ctx) error {
 	id, err := uuid.Parse(c.Params("id"))
 	if err != nil {
@@ -685,9 +937,11 @@ func (h *PortfolioHandler) toPortfolioDetailDTO(p *domain.Portfolio, currentUser
 		Slug:            p.Slug,
 		ThumbnailURL:    p.ThumbnailURL,
 		Status:          string(p.Status),
+		Visibility:      string(p.Visibility),
 		AdminReviewNote: p.AdminReviewNote,
 		ReviewedAt:      p.ReviewedAt,
 		PublishedAt:     p.PublishedAt,
+		PublishAt:       p.PublishAt,
 		CreatedAt:       p.CreatedAt,
 		UpdatedAt:       p.UpdatedAt,
 		LikeCount:       likeCount,
@@ -709,23 +963,22 @@ func (h *PortfolioHandler) toPortfolioDetailDTO(p *domain.Portfolio, currentUser
 		}
 	}
 
+	if collaborators, err := h.collabService.List(p.ID, false); err == nil && len(collaborators) > 0 {
+		pDTO.Collaborators = dto.CollaboratorsToDTO(collaborators)
+	}
+
 	for _, t := range p.Tags {
 		pDTO.Tags = append(pDTO.Tags, dto.TagDTO{ID: t.ID, Nama: t.Nama})
 	}
 
+	pDTO.SubmittedAt = p.SubmittedAt
 	if p.Series != nil {
-		pDTO.Series = &dto.PortfolioSeriesDTO{ID: p.Series.ID, Nama: p.Series.Nama}
+		pDTO.Series = &dto.PortfolioSeriesDTO{ID: p.Series.ID, Nama: p.Series.Nama, ClosesAt: p.Series.ClosesAt}
+		pDTO.IsLate = p.SubmittedAt != nil && p.Series.IsLate(p.SubmittedAt, time.Now())
 	}
 
 	for _, b := range p.ContentBlocks {
-		pDTO.ContentBlocks = append(pDTO.ContentBlocks, dto.ContentBlockDTO{
-			ID:         b.ID,
-			BlockType:  string(b.BlockType),
-			BlockOrder: b.BlockOrder,
-			Payload:    b.Payload,
-			CreatedAt:  b.CreatedAt,
-			UpdatedAt:  b.UpdatedAt,
-		})
+		pDTO.ContentBlocks = append(pDTO.ContentBlocks, dto.ContentBlockToDTO(b))
 	}
 
 	return pDTO
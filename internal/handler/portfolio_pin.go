@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/middleware"
+	"github.com/grafikarsa/backend/internal/service"
+)
+
+// ============================================================================
+// PROFILE PINS & ORDER
+// ============================================================================
+
+// PinPortfolio - POST /me/portfolios/:id/pin
+func (h *PortfolioHandler) PinPortfolio(c *fiber.Ctx) error {
+	return h.setPinned(c, true)
+}
+
+// UnpinPortfolio - DELETE /me/portfolios/:id/pin
+func (h *PortfolioHandler) UnpinPortfolio(c *fiber.Ctx) error {
+	return h.setPinned(c, false)
+}
+
+// ReorderMyPortfolios - PUT /me/portfolios/reorder
+// Sets the display order of the requester's portfolios on their profile; pinned ones still lead.
+func (h *PortfolioHandler) ReorderMyPortfolios(c *fiber.Ctx) error {
+	var req dto.ReorderProfilePortfoliosRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Request body tidak valid"))
+	}
+	if len(req.PortfolioIDs) == 0 {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse(
+			"VALIDATION_ERROR", "Validasi gagal",
+			dto.ErrorDetail{Field: "portfolio_ids", Message: "Urutan portfolio wajib diisi"},
+		))
+	}
+
+	err := h.profileOrder.Reorder(*middleware.GetUserID(c), req.PortfolioIDs, time.Now())
+	if errors.Is(err, service.ErrNotAuthored) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse(
+			"VALIDATION_ERROR", "Validasi gagal",
+			dto.ErrorDetail{Field: "portfolio_ids", Message: "Hanya portfolio milik Anda yang bisa diurutkan"},
+		))
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengubah urutan portfolio"))
+	}
+	return c.JSON(dto.SuccessResponse(nil, "Urutan portfolio berhasil diperbarui"))
+}
+
+func (h *PortfolioHandler) setPinned(c *fiber.Ctx, pinned bool) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "ID tidak valid"))
+	}
+	portfolio, err := h.portfolioRepo.FindByID(id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("PORTFOLIO_NOT_FOUND", "Portfolio tidak ditemukan"))
+	}
+
+	userID := *middleware.GetUserID(c)
+	if pinned {
+		err = h.profileOrder.Pin(userID, portfolio, time.Now())
+	} else {
+		err = h.profileOrder.Unpin(userID, portfolio, time.Now())
+	}
+	switch {
+	case errors.Is(err, service.ErrNotAuthored):
+		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse("FORBIDDEN", "Anda tidak memiliki akses ke portfolio ini"))
+	case errors.Is(err, service.ErrPinNotPublished):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("PORTFOLIO_NOT_PUBLISHED", "Hanya portfolio yang sudah publish yang bisa di-pin"))
+	case errors.Is(err, service.ErrPinLimit):
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse("PIN_LIMIT", "Maksimal 6 portfolio bisa di-pin, lepas pin yang lain terlebih dahulu"))
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal menyimpan pin portfolio"))
+	}
+
+	message := "Portfolio berhasil di-pin"
+	if !pinned {
+		message = "Pin portfolio berhasil dilepas"
+	}
+	return c.JSON(dto.SuccessResponse(map[string]interface{}{
+		"id":        portfolio.ID,
+		"is_pinned": pinned,
+	}, message))
+}
@@ -0,0 +1,265 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/middleware"
+	"github.com/grafikarsa/backend/internal/service"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// PRIVATE SHARE LINKS
+// ============================================================================
+
+// ListShareLinks - GET /portfolios/:id/share-links
+func (h *PortfolioHandler) ListShareLinks(c *fiber.Ctx) error {
+	portfolio, ok := h.findSharedPortfolio(c)
+	if !ok {
+		return nil
+	}
+
+	links, err := h.shareLinkRepo.ListByPortfolio(portfolio.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil share link"))
+	}
+
+	now := time.Now()
+	result := make([]dto.ShareLinkDTO, 0, len(links))
+	for _, l := range links {
+		result = append(result, dto.ShareLinkToDTO(l, now))
+	}
+	return c.JSON(dto.SuccessResponse(result, ""))
+}
+
+// CreateShareLink - POST /portfolios/:id/share-links
+// The token is only returned here; the link is rebuilt on the client as /shared/{token}.
+func (h *PortfolioHandler) CreateShareLink(c *fiber.Ctx) error {
+	portfolio, ok := h.findSharedPortfolio(c)
+	if !ok {
+		return nil
+	}
+
+	var req dto.CreateShareLinkRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Request body tidak valid"))
+	}
+
+	var details []dto.ErrorDetail
+	if req.ExpiresInDays < 0 || req.ExpiresInDays > service.MaxShareLinkDays {
+		details = append(details, dto.ErrorDetail{Field: "expires_in_days", Message: "Masa berlaku harus antara 1 dan 30 hari"})
+	}
+	if req.Label != nil {
+		label := strings.TrimSpace(*req.Label)
+		if utf8.RuneCountInString(label) > 100 {
+			details = append(details, dto.ErrorDetail{Field: "label", Message: "Label maksimal 100 karakter"})
+		}
+		if label == "" {
+			req.Label = nil
+		} else {
+			req.Label = &label
+		}
+	}
+	if len(details) > 0 {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal", details...))
+	}
+
+	now := time.Now()
+	link, token, err := h.shareLinkService.Mint(portfolio.ID, *middleware.GetUserID(c), req.Label, req.AllowComments, req.ExpiresInDays, now)
+	if errors.Is(err, service.ErrShareLinkLimit) {
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse(
+			"SHARE_LINK_LIMIT", "Jumlah share link aktif sudah maksimal, cabut link lama terlebih dahulu",
+		))
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal membuat share link"))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse(dto.CreatedShareLinkDTO{
+		ShareLinkDTO: dto.ShareLinkToDTO(*link, now),
+		Token:        token,
+	}, "Share link berhasil dibuat"))
+}
+
+// RevokeShareLink - DELETE /portfolios/:id/share-links/:link_id
+func (h *PortfolioHandler) RevokeShareLink(c *fiber.Ctx) error {
+	portfolio, ok := h.findSharedPortfolio(c)
+	if !ok {
+		return nil
+	}
+
+	linkID, err := uuid.Parse(c.Params("link_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "ID share link tidak valid"))
+	}
+	link, err := h.shareLinkRepo.FindByID(linkID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && link.PortfolioID != portfolio.ID) {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("SHARE_LINK_NOT_FOUND", "Share link tidak ditemukan"))
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mencabut share link"))
+	}
+
+	if err := h.shareLinkRepo.Revoke(link.ID, time.Now()); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mencabut share link"))
+	}
+	return c.JSON(dto.SuccessResponse(nil, "Share link berhasil dicabut"))
+}
+
+// ListShareComments - GET /portfolios/:id/share-links/comments
+// Guest comments from every link of the portfolio, optionally narrowed with ?link_id=.
+func (h *PortfolioHandler) ListShareComments(c *fiber.Ctx) error {
+	portfolio, ok := h.findSharedPortfolio(c)
+	if !ok {
+		return nil
+	}
+
+	var linkID *uuid.UUID
+	if raw := c.Query("link_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "ID share link tidak valid"))
+		}
+		linkID = &id
+	}
+
+	comments, err := h.shareLinkRepo.ListComments(portfolio.ID, linkID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil komentar"))
+	}
+	return c.JSON(dto.SuccessResponse(dto.ShareCommentsToDTO(comments), ""))
+}
+
+// GetShared - GET /shared/:token
+// Read-only view of a portfolio in any status for whoever holds a valid link. A published
+// portfolio with pending changes is shown together with its working draft.
+func (h *PortfolioHandler) GetShared(c *fiber.Ctx) error {
+	link, ok := h.openShareLink(c, true)
+	if !ok {
+		return nil
+	}
+
+	portfolio, err := h.portfolioRepo.FindByID(link.PortfolioID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("SHARE_LINK_NOT_FOUND", "Link berbagi tidak ditemukan"))
+	}
+
+	var sessionPtr *string
+	if sessionID := strings.Clone(c.Get("X-Session-ID")); sessionID != "" {
+		sessionPtr = &sessionID
+	}
+	go h.analytics.RecordView(portfolio.ID, nil, sessionPtr, domain.ViewSourceShareLink, time.Now())
+
+	result := h.toPortfolioDetailDTO(portfolio, nil)
+	if draft, _ := h.draftService.Find(portfolio.ID); draft != nil {
+		result.Draft = dto.DraftToDTO(draft)
+	}
+
+	return c.JSON(dto.SuccessResponse(dto.SharedPortfolioDTO{
+		Portfolio:     result,
+		Label:         link.Label,
+		AllowComments: link.AllowComments,
+		ExpiresAt:     link.ExpiresAt,
+	}, ""))
+}
+
+// GetSharedComments - GET /shared/:token/comments
+func (h *PortfolioHandler) GetSharedComments(c *fiber.Ctx) error {
+	link, ok := h.openShareLink(c, false)
+	if !ok {
+		return nil
+	}
+
+	comments, err := h.shareLinkRepo.ListComments(link.PortfolioID, &link.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil komentar"))
+	}
+	return c.JSON(dto.SuccessResponse(dto.ShareCommentsToDTO(comments), ""))
+}
+
+// CreateSharedComment - POST /shared/:token/comments
+func (h *PortfolioHandler) CreateSharedComment(c *fiber.Ctx) error {
+	link, ok := h.openShareLink(c, false)
+	if !ok {
+		return nil
+	}
+
+	var req dto.CreateShareCommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Request body tidak valid"))
+	}
+
+	var details []dto.ErrorDetail
+	name := strings.TrimSpace(req.AuthorName)
+	if name == "" || utf8.RuneCountInString(name) > 100 {
+		details = append(details, dto.ErrorDetail{Field: "author_name", Message: "Nama wajib diisi, maksimal 100 karakter"})
+	}
+	content := strings.TrimSpace(req.Content)
+	if content == "" || utf8.RuneCountInString(content) > service.MaxShareCommentLength {
+		details = append(details, dto.ErrorDetail{Field: "content", Message: "Komentar wajib diisi, maksimal 2000 karakter"})
+	}
+	if len(details) > 0 {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal", details...))
+	}
+
+	comment, err := h.shareLinkService.Comment(link, name, content)
+	if errors.Is(err, service.ErrShareCommentsOff) {
+		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse("COMMENTS_DISABLED", "Komentar tidak diaktifkan untuk link ini"))
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengirim komentar"))
+	}
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse(dto.ShareCommentToDTO(*comment), "Komentar berhasil dikirim"))
+}
+
+// findSharedPortfolio loads the portfolio whose share links are managed; only its editors
+// and admins may do so
+func (h *PortfolioHandler) findSharedPortfolio(c *fiber.Ctx) (*domain.Portfolio, bool) {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "ID tidak valid"))
+		return nil, false
+	}
+	portfolio, err := h.portfolioRepo.FindByID(id)
+	if err != nil {
+		c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("PORTFOLIO_NOT_FOUND", "Portfolio tidak ditemukan"))
+		return nil, false
+	}
+	if !h.collabService.CanEdit(portfolio, middleware.GetUserID(c)) && middleware.GetUserRole(c) != "admin" {
+		c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse("FORBIDDEN", "Anda tidak memiliki akses"))
+		return nil, false
+	}
+	return portfolio, true
+}
+
+// openShareLink resolves the :token param; countView records the visit on the link
+func (h *PortfolioHandler) openShareLink(c *fiber.Ctx, countView bool) (*domain.PortfolioShareLink, bool) {
+	token := c.Params("token")
+	var link *domain.PortfolioShareLink
+	var err error
+	if countView {
+		link, err = h.shareLinkService.Open(token, time.Now())
+	} else {
+		link, err = h.shareLinkService.Find(token, time.Now())
+	}
+
+	switch {
+	case errors.Is(err, service.ErrShareLinkNotFound):
+		c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("SHARE_LINK_NOT_FOUND", "Link berbagi tidak ditemukan"))
+		return nil, false
+	case errors.Is(err, service.ErrShareLinkExpired):
+		c.Status(fiber.StatusGone).JSON(dto.ErrorResponse("SHARE_LINK_EXPIRED", "Link berbagi sudah kedaluwarsa"))
+		return nil, false
+	case err != nil:
+		c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal membuka link berbagi"))
+		return nil, false
+	}
+	return link, true
+}
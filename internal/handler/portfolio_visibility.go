@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/middleware"
+	"github.com/grafikarsa/backend/internal/repository"
+)
+
+// currentViewer describes the requester for visibility checks on published portfolios.
+// Routes must run the Optional or Required auth middleware for signed-in users to count.
+func currentViewer(c *fiber.Ctx, userRepo *repository.UserRepository) repository.Viewer {
+	userID := middleware.GetUserID(c)
+	if userID == nil {
+		return repository.Viewer{}
+	}
+
+	role := domain.UserRole(middleware.GetUserRole(c))
+	viewer := repository.Viewer{
+		UserID:  userID,
+		IsStaff: role == domain.RoleAdmin || role == domain.RoleTeacher,
+	}
+	if !viewer.IsStaff {
+		if user, err := userRepo.FindByID(*userID); err == nil && user.Kelas != nil {
+			viewer.JurusanID = &user.Kelas.JurusanID
+		}
+	}
+	return viewer
+}
+
+// isValidVisibility reports whether v is a known portfolio visibility level
+func isValidVisibility(v domain.PortfolioVisibility) bool {
+	switch v {
+	case domain.VisibilityPublic, domain.VisibilityLoggedIn, domain.VisibilityJurusan, domain.VisibilityUnlisted:
+		return true
+	}
+	return false
+}
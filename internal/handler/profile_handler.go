@@ -2,20 +2,24 @@ package handler
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/grafikarsa/backend/internal/auth"
 	"github.com/grafikarsa/backend/internal/domain"
 	"github.com/grafikarsa/backend/internal/dto"
 	"github.com/grafikarsa/backend/internal/middleware"
 	"github.com/grafikarsa/backend/internal/repository"
+	"github.com/grafikarsa/backend/internal/service"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type ProfileHandler struct {
-	userRepo  *repository.UserRepository
-	adminRepo *repository.AdminRepository
+	userRepo   *repository.UserRepository
+	adminRepo  *repository.AdminRepository
+	textFilter *service.TextFilterService
+	jwt        *auth.JWTService
 }
 
-func NewProfileHandler(userRepo *repository.UserRepository, adminRepo *repository.AdminRepository) *ProfileHandler {
-	return &ProfileHandler{userRepo: userRepo, adminRepo: adminRepo}
+func NewProfileHandler(userRepo *repository.UserRepository, adminRepo *repository.AdminRepository, textFilter *service.TextFilterService, jwtService *auth.JWTService) *ProfileHandler {
+	return &ProfileHandler{userRepo: userRepo, adminRepo: adminRepo, textFilter: textFilter, jwt: jwtService}
 }
 
 func (h *ProfileHandler) GetMe(c *fiber.Ctx) error {
@@ -122,6 +126,7 @@ func (h *ProfileHandler) UpdateMe(c *fiber.Ctx) error {
 	}
 
 	// Check username uniqueness
+	previousUsername := user.Username
 	if req.Username != nil && *req.Username != user.Username {
 		exists, _ := h.userRepo.UsernameExists(*req.Username, userID)
 		if exists {
@@ -143,6 +148,10 @@ func (h *ProfileHandler) UpdateMe(c *fiber.Ctx) error {
 		user.Email = *req.Email
 	}
 
+	var filtered service.TextFilterResult
+	if !filterTextField(c, h.textFilter, "nama", req.Nama, &filtered) || !filterTextField(c, h.textFilter, "bio", req.Bio, &filtered) {
+		return nil
+	}
 	if req.Nama != nil {
 		user.Nama = *req.Nama
 	}
@@ -150,11 +159,18 @@ func (h *ProfileHandler) UpdateMe(c *fiber.Ctx) error {
 		user.Bio = req.Bio
 	}
 
-	if err := h.userRepo.Update(user); err != nil {
+	// Renames keep the old username so profile and portfolio links shared before still resolve
+	if user.Username != previousUsername {
+		err = h.userRepo.ChangeUsername(user, previousUsername)
+	} else {
+		err = h.userRepo.Update(user)
+	}
+	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
 			"INTERNAL_ERROR", "Gagal memperbarui profil",
 		))
 	}
+	h.textFilter.Flag(domain.ReportTargetUser, user.ID, user.ID, filtered)
 
 	return c.JSON(dto.SuccessResponse(map[string]interface{}{
 		"id":       user.ID,
@@ -214,13 +230,30 @@ func (h *ProfileHandler) UpdatePassword(c *fiber.Ctx) error {
 		))
 	}
 
+	wasPending := user.MustChangePassword
 	user.PasswordHash = string(hashedPassword)
+	user.MustChangePassword = false
 	if err := h.userRepo.Update(user); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
 			"INTERNAL_ERROR", "Gagal memperbarui password",
 		))
 	}
 
+	// The current access token still carries the pending change; hand out one without it
+	if wasPending {
+		accessToken, _, err := h.jwt.GenerateAccessToken(user.ID, string(user.Role), false)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse(
+				"INTERNAL_ERROR", "Gagal membuat token",
+			))
+		}
+		return c.JSON(dto.SuccessResponse(dto.RefreshResponse{
+			AccessToken: accessToken,
+			TokenType:   "Bearer",
+			ExpiresIn:   int64(h.jwt.GetAccessExpiry().Seconds()),
+		}, "Password berhasil diubah"))
+	}
+
 	return c.JSON(dto.SuccessResponse(nil, "Password berhasil diubah"))
 }
 
@@ -78,6 +78,8 @@ func (h *PublicHandler) ListSeries(c *fiber.Ctx) error {
 			ID:         s.ID,
 			Nama:       s.Nama,
 			Deskripsi:  s.Deskripsi,
+			OpensAt:    s.OpensAt,
+			ClosesAt:   s.ClosesAt,
 			BlockCount: len(s.Blocks),
 			Blocks:     dto.SeriesBlocksToDTOs(s.Blocks),
 		})
@@ -101,6 +103,8 @@ func (h *PublicHandler) GetSeries(c *fiber.Ctx) error {
 		ID:         series.ID,
 		Nama:       series.Nama,
 		Deskripsi:  series.Deskripsi,
+		OpensAt:    series.OpensAt,
+		ClosesAt:   series.ClosesAt,
 		BlockCount: len(series.Blocks),
 		Blocks:     dto.SeriesBlocksToDTOs(series.Blocks),
 	}, ""))
@@ -116,7 +120,7 @@ func (h *PublicHandler) GetTopStudents(c *fiber.Ctx) error {
 }
 
 func (h *PublicHandler) GetTopProjects(c *fiber.Ctx) error {
-	projects, err := h.userRepo.GetTopProjects(3)
+	projects, err := h.userRepo.GetTopProjects(currentViewer(c, h.userRepo), 3)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil data top projects"))
 	}
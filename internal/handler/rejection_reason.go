@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+)
+
+// maxChecklistItems bounds the review checklist of one series
+const maxChecklistItems = 30
+
+// ============================================================================
+// REJECTION REASONS & SERIES REVIEW CHECKLISTS
+// ============================================================================
+
+// ListRejectionReasons - GET /admin/rejection-reasons
+func (h *AdminHandler) ListRejectionReasons(c *fiber.Ctx) error {
+	reasons, err := h.rejectionRepo.ListReasons(c.QueryBool("active_only", false))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("FETCH_FAILED", "Gagal mengambil alasan penolakan"))
+	}
+
+	result := make([]dto.RejectionReasonDTO, 0, len(reasons))
+	for i := range reasons {
+		result = append(result, toRejectionReasonDTO(&reasons[i]))
+	}
+	return c.JSON(dto.SuccessResponse(result, ""))
+}
+
+// CreateRejectionReason - POST /admin/rejection-reasons
+func (h *AdminHandler) CreateRejectionReason(c *fiber.Ctx) error {
+	var req dto.CreateRejectionReasonRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("INVALID_REQUEST", "Format request tidak valid"))
+	}
+
+	nama := strings.TrimSpace(req.Nama)
+	if n := utf8.RuneCountInString(nama); n < 2 || n > 100 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Nama alasan harus 2-100 karakter"))
+	}
+
+	reason := &domain.RejectionReason{
+		Nama:      nama,
+		Deskripsi: normalizeDeskripsi(req.Deskripsi),
+		IsActive:  true,
+	}
+	if err := h.rejectionRepo.CreateReason(reason); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("CREATE_FAILED", "Gagal membuat alasan penolakan"))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse(toRejectionReasonDTO(reason), "Alasan penolakan berhasil dibuat"))
+}
+
+// UpdateRejectionReason - PATCH /admin/rejection-reasons/:id
+func (h *AdminHandler) UpdateRejectionReason(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("INVALID_ID", "ID tidak valid"))
+	}
+
+	reason, err := h.rejectionRepo.FindReasonByID(id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("NOT_FOUND", "Alasan penolakan tidak ditemukan"))
+	}
+
+	var req dto.UpdateRejectionReasonRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("INVALID_REQUEST", "Format request tidak valid"))
+	}
+
+	if req.Nama != nil {
+		nama := strings.TrimSpace(*req.Nama)
+		if n := utf8.RuneCountInString(nama); n < 2 || n > 100 {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Nama alasan harus 2-100 karakter"))
+		}
+		reason.Nama = nama
+	}
+	if req.Deskripsi != nil {
+		reason.Deskripsi = normalizeDeskripsi(req.Deskripsi)
+	}
+	if req.IsActive != nil {
+		reason.IsActive = *req.IsActive
+	}
+
+	if err := h.rejectionRepo.UpdateReason(reason); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("UPDATE_FAILED", "Gagal mengupdate alasan penolakan"))
+	}
+
+	return c.JSON(dto.SuccessResponse(toRejectionReasonDTO(reason), "Alasan penolakan berhasil diupdate"))
+}
+
+// DeleteRejectionReason - DELETE /admin/rejection-reasons/:id
+// Past rejections keep showing the reason; it just can no longer be picked.
+func (h *AdminHandler) DeleteRejectionReason(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("INVALID_ID", "ID tidak valid"))
+	}
+
+	if _, err := h.rejectionRepo.FindReasonByID(id); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("NOT_FOUND", "Alasan penolakan tidak ditemukan"))
+	}
+
+	if err := h.rejectionRepo.DeleteReason(id); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("DELETE_FAILED", "Gagal menghapus alasan penolakan"))
+	}
+
+	return c.JSON(dto.SuccessResponse(nil, "Alasan penolakan berhasil dihapus"))
+}
+
+// ReorderRejectionReasons - PUT /admin/rejection-reasons/reorder
+func (h *AdminHandler) ReorderRejectionReasons(c *fiber.Ctx) error {
+	var req dto.ReorderRejectionReasonsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("INVALID_REQUEST", "Format request tidak valid"))
+	}
+
+	if len(req.Orders) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Orders tidak boleh kosong"))
+	}
+
+	orders := make([]struct {
+		ID     uuid.UUID
+		Urutan int
+	}, len(req.Orders))
+
+	for i, o := range req.Orders {
+		id, err := uuid.Parse(o.ID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("INVALID_ID", "ID tidak valid: "+o.ID))
+		}
+		orders[i].ID = id
+		orders[i].Urutan = o.Urutan
+	}
+
+	if err := h.rejectionRepo.ReorderReasons(orders); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("REORDER_FAILED", "Gagal mengubah urutan alasan penolakan"))
+	}
+
+	return c.JSON(dto.SuccessResponse(nil, "Urutan alasan penolakan berhasil diubah"))
+}
+
+// GetSeriesChecklist - GET /admin/series/:id/checklist
+func (h *AdminHandler) GetSeriesChecklist(c *fiber.Ctx) error {
+	series, ok := h.checklistSeries(c)
+	if !ok {
+		return nil
+	}
+
+	items, err := h.rejectionRepo.ListChecklist(series.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil checklist review"))
+	}
+	return c.JSON(dto.SuccessResponse(toChecklistDTOs(items), ""))
+}
+
+// UpdateSeriesChecklist - PUT /admin/series/:id/checklist
+// Replaces the points moderators go through when reviewing portfolios of the series.
+func (h *AdminHandler) UpdateSeriesChecklist(c *fiber.Ctx) error {
+	series, ok := h.checklistSeries(c)
+	if !ok {
+		return nil
+	}
+
+	var req dto.UpdateChecklistRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Request body tidak valid"))
+	}
+
+	labels := make([]string, 0, len(req.Items))
+	for _, item := range req.Items {
+		label := strings.TrimSpace(item)
+		if label == "" || utf8.RuneCountInString(label) > 200 {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal",
+				dto.ErrorDetail{Field: "items", Message: "Setiap poin checklist wajib diisi, maksimal 200 karakter"},
+			))
+		}
+		labels = append(labels, label)
+	}
+	if len(labels) > maxChecklistItems {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal",
+			dto.ErrorDetail{Field: "items", Message: "Checklist maksimal 30 poin"},
+		))
+	}
+
+	items, err := h.rejectionRepo.ReplaceChecklist(series.ID, labels)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal menyimpan checklist review"))
+	}
+	return c.JSON(dto.SuccessResponse(toChecklistDTOs(items), "Checklist review berhasil disimpan"))
+}
+
+func (h *AdminHandler) checklistSeries(c *fiber.Ctx) (*domain.Series, bool) {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "ID tidak valid"))
+		return nil, false
+	}
+	series, err := h.adminRepo.FindSeriesByID(id)
+	if err != nil {
+		c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("SERIES_NOT_FOUND", "Series tidak ditemukan"))
+		return nil, false
+	}
+	return series, true
+}
+
+func toRejectionReasonDTO(r *domain.RejectionReason) dto.RejectionReasonDTO {
+	return dto.RejectionReasonDTO{
+		ID:        r.ID,
+		Nama:      r.Nama,
+		Deskripsi: r.Deskripsi,
+		Urutan:    r.Urutan,
+		IsActive:  r.IsActive,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
+
+func toChecklistDTOs(items []domain.SeriesChecklistItem) []dto.ChecklistItemDTO {
+	result := make([]dto.ChecklistItemDTO, 0, len(items))
+	for _, item := range items {
+		result = append(result, dto.ChecklistItemDTO{ID: item.ID, Label: item.Label, Urutan: item.Urutan})
+	}
+	return result
+}
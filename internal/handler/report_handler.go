@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/middleware"
+	"github.com/grafikarsa/backend/internal/repository"
+	"github.com/grafikarsa/backend/internal/service"
+)
+
+type ReportHandler struct {
+	reportService *service.ReportService
+	reportRepo    *repository.ReportRepository
+	userRepo      *repository.UserRepository
+}
+
+func NewReportHandler(reportService *service.ReportService, reportRepo *repository.ReportRepository, userRepo *repository.UserRepository) *ReportHandler {
+	return &ReportHandler{reportService: reportService, reportRepo: reportRepo, userRepo: userRepo}
+}
+
+var reportReasons = map[domain.ReportReason]bool{
+	domain.ReportReasonSpam:          true,
+	domain.ReportReasonHarassment:    true,
+	domain.ReportReasonInappropriate: true,
+	domain.ReportReasonPlagiarism:    true,
+	domain.ReportReasonImpersonation: true,
+	domain.ReportReasonOther:         true,
+}
+
+var reportActions = map[domain.ReportAction]bool{
+	domain.ReportActionDismiss:        true,
+	domain.ReportActionHideContent:    true,
+	domain.ReportActionUnpublish:      true,
+	domain.ReportActionDeactivateUser: true,
+}
+
+// Create - POST /reports
+func (h *ReportHandler) Create(c *fiber.Ctx) error {
+	var req dto.CreateReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Request body tidak valid"))
+	}
+
+	var details []dto.ErrorDetail
+	if !isValidReportTarget(req.TargetType) {
+		details = append(details, dto.ErrorDetail{Field: "target_type", Message: "Jenis laporan harus portfolio, comment, user, atau message"})
+	}
+	if req.TargetID == uuid.Nil {
+		details = append(details, dto.ErrorDetail{Field: "target_id", Message: "Target laporan wajib diisi"})
+	}
+	reason := domain.ReportReason(req.Reason)
+	if !reportReasons[reason] {
+		details = append(details, dto.ErrorDetail{Field: "reason", Message: "Alasan laporan tidak valid"})
+	}
+	note := normalizeDeskripsi(req.Details)
+	if note == nil && reason == domain.ReportReasonOther {
+		details = append(details, dto.ErrorDetail{Field: "details", Message: "Jelaskan alasan laporan Anda"})
+	}
+	if note != nil && utf8.RuneCountInString(*note) > 1000 {
+		details = append(details, dto.ErrorDetail{Field: "details", Message: "Keterangan maksimal 1000 karakter"})
+	}
+	if len(details) > 0 {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal", details...))
+	}
+
+	report, err := h.reportService.Submit(*middleware.GetUserID(c), currentViewer(c, h.userRepo), domain.ReportTargetType(req.TargetType), req.TargetID, reason, note)
+	switch {
+	case errors.Is(err, service.ErrReportTargetNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("REPORT_TARGET_NOT_FOUND", "Konten yang dilaporkan tidak ditemukan"))
+	case errors.Is(err, service.ErrReportOwnContent):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("CANNOT_REPORT_SELF", "Anda tidak bisa melaporkan konten milik sendiri"))
+	case errors.Is(err, service.ErrAlreadyReported):
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse("ALREADY_REPORTED", "Anda sudah melaporkan konten ini dan laporan masih ditinjau"))
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengirim laporan"))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse(map[string]interface{}{
+		"id":     report.ID,
+		"status": report.Status,
+	}, "Laporan berhasil dikirim, terima kasih"))
+}
+
+// AdminList - GET /admin/reports
+// Moderation queue; every reported target appears once with its report count.
+func (h *ReportHandler) AdminList(c *fiber.Ctx) error {
+	status := domain.ReportStatus(c.Query("status", string(domain.ReportStatusPending)))
+	if status != domain.ReportStatusPending && status != domain.ReportStatusResolved && status != domain.ReportStatusDismissed {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Status laporan tidak valid"))
+	}
+	targetType := c.Query("target_type")
+	if targetType != "" && !isValidReportTarget(targetType) {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Jenis laporan tidak valid"))
+	}
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 20)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	cases, total, err := h.reportRepo.ListCases(status, targetType, page, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil laporan"))
+	}
+
+	result := make([]dto.ReportCaseDTO, 0, len(cases))
+	for _, rc := range cases {
+		reasons := make(map[string]int64, len(rc.Reasons))
+		for reason, count := range rc.Reasons {
+			reasons[string(reason)] = count
+		}
+		result = append(result, dto.ReportCaseDTO{
+			TargetType:  string(rc.TargetType),
+			TargetID:    rc.TargetID,
+			TargetUser:  toUserBriefDTO(rc.TargetUser),
+			ReportCount: rc.ReportCount,
+			Reasons:     reasons,
+		})
+	}
+
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+	return c.JSON(dto.SuccessWithMeta(result, &dto.Meta{CurrentPage: page, PerPage: limit, TotalPages: totalPages, TotalCount: total}))
+}
+
+// AdminGet - GET /admin/reports/:target_type/:target_id
+func (h *ReportHandler) AdminGet(c *fiber.Ctx) error {
+	targetType, targetID, ok := reportTargetParams(c)
+	if !ok {
+		return nil
+	}
+
+	reports, err := h.reportRepo.FindByTarget(targetType, targetID, "")
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil laporan"))
+	}
+	if len(reports) == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("REPORT_NOT_FOUND", "Laporan tidak ditemukan"))
+	}
+
+	detail := dto.ReportCaseDetailDTO{
+		Target:  h.reportService.Preview(targetType, targetID),
+		Reports: make([]dto.ReportDTO, 0, len(reports)),
+	}
+	if owner, err := h.userRepo.FindByID(reports[0].TargetUserID); err == nil {
+		detail.TargetUser = toUserBriefDTO(owner)
+	}
+	for _, r := range reports {
+		item := dto.ReportDTO{
+			ID:             r.ID,
+			Reporter:       toUserBriefDTO(r.Reporter),
+			Reason:         string(r.Reason),
+			Details:        r.Details,
+			Status:         string(r.Status),
+			ResolutionNote: r.ResolutionNote,
+			ResolvedAt:     r.ResolvedAt,
+			CreatedAt:      r.CreatedAt,
+		}
+		if r.Action != nil {
+			action := string(*r.Action)
+			item.Action = &action
+		}
+		detail.Reports = append(detail.Reports, item)
+	}
+	return c.JSON(dto.SuccessResponse(detail, ""))
+}
+
+// AdminResolve - POST /admin/reports/:target_type/:target_id/resolve
+// Takes action on the target and closes every open report on it.
+func (h *ReportHandler) AdminResolve(c *fiber.Ctx) error {
+	targetType, targetID, ok := reportTargetParams(c)
+	if !ok {
+		return nil
+	}
+
+	var req dto.ResolveReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Request body tidak valid"))
+	}
+	action := domain.ReportAction(req.Action)
+	if !reportActions[action] {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal",
+			dto.ErrorDetail{Field: "action", Message: "Tindakan harus dismiss, hide_content, unpublish, atau deactivate_user"},
+		))
+	}
+	note := normalizeDeskripsi(req.Note)
+	if note != nil && utf8.RuneCountInString(*note) > 1000 {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal",
+			dto.ErrorDetail{Field: "note", Message: "Catatan maksimal 1000 karakter"},
+		))
+	}
+
+	closed, err := h.reportService.Resolve(targetType, targetID, action, note, *middleware.GetUserID(c), time.Now())
+	switch {
+	case errors.Is(err, service.ErrReportActionInvalid):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("ACTION_NOT_APPLICABLE", "Tindakan ini tidak berlaku untuk konten atau pengguna yang dilaporkan"))
+	case errors.Is(err, service.ErrNoPendingReports):
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("REPORT_NOT_FOUND", "Tidak ada laporan yang menunggu tindakan untuk konten ini"))
+	case errors.Is(err, service.ErrReportTargetNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("REPORT_TARGET_NOT_FOUND", "Konten yang dilaporkan tidak ditemukan"))
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal menindaklanjuti laporan"))
+	}
+
+	message := "Laporan berhasil ditindaklanjuti"
+	if action == domain.ReportActionDismiss {
+		message = "Laporan diabaikan"
+	}
+	return c.JSON(dto.SuccessResponse(map[string]interface{}{
+		"target_type":    targetType,
+		"target_id":      targetID,
+		"action":         action,
+		"reports_closed": closed,
+	}, message))
+}
+
+func reportTargetParams(c *fiber.Ctx) (domain.ReportTargetType, uuid.UUID, bool) {
+	targetType := c.Params("target_type")
+	if !isValidReportTarget(targetType) {
+		c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Jenis laporan tidak valid"))
+		return "", uuid.Nil, false
+	}
+	targetID, err := uuid.Parse(c.Params("target_id"))
+	if err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "ID tidak valid"))
+		return "", uuid.Nil, false
+	}
+	return domain.ReportTargetType(targetType), targetID, true
+}
+
+func isValidReportTarget(t string) bool {
+	switch domain.ReportTargetType(t) {
+	case domain.ReportTargetPortfolio, domain.ReportTargetComment, domain.ReportTargetUser, domain.ReportTargetMessage:
+		return true
+	}
+	return false
+}
+
+func toUserBriefDTO(u *domain.User) *dto.UserBriefDTO {
+	if u == nil {
+		return nil
+	}
+	return &dto.UserBriefDTO{ID: u.ID, Username: u.Username, Nama: u.Nama, Role: string(u.Role), AvatarURL: u.AvatarURL}
+}
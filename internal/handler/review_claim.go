@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/middleware"
+	"github.com/grafikarsa/backend/internal/service"
+)
+
+// ============================================================================
+// REVIEW CLAIMS & REVIEWER POOLS
+// ============================================================================
+
+// ClaimPortfolio - POST /admin/portfolios/:id/claim
+// Locks a pending portfolio for the requester so other moderators leave it alone.
+func (h *AdminHandler) ClaimPortfolio(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "ID tidak valid"))
+	}
+
+	reviewerID := *middleware.GetUserID(c)
+	claim, err := h.reviewClaims.Claim(id, reviewerID, time.Now())
+	switch {
+	case errors.Is(err, service.ErrNotPendingReview):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("NOT_PENDING_REVIEW", "Portfolio tidak sedang menunggu review"))
+	case errors.Is(err, service.ErrClaimedByOther):
+		return claimedByOther(c, claim)
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengklaim portfolio"))
+	}
+	return c.JSON(dto.SuccessResponse(toReviewClaimDTO(claim, reviewerID), "Portfolio berhasil diklaim"))
+}
+
+// ReleaseClaim - DELETE /admin/portfolios/:id/claim
+// Admins may release any claim, including an assignment another reviewer has not picked up.
+func (h *AdminHandler) ReleaseClaim(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "ID tidak valid"))
+	}
+
+	if middleware.GetUserRole(c) == "admin" {
+		if err := h.reviewClaims.ForceRelease(id); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal melepas klaim portfolio"))
+		}
+		return c.JSON(dto.SuccessResponse(nil, "Klaim portfolio dilepas"))
+	}
+
+	err = h.reviewClaims.Release(id, *middleware.GetUserID(c))
+	if errors.Is(err, service.ErrNotClaimed) {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("CLAIM_NOT_FOUND", "Anda tidak sedang mengklaim portfolio ini"))
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal melepas klaim portfolio"))
+	}
+	return c.JSON(dto.SuccessResponse(nil, "Klaim portfolio dilepas"))
+}
+
+// MyReviewQueue - GET /admin/portfolios/my-queue
+// Pending portfolios the requester claimed or was assigned, oldest first.
+func (h *AdminHandler) MyReviewQueue(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	reviewerID := *middleware.GetUserID(c)
+	portfolios, total, err := h.reviewRepo.MyQueue(reviewerID, time.Now(), page, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil antrean review"))
+	}
+	result := h.pendingPortfolioDTOs(portfolios, reviewerID)
+
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+	return c.JSON(dto.SuccessWithMeta(result, &dto.Meta{CurrentPage: page, PerPage: limit, TotalPages: totalPages, TotalCount: total}))
+}
+
+// ListReviewerPools - GET /admin/review-pools
+func (h *AdminHandler) ListReviewerPools(c *fiber.Ctx) error {
+	members, err := h.reviewRepo.ListPools()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil pembagian reviewer"))
+	}
+
+	result := []dto.ReviewerPoolDTO{}
+	for _, m := range members {
+		if len(result) == 0 || result[len(result)-1].JurusanID != m.JurusanID {
+			result = append(result, dto.ReviewerPoolDTO{JurusanID: m.JurusanID, Reviewers: []dto.ReviewerPoolMemberDTO{}})
+		}
+		pool := &result[len(result)-1]
+		pool.Reviewers = append(pool.Reviewers, dto.ReviewerPoolMemberDTO{
+			Reviewer:       toUserBriefDTO(m.Reviewer),
+			LastAssignedAt: m.LastAssignedAt,
+		})
+	}
+	return c.JSON(dto.SuccessResponse(result, ""))
+}
+
+// SetReviewerPool - PUT /admin/review-pools/:jurusan_id
+// Replaces the moderators who receive new submissions of a jurusan in turn; an empty list
+// turns automatic assignment off for it.
+func (h *AdminHandler) SetReviewerPool(c *fiber.Ctx) error {
+	jurusanID, err := uuid.Parse(c.Params("jurusan_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "ID tidak valid"))
+	}
+	if _, err := h.adminRepo.FindJurusanByID(jurusanID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("JURUSAN_NOT_FOUND", "Jurusan tidak ditemukan"))
+	}
+
+	var req dto.SetReviewerPoolRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Request body tidak valid"))
+	}
+
+	err = h.reviewClaims.SetPool(jurusanID, req.ReviewerIDs)
+	if errors.Is(err, service.ErrNotReviewer) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal",
+			dto.ErrorDetail{Field: "reviewer_ids", Message: "Semua reviewer harus user aktif dengan akses moderasi"},
+		))
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal menyimpan pembagian reviewer"))
+	}
+	return c.JSON(dto.SuccessResponse(nil, "Pembagian reviewer berhasil disimpan"))
+}
+
+// checkClaim refuses to moderate a portfolio another reviewer has claimed. Admins are held
+// back too; taking a portfolio over is an explicit force-release through ReleaseClaim.
+func (h *AdminHandler) checkClaim(c *fiber.Ctx, portfolioID uuid.UUID) bool {
+	claim, err := h.reviewClaims.CheckClaim(portfolioID, *middleware.GetUserID(c), time.Now())
+	if errors.Is(err, service.ErrClaimedByOther) {
+		claimedByOther(c, claim)
+		return false
+	}
+	if err != nil {
+		c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal memeriksa klaim portfolio"))
+		return false
+	}
+	return true
+}
+
+func claimedByOther(c *fiber.Ctx, claim *domain.ReviewClaim) error {
+	message := "Portfolio sedang ditinjau moderator lain"
+	if claim != nil && claim.Reviewer != nil {
+		message = "Portfolio sedang ditinjau oleh " + claim.Reviewer.Nama
+	}
+	return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse("CLAIMED_BY_OTHER", message))
+}
+
+// pendingPortfolioDTOs builds the review list rows, each with the claim currently holding it
+func (h *AdminHandler) pendingPortfolioDTOs(portfolios []domain.Portfolio, reviewerID uuid.UUID) []dto.AdminPortfolioDTO {
+	ids := make([]uuid.UUID, 0, len(portfolios))
+	for _, p := range portfolios {
+		ids = append(ids, p.ID)
+	}
+	claims, _ := h.reviewRepo.ActiveClaims(ids, time.Now())
+
+	var result []dto.AdminPortfolioDTO
+	for _, p := range portfolios {
+		pDTO := dto.AdminPortfolioDTO{
+			ID: p.ID, Judul: p.Judul, Slug: p.Slug, ThumbnailURL: p.ThumbnailURL, Status: string(p.Status), CreatedAt: p.CreatedAt,
+			HasPendingEdit: p.IsApproved(),
+		}
+		if p.User != nil {
+			var kelasNama, jurusanNama *string
+			if p.User.Kelas != nil {
+				kelasNama = &p.User.Kelas.Nama
+				if p.User.Kelas.Jurusan != nil {
+					jurusanNama = &p.User.Kelas.Jurusan.Nama
+				}
+			}
+			pDTO.User = &dto.PortfolioUserDTO{
+				ID: p.User.ID, Username: p.User.Username, Nama: p.User.Nama, AvatarURL: p.User.AvatarURL,
+				Role: string(p.User.Role), KelasNama: kelasNama, JurusanNama: jurusanNama,
+			}
+		}
+		if claim, ok := claims[p.ID]; ok {
+			pDTO.Claim = toReviewClaimDTO(&claim, reviewerID)
+		}
+		result = append(result, pDTO)
+	}
+	return result
+}
+
+func toReviewClaimDTO(claim *domain.ReviewClaim, reviewerID uuid.UUID) *dto.ReviewClaimDTO {
+	return &dto.ReviewClaimDTO{
+		Reviewer:   toUserBriefDTO(claim.Reviewer),
+		IsMine:     claim.ReviewerID == reviewerID,
+		IsAssigned: claim.IsAssigned,
+		ClaimedAt:  claim.ClaimedAt,
+		ExpiresAt:  claim.ExpiresAt,
+	}
+}
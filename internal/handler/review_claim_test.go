@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/repository"
+	"github.com/grafikarsa/backend/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// An admin cannot approve or reject over a live claim; they have to release it first
+func TestCheckClaim_AdminRespectsClaims(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.User{}, &domain.ReviewClaim{}))
+
+	moderator := domain.User{Username: "moderator", Email: "moderator@example.com", Nama: "Moderator", Role: domain.RoleTeacher}
+	require.NoError(t, db.Create(&moderator).Error)
+	portfolioID, adminID := uuid.New(), uuid.New()
+	now := time.Now()
+	require.NoError(t, db.Create(&domain.ReviewClaim{PortfolioID: portfolioID, ReviewerID: moderator.ID, ClaimedAt: now, ExpiresAt: now.Add(time.Hour)}).Error)
+
+	reviewRepo := repository.NewReviewRepository(db)
+	h := &AdminHandler{reviewClaims: service.NewReviewClaimService(reviewRepo, nil, nil)}
+
+	app := fiber.New()
+	app.Post("/:id/approve", func(c *fiber.Ctx) error {
+		c.Locals("userID", adminID)
+		c.Locals("userRole", "admin")
+		if !h.checkClaim(c, uuid.MustParse(c.Params("id"))) {
+			return nil
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/"+portfolioID.String()+"/approve", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+
+	require.NoError(t, h.reviewClaims.ForceRelease(portfolioID))
+	resp, err = app.Test(httptest.NewRequest("POST", "/"+portfolioID.String()+"/approve", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/middleware"
+	"github.com/grafikarsa/backend/internal/repository"
+	"github.com/grafikarsa/backend/internal/service"
+)
+
+type RevisionHandler struct {
+	revisionRepo  *repository.RevisionRepository
+	portfolioRepo *repository.PortfolioRepository
+	adminRepo     *repository.AdminRepository
+	teacherRepo   *repository.TeacherRepository
+}
+
+func NewRevisionHandler(revisionRepo *repository.RevisionRepository, portfolioRepo *repository.PortfolioRepository, adminRepo *repository.AdminRepository, teacherRepo *repository.TeacherRepository) *RevisionHandler {
+	return &RevisionHandler{
+		revisionRepo:  revisionRepo,
+		portfolioRepo: portfolioRepo,
+		adminRepo:     adminRepo,
+		teacherRepo:   teacherRepo,
+	}
+}
+
+// List - GET /portfolios/:id/revisions
+func (h *RevisionHandler) List(c *fiber.Ctx) error {
+	portfolio, ok := h.accessiblePortfolio(c)
+	if !ok {
+		return nil
+	}
+
+	revisions, err := h.revisionRepo.ListByPortfolio(portfolio.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil riwayat revisi"))
+	}
+
+	result := make([]dto.RevisionSummaryDTO, 0, len(revisions))
+	for i := range revisions {
+		result = append(result, dto.RevisionToSummaryDTO(&revisions[i]))
+	}
+	return c.JSON(dto.SuccessResponse(result, ""))
+}
+
+// Get - GET /portfolios/:id/revisions/:number
+func (h *RevisionHandler) Get(c *fiber.Ctx) error {
+	portfolio, ok := h.accessiblePortfolio(c)
+	if !ok {
+		return nil
+	}
+
+	revision, ok := h.findRevision(c, portfolio.ID, c.Params("number"))
+	if !ok {
+		return nil
+	}
+
+	return c.JSON(dto.SuccessResponse(dto.RevisionDetailDTO{
+		RevisionSummaryDTO: dto.RevisionToSummaryDTO(revision),
+		Snapshot:           revision.Snapshot,
+	}, ""))
+}
+
+// Diff - GET /portfolios/:id/revisions/diff?from=1&to=2
+func (h *RevisionHandler) Diff(c *fiber.Ctx) error {
+	portfolio, ok := h.accessiblePortfolio(c)
+	if !ok {
+		return nil
+	}
+
+	from, ok := h.findRevision(c, portfolio.ID, c.Query("from"))
+	if !ok {
+		return nil
+	}
+	to, ok := h.findRevision(c, portfolio.ID, c.Query("to"))
+	if !ok {
+		return nil
+	}
+
+	diff := service.DiffSnapshots(from.Snapshot, to.Snapshot)
+	diff.From = dto.RevisionToSummaryDTO(from)
+	diff.To = dto.RevisionToSummaryDTO(to)
+	return c.JSON(dto.SuccessResponse(diff, ""))
+}
+
+// accessiblePortfolio loads the portfolio from :id and checks that the caller is its owner,
+// an admin or reviewer, or the owner's wali kelas
+func (h *RevisionHandler) accessiblePortfolio(c *fiber.Ctx) (*domain.Portfolio, bool) {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "ID tidak valid"))
+		return nil, false
+	}
+
+	portfolio, err := h.portfolioRepo.FindByID(id)
+	if err != nil {
+		c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("PORTFOLIO_NOT_FOUND", "Portfolio tidak ditemukan"))
+		return nil, false
+	}
+
+	userID := middleware.GetUserID(c)
+	if userID == nil {
+		c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse("UNAUTHORIZED", "Unauthorized"))
+		return nil, false
+	}
+
+	allowed := *userID == portfolio.UserID
+	switch middleware.GetUserRole(c) {
+	case string(domain.RoleAdmin):
+		allowed = true
+	case string(domain.RoleTeacher):
+		allowed, _ = h.teacherRepo.IsStudentOfTeacher(*userID, portfolio.UserID)
+	}
+	if !allowed {
+		allowed, _ = h.adminRepo.HasCapability(*userID, "moderation")
+	}
+	if !allowed {
+		c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse("FORBIDDEN", "Anda tidak memiliki akses ke portfolio ini"))
+		return nil, false
+	}
+
+	return portfolio, true
+}
+
+func (h *RevisionHandler) findRevision(c *fiber.Ctx, portfolioID uuid.UUID, raw string) (*domain.PortfolioRevision, bool) {
+	number, err := strconv.Atoi(raw)
+	if err != nil || number < 1 {
+		c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Nomor revisi tidak valid"))
+		return nil, false
+	}
+
+	revision, err := h.revisionRepo.FindByNumber(portfolioID, number)
+	if err != nil {
+		c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("REVISION_NOT_FOUND", "Revisi "+raw+" tidak ditemukan"))
+		return nil, false
+	}
+	return revision, true
+}
+
+// recordRevision snapshots a portfolio after a workflow transition. A failed snapshot is
+// logged and never fails the transition itself.
+func recordRevision(revisionService *service.RevisionService, portfolio *domain.Portfolio, reason domain.RevisionReason, createdBy *uuid.UUID) {
+	recordSnapshot(revisionService, portfolio.ID, domain.NewPortfolioSnapshot(portfolio), reason, createdBy)
+}
+
+// recordSnapshot is recordRevision for content that is not the live portfolio, such as a working draft
+func recordSnapshot(revisionService *service.RevisionService, portfolioID uuid.UUID, snapshot domain.PortfolioSnapshot, reason domain.RevisionReason, createdBy *uuid.UUID) {
+	if revisionService == nil {
+		return
+	}
+	if _, err := revisionService.RecordSnapshot(portfolioID, snapshot, reason, createdBy); err != nil {
+		log.Printf("[REVISION] Failed to record %s revision for portfolio %s: %v", reason, portfolioID, err)
+	}
+}
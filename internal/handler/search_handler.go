@@ -106,7 +106,7 @@ func (h *SearchHandler) SearchPortfolios(c *fiber.Ctx) error {
 
 	currentUserID := middleware.GetUserID(c)
 
-	portfolios, total, err := h.portfolioRepo.ListPublished(query, tagIDs, jurusanID, kelasID, nil, "-published_at", page, limit)
+	portfolios, total, err := h.portfolioRepo.ListPublished(currentViewer(c, h.userRepo), query, tagIDs, jurusanID, kelasID, nil, "-published_at", page, limit)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mencari portfolios"))
 	}
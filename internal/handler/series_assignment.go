@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/middleware"
+	"github.com/grafikarsa/backend/internal/service"
+)
+
+// GetSeriesReport - GET /admin/series/:id/report
+// Lists every targeted student with their progress on the series.
+func (h *AdminHandler) GetSeriesReport(c *fiber.Ctx) error {
+	series, ok := findReportSeries(c, h.adminRepo.FindSeriesByID)
+	if !ok {
+		return nil
+	}
+
+	report, err := h.assignmentService.Report(series, nil, time.Now())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil laporan series"))
+	}
+	return c.JSON(dto.SuccessResponse(report, ""))
+}
+
+// GetSeriesReport - GET /teacher/series/:id/report
+// Same report as the admin one, limited to students of the teacher's own classes.
+func (h *TeacherHandler) GetSeriesReport(c *fiber.Ctx) error {
+	series, ok := findReportSeries(c, h.adminRepo.FindSeriesByID)
+	if !ok {
+		return nil
+	}
+
+	report, err := h.assignmentService.Report(series, middleware.GetUserID(c), time.Now())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil laporan series"))
+	}
+	return c.JSON(dto.SuccessResponse(report, ""))
+}
+
+func findReportSeries(c *fiber.Ctx, find func(uuid.UUID) (*domain.Series, error)) (*domain.Series, bool) {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "ID tidak valid"))
+		return nil, false
+	}
+	series, err := find(id)
+	if err != nil {
+		c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("SERIES_NOT_FOUND", "Series tidak ditemukan"))
+		return nil, false
+	}
+	return series, true
+}
+
+func (h *AdminHandler) toSeriesDetailDTO(series *domain.Series, portfolioCount int64) dto.SeriesDetailDTO {
+	targets, _ := h.assignmentRepo.ListTargets(series.ID)
+	return dto.SeriesDetailDTO{
+		ID:             series.ID,
+		Nama:           series.Nama,
+		Deskripsi:      series.Deskripsi,
+		IsActive:       series.IsActive,
+		OpensAt:        series.OpensAt,
+		ClosesAt:       series.ClosesAt,
+		Targets:        dto.SeriesTargetsToDTOs(targets),
+		Blocks:         dto.SeriesBlocksToDTOs(series.Blocks),
+		PortfolioCount: portfolioCount,
+		CreatedAt:      series.CreatedAt,
+	}
+}
+
+// checkSeriesTargets rejects target kelas/jurusan IDs that do not exist
+func (h *AdminHandler) checkSeriesTargets(c *fiber.Ctx, kelasIDs, jurusanIDs []uuid.UUID) bool {
+	var details []dto.ErrorDetail
+
+	kelasCount, err := h.assignmentRepo.CountExistingKelas(kelasIDs)
+	if err != nil {
+		c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal memvalidasi target series"))
+		return false
+	}
+	if kelasCount != int64(len(uniqueIDs(kelasIDs))) {
+		details = append(details, dto.ErrorDetail{Field: "target_kelas_ids", Message: "Kelas target tidak ditemukan"})
+	}
+
+	jurusanCount, err := h.assignmentRepo.CountExistingJurusan(jurusanIDs)
+	if err != nil {
+		c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal memvalidasi target series"))
+		return false
+	}
+	if jurusanCount != int64(len(uniqueIDs(jurusanIDs))) {
+		details = append(details, dto.ErrorDetail{Field: "target_jurusan_ids", Message: "Jurusan target tidak ditemukan"})
+	}
+
+	if len(details) > 0 {
+		c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal", details...))
+		return false
+	}
+	return true
+}
+
+// validateSeriesBlockTypes rejects template blocks of an unknown block type
+func validateSeriesBlockTypes(blocks []dto.CreateSeriesBlockRequest) []dto.ErrorDetail {
+	var details []dto.ErrorDetail
+	for i, b := range blocks {
+		if !service.IsValidBlockType(domain.ContentBlockType(b.BlockType)) {
+			details = append(details, dto.ErrorDetail{Field: fmt.Sprintf("blocks.%d.block_type", i), Message: "Tipe block tidak dikenal"})
+		}
+	}
+	return details
+}
+
+// validateSeriesSchedule checks that a series closes after it opens
+func validateSeriesSchedule(opensAt, closesAt *time.Time) *dto.ErrorDetail {
+	if opensAt != nil && closesAt != nil && !closesAt.After(*opensAt) {
+		return &dto.ErrorDetail{Field: "closes_at", Message: "Deadline harus setelah waktu mulai"}
+	}
+	return nil
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
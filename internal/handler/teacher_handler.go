@@ -0,0 +1,310 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/middleware"
+	"github.com/grafikarsa/backend/internal/repository"
+	"github.com/grafikarsa/backend/internal/service"
+)
+
+// TeacherHandler exposes class-scoped views for wali kelas. Access is limited to
+// the teacher's own classes; no admin capability is involved.
+type TeacherHandler struct {
+	teacherRepo       *repository.TeacherRepository
+	adminRepo         *repository.AdminRepository
+	portfolioRepo     *repository.PortfolioRepository
+	assessmentRepo    *repository.AssessmentRepository
+	assignmentService *service.SeriesAssignmentService
+}
+
+func NewTeacherHandler(teacherRepo *repository.TeacherRepository, adminRepo *repository.AdminRepository, portfolioRepo *repository.PortfolioRepository, assessmentRepo *repository.AssessmentRepository, assignmentService *service.SeriesAssignmentService) *TeacherHandler {
+	return &TeacherHandler{
+		teacherRepo:       teacherRepo,
+		adminRepo:         adminRepo,
+		portfolioRepo:     portfolioRepo,
+		assessmentRepo:    assessmentRepo,
+		assignmentService: assignmentService,
+	}
+}
+
+// ListClasses - GET /teacher/classes
+func (h *TeacherHandler) ListClasses(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	kelas, err := h.teacherRepo.ListClasses(*userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil data kelas"))
+	}
+
+	result := make([]dto.KelasDetailDTO, 0, len(kelas))
+	for _, k := range kelas {
+		kDTO := dto.KelasDetailDTO{
+			ID: k.ID, Nama: k.Nama, Tingkat: k.Tingkat, Rombel: k.Rombel, CreatedAt: k.CreatedAt,
+		}
+		if k.TahunAjaran != nil {
+			kDTO.TahunAjaran = &dto.TahunAjaranDTO{
+				ID: k.TahunAjaran.ID, TahunMulai: k.TahunAjaran.TahunMulai, IsActive: k.TahunAjaran.IsActive,
+			}
+		}
+		if k.Jurusan != nil {
+			kDTO.Jurusan = &dto.JurusanDTO{ID: k.Jurusan.ID, Nama: k.Jurusan.Nama, Kode: k.Jurusan.Kode}
+		}
+		kDTO.StudentCount, _ = h.adminRepo.GetKelasStudentCount(k.ID)
+		result = append(result, kDTO)
+	}
+
+	return c.JSON(dto.SuccessResponse(result, ""))
+}
+
+// ListStudents - GET /teacher/classes/:id/students
+func (h *TeacherHandler) ListStudents(c *fiber.Ctx) error {
+	kelasID, ok := h.ownedKelas(c)
+	if !ok {
+		return nil
+	}
+
+	students, err := h.teacherRepo.ListStudents(kelasID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil data siswa"))
+	}
+	counts, _ := h.teacherRepo.GetStudentPortfolioCounts(kelasID)
+
+	result := make([]dto.TeacherStudentDTO, 0, len(students))
+	for _, s := range students {
+		portfolioCounts := counts[s.ID]
+		if portfolioCounts == nil {
+			portfolioCounts = map[string]int64{}
+		}
+		result = append(result, dto.TeacherStudentDTO{
+			ID: s.ID, Username: s.Username, Nama: s.Nama, NIS: s.NIS, NISN: s.NISN, AvatarURL: s.AvatarURL,
+			IsActive: s.IsActive, LastLoginAt: s.LastLoginAt, PortfolioCounts: portfolioCounts,
+		})
+	}
+
+	return c.JSON(dto.SuccessWithMeta(result, &dto.Meta{TotalCount: int64(len(result))}))
+}
+
+// ListPortfolios - GET /teacher/classes/:id/portfolios
+// Includes drafts, pending and rejected work, unlike the public listing.
+func (h *TeacherHandler) ListPortfolios(c *fiber.Ctx) error {
+	kelasID, ok := h.ownedKelas(c)
+	if !ok {
+		return nil
+	}
+
+	search := c.Query("search")
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	var status *string
+	if s := c.Query("status"); s != "" {
+		status = &s
+	}
+
+	portfolios, total, err := h.teacherRepo.ListClassPortfolios(kelasID, status, search, page, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("INTERNAL_ERROR", "Gagal mengambil data portfolio"))
+	}
+
+	result := make([]dto.AdminPortfolioDTO, 0, len(portfolios))
+	for _, p := range portfolios {
+		pDTO := dto.AdminPortfolioDTO{
+			ID: p.ID, Judul: p.Judul, Slug: p.Slug, ThumbnailURL: p.ThumbnailURL, Status: string(p.Status), CreatedAt: p.CreatedAt,
+		}
+		if p.User != nil {
+			pDTO.User = &dto.PortfolioUserDTO{
+				ID: p.User.ID, Username: p.User.Username, Nama: p.User.Nama, AvatarURL: p.User.AvatarURL, Role: string(p.User.Role),
+			}
+		}
+		result = append(result, pDTO)
+	}
+
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+
+	return c.JSON(dto.SuccessWithMeta(result, &dto.Meta{CurrentPage: page, PerPage: limit, TotalPages: totalPages, TotalCount: total}))
+}
+
+// GetPortfolio - GET /teacher/portfolios/:id
+func (h *TeacherHandler) GetPortfolio(c *fiber.Ctx) error {
+	portfolio, ok := h.studentPortfolio(c)
+	if !ok {
+		return nil
+	}
+
+	likeCount, _ := h.portfolioRepo.GetLikeCount(portfolio.ID)
+
+	result := dto.PortfolioDetailDTO{
+		ID: portfolio.ID, Judul: portfolio.Judul, Slug: portfolio.Slug, ThumbnailURL: portfolio.ThumbnailURL,
+		Status: string(portfolio.Status), AdminReviewNote: portfolio.AdminReviewNote, ReviewedAt: portfolio.ReviewedAt,
+		PublishedAt: portfolio.PublishedAt, CreatedAt: portfolio.CreatedAt, UpdatedAt: portfolio.UpdatedAt, LikeCount: likeCount,
+	}
+
+	if portfolio.User != nil {
+		var kelasNama, jurusanNama *string
+		if portfolio.User.Kelas != nil {
+			kelasNama = &portfolio.User.Kelas.Nama
+			if portfolio.User.Kelas.Jurusan != nil {
+				jurusanNama = &portfolio.User.Kelas.Jurusan.Nama
+			}
+		}
+		result.User = &dto.PortfolioUserDTO{
+			ID: portfolio.User.ID, Username: portfolio.User.Username, Nama: portfolio.User.Nama,
+			AvatarURL: portfolio.User.AvatarURL, Role: string(portfolio.User.Role), KelasNama: kelasNama, JurusanNama: jurusanNama,
+		}
+	}
+	if portfolio.Series != nil {
+		result.Series = &dto.PortfolioSeriesDTO{ID: portfolio.Series.ID, Nama: portfolio.Series.Nama}
+	}
+
+	for _, t := range portfolio.Tags {
+		result.Tags = append(result.Tags, dto.TagDTO{ID: t.ID, Nama: t.Nama})
+	}
+	for _, b := range portfolio.ContentBlocks {
+		result.ContentBlocks = append(result.ContentBlocks, dto.ContentBlockToDTO(b))
+	}
+
+	return c.JSON(dto.SuccessResponse(result, ""))
+}
+
+// GetPortfolioAssessment - GET /teacher/portfolios/:id/assessment
+func (h *TeacherHandler) GetPortfolioAssessment(c *fiber.Ctx) error {
+	portfolio, ok := h.studentPortfolio(c)
+	if !ok {
+		return nil
+	}
+
+	assessment, err := h.assessmentRepo.FindAssessmentByPortfolioID(portfolio.ID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("NOT_FOUND", "Portfolio belum dinilai"))
+	}
+
+	return c.JSON(dto.SuccessResponse(toAssessmentResponse(assessment), ""))
+}
+
+// ListAssessments - GET /teacher/classes/:id/assessments
+func (h *TeacherHandler) ListAssessments(c *fiber.Ctx) error {
+	kelasID, ok := h.ownedKelas(c)
+	if !ok {
+		return nil
+	}
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 50)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	portfolios, total, err := h.teacherRepo.ListClassAssessments(kelasID, page, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("FETCH_FAILED", "Gagal mengambil data penilaian"))
+	}
+
+	published, assessed, average, _ := h.teacherRepo.GetClassAssessmentSummary(kelasID)
+	result := dto.ClassAssessmentsResponse{
+		Summary: dto.ClassAssessmentSummaryDTO{
+			Published: published, Assessed: assessed, Pending: published - assessed, AverageScore: average,
+		},
+		Portfolios: make([]dto.PortfolioForAssessment, 0, len(portfolios)),
+	}
+
+	for _, p := range portfolios {
+		resp := dto.PortfolioForAssessment{
+			ID:           p.Portfolio.ID.String(),
+			Judul:        p.Portfolio.Judul,
+			Slug:         p.Portfolio.Slug,
+			ThumbnailURL: p.Portfolio.ThumbnailURL,
+			PublishedAt:  p.Portfolio.PublishedAt,
+		}
+		if p.Portfolio.User != nil {
+			resp.User = &dto.UserBriefResponse{
+				ID:        p.Portfolio.User.ID.String(),
+				Username:  p.Portfolio.User.Username,
+				Nama:      p.Portfolio.User.Nama,
+				AvatarURL: p.Portfolio.User.AvatarURL,
+			}
+		}
+		if p.Assessment != nil {
+			resp.Assessment = &dto.AssessmentBrief{
+				ID:         p.Assessment.ID.String(),
+				TotalScore: p.Assessment.TotalScore,
+				AssessedAt: p.Assessment.CreatedAt,
+			}
+			if p.Assessment.Assessor != nil {
+				resp.Assessment.Assessor = &dto.UserBriefResponse{
+					ID:        p.Assessment.Assessor.ID.String(),
+					Username:  p.Assessment.Assessor.Username,
+					Nama:      p.Assessment.Assessor.Nama,
+					AvatarURL: p.Assessment.Assessor.AvatarURL,
+				}
+			}
+		}
+		result.Portfolios = append(result.Portfolios, resp)
+	}
+
+	totalPages := (total + int64(limit) - 1) / int64(limit)
+
+	return c.JSON(dto.SuccessWithMeta(result, &dto.Meta{CurrentPage: page, PerPage: limit, TotalPages: int(totalPages), TotalCount: total}))
+}
+
+// ownedKelas parses :id and checks the current teacher is its wali kelas.
+// On failure it writes the error response and returns false.
+func (h *TeacherHandler) ownedKelas(c *fiber.Ctx) (uuid.UUID, bool) {
+	userID := middleware.GetUserID(c)
+
+	kelasID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		_ = c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "ID tidak valid"))
+		return uuid.Nil, false
+	}
+
+	isWali, _ := h.teacherRepo.IsWaliKelas(*userID, kelasID)
+	if !isWali {
+		_ = c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse("FORBIDDEN", "Anda bukan wali kelas ini"))
+		return uuid.Nil, false
+	}
+
+	return kelasID, true
+}
+
+// studentPortfolio loads portfolio :id if its owner is in one of the teacher's classes.
+// On failure it writes the error response and returns false.
+func (h *TeacherHandler) studentPortfolio(c *fiber.Ctx) (*domain.Portfolio, bool) {
+	userID := middleware.GetUserID(c)
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		_ = c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "ID tidak valid"))
+		return nil, false
+	}
+
+	portfolio, err := h.portfolioRepo.FindByID(id)
+	if err != nil {
+		_ = c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("PORTFOLIO_NOT_FOUND", "Portfolio tidak ditemukan"))
+		return nil, false
+	}
+
+	isStudent, _ := h.teacherRepo.IsStudentOfTeacher(*userID, portfolio.UserID)
+	if !isStudent {
+		_ = c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse("FORBIDDEN", "Portfolio ini bukan milik siswa Anda"))
+		return nil, false
+	}
+
+	return portfolio, true
+}
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/middleware"
+	"github.com/grafikarsa/backend/internal/repository"
+	"github.com/grafikarsa/backend/internal/service"
+)
+
+type TextFilterHandler struct {
+	repo   *repository.TextFilterRepository
+	filter *service.TextFilterService
+}
+
+func NewTextFilterHandler(repo *repository.TextFilterRepository, filter *service.TextFilterService) *TextFilterHandler {
+	return &TextFilterHandler{repo: repo, filter: filter}
+}
+
+// List - GET /admin/text-filters
+func (h *TextFilterHandler) List(c *fiber.Ctx) error {
+	rules, err := h.repo.List(c.Query("action"), c.Query("bahasa"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("FETCH_FAILED", "Gagal mengambil aturan filter"))
+	}
+
+	result := make([]dto.TextFilterRuleDTO, 0, len(rules))
+	for i := range rules {
+		result = append(result, toTextFilterRuleDTO(&rules[i]))
+	}
+	return c.JSON(dto.SuccessResponse(result, ""))
+}
+
+// Create - POST /admin/text-filters
+func (h *TextFilterHandler) Create(c *fiber.Ctx) error {
+	var req dto.CreateTextFilterRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("INVALID_REQUEST", "Format request tidak valid"))
+	}
+
+	rule := &domain.TextFilterRule{
+		Pattern:   strings.TrimSpace(req.Pattern),
+		MatchType: domain.TextFilterMatch(req.MatchType),
+		Action:    domain.TextFilterAction(req.Action),
+		Bahasa:    normalizeDeskripsi(req.Bahasa),
+		IsActive:  true,
+		CreatedBy: middleware.GetUserID(c),
+	}
+	if rule.MatchType == "" {
+		rule.MatchType = domain.TextFilterWord
+	}
+	if details := validateTextFilterRule(rule); len(details) > 0 {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal", details...))
+	}
+
+	if err := h.repo.Create(rule); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("CREATE_FAILED", "Gagal membuat aturan filter"))
+	}
+	h.filter.Invalidate()
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse(toTextFilterRuleDTO(rule), "Aturan filter berhasil dibuat"))
+}
+
+// Update - PATCH /admin/text-filters/:id
+func (h *TextFilterHandler) Update(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("INVALID_ID", "ID tidak valid"))
+	}
+
+	rule, err := h.repo.FindByID(id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("NOT_FOUND", "Aturan filter tidak ditemukan"))
+	}
+
+	var req dto.UpdateTextFilterRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("INVALID_REQUEST", "Format request tidak valid"))
+	}
+
+	if req.Pattern != nil {
+		rule.Pattern = strings.TrimSpace(*req.Pattern)
+	}
+	if req.MatchType != nil {
+		rule.MatchType = domain.TextFilterMatch(*req.MatchType)
+	}
+	if req.Action != nil {
+		rule.Action = domain.TextFilterAction(*req.Action)
+	}
+	if req.Bahasa != nil {
+		rule.Bahasa = normalizeDeskripsi(req.Bahasa)
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+	if details := validateTextFilterRule(rule); len(details) > 0 {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("VALIDATION_ERROR", "Validasi gagal", details...))
+	}
+
+	if err := h.repo.Update(rule); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("UPDATE_FAILED", "Gagal mengupdate aturan filter"))
+	}
+	h.filter.Invalidate()
+
+	return c.JSON(dto.SuccessResponse(toTextFilterRuleDTO(rule), "Aturan filter berhasil diupdate"))
+}
+
+// Delete - DELETE /admin/text-filters/:id
+func (h *TextFilterHandler) Delete(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("INVALID_ID", "ID tidak valid"))
+	}
+
+	if _, err := h.repo.FindByID(id); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("NOT_FOUND", "Aturan filter tidak ditemukan"))
+	}
+
+	if err := h.repo.Delete(id); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse("DELETE_FAILED", "Gagal menghapus aturan filter"))
+	}
+	h.filter.Invalidate()
+
+	return c.JSON(dto.SuccessResponse(nil, "Aturan filter berhasil dihapus"))
+}
+
+// Test - POST /admin/text-filters/test
+// Shows what the active rules would do to a sample text, without saving anything.
+func (h *TextFilterHandler) Test(c *fiber.Ctx) error {
+	var req dto.TestTextFilterRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("INVALID_REQUEST", "Format request tidak valid"))
+	}
+
+	text, result := h.filter.Apply(req.Text)
+	matches := result.Matches
+	if matches == nil {
+		matches = []string{}
+	}
+	return c.JSON(dto.SuccessResponse(dto.TextFilterTestDTO{
+		Text:    text,
+		Blocked: result.Blocked,
+		Flagged: result.Flagged,
+		Matches: matches,
+	}, ""))
+}
+
+func validateTextFilterRule(rule *domain.TextFilterRule) []dto.ErrorDetail {
+	var details []dto.ErrorDetail
+	if rule.Pattern == "" || utf8.RuneCountInString(rule.Pattern) > 200 {
+		details = append(details, dto.ErrorDetail{Field: "pattern", Message: "Pola wajib diisi, maksimal 200 karakter"})
+	}
+	if rule.MatchType != domain.TextFilterWord && rule.MatchType != domain.TextFilterRegex {
+		details = append(details, dto.ErrorDetail{Field: "match_type", Message: "Jenis pola harus word atau regex"})
+	} else if rule.Pattern != "" {
+		if _, err := service.CompileFilterRule(rule.MatchType, rule.Pattern); err != nil {
+			details = append(details, dto.ErrorDetail{Field: "pattern", Message: "Regex tidak valid: " + err.Error()})
+		}
+	}
+	switch rule.Action {
+	case domain.TextFilterBlock, domain.TextFilterMask, domain.TextFilterFlag:
+	default:
+		details = append(details, dto.ErrorDetail{Field: "action", Message: "Tindakan harus block, mask, atau flag"})
+	}
+	if rule.Bahasa != nil && *rule.Bahasa != "id" && *rule.Bahasa != "en" {
+		details = append(details, dto.ErrorDetail{Field: "bahasa", Message: "Bahasa harus id atau en"})
+	}
+	return details
+}
+
+// filterTextField runs a request field through the text filter, collecting what was found
+// into found. It answers 422 and returns false when the field holds a blocked term.
+func filterTextField(c *fiber.Ctx, filter *service.TextFilterService, field string, text *string, found *service.TextFilterResult) bool {
+	if text == nil {
+		return true
+	}
+	filtered, result := filter.Apply(*text)
+	if result.Blocked {
+		contentBlocked(c, field)
+		return false
+	}
+	*text = filtered
+	found.Merge(result)
+	return true
+}
+
+func contentBlocked(c *fiber.Ctx, field string) error {
+	return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse("CONTENT_BLOCKED", "Konten mengandung kata yang tidak diperbolehkan",
+		dto.ErrorDetail{Field: field, Message: "Mengandung kata yang tidak diperbolehkan"},
+	))
+}
+
+func toTextFilterRuleDTO(r *domain.TextFilterRule) dto.TextFilterRuleDTO {
+	return dto.TextFilterRuleDTO{
+		ID:        r.ID,
+		Pattern:   r.Pattern,
+		MatchType: string(r.MatchType),
+		Action:    string(r.Action),
+		Bahasa:    r.Bahasa,
+		IsActive:  r.IsActive,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/grafikarsa/backend/internal/dto"
 	"github.com/grafikarsa/backend/internal/middleware"
 	"github.com/grafikarsa/backend/internal/repository"
+	"github.com/grafikarsa/backend/internal/service"
 	"github.com/grafikarsa/backend/internal/storage"
 )
 
@@ -20,6 +21,8 @@ type UploadHandler struct {
 	minioClient    *storage.MinIOClient
 	userRepo       *repository.UserRepository
 	portfolioRepo  *repository.PortfolioRepository
+	draftService   *service.DraftService
+	collabService  *service.CollaborationService
 	pendingUploads map[string]*PendingUpload
 	mu             sync.RWMutex
 }
@@ -41,6 +44,8 @@ var uploadLimits = map[string]int64{
 	"thumbnail":       5 * 1024 * 1024,  // 5MB
 	"portfolio_image": 10 * 1024 * 1024, // 10MB
 	"document":        20 * 1024 * 1024, // 20MB for PDF, DOC, PPT files
+	"audio":           25 * 1024 * 1024, // 25MB
+	"model_3d":        50 * 1024 * 1024, // 50MB for glTF/GLB models
 }
 
 var allowedTypes = map[string][]string{
@@ -55,13 +60,35 @@ var allowedTypes = map[string][]string{
 		"application/vnd.ms-powerpoint",
 		"application/vnd.openxmlformats-officedocument.presentationml.presentation",
 	},
+	"audio": {"audio/mpeg", "audio/wav", "audio/ogg", "audio/mp4", "audio/aac"},
+	// Browsers rarely know the glTF types, so the extension is checked as well
+	"model_3d": {"model/gltf-binary", "model/gltf+json", "application/octet-stream"},
 }
 
-func NewUploadHandler(minioClient *storage.MinIOClient, userRepo *repository.UserRepository, portfolioRepo *repository.PortfolioRepository) *UploadHandler {
+// allowedExtensions restricts upload types whose content type alone says too little
+var allowedExtensions = map[string][]string{
+	"model_3d": {".glb", ".gltf"},
+}
+
+// portfolioUploadTypes are stored under a portfolio and need edit access to it
+var portfolioUploadTypes = map[string]bool{
+	"thumbnail":       true,
+	"portfolio_image": true,
+	"document":        true,
+	"audio":           true,
+	"model_3d":        true,
+}
+
+// portfolioObjectPrefixes are the object key prefixes of portfolioUploadTypes
+var portfolioObjectPrefixes = []string{"thumbnails/", "portfolio-images/", "documents/", "audio/", "models/"}
+
+func NewUploadHandler(minioClient *storage.MinIOClient, userRepo *repository.UserRepository, portfolioRepo *repository.PortfolioRepository, draftService *service.DraftService, collabService *service.CollaborationService) *UploadHandler {
 	return &UploadHandler{
 		minioClient:    minioClient,
 		userRepo:       userRepo,
 		portfolioRepo:  portfolioRepo,
+		draftService:   draftService,
+		collabService:  collabService,
 		pendingUploads: make(map[string]*PendingUpload),
 	}
 }
@@ -110,8 +137,14 @@ func (h *UploadHandler) Presign(c *fiber.Ctx) error {
 		))
 	}
 
-	// Validate portfolio ownership for thumbnail/portfolio_image/document
-	if req.UploadType == "thumbnail" || req.UploadType == "portfolio_image" || req.UploadType == "document" {
+	if exts, ok := allowedExtensions[req.UploadType]; ok && !hasExtension(req.Filename, exts) {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("INVALID_CONTENT_TYPE", "Tipe file tidak diizinkan",
+			dto.ErrorDetail{Field: "filename", Message: fmt.Sprintf("Ekstensi file yang diizinkan: %s", strings.Join(exts, ", "))},
+		))
+	}
+
+	// Validate portfolio ownership for files stored under a portfolio
+	if portfolioUploadTypes[req.UploadType] {
 		if req.PortfolioID == nil {
 			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse("VALIDATION_ERROR", "portfolio_id wajib diisi"))
 		}
@@ -119,7 +152,7 @@ func (h *UploadHandler) Presign(c *fiber.Ctx) error {
 		if err != nil {
 			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("PORTFOLIO_NOT_FOUND", "Portfolio tidak ditemukan"))
 		}
-		if portfolio.UserID != *userID && middleware.GetUserRole(c) != "admin" {
+		if !h.collabService.CanEdit(portfolio, userID) && middleware.GetUserRole(c) != "admin" {
 			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse("FORBIDDEN", "Anda tidak memiliki akses untuk upload ke portfolio ini"))
 		}
 	}
@@ -140,6 +173,10 @@ func (h *UploadHandler) Presign(c *fiber.Ctx) error {
 		objectKey = fmt.Sprintf("portfolio-images/%s/%s%s", req.PortfolioID.String(), fileID, ext)
 	case "document":
 		objectKey = fmt.Sprintf("documents/%s/%s%s", req.PortfolioID.String(), fileID, ext)
+	case "audio":
+		objectKey = fmt.Sprintf("audio/%s/%s%s", req.PortfolioID.String(), fileID, ext)
+	case "model_3d":
+		objectKey = fmt.Sprintf("models/%s/%s%s", req.PortfolioID.String(), fileID, ext)
 	}
 
 	// Generate presigned URL
@@ -223,8 +260,18 @@ func (h *UploadHandler) Confirm(c *fiber.Ctx) error {
 		h.userRepo.Update(user)
 	case "thumbnail":
 		portfolio, _ := h.portfolioRepo.FindByID(*pending.PortfolioID)
-		portfolio.ThumbnailURL = &publicURL
-		h.portfolioRepo.Update(portfolio)
+		if portfolio.IsApproved() {
+			// The live thumbnail only changes once the draft is approved
+			draft, ok := openDraft(c, h.draftService, portfolio)
+			if !ok {
+				return nil
+			}
+			draft.Content.ThumbnailURL = &publicURL
+			h.draftService.Save(draft)
+		} else {
+			portfolio.ThumbnailURL = &publicURL
+			h.portfolioRepo.Update(portfolio)
+		}
 	}
 
 	pending.Confirmed = true
@@ -246,6 +293,9 @@ func (h *UploadHandler) Confirm(c *fiber.Ctx) error {
 		"banner":          "Banner berhasil diperbarui",
 		"thumbnail":       "Thumbnail portfolio berhasil diperbarui",
 		"portfolio_image": "Gambar berhasil diupload",
+		"document":        "Dokumen berhasil diupload",
+		"audio":           "Audio berhasil diupload",
+		"model_3d":        "Model 3D berhasil diupload",
 	}
 
 	return c.JSON(dto.SuccessResponse(response, messages[pending.UploadType]))
@@ -277,7 +327,7 @@ func (h *UploadHandler) Delete(c *fiber.Ctx) error {
 			if !strings.HasPrefix(objectKey, expectedPrefixAvatar) && !strings.HasPrefix(objectKey, expectedPrefixBanner) {
 				return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse("FORBIDDEN", "Anda tidak memiliki akses untuk menghapus file ini"))
 			}
-		} else if strings.HasPrefix(objectKey, "thumbnails/") || strings.HasPrefix(objectKey, "portfolio-images/") || strings.HasPrefix(objectKey, "documents/") {
+		} else if hasAnyPrefix(objectKey, portfolioObjectPrefixes) {
 			// Expected format: prefix/{portfolioID}/{fileID}.ext
 			parts := strings.Split(objectKey, "/")
 			if len(parts) < 2 {
@@ -298,7 +348,7 @@ func (h *UploadHandler) Delete(c *fiber.Ctx) error {
 				return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse("PORTFOLIO_NOT_FOUND", "Portfolio tidak ditemukan associated dengan file"))
 			}
 
-			if portfolio.UserID != *userID {
+			if !h.collabService.CanEdit(portfolio, userID) {
 				return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse("FORBIDDEN", "Anda tidak memiliki akses untuk menghapus file portfolio ini"))
 			}
 		} else {
@@ -330,3 +380,22 @@ func (h *UploadHandler) PresignView(c *fiber.Ctx) error {
 		"expires_in": 3600,
 	}, ""))
 }
+
+func hasExtension(filename string, exts []string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	for _, e := range exts {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
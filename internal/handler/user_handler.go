@@ -95,6 +95,10 @@ func (h *UserHandler) GetByUsername(c *fiber.Ctx) error {
 	isAdmin := middleware.GetUserRole(c) == "admin"
 
 	user, err := h.userRepo.FindByUsername(username)
+	if err != nil {
+		// Profile links shared before a rename still lead to the user
+		user, err = h.userRepo.FindByPreviousUsername(username)
+	}
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse(
 			"USER_NOT_FOUND", "User tidak ditemukan",
@@ -110,7 +114,7 @@ func (h *UserHandler) GetByUsername(c *fiber.Ctx) error {
 
 	followerCount, _ := h.userRepo.GetFollowerCount(user.ID)
 	followingCount, _ := h.userRepo.GetFollowingCount(user.ID)
-	portfolioCount, _ := h.userRepo.GetPublishedPortfolioCount(user.ID)
+	portfolioCount, _ := h.userRepo.GetPublishedPortfolioCount(currentViewer(c, h.userRepo), user.ID)
 
 	isFollowing := false
 	if currentUserID != nil {
@@ -175,6 +179,9 @@ func (h *UserHandler) GetByUsername(c *fiber.Ctx) error {
 		IsFollowing:    isFollowing,
 		CreatedAt:      user.CreatedAt,
 	}
+	if user.Username != username {
+		userDTO.Redirect = &dto.RedirectDTO{Username: user.Username}
+	}
 
 	if user.Kelas != nil {
 		userDTO.Kelas = &dto.KelasDTO{ID: user.Kelas.ID, Nama: user.Kelas.Nama}
@@ -66,6 +66,15 @@ func (m *AuthMiddleware) Required() fiber.Handler {
 		}
 
 		userID, _ := uuid.Parse(claims.Sub)
+
+		// Accounts with a generated initial password may only change it (or log out) until they do
+		if claims.MustChangePassword && !passwordChangeExempt(c) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse(
+				"PASSWORD_CHANGE_REQUIRED",
+				"Silakan ganti password awal Anda terlebih dahulu",
+			))
+		}
+
 		c.Locals("userID", userID)
 		c.Locals("userRole", claims.Role)
 		c.Locals("jti", claims.JTI)
@@ -74,6 +83,18 @@ func (m *AuthMiddleware) Required() fiber.Handler {
 	}
 }
 
+// passwordChangeExempt lists the routes a user with a pending password change can still reach
+func passwordChangeExempt(c *fiber.Ctx) bool {
+	switch route := c.Method() + " " + strings.TrimSuffix(c.Path(), "/"); route {
+	case "PATCH /api/v1/me/password",
+		"GET /api/v1/me",
+		"POST /api/v1/auth/logout",
+		"POST /api/v1/auth/logout-all":
+		return true
+	}
+	return false
+}
+
 // Optional authentication
 func (m *AuthMiddleware) Optional() fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -122,6 +143,20 @@ func (m *AuthMiddleware) AdminOnly() fiber.Handler {
 	}
 }
 
+// TeacherOnly restricts a route to users with the teacher role
+func (m *AuthMiddleware) TeacherOnly() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role := c.Locals("userRole")
+		if role == nil || role.(string) != "teacher" {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse(
+				"FORBIDDEN",
+				"Akses ditolak. Hanya guru yang diizinkan.",
+			))
+		}
+		return c.Next()
+	}
+}
+
 // Get current user ID from context
 func GetUserID(c *fiber.Ctx) *uuid.UUID {
 	userID := c.Locals("userID")
@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/grafikarsa/backend/internal/domain"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AdminRepository struct {
@@ -109,7 +110,7 @@ func (r *AdminRepository) CreateKelas(kelas *domain.Kelas) error {
 
 func (r *AdminRepository) FindKelasByID(id uuid.UUID) (*domain.Kelas, error) {
 	var kelas domain.Kelas
-	err := r.db.Preload("TahunAjaran").Preload("Jurusan").
+	err := r.db.Preload("TahunAjaran").Preload("Jurusan").Preload("WaliKelas").
 		Where("id = ? AND deleted_at IS NULL", id).First(&kelas).Error
 	return &kelas, err
 }
@@ -133,7 +134,7 @@ func (r *AdminRepository) ListKelas(tahunAjaranID, jurusanID *uuid.UUID, tingkat
 	query.Count(&total)
 
 	offset := (page - 1) * limit
-	err := query.Preload("TahunAjaran").Preload("Jurusan").
+	err := query.Preload("TahunAjaran").Preload("Jurusan").Preload("WaliKelas").
 		Offset(offset).Limit(limit).
 		Order("nama ASC").
 		Find(&kelas).Error
@@ -202,6 +203,23 @@ func (r *AdminRepository) FindTagByID(id uuid.UUID) (*domain.Tag, error) {
 	return &tag, err
 }
 
+// FindTagByNama finds a tag by name, ignoring case
+func (r *AdminRepository) FindTagByNama(nama string) (*domain.Tag, error) {
+	var tag domain.Tag
+	err := r.db.Where("LOWER(nama) = LOWER(?) AND deleted_at IS NULL", nama).First(&tag).Error
+	return &tag, err
+}
+
+// FindTagsByIDs returns the existing tags among ids
+func (r *AdminRepository) FindTagsByIDs(ids []uuid.UUID) ([]domain.Tag, error) {
+	var tags []domain.Tag
+	if len(ids) == 0 {
+		return tags, nil
+	}
+	err := r.db.Where("id IN ? AND deleted_at IS NULL", ids).Order("nama ASC").Find(&tags).Error
+	return tags, err
+}
+
 func (r *AdminRepository) ListTags(search string) ([]domain.Tag, error) {
 	var tags []domain.Tag
 	query := r.db.Where("deleted_at IS NULL")
@@ -301,20 +319,51 @@ func (r *AdminRepository) GetSeriesPortfolioCount(id uuid.UUID) (int64, error) {
 	return count, err
 }
 
-// UpdateSeriesBlocks replaces all blocks for a series
+// UpdateSeriesBlocks replaces the block template of a series. Blocks carrying the ID of an
+// existing block of the series are updated in place so portfolio blocks stay linked to them.
 func (r *AdminRepository) UpdateSeriesBlocks(seriesID uuid.UUID, blocks []domain.SeriesBlock) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		// Delete existing blocks
-		if err := tx.Where("series_id = ?", seriesID).Delete(&domain.SeriesBlock{}).Error; err != nil {
+		var existing []uuid.UUID
+		if err := tx.Model(&domain.SeriesBlock{}).Where("series_id = ?", seriesID).Pluck("id", &existing).Error; err != nil {
 			return err
 		}
-		// Insert new blocks
-		if len(blocks) > 0 {
-			for i := range blocks {
-				blocks[i].SeriesID = seriesID
-				blocks[i].BlockOrder = i
+		known := make(map[uuid.UUID]bool, len(existing))
+		for _, id := range existing {
+			known[id] = true
+		}
+
+		keep := make([]uuid.UUID, 0, len(blocks))
+		for _, b := range blocks {
+			if known[b.ID] {
+				keep = append(keep, b.ID)
 			}
-			if err := tx.Create(&blocks).Error; err != nil {
+		}
+
+		// Delete blocks no longer in the template
+		removed := tx.Where("series_id = ?", seriesID)
+		if len(keep) > 0 {
+			removed = removed.Where("id NOT IN ?", keep)
+		}
+		if err := removed.Delete(&domain.SeriesBlock{}).Error; err != nil {
+			return err
+		}
+
+		for i := range blocks {
+			blocks[i].SeriesID = seriesID
+			blocks[i].BlockOrder = i
+			if known[blocks[i].ID] {
+				if err := tx.Model(&domain.SeriesBlock{}).Where("id = ?", blocks[i].ID).Updates(map[string]interface{}{
+					"block_type":  blocks[i].BlockType,
+					"block_order": blocks[i].BlockOrder,
+					"instruksi":   blocks[i].Instruksi,
+					"is_required": blocks[i].IsRequired,
+				}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			blocks[i].ID = uuid.Nil
+			if err := tx.Create(&blocks[i]).Error; err != nil {
 				return err
 			}
 		}
@@ -368,7 +417,9 @@ func (r *AdminRepository) ListPortfolios(search string, status *string, userID,
 		query = query.Joins("JOIN users ON portfolios.user_id = users.id").
 			Where("portfolios.judul ILIKE ? OR users.nama ILIKE ?", "%"+search+"%", "%"+search+"%")
 	}
-	if status != nil {
+	if status != nil && *status == string(domain.StatusPendingReview) {
+		query = query.Where(pendingReviewCondition)
+	} else if status != nil {
 		query = query.Where("portfolios.status = ?", *status)
 	}
 	if userID != nil {
@@ -391,6 +442,11 @@ func (r *AdminRepository) ListPortfolios(search string, status *string, userID,
 	return portfolios, total, err
 }
 
+// pendingReviewCondition matches portfolios waiting for review: new submissions and
+// published portfolios whose working draft was submitted
+const pendingReviewCondition = `(portfolios.status = 'pending_review' OR (portfolios.status IN ('published', 'scheduled') AND EXISTS (
+	SELECT 1 FROM portfolio_drafts d WHERE d.portfolio_id = portfolios.id AND d.status = 'pending_review')))`
+
 func (r *AdminRepository) ListPendingPortfolios(search string, jurusanID *uuid.UUID, sort string, page, limit int) ([]domain.Portfolio, int64, error) {
 	status := "pending_review"
 	return r.ListPortfolios(search, &status, nil, jurusanID, page, limit)
@@ -411,7 +467,7 @@ func (r *AdminRepository) GetUserStats() (total, students, alumni, admins, newTh
 func (r *AdminRepository) GetPortfolioStats() (total, published, pending, draft, rejected, archived, newThisMonth int64, err error) {
 	r.db.Model(&domain.Portfolio{}).Where("deleted_at IS NULL").Count(&total)
 	r.db.Model(&domain.Portfolio{}).Where("deleted_at IS NULL AND status = 'published'").Count(&published)
-	r.db.Model(&domain.Portfolio{}).Where("deleted_at IS NULL AND status = 'pending_review'").Count(&pending)
+	r.db.Model(&domain.Portfolio{}).Where("deleted_at IS NULL").Where(pendingReviewCondition).Count(&pending)
 	r.db.Model(&domain.Portfolio{}).Where("deleted_at IS NULL AND status = 'draft'").Count(&draft)
 	r.db.Model(&domain.Portfolio{}).Where("deleted_at IS NULL AND status = 'rejected'").Count(&rejected)
 	r.db.Model(&domain.Portfolio{}).Where("deleted_at IS NULL AND status = 'archived'").Count(&archived)
@@ -451,7 +507,7 @@ func (r *AdminRepository) GetRecentUsers(limit int) ([]domain.User, error) {
 func (r *AdminRepository) GetRecentPendingPortfolios(limit int) ([]domain.Portfolio, error) {
 	var portfolios []domain.Portfolio
 	err := r.db.Preload("User.Kelas.Jurusan").
-		Where("deleted_at IS NULL AND status = 'pending_review'").
+		Where("deleted_at IS NULL").Where(pendingReviewCondition).
 		Order("created_at DESC").
 		Limit(limit).
 		Find(&portfolios).Error
@@ -777,3 +833,175 @@ func (r *AdminRepository) BulkCreateKelas(kelasList []domain.Kelas) ([]domain.Ke
 	})
 	return created, err
 }
+
+// FindStudentsByNISOrNISN returns users whose NIS or NISN appears in the given lists
+func (r *AdminRepository) FindStudentsByNISOrNISN(nisList, nisnList []string) ([]domain.User, error) {
+	var users []domain.User
+	err := r.db.Model(&domain.User{}).
+		Where("(nis IN ? OR nisn IN ?) AND deleted_at IS NULL", nisList, nisnList).
+		Find(&users).Error
+	return users, err
+}
+
+// UpdateImportedStudent applies the given column updates to a student and, when kelasID is set,
+// records the class move in student_class_history
+func (r *AdminRepository) UpdateImportedStudent(userID uuid.UUID, updates map[string]interface{}, kelasID *uuid.UUID, tahunAjaranID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.User{}).Where("id = ?", userID).Updates(updates).Error; err != nil {
+			return err
+		}
+		if kelasID == nil {
+			return nil
+		}
+		return recordClassMove(tx, ClassMove{
+			UserID:        userID,
+			KelasID:       *kelasID,
+			TahunAjaranID: tahunAjaranID,
+			EffectiveDate: today(),
+		})
+	})
+}
+
+// ClassMove describes a single student moving into a kelas
+type ClassMove struct {
+	UserID        uuid.UUID
+	KelasID       uuid.UUID
+	TahunAjaranID uuid.UUID
+	EffectiveDate time.Time
+	Reason        *string
+	MovedBy       *uuid.UUID
+}
+
+// recordClassMove closes the student's open class history on the effective date and
+// opens (or reopens) the history row of the target kelas
+func recordClassMove(tx *gorm.DB, move ClassMove) error {
+	if err := tx.Model(&domain.StudentClassHistory{}).
+		Where("user_id = ? AND kelas_id <> ? AND end_date IS NULL", move.UserID, move.KelasID).
+		Update("end_date", move.EffectiveDate).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&domain.StudentClassHistory{}).
+		Where("user_id = ? AND kelas_id <> ? AND is_current = true", move.UserID, move.KelasID).
+		Update("is_current", false).Error; err != nil {
+		return err
+	}
+	history := &domain.StudentClassHistory{
+		UserID:        move.UserID,
+		KelasID:       move.KelasID,
+		TahunAjaranID: move.TahunAjaranID,
+		IsCurrent:     true,
+		EffectiveDate: move.EffectiveDate,
+		Reason:        move.Reason,
+		MovedBy:       move.MovedBy,
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "kelas_id"}, {Name: "tahun_ajaran_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"is_current":     true,
+			"effective_date": move.EffectiveDate,
+			"end_date":       nil,
+			"reason":         move.Reason,
+			"moved_by":       move.MovedBy,
+		}),
+	}).Create(history).Error
+}
+
+// TransferStudents moves every student in moves to its target kelas in one transaction.
+// History is written before users.kelas_id so the sync trigger keeps the dated rows.
+func (r *AdminRepository) TransferStudents(moves []ClassMove) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, move := range moves {
+			if err := recordClassMove(tx, move); err != nil {
+				return err
+			}
+			if err := tx.Model(&domain.User{}).Where("id = ?", move.UserID).
+				Update("kelas_id", move.KelasID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindUsersByIDs returns the non-deleted users among ids
+func (r *AdminRepository) FindUsersByIDs(ids []uuid.UUID) ([]domain.User, error) {
+	var users []domain.User
+	err := r.db.Where("id IN ? AND deleted_at IS NULL", ids).Find(&users).Error
+	return users, err
+}
+
+// FindOpenClassHistory returns the open class history row of each given student, keyed by user ID
+func (r *AdminRepository) FindOpenClassHistory(userIDs []uuid.UUID) (map[uuid.UUID]domain.StudentClassHistory, error) {
+	var rows []domain.StudentClassHistory
+	err := r.db.Where("user_id IN ? AND end_date IS NULL", userIDs).
+		Order("effective_date ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uuid.UUID]domain.StudentClassHistory, len(rows))
+	for _, row := range rows {
+		result[row.UserID] = row
+	}
+	return result, nil
+}
+
+// GetKelasRoster returns the class history rows of a kelas. With at set, only students who were
+// in the kelas on that date are returned; otherwise everyone who was ever in it.
+func (r *AdminRepository) GetKelasRoster(kelasID uuid.UUID, at *time.Time) ([]domain.StudentClassHistory, error) {
+	var rows []domain.StudentClassHistory
+	query := r.db.Model(&domain.StudentClassHistory{}).
+		Joins("JOIN users ON users.id = student_class_history.user_id").
+		Where("student_class_history.kelas_id = ? AND users.deleted_at IS NULL", kelasID)
+	if at != nil {
+		query = query.Where("student_class_history.effective_date <= ?", *at).
+			Where("student_class_history.end_date IS NULL OR student_class_history.end_date > ?", *at)
+	}
+	err := query.Preload("User").
+		Order("users.nama ASC, student_class_history.effective_date ASC").
+		Find(&rows).Error
+	return rows, err
+}
+
+// today returns the current date at midnight UTC, matching how DATE columns are read back
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// StudentScope is the part of the school a roster import covers: the students of KelasIDs,
+// or of every kelas of JurusanIDs
+type StudentScope struct {
+	KelasIDs   []uuid.UUID
+	JurusanIDs []uuid.UUID
+}
+
+// activeStudentsExcept scopes active students within scope that are not part of keepIDs
+func (r *AdminRepository) activeStudentsExcept(keepIDs []uuid.UUID, scope StudentScope) *gorm.DB {
+	query := r.db.Model(&domain.User{}).
+		Where("role = ? AND is_active = true AND deleted_at IS NULL AND id NOT IN ?", domain.RoleStudent, keepIDs)
+	if len(scope.JurusanIDs) > 0 {
+		return query.Where("kelas_id IN (SELECT id FROM kelas WHERE jurusan_id IN ?)", scope.JurusanIDs)
+	}
+	return query.Where("kelas_id IN ?", scope.KelasIDs)
+}
+
+// CountActiveStudentsExcept counts active students within scope that are not part of keepIDs
+func (r *AdminRepository) CountActiveStudentsExcept(keepIDs []uuid.UUID, scope StudentScope) (int64, error) {
+	if len(keepIDs) == 0 || (len(scope.KelasIDs) == 0 && len(scope.JurusanIDs) == 0) {
+		return 0, nil
+	}
+	var count int64
+	err := r.activeStudentsExcept(keepIDs, scope).Count(&count).Error
+	return count, err
+}
+
+// DeactivateStudentsExcept deactivates every active student within scope that is not part of keepIDs.
+// An empty scope deactivates nobody.
+func (r *AdminRepository) DeactivateStudentsExcept(keepIDs []uuid.UUID, scope StudentScope) (int64, error) {
+	if len(keepIDs) == 0 || (len(scope.KelasIDs) == 0 && len(scope.JurusanIDs) == 0) {
+		return 0, nil
+	}
+	result := r.activeStudentsExcept(keepIDs, scope).Update("is_active", false)
+	return result.RowsAffected, result.Error
+}
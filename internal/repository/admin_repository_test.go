@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupClassHistoryTestDB creates an in-memory SQLite database with users and class history
+func setupClassHistoryTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&domain.User{}, &domain.StudentClassHistory{})
+	require.NoError(t, err)
+
+	// Mirrors student_class_history_unique, which the upsert relies on
+	err = db.Exec("CREATE UNIQUE INDEX student_class_history_unique ON student_class_history(user_id, kelas_id, tahun_ajaran_id)").Error
+	require.NoError(t, err)
+
+	return db
+}
+
+func civilDate(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func rosterUserIDs(rows []domain.StudentClassHistory) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.UserID)
+	}
+	return ids
+}
+
+// A mid-year transfer closes the old stay on the effective date and shows up in roster-at-date
+func TestTransferStudents_RosterAtDate(t *testing.T) {
+	db := setupClassHistoryTestDB(t)
+	repo := NewAdminRepository(db)
+
+	tahunAjaranID := uuid.New()
+	kelasA := uuid.New()
+	kelasB := uuid.New()
+
+	student := &domain.User{Username: "siswa1", Email: "siswa1@example.com", Nama: "Siswa Satu", Role: domain.RoleStudent, IsActive: true}
+	require.NoError(t, db.Create(student).Error)
+
+	require.NoError(t, repo.TransferStudents([]ClassMove{{
+		UserID: student.ID, KelasID: kelasA, TahunAjaranID: tahunAjaranID, EffectiveDate: civilDate(2025, time.July, 14),
+	}}))
+
+	reason := "Pindah peminatan"
+	require.NoError(t, repo.TransferStudents([]ClassMove{{
+		UserID: student.ID, KelasID: kelasB, TahunAjaranID: tahunAjaranID, EffectiveDate: civilDate(2026, time.January, 5), Reason: &reason,
+	}}))
+
+	before := civilDate(2025, time.December, 1)
+	after := civilDate(2026, time.February, 1)
+
+	rows, err := repo.GetKelasRoster(kelasA, &before)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{student.ID}, rosterUserIDs(rows))
+
+	rows, err = repo.GetKelasRoster(kelasA, &after)
+	require.NoError(t, err)
+	assert.Empty(t, rows, "student left kelas A before the date")
+
+	rows, err = repo.GetKelasRoster(kelasB, &after)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.True(t, rows[0].IsCurrent)
+	assert.Nil(t, rows[0].EndDate)
+	require.NotNil(t, rows[0].Reason)
+	assert.Equal(t, reason, *rows[0].Reason)
+
+	// Without a date every stay of the kelas is listed
+	rows, err = repo.GetKelasRoster(kelasA, nil)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.False(t, rows[0].IsCurrent)
+	require.NotNil(t, rows[0].EndDate)
+	assert.True(t, rows[0].EndDate.Equal(civilDate(2026, time.January, 5)))
+
+	var updated domain.User
+	require.NoError(t, db.First(&updated, "id = ?", student.ID).Error)
+	require.NotNil(t, updated.KelasID)
+	assert.Equal(t, kelasB, *updated.KelasID)
+}
+
+// A roster only deactivates missing students of the kelas or jurusan it covers
+func TestDeactivateStudentsExcept_Scoped(t *testing.T) {
+	db := setupClassHistoryTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.Kelas{}))
+	repo := NewAdminRepository(db)
+
+	dkv, rpl := uuid.New(), uuid.New()
+	dkvA := domain.Kelas{Nama: "X DKV A", JurusanID: dkv}
+	dkvB := domain.Kelas{Nama: "X DKV B", JurusanID: dkv}
+	rplA := domain.Kelas{Nama: "X RPL A", JurusanID: rpl}
+	for _, k := range []*domain.Kelas{&dkvA, &dkvB, &rplA} {
+		require.NoError(t, db.Create(k).Error)
+	}
+	student := func(username string, kelasID uuid.UUID) domain.User {
+		u := domain.User{Username: username, Email: username + "@example.com", Nama: username, Role: domain.RoleStudent, IsActive: true, KelasID: &kelasID}
+		require.NoError(t, db.Create(&u).Error)
+		return u
+	}
+	listed := student("listed", dkvA.ID)
+	student("dropped", dkvA.ID)
+	student("other-class", dkvB.ID)
+	student("other-jurusan", rplA.ID)
+
+	keep := []uuid.UUID{listed.ID}
+	count, err := repo.CountActiveStudentsExcept(keep, StudentScope{JurusanIDs: []uuid.UUID{dkv}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	deactivated, err := repo.DeactivateStudentsExcept(keep, StudentScope{})
+	require.NoError(t, err)
+	assert.Zero(t, deactivated, "an empty scope deactivates nobody")
+
+	deactivated, err = repo.DeactivateStudentsExcept(keep, StudentScope{KelasIDs: []uuid.UUID{dkvA.ID}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deactivated)
+
+	var active []string
+	require.NoError(t, db.Model(&domain.User{}).Where("is_active = ?", true).Order("username").Pluck("username", &active).Error)
+	assert.Equal(t, []string{"listed", "other-class", "other-jurusan"}, active)
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"gorm.io/gorm"
+)
+
+// AudienceGroup counts the signed-in viewers sharing a role and jurusan
+type AudienceGroup struct {
+	Role    string
+	Jurusan *string
+	Viewers int64
+}
+
+// PortfolioEngagement is the reach of one portfolio within an analytics window
+type PortfolioEngagement struct {
+	ID    uuid.UUID
+	Judul string
+	Slug  string
+	Views int64
+	Likes int64
+}
+
+type AnalyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepository(db *gorm.DB) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+// RecordViewEvent logs a single visit, unlike ViewRepository.RecordView which keeps one row per viewer
+func (r *AnalyticsRepository) RecordViewEvent(event *domain.PortfolioViewEvent) error {
+	return r.db.Create(event).Error
+}
+
+// viewerKeySQL identifies the viewer behind a visit: the user, else the guest session, else
+// the visit itself
+const viewerKeySQL = `CASE
+	WHEN user_id IS NOT NULL THEN 'u:' || CAST(user_id AS TEXT)
+	WHEN session_id IS NOT NULL THEN 's:' || session_id
+	ELSE 'e:' || CAST(id AS TEXT) END`
+
+// ViewTotals sums the visits of a portfolio within an analytics window
+type ViewTotals struct {
+	Views         int64
+	UniqueViewers int64
+	Guests        int64
+}
+
+// DayCount is a count on one calendar day, formatted 2006-01-02
+type DayCount struct {
+	Day           string
+	Count         int64
+	UniqueViewers int64
+}
+
+// SourceCount is the number of visits that came through one traffic source
+type SourceCount struct {
+	Source domain.ViewSource
+	Views  int64
+}
+
+// ViewTotals counts the visits since a moment, the distinct viewers behind them and the
+// distinct guest sessions among those
+func (r *AnalyticsRepository) ViewTotals(portfolioID uuid.UUID, since time.Time) (ViewTotals, error) {
+	var totals ViewTotals
+	err := r.db.Model(&domain.PortfolioViewEvent{}).
+		Select(`COUNT(*) AS views,
+			COUNT(DISTINCT `+viewerKeySQL+`) AS unique_viewers,
+			COUNT(DISTINCT CASE WHEN user_id IS NULL THEN `+viewerKeySQL+` END) AS guests`).
+		Where("portfolio_id = ? AND viewed_at >= ?", portfolioID, since).
+		Scan(&totals).Error
+	return totals, err
+}
+
+// DailyViews counts the visits and distinct viewers per calendar day since a moment. Days
+// are cut in loc, by its offset at since.
+func (r *AnalyticsRepository) DailyViews(portfolioID uuid.UUID, since time.Time, loc *time.Location) ([]DayCount, error) {
+	day, dayArgs := r.localDay("viewed_at", since.In(loc))
+	var rows []DayCount
+	err := r.db.Table("(?) AS v", r.db.Model(&domain.PortfolioViewEvent{}).
+		Select(day+" AS day, "+viewerKeySQL+" AS viewer", dayArgs...).
+		Where("portfolio_id = ? AND viewed_at >= ?", portfolioID, since)).
+		Select("day, COUNT(*) AS count, COUNT(DISTINCT viewer) AS unique_viewers").
+		Group("day").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// DailyLikes counts the likes given per calendar day since a moment, cut like DailyViews
+func (r *AnalyticsRepository) DailyLikes(portfolioID uuid.UUID, since time.Time, loc *time.Location) ([]DayCount, error) {
+	day, dayArgs := r.localDay("created_at", since.In(loc))
+	var rows []DayCount
+	err := r.db.Table("(?) AS l", r.db.Model(&domain.PortfolioLike{}).
+		Select(day+" AS day", dayArgs...).
+		Where("portfolio_id = ? AND created_at >= ?", portfolioID, since)).
+		Select("day, COUNT(*) AS count").
+		Group("day").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// SourceCounts counts the visits since a moment per traffic source
+func (r *AnalyticsRepository) SourceCounts(portfolioID uuid.UUID, since time.Time) ([]SourceCount, error) {
+	var rows []SourceCount
+	err := r.db.Model(&domain.PortfolioViewEvent{}).
+		Select("source, COUNT(*) AS views").
+		Where("portfolio_id = ? AND viewed_at >= ?", portfolioID, since).
+		Group("source").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// localDay returns the expression formatting a timestamp column as the 2006-01-02 date at the
+// UTC offset of at. Postgres runs the app; SQLite only backs the tests.
+func (r *AnalyticsRepository) localDay(column string, at time.Time) (string, []interface{}) {
+	_, offset := at.Zone()
+	if r.db.Dialector.Name() == "sqlite" {
+		return "strftime('%Y-%m-%d', " + column + ", ?)", []interface{}{fmt.Sprintf("%+d seconds", offset)}
+	}
+	return "to_char((" + column + " AT TIME ZONE 'UTC') + ? * INTERVAL '1 second', 'YYYY-MM-DD')", []interface{}{offset}
+}
+
+// CountLikesBefore returns how many likes a portfolio had collected before a moment
+func (r *AnalyticsRepository) CountLikesBefore(portfolioID uuid.UUID, before time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.PortfolioLike{}).
+		Where("portfolio_id = ? AND created_at < ?", portfolioID, before).
+		Count(&count).Error
+	return count, err
+}
+
+// AudienceGroups counts the distinct signed-in viewers since a moment by role and jurusan.
+// Guests have neither and are left out.
+func (r *AnalyticsRepository) AudienceGroups(portfolioID uuid.UUID, since time.Time) ([]AudienceGroup, error) {
+	var groups []AudienceGroup
+	err := r.db.Table("portfolio_view_events e").
+		Select("u.role AS role, j.nama AS jurusan, COUNT(DISTINCT e.user_id) AS viewers").
+		Joins("JOIN users u ON u.id = e.user_id").
+		Joins("LEFT JOIN kelas k ON k.id = u.kelas_id").
+		Joins("LEFT JOIN jurusan j ON j.id = k.jurusan_id").
+		Where("e.portfolio_id = ? AND e.viewed_at >= ? AND e.user_id IS NOT NULL", portfolioID, since).
+		Group("u.role, j.nama").
+		Scan(&groups).Error
+	return groups, err
+}
+
+// AuthorEngagement returns views and likes since a moment for every published portfolio of
+// an author, plus the portfolio being looked at even when it is not published
+func (r *AnalyticsRepository) AuthorEngagement(userID, portfolioID uuid.UUID, since time.Time) ([]PortfolioEngagement, error) {
+	var rows []PortfolioEngagement
+	err := r.db.Table("portfolios p").
+		Select(`p.id, p.judul, p.slug,
+			(SELECT COUNT(*) FROM portfolio_view_events WHERE portfolio_id = p.id AND viewed_at >= ?) AS views,
+			(SELECT COUNT(*) FROM portfolio_likes WHERE portfolio_id = p.id AND created_at >= ?) AS likes`, since, since).
+		Where("p.user_id = ? AND p.deleted_at IS NULL", userID).
+		Where("p.status = ? OR p.id = ?", domain.StatusPublished, portfolioID).
+		Order("views DESC, p.created_at DESC").
+		Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Audience groups count each signed-in viewer once and engagement compares the author's portfolios
+func TestAnalytics_AudienceAndEngagement(t *testing.T) {
+	db := setupDraftTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.User{}, &domain.Kelas{}, &domain.Jurusan{}, &domain.PortfolioLike{}, &domain.PortfolioViewEvent{}))
+	repo := NewAnalyticsRepository(db)
+
+	jurusan := domain.Jurusan{Nama: "Desain Komunikasi Visual", Kode: "DKV"}
+	require.NoError(t, db.Create(&jurusan).Error)
+	kelas := domain.Kelas{TahunAjaranID: uuid.New(), JurusanID: jurusan.ID, Tingkat: 11, Rombel: "A", Nama: "XI DKV A"}
+	require.NoError(t, db.Create(&kelas).Error)
+	student := domain.User{Username: "siswa", Email: "siswa@example.com", Nama: "Siswa", Role: domain.RoleStudent, KelasID: &kelas.ID}
+	teacher := domain.User{Username: "guru", Email: "guru@example.com", Nama: "Guru", Role: domain.RoleTeacher}
+	require.NoError(t, db.Create(&student).Error)
+	require.NoError(t, db.Create(&teacher).Error)
+
+	authorID := uuid.New()
+	current := domain.Portfolio{UserID: authorID, Judul: "Poster", Slug: "poster", Status: domain.StatusPublished}
+	other := domain.Portfolio{UserID: authorID, Judul: "Logo", Slug: "logo", Status: domain.StatusPublished}
+	draft := domain.Portfolio{UserID: authorID, Judul: "Draft", Slug: "draft", Status: domain.StatusDraft}
+	for _, p := range []*domain.Portfolio{&current, &other, &draft} {
+		require.NoError(t, db.Create(p).Error)
+	}
+
+	now := time.Now()
+	since := now.AddDate(0, 0, -7)
+	session := "guest-1"
+	events := []domain.PortfolioViewEvent{
+		{PortfolioID: current.ID, UserID: &student.ID, Source: domain.ViewSourceFeed, ViewedAt: now.Add(-time.Hour)},
+		{PortfolioID: current.ID, UserID: &student.ID, Source: domain.ViewSourceProfile, ViewedAt: now.Add(-2 * time.Hour)},
+		{PortfolioID: current.ID, UserID: &teacher.ID, Source: domain.ViewSourceDirect, ViewedAt: now.Add(-3 * time.Hour)},
+		{PortfolioID: current.ID, SessionID: &session, Source: domain.ViewSourceSearch, ViewedAt: now.Add(-4 * time.Hour)},
+		{PortfolioID: current.ID, UserID: &teacher.ID, Source: domain.ViewSourceDirect, ViewedAt: now.AddDate(0, 0, -10)},
+		{PortfolioID: other.ID, UserID: &student.ID, Source: domain.ViewSourceFeed, ViewedAt: now.Add(-time.Hour)},
+	}
+	for i := range events {
+		require.NoError(t, repo.RecordViewEvent(&events[i]))
+	}
+	require.NoError(t, db.Create(&domain.PortfolioLike{UserID: student.ID, PortfolioID: current.ID, CreatedAt: now.Add(-time.Hour)}).Error)
+	require.NoError(t, db.Create(&domain.PortfolioLike{UserID: teacher.ID, PortfolioID: current.ID, CreatedAt: now.AddDate(0, 0, -10)}).Error)
+
+	totals, err := repo.ViewTotals(current.ID, since)
+	require.NoError(t, err)
+	assert.Equal(t, ViewTotals{Views: 4, UniqueViewers: 3, Guests: 1}, totals, "repeat visits count once per viewer")
+
+	sources, err := repo.SourceCounts(current.ID, since)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []SourceCount{
+		{Source: domain.ViewSourceFeed, Views: 1},
+		{Source: domain.ViewSourceProfile, Views: 1},
+		{Source: domain.ViewSourceDirect, Views: 1},
+		{Source: domain.ViewSourceSearch, Views: 1},
+	}, sources)
+
+	groups, err := repo.AudienceGroups(current.ID, since)
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	byRole := map[string]AudienceGroup{}
+	for _, g := range groups {
+		byRole[g.Role] = g
+	}
+	assert.Equal(t, int64(1), byRole["student"].Viewers)
+	require.NotNil(t, byRole["student"].Jurusan)
+	assert.Equal(t, jurusan.Nama, *byRole["student"].Jurusan)
+	assert.Nil(t, byRole["teacher"].Jurusan)
+
+	likes, err := repo.DailyLikes(current.ID, since, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, likes, 1)
+	assert.Equal(t, int64(1), likes[0].Count)
+	before, err := repo.CountLikesBefore(current.ID, since)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), before)
+
+	engagement, err := repo.AuthorEngagement(authorID, current.ID, since)
+	require.NoError(t, err)
+	require.Len(t, engagement, 2)
+	assert.Equal(t, current.ID, engagement[0].ID)
+	assert.Equal(t, int64(4), engagement[0].Views)
+	assert.Equal(t, int64(1), engagement[0].Likes)
+	assert.Equal(t, int64(1), engagement[1].Views)
+}
+
+// Visits are cut into calendar days in the viewer's time zone, counting each viewer once a day
+func TestAnalytics_DailyViews(t *testing.T) {
+	db := setupDraftTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.PortfolioViewEvent{}))
+	repo := NewAnalyticsRepository(db)
+
+	wib := time.FixedZone("WIB", 7*60*60)
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, wib)
+	portfolioID, viewer := uuid.New(), uuid.New()
+	session := "guest-1"
+	for _, e := range []domain.PortfolioViewEvent{
+		// 1 March 06:00 WIB is still 28 February in UTC
+		{PortfolioID: portfolioID, UserID: &viewer, Source: domain.ViewSourceFeed, ViewedAt: from.Add(6 * time.Hour)},
+		{PortfolioID: portfolioID, UserID: &viewer, Source: domain.ViewSourceFeed, ViewedAt: from.Add(8 * time.Hour)},
+		{PortfolioID: portfolioID, SessionID: &session, Source: domain.ViewSourceDirect, ViewedAt: from.Add(23 * time.Hour)},
+		{PortfolioID: portfolioID, UserID: &viewer, Source: domain.ViewSourceFeed, ViewedAt: from.Add(25 * time.Hour)},
+		{PortfolioID: portfolioID, UserID: &viewer, Source: domain.ViewSourceFeed, ViewedAt: from.Add(-time.Hour)},
+	} {
+		// SQLite compares times as text, so store them all in one zone as postgres would
+		e.ViewedAt = e.ViewedAt.UTC()
+		require.NoError(t, repo.RecordViewEvent(&e))
+	}
+
+	days, err := repo.DailyViews(portfolioID, from.UTC(), wib)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []DayCount{
+		{Day: "2025-03-01", Count: 3, UniqueViewers: 2},
+		{Day: "2025-03-02", Count: 1, UniqueViewers: 1},
+	}, days)
+}
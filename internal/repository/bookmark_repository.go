@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"gorm.io/gorm"
+)
+
+// liveBookmarkCondition keeps items whose portfolio is still published
+const liveBookmarkCondition = "bookmark_items.portfolio_id IN (SELECT id FROM portfolios WHERE status = 'published' AND deleted_at IS NULL)"
+
+type BookmarkRepository struct {
+	db *gorm.DB
+}
+
+func NewBookmarkRepository(db *gorm.DB) *BookmarkRepository {
+	return &BookmarkRepository{db: db}
+}
+
+func (r *BookmarkRepository) CreateCollection(collection *domain.BookmarkCollection) error {
+	return r.db.Create(collection).Error
+}
+
+func (r *BookmarkRepository) FindCollection(id uuid.UUID) (*domain.BookmarkCollection, error) {
+	var collection domain.BookmarkCollection
+	if err := r.db.Preload("User.Kelas").Where("id = ?", id).First(&collection).Error; err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+// ListCollections returns the collections of a user, newest first; publicOnly leaves out private ones
+func (r *BookmarkRepository) ListCollections(userID uuid.UUID, publicOnly bool) ([]domain.BookmarkCollection, error) {
+	var collections []domain.BookmarkCollection
+	query := r.db.Where("user_id = ?", userID)
+	if publicOnly {
+		query = query.Where("is_public = ?", true)
+	}
+	err := query.Order("created_at DESC").Find(&collections).Error
+	return collections, err
+}
+
+func (r *BookmarkRepository) CountCollections(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.BookmarkCollection{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *BookmarkRepository) UpdateCollection(collection *domain.BookmarkCollection) error {
+	return r.db.Save(collection).Error
+}
+
+func (r *BookmarkRepository) DeleteCollection(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ?", id).Delete(&domain.BookmarkItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&domain.BookmarkCollection{}).Error
+	})
+}
+
+// CountItems returns the number of live items per collection
+func (r *BookmarkRepository) CountItems(collectionIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	counts := make(map[uuid.UUID]int64, len(collectionIDs))
+	if len(collectionIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		CollectionID uuid.UUID
+		Count        int64
+	}
+	err := r.db.Model(&domain.BookmarkItem{}).
+		Select("collection_id, COUNT(*) AS count").
+		Where("collection_id IN ?", collectionIDs).
+		Where(liveBookmarkCondition).
+		Group("collection_id").
+		Scan(&rows).Error
+	for _, row := range rows {
+		counts[row.CollectionID] = row.Count
+	}
+	return counts, err
+}
+
+// ListItems returns the items of a collection in their order, with portfolios viewer may see.
+// Unlisted portfolios are only listed for the owner of the collection.
+func (r *BookmarkRepository) ListItems(collection *domain.BookmarkCollection, viewer Viewer) ([]domain.BookmarkItem, error) {
+	var items []domain.BookmarkItem
+	listed := viewer.UserID == nil || *viewer.UserID != collection.UserID
+	visibility, visibilityArgs := visibilityCondition("portfolios", viewer, listed)
+	err := r.db.Preload("Portfolio.User.Kelas").Preload("Portfolio.Tags").
+		Joins("JOIN portfolios ON portfolios.id = bookmark_items.portfolio_id").
+		Where("bookmark_items.collection_id = ? AND portfolios.status = ? AND portfolios.deleted_at IS NULL", collection.ID, domain.StatusPublished).
+		Where(visibility, visibilityArgs...).
+		Order("bookmark_items.item_order ASC, bookmark_items.created_at ASC").
+		Find(&items).Error
+	return items, err
+}
+
+// HasItem reports whether the portfolio is already in the collection
+func (r *BookmarkRepository) HasItem(collectionID, portfolioID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.BookmarkItem{}).
+		Where("collection_id = ? AND portfolio_id = ?", collectionID, portfolioID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// AddItem appends the item at the end of its collection
+func (r *BookmarkRepository) AddItem(item *domain.BookmarkItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var maxOrder *int
+		if err := tx.Model(&domain.BookmarkItem{}).
+			Where("collection_id = ?", item.CollectionID).
+			Select("MAX(item_order)").
+			Scan(&maxOrder).Error; err != nil {
+			return err
+		}
+		item.ItemOrder = 0
+		if maxOrder != nil {
+			item.ItemOrder = *maxOrder + 1
+		}
+		return tx.Create(item).Error
+	})
+}
+
+// RemoveItem takes a portfolio out of a collection and reports whether it was there
+func (r *BookmarkRepository) RemoveItem(collectionID, portfolioID uuid.UUID) (bool, error) {
+	result := r.db.Where("collection_id = ? AND portfolio_id = ?", collectionID, portfolioID).
+		Delete(&domain.BookmarkItem{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ReorderItems gives the listed portfolios the order of their position; others keep theirs
+func (r *BookmarkRepository) ReorderItems(collectionID uuid.UUID, portfolioIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for order, portfolioID := range portfolioIDs {
+			if err := tx.Model(&domain.BookmarkItem{}).
+				Where("collection_id = ? AND portfolio_id = ?", collectionID, portfolioID).
+				Update("item_order", order).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
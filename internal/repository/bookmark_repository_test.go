@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Items keep their order, and leave every collection once their portfolio is no longer live
+func TestBookmarkItems_OrderAndWithdrawal(t *testing.T) {
+	db := setupDraftTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.User{}, &domain.Kelas{}, &domain.Tag{}, &domain.BookmarkCollection{}, &domain.BookmarkItem{}))
+	repo := NewBookmarkRepository(db)
+	portfolioRepo := NewPortfolioRepository(db)
+
+	var portfolios []domain.Portfolio
+	for _, slug := range []string{"poster", "logo", "maskot"} {
+		p := domain.Portfolio{UserID: uuid.New(), Judul: slug, Slug: slug, Status: domain.StatusPublished, Visibility: domain.VisibilityPublic}
+		require.NoError(t, db.Create(&p).Error)
+		portfolios = append(portfolios, p)
+	}
+
+	collection := domain.BookmarkCollection{UserID: uuid.New(), Nama: "Inspirasi UI", IsPublic: true}
+	require.NoError(t, repo.CreateCollection(&collection))
+	for _, p := range portfolios {
+		require.NoError(t, repo.AddItem(&domain.BookmarkItem{CollectionID: collection.ID, PortfolioID: p.ID}))
+	}
+
+	order := func() []uuid.UUID {
+		items, err := repo.ListItems(&collection, Viewer{})
+		require.NoError(t, err)
+		var ids []uuid.UUID
+		for _, item := range items {
+			ids = append(ids, item.PortfolioID)
+		}
+		return ids
+	}
+	assert.Equal(t, []uuid.UUID{portfolios[0].ID, portfolios[1].ID, portfolios[2].ID}, order())
+
+	require.NoError(t, repo.ReorderItems(collection.ID, []uuid.UUID{portfolios[2].ID, portfolios[0].ID, portfolios[1].ID}))
+	assert.Equal(t, []uuid.UUID{portfolios[2].ID, portfolios[0].ID, portfolios[1].ID}, order())
+
+	archived := portfolios[0]
+	archived.Status = domain.StatusArchived
+	require.NoError(t, portfolioRepo.Update(&archived))
+	require.NoError(t, portfolioRepo.Delete(portfolios[1].ID))
+	assert.Equal(t, []uuid.UUID{portfolios[2].ID}, order())
+
+	counts, err := repo.CountItems([]uuid.UUID{collection.ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), counts[collection.ID])
+	has, err := repo.HasItem(collection.ID, archived.ID)
+	require.NoError(t, err)
+	assert.False(t, has, "items of an unpublished portfolio are removed, not just hidden")
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"gorm.io/gorm"
+)
+
+// authoredByCondition matches portfolios a user owns or co-authors through an accepted invitation.
+// It takes the user ID twice.
+const authoredByCondition = "(portfolios.user_id = ? OR portfolios.id IN (SELECT portfolio_id FROM portfolio_collaborators WHERE user_id = ? AND status = 'accepted'))"
+
+type CollaboratorRepository struct {
+	db *gorm.DB
+}
+
+func NewCollaboratorRepository(db *gorm.DB) *CollaboratorRepository {
+	return &CollaboratorRepository{db: db}
+}
+
+func (r *CollaboratorRepository) Create(collaborator *domain.PortfolioCollaborator) error {
+	return r.db.Create(collaborator).Error
+}
+
+func (r *CollaboratorRepository) FindByID(id uuid.UUID) (*domain.PortfolioCollaborator, error) {
+	var collaborator domain.PortfolioCollaborator
+	err := r.db.Preload("Portfolio").Where("id = ?", id).First(&collaborator).Error
+	if err != nil {
+		return nil, err
+	}
+	return &collaborator, nil
+}
+
+func (r *CollaboratorRepository) FindByPortfolioAndUser(portfolioID, userID uuid.UUID) (*domain.PortfolioCollaborator, error) {
+	var collaborator domain.PortfolioCollaborator
+	err := r.db.Where("portfolio_id = ? AND user_id = ?", portfolioID, userID).First(&collaborator).Error
+	if err != nil {
+		return nil, err
+	}
+	return &collaborator, nil
+}
+
+// ListByPortfolio returns every invitation of a portfolio; acceptedOnly hides pending and declined ones
+func (r *CollaboratorRepository) ListByPortfolio(portfolioID uuid.UUID, acceptedOnly bool) ([]domain.PortfolioCollaborator, error) {
+	var collaborators []domain.PortfolioCollaborator
+	query := r.db.Preload("User.Kelas").Where("portfolio_id = ?", portfolioID)
+	if acceptedOnly {
+		query = query.Where("status = ?", domain.CollaboratorAccepted)
+	}
+	err := query.Order("created_at ASC").Find(&collaborators).Error
+	return collaborators, err
+}
+
+// AcceptedUserIDs returns the co-authors of a portfolio, not including its owner
+func (r *CollaboratorRepository) AcceptedUserIDs(portfolioID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&domain.PortfolioCollaborator{}).
+		Where("portfolio_id = ? AND status = ?", portfolioID, domain.CollaboratorAccepted).
+		Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+// IsEditor reports whether userID accepted an editor invitation on the portfolio
+func (r *CollaboratorRepository) IsEditor(portfolioID, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.PortfolioCollaborator{}).
+		Where("portfolio_id = ? AND user_id = ? AND role = ? AND status = ?",
+			portfolioID, userID, domain.CollaboratorEditor, domain.CollaboratorAccepted).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// RolesByPortfolioIDs returns the accepted co-author role of userID for each portfolio in ids
+func (r *CollaboratorRepository) RolesByPortfolioIDs(userID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]domain.CollaboratorRole, error) {
+	result := make(map[uuid.UUID]domain.CollaboratorRole)
+	if len(ids) == 0 {
+		return result, nil
+	}
+	var collaborators []domain.PortfolioCollaborator
+	err := r.db.Select("portfolio_id", "role").
+		Where("user_id = ? AND status = ? AND portfolio_id IN ?", userID, domain.CollaboratorAccepted, ids).
+		Find(&collaborators).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range collaborators {
+		result[c.PortfolioID] = c.Role
+	}
+	return result, nil
+}
+
+// Respond records the invitee's answer. It reports false when the invitation was already answered.
+func (r *CollaboratorRepository) Respond(id uuid.UUID, status domain.CollaboratorStatus, respondedAt time.Time) (bool, error) {
+	result := r.db.Model(&domain.PortfolioCollaborator{}).
+		Where("id = ? AND status = ?", id, domain.CollaboratorPending).
+		Updates(map[string]interface{}{"status": status, "responded_at": respondedAt})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Reinvite turns a declined invitation back into a pending one with a new role
+func (r *CollaboratorRepository) Reinvite(collaborator *domain.PortfolioCollaborator) error {
+	return r.db.Model(collaborator).Updates(map[string]interface{}{
+		"role":         collaborator.Role,
+		"status":       domain.CollaboratorPending,
+		"invited_by":   collaborator.InvitedBy,
+		"responded_at": nil,
+	}).Error
+}
+
+func (r *CollaboratorRepository) UpdateRole(id uuid.UUID, role domain.CollaboratorRole) error {
+	return r.db.Model(&domain.PortfolioCollaborator{}).Where("id = ?", id).Update("role", role).Error
+}
+
+func (r *CollaboratorRepository) Delete(id uuid.UUID) error {
+	return r.db.Where("id = ?", id).Delete(&domain.PortfolioCollaborator{}).Error
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A co-authored portfolio counts for the co-author only once the invitation is accepted
+func TestCollaborator_CountsAfterAccept(t *testing.T) {
+	db := setupDraftTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.PortfolioCollaborator{}))
+	collabRepo := NewCollaboratorRepository(db)
+	userRepo := NewUserRepository(db)
+
+	owner, member := uuid.New(), uuid.New()
+	portfolio := domain.Portfolio{UserID: owner, Judul: "Game Tim", Slug: "game-tim", Status: domain.StatusPublished}
+	require.NoError(t, db.Create(&portfolio).Error)
+
+	invite := domain.PortfolioCollaborator{PortfolioID: portfolio.ID, UserID: member, Role: domain.CollaboratorEditor, Status: domain.CollaboratorPending}
+	require.NoError(t, collabRepo.Create(&invite))
+
+	count, err := userRepo.GetPublishedPortfolioCount(Viewer{}, member)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+	isEditor, _ := collabRepo.IsEditor(portfolio.ID, member)
+	assert.False(t, isEditor)
+
+	updated, err := collabRepo.Respond(invite.ID, domain.CollaboratorAccepted, time.Now())
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	// A second answer to the same invitation is ignored
+	updated, err = collabRepo.Respond(invite.ID, domain.CollaboratorDeclined, time.Now())
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	count, err = userRepo.GetPublishedPortfolioCount(Viewer{}, member)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	isEditor, _ = collabRepo.IsEditor(portfolio.ID, member)
+	assert.True(t, isEditor)
+
+	ownerCount, _ := userRepo.GetPublishedPortfolioCount(Viewer{}, owner)
+	assert.Equal(t, int64(1), ownerCount)
+}
@@ -28,6 +28,11 @@ func (r *CommentRepository) Update(comment *domain.Comment) error {
 	return r.db.Save(comment).Error
 }
 
+// SetHidden hides a comment behind a moderation placeholder, or shows it again
+func (r *CommentRepository) SetHidden(id uuid.UUID, hidden bool) error {
+	return r.db.Model(&domain.Comment{}).Where("id = ?", id).Update("is_hidden", hidden).Error
+}
+
 func (r *CommentRepository) Delete(id uuid.UUID) error {
 	return r.db.Delete(&domain.Comment{}, "id = ?", id).Error
 }
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DraftRepository struct {
+	db *gorm.DB
+}
+
+func NewDraftRepository(db *gorm.DB) *DraftRepository {
+	return &DraftRepository{db: db}
+}
+
+func (r *DraftRepository) FindByPortfolioID(portfolioID uuid.UUID) (*domain.PortfolioDraft, error) {
+	var draft domain.PortfolioDraft
+	err := r.db.Where("portfolio_id = ?", portfolioID).First(&draft).Error
+	if err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+// StatusesByPortfolioIDs returns the draft status of every portfolio in ids that has a draft
+func (r *DraftRepository) StatusesByPortfolioIDs(ids []uuid.UUID) (map[uuid.UUID]domain.DraftStatus, error) {
+	result := make(map[uuid.UUID]domain.DraftStatus)
+	if len(ids) == 0 {
+		return result, nil
+	}
+	var drafts []domain.PortfolioDraft
+	if err := r.db.Select("portfolio_id", "status").Where("portfolio_id IN ?", ids).Find(&drafts).Error; err != nil {
+		return nil, err
+	}
+	for _, d := range drafts {
+		result[d.PortfolioID] = d.Status
+	}
+	return result, nil
+}
+
+// Save creates the draft or overwrites it
+func (r *DraftRepository) Save(draft *domain.PortfolioDraft) error {
+	return r.db.Save(draft).Error
+}
+
+func (r *DraftRepository) DeleteByPortfolioID(portfolioID uuid.UUID) error {
+	return r.db.Where("portfolio_id = ?", portfolioID).Delete(&domain.PortfolioDraft{}).Error
+}
+
+// Publish replaces the live metadata, tags and content blocks of the portfolio with the
+// draft content and removes the draft, all in one transaction. Block IDs are kept so
+// revisions and block references stay comparable across the swap.
+func (r *DraftRepository) Publish(draft *domain.PortfolioDraft, reviewedBy *uuid.UUID, note *string, reviewedAt time.Time) error {
+	content := draft.Content
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Portfolio{}).
+			Where("id = ? AND deleted_at IS NULL", draft.PortfolioID).
+			Updates(map[string]interface{}{
+				"judul":             content.Judul,
+				"thumbnail_url":     content.ThumbnailURL,
+				"series_id":         content.SeriesID,
+				"admin_review_note": note,
+				"reviewed_by":       reviewedBy,
+				"reviewed_at":       reviewedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Where("portfolio_id = ?", draft.PortfolioID).Delete(&domain.PortfolioTag{}).Error; err != nil {
+			return err
+		}
+		if len(content.Tags) > 0 {
+			tags := make([]domain.PortfolioTag, 0, len(content.Tags))
+			for _, t := range content.Tags {
+				tags = append(tags, domain.PortfolioTag{PortfolioID: draft.PortfolioID, TagID: t.ID})
+			}
+			if err := tx.Create(&tags).Error; err != nil {
+				return err
+			}
+		}
+
+		keep := make([]uuid.UUID, 0, len(content.Blocks))
+		for _, b := range content.Blocks {
+			keep = append(keep, b.ID)
+		}
+		removed := tx.Where("portfolio_id = ?", draft.PortfolioID)
+		if len(keep) > 0 {
+			removed = removed.Where("id NOT IN ?", keep)
+		}
+		if err := removed.Delete(&domain.ContentBlock{}).Error; err != nil {
+			return err
+		}
+		for _, b := range content.Blocks {
+			block := domain.ContentBlock{
+				ID:            b.ID,
+				PortfolioID:   draft.PortfolioID,
+				SeriesBlockID: b.SeriesBlockID,
+				BlockType:     b.BlockType,
+				BlockOrder:    b.BlockOrder,
+				Payload:       b.Payload,
+			}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"series_block_id", "block_type", "block_order", "payload", "embed_checked_at", "updated_at"}),
+			}).Create(&block).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Delete(&domain.PortfolioDraft{}, "id = ?", draft.ID).Error
+	})
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupDraftTestDB creates an in-memory SQLite database with portfolios, blocks, tags and drafts
+func setupDraftTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&domain.Portfolio{}, &domain.PortfolioTag{}, &domain.ContentBlock{}, &domain.PortfolioDraft{})
+	require.NoError(t, err)
+
+	return db
+}
+
+// Approving a draft swaps in its content in place, keeping block IDs, and drops the draft
+func TestDraftPublish_ReplacesLiveContent(t *testing.T) {
+	db := setupDraftTestDB(t)
+	repo := NewDraftRepository(db)
+
+	portfolio := domain.Portfolio{UserID: uuid.New(), Judul: "Versi Lama", Slug: "versi-lama", Status: domain.StatusPublished}
+	require.NoError(t, db.Create(&portfolio).Error)
+	oldTag, newTag := uuid.New(), uuid.New()
+	require.NoError(t, db.Create(&domain.PortfolioTag{PortfolioID: portfolio.ID, TagID: oldTag}).Error)
+
+	kept := domain.ContentBlock{ID: uuid.New(), PortfolioID: portfolio.ID, BlockType: domain.BlockText, BlockOrder: 0, Payload: domain.JSONB{"content": "lama"}}
+	dropped := domain.ContentBlock{ID: uuid.New(), PortfolioID: portfolio.ID, BlockType: domain.BlockText, BlockOrder: 1, Payload: domain.JSONB{"content": "hapus"}}
+	require.NoError(t, db.Create(&kept).Error)
+	require.NoError(t, db.Create(&dropped).Error)
+
+	added := uuid.New()
+	thumbnail := "https://cdn.example/thumb.png"
+	draft := &domain.PortfolioDraft{
+		PortfolioID: portfolio.ID,
+		Status:      domain.DraftPendingReview,
+		Content: domain.PortfolioSnapshot{
+			Judul:        "Versi Baru",
+			Slug:         portfolio.Slug,
+			ThumbnailURL: &thumbnail,
+			Status:       domain.StatusPublished,
+			Tags:         []domain.SnapshotTag{{ID: newTag, Nama: "Baru"}},
+			Blocks: []domain.SnapshotBlock{
+				{ID: added, BlockType: domain.BlockImage, BlockOrder: 0, Payload: domain.JSONB{"url": "a.png"}},
+				{ID: kept.ID, BlockType: domain.BlockText, BlockOrder: 1, Payload: domain.JSONB{"content": "baru"}},
+			},
+		},
+	}
+	require.NoError(t, repo.Save(draft))
+
+	reviewer := uuid.New()
+	note := "ok"
+	require.NoError(t, repo.Publish(draft, &reviewer, &note, time.Now()))
+
+	var live domain.Portfolio
+	require.NoError(t, db.First(&live, "id = ?", portfolio.ID).Error)
+	assert.Equal(t, "Versi Baru", live.Judul)
+	assert.Equal(t, domain.StatusPublished, live.Status)
+	require.NotNil(t, live.ThumbnailURL)
+	assert.Equal(t, thumbnail, *live.ThumbnailURL)
+
+	var tagIDs []uuid.UUID
+	db.Model(&domain.PortfolioTag{}).Where("portfolio_id = ?", portfolio.ID).Pluck("tag_id", &tagIDs)
+	assert.Equal(t, []uuid.UUID{newTag}, tagIDs)
+
+	var blocks []domain.ContentBlock
+	db.Where("portfolio_id = ?", portfolio.ID).Order("block_order ASC").Find(&blocks)
+	require.Len(t, blocks, 2)
+	assert.Equal(t, added, blocks[0].ID)
+	assert.Equal(t, kept.ID, blocks[1].ID)
+	assert.Equal(t, "baru", blocks[1].Payload["content"])
+
+	_, err := repo.FindByPortfolioID(portfolio.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
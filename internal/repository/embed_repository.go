@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"gorm.io/gorm"
+)
+
+// EmbedRepository keeps the cached embed checks of content blocks
+type EmbedRepository struct {
+	db *gorm.DB
+}
+
+func NewEmbedRepository(db *gorm.DB) *EmbedRepository {
+	return &EmbedRepository{db: db}
+}
+
+// FindDueChecks returns blocks of the given types on live portfolios that were never checked
+// or were last checked before staleBefore, never-checked blocks first
+func (r *EmbedRepository) FindDueChecks(types []domain.ContentBlockType, staleBefore time.Time, limit int) ([]domain.ContentBlock, error) {
+	var blocks []domain.ContentBlock
+	err := r.db.Select("content_blocks.*").
+		Joins("JOIN portfolios ON portfolios.id = content_blocks.portfolio_id AND portfolios.deleted_at IS NULL").
+		Where("content_blocks.block_type IN ?", types).
+		Where("content_blocks.embed_checked_at IS NULL OR content_blocks.embed_checked_at < ?", staleBefore).
+		Order("content_blocks.embed_checked_at ASC NULLS FIRST").
+		Limit(limit).
+		Find(&blocks).Error
+	return blocks, err
+}
+
+// SaveCheck stores the outcome of a check; updated_at is left alone since the content did not change
+func (r *EmbedRepository) SaveCheck(blockID uuid.UUID, status *domain.EmbedStatus, meta domain.JSONB, checkedAt time.Time) error {
+	var metaValue interface{}
+	if meta != nil {
+		metaValue = meta
+	}
+	return r.db.Model(&domain.ContentBlock{}).
+		Where("id = ?", blockID).
+		UpdateColumns(map[string]interface{}{
+			"embed_status":     status,
+			"embed_meta":       metaValue,
+			"embed_checked_at": checkedAt,
+		}).Error
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// Blocks come due when unchecked or stale, unchecked ones first, and only on live portfolios
+func TestEmbedFindDueChecks(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.Portfolio{}, &domain.ContentBlock{}))
+	repo := NewEmbedRepository(db)
+
+	now := time.Now()
+	live := domain.Portfolio{UserID: uuid.New(), Judul: "Live", Slug: "live", Status: domain.StatusPublished}
+	deletedAt := now
+	deleted := domain.Portfolio{UserID: uuid.New(), Judul: "Dihapus", Slug: "dihapus", Status: domain.StatusPublished}
+	require.NoError(t, db.Create(&live).Error)
+	require.NoError(t, db.Create(&deleted).Error)
+	require.NoError(t, db.Model(&deleted).Update("deleted_at", &deletedAt).Error)
+
+	stale, fresh := now.Add(-48*time.Hour), now.Add(-time.Hour)
+	blocks := []domain.ContentBlock{
+		{ID: uuid.New(), PortfolioID: live.ID, BlockType: domain.BlockFigma, BlockOrder: 0, EmbedCheckedAt: &stale},
+		{ID: uuid.New(), PortfolioID: live.ID, BlockType: domain.BlockYoutube, BlockOrder: 1},
+		{ID: uuid.New(), PortfolioID: live.ID, BlockType: domain.BlockCanva, BlockOrder: 2, EmbedCheckedAt: &fresh},
+		{ID: uuid.New(), PortfolioID: live.ID, BlockType: domain.BlockText, BlockOrder: 3},
+		{ID: uuid.New(), PortfolioID: deleted.ID, BlockType: domain.BlockFigma, BlockOrder: 0},
+	}
+	require.NoError(t, db.Create(&blocks).Error)
+
+	types := []domain.ContentBlockType{domain.BlockYoutube, domain.BlockFigma, domain.BlockCanva}
+	due, err := repo.FindDueChecks(types, now.Add(-24*time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, blocks[1].ID, due[0].ID)
+	assert.Equal(t, blocks[0].ID, due[1].ID)
+
+	status := domain.EmbedDead
+	require.NoError(t, repo.SaveCheck(blocks[1].ID, &status, nil, now))
+	due, err = repo.FindDueChecks(types, now.Add(-24*time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, blocks[0].ID, due[0].ID)
+
+	var saved domain.ContentBlock
+	require.NoError(t, db.First(&saved, "id = ?", blocks[1].ID).Error)
+	require.NotNil(t, saved.EmbedStatus)
+	assert.Equal(t, domain.EmbedDead, *saved.EmbedStatus)
+	assert.Nil(t, saved.EmbedMeta)
+}
@@ -22,14 +22,16 @@ type FeedPortfolio struct {
 	AssessmentScore *float64 `gorm:"column:assessment_score"`
 }
 
-// GetPublishedPortfolios returns all published portfolios for feed calculation
+// GetPublishedPortfolios returns all published portfolios visible to viewer for feed calculation
 // This is the base query that will be used by the feed service to calculate rankings
-func (r *FeedRepository) GetPublishedPortfolios(page, limit int) ([]FeedPortfolio, int64, error) {
+func (r *FeedRepository) GetPublishedPortfolios(viewer Viewer, page, limit int) ([]FeedPortfolio, int64, error) {
 	var portfolios []FeedPortfolio
 	var total int64
 
+	visibility, visibilityArgs := visibilityCondition("portfolios", viewer, true)
 	baseQuery := r.db.Model(&domain.Portfolio{}).
-		Where("portfolios.status = ? AND portfolios.deleted_at IS NULL", domain.StatusPublished)
+		Where("portfolios.status = ? AND portfolios.deleted_at IS NULL", domain.StatusPublished).
+		Where(visibility, visibilityArgs...)
 
 	baseQuery.Count(&total)
 
@@ -42,6 +44,7 @@ func (r *FeedRepository) GetPublishedPortfolios(page, limit int) ([]FeedPortfoli
 			(SELECT total_score FROM portfolio_assessments WHERE portfolio_id = portfolios.id) as assessment_score
 		`).
 		Where("portfolios.status = ? AND portfolios.deleted_at IS NULL", domain.StatusPublished).
+		Where(visibility, visibilityArgs...).
 		Preload("User.Kelas.Jurusan").
 		Preload("ContentBlocks").
 		Offset(offset).
@@ -115,13 +118,15 @@ func (r *FeedRepository) loadTagsForPortfolios(portfolios []FeedPortfolio) {
 	}
 }
 
-// GetRecentFeed returns portfolios sorted by published_at descending
-func (r *FeedRepository) GetRecentFeed(page, limit int) ([]FeedPortfolio, int64, error) {
+// GetRecentFeed returns portfolios visible to viewer sorted by published_at descending
+func (r *FeedRepository) GetRecentFeed(viewer Viewer, page, limit int) ([]FeedPortfolio, int64, error) {
 	var portfolios []FeedPortfolio
 	var total int64
 
+	visibility, visibilityArgs := visibilityCondition("portfolios", viewer, true)
 	baseQuery := r.db.Model(&domain.Portfolio{}).
-		Where("portfolios.status = ? AND portfolios.deleted_at IS NULL", domain.StatusPublished)
+		Where("portfolios.status = ? AND portfolios.deleted_at IS NULL", domain.StatusPublished).
+		Where(visibility, visibilityArgs...)
 
 	baseQuery.Count(&total)
 
@@ -134,6 +139,7 @@ func (r *FeedRepository) GetRecentFeed(page, limit int) ([]FeedPortfolio, int64,
 			(SELECT total_score FROM portfolio_assessments WHERE portfolio_id = portfolios.id) as assessment_score
 		`).
 		Where("portfolios.status = ? AND portfolios.deleted_at IS NULL", domain.StatusPublished).
+		Where(visibility, visibilityArgs...).
 		Preload("User.Kelas.Jurusan").
 		Offset(offset).
 		Limit(limit).
@@ -151,14 +157,16 @@ func (r *FeedRepository) GetRecentFeed(page, limit int) ([]FeedPortfolio, int64,
 }
 
 // GetFollowingFeed returns portfolios from users that the given user follows
-func (r *FeedRepository) GetFollowingFeed(userID uuid.UUID, page, limit int) ([]FeedPortfolio, int64, error) {
+func (r *FeedRepository) GetFollowingFeed(viewer Viewer, userID uuid.UUID, page, limit int) ([]FeedPortfolio, int64, error) {
 	var portfolios []FeedPortfolio
 	var total int64
 
+	visibility, visibilityArgs := visibilityCondition("portfolios", viewer, true)
 	baseQuery := r.db.Model(&domain.Portfolio{}).
 		Joins("JOIN follows ON portfolios.user_id = follows.following_id").
 		Where("follows.follower_id = ? AND portfolios.status = ? AND portfolios.deleted_at IS NULL",
-			userID, domain.StatusPublished)
+			userID, domain.StatusPublished).
+		Where(visibility, visibilityArgs...)
 
 	baseQuery.Count(&total)
 
@@ -173,6 +181,7 @@ func (r *FeedRepository) GetFollowingFeed(userID uuid.UUID, page, limit int) ([]
 		Joins("JOIN follows ON portfolios.user_id = follows.following_id").
 		Where("follows.follower_id = ? AND portfolios.status = ? AND portfolios.deleted_at IS NULL",
 			userID, domain.StatusPublished).
+		Where(visibility, visibilityArgs...).
 		Preload("User.Kelas.Jurusan").
 		Offset(offset).
 		Limit(limit).
@@ -190,10 +199,11 @@ func (r *FeedRepository) GetFollowingFeed(userID uuid.UUID, page, limit int) ([]
 }
 
 // GetPortfoliosForSmartFeed returns portfolios with all data needed for smart ranking
-// Excludes user's own portfolios from the feed
-func (r *FeedRepository) GetPortfoliosForSmartFeed(userID uuid.UUID, batchSize int) ([]FeedPortfolio, error) {
+// Excludes user's own portfolios and those hidden from viewer
+func (r *FeedRepository) GetPortfoliosForSmartFeed(viewer Viewer, userID uuid.UUID, batchSize int) ([]FeedPortfolio, error) {
 	var portfolios []FeedPortfolio
 
+	visibility, visibilityArgs := visibilityCondition("portfolios", viewer, true)
 	err := r.db.Table("portfolios").
 		Select(`
 			portfolios.*,
@@ -203,6 +213,7 @@ func (r *FeedRepository) GetPortfoliosForSmartFeed(userID uuid.UUID, batchSize i
 		`).
 		Where("portfolios.status = ? AND portfolios.deleted_at IS NULL AND portfolios.user_id != ?",
 			domain.StatusPublished, userID).
+		Where(visibility, visibilityArgs...).
 		Preload("User.Kelas.Jurusan").
 		Preload("ContentBlocks").
 		Limit(batchSize).
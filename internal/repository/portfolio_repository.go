@@ -1,9 +1,13 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/grafikarsa/backend/internal/domain"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PortfolioRepository struct {
@@ -29,55 +33,153 @@ func (r *PortfolioRepository) FindByID(id uuid.UUID) (*domain.Portfolio, error)
 	return &portfolio, nil
 }
 
+// FindBySlugAndUserID finds a portfolio of a user by its slug, falling back to the slugs it
+// had before its title changed
 func (r *PortfolioRepository) FindBySlugAndUserID(slug string, userID uuid.UUID) (*domain.Portfolio, error) {
 	var portfolio domain.Portfolio
-	err := r.db.Preload("User.Kelas.Jurusan").Preload("Tags").Preload("Series").Preload("ContentBlocks", func(db *gorm.DB) *gorm.DB {
-		return db.Order("block_order ASC")
-	}).Where("slug = ? AND user_id = ? AND deleted_at IS NULL", slug, userID).First(&portfolio).Error
+	err := r.withDetails().Where("slug = ? AND user_id = ? AND deleted_at IS NULL", slug, userID).First(&portfolio).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = r.withDetails().
+			Where("id IN (SELECT portfolio_id FROM portfolio_slug_history WHERE slug = ? AND user_id = ?)", slug, userID).
+			Where("deleted_at IS NULL").
+			First(&portfolio).Error
+	}
 	if err != nil {
 		return nil, err
 	}
 	return &portfolio, nil
 }
 
-func (r *PortfolioRepository) FindPublishedBySlugAndUsername(slug string, username string) (*domain.Portfolio, error) {
-	var portfolio domain.Portfolio
-	query := r.db.Preload("User.Kelas.Jurusan").Preload("Tags").Preload("ContentBlocks", func(db *gorm.DB) *gorm.DB {
-		return db.Order("block_order ASC")
-	}).Joins("JOIN users ON users.id = portfolios.user_id").
-		Where("portfolios.slug = ? AND portfolios.deleted_at IS NULL AND portfolios.status = ?", slug, domain.StatusPublished)
-
+// FindPublishedBySlugAndUsername finds a published portfolio viewer may open, unlisted ones included.
+// Old slugs and old usernames still resolve, so links shared before a rename keep working; callers
+// tell such a hit by comparing the portfolio's slug and author username with what they asked for.
+func (r *PortfolioRepository) FindPublishedBySlugAndUsername(slug string, username string, viewer Viewer) (*domain.Portfolio, error) {
+	var authorID *uuid.UUID
 	if username != "" {
-		query = query.Where("users.username = ? AND users.deleted_at IS NULL", username)
+		id, err := r.authorIDByUsername(username)
+		if err != nil {
+			return nil, err
+		}
+		authorID = &id
+	}
+
+	visibility, visibilityArgs := visibilityCondition("portfolios", viewer, false)
+	published := func() *gorm.DB {
+		query := r.withDetails().Joins("JOIN users ON users.id = portfolios.user_id").
+			Where("portfolios.deleted_at IS NULL AND portfolios.status = ?", domain.StatusPublished).
+			Where(visibility, visibilityArgs...)
+		if authorID != nil {
+			query = query.Where("portfolios.user_id = ?", *authorID)
+		}
+		return query
 	}
 
-	err := query.First(&portfolio).Error
+	var portfolio domain.Portfolio
+	err := published().Where("portfolios.slug = ?", slug).First(&portfolio).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = published().
+			Where("portfolios.id IN (SELECT portfolio_id FROM portfolio_slug_history WHERE slug = ? AND user_id = portfolios.user_id)", slug).
+			Order("portfolios.published_at DESC").
+			First(&portfolio).Error
+	}
 	if err != nil {
 		return nil, err
 	}
 	return &portfolio, nil
 }
 
+// authorIDByUsername resolves a current username, or failing that one the user had before
+func (r *PortfolioRepository) authorIDByUsername(username string) (uuid.UUID, error) {
+	var user domain.User
+	err := r.db.Select("id").Where("username = ? AND deleted_at IS NULL", username).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		var previous domain.UsernameHistory
+		err = r.db.Joins("JOIN users ON users.id = username_history.user_id AND users.deleted_at IS NULL").
+			Where("username_history.username = ?", username).
+			First(&previous).Error
+		return previous.UserID, err
+	}
+	return user.ID, err
+}
+
+// withDetails preloads what a portfolio page shows
+func (r *PortfolioRepository) withDetails() *gorm.DB {
+	return r.db.Preload("User.Kelas.Jurusan").Preload("Tags").Preload("Series").Preload("ContentBlocks", func(db *gorm.DB) *gorm.DB {
+		return db.Order("block_order ASC")
+	})
+}
+
+// Update saves the portfolio. Bookmarks only hold live work, so a portfolio that is no
+// longer published leaves every bookmark collection.
 func (r *PortfolioRepository) Update(portfolio *domain.Portfolio) error {
-	return r.db.Save(portfolio).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(portfolio).Error; err != nil {
+			return err
+		}
+		if portfolio.Status == domain.StatusPublished {
+			return nil
+		}
+		return tx.Where("portfolio_id = ?", portfolio.ID).Delete(&domain.BookmarkItem{}).Error
+	})
+}
+
+// IsVisibleTo reports whether viewer may open the published portfolio by its link
+func (r *PortfolioRepository) IsVisibleTo(id uuid.UUID, viewer Viewer) (bool, error) {
+	var count int64
+	visibility, visibilityArgs := visibilityCondition("portfolios", viewer, false)
+	err := r.db.Model(&domain.Portfolio{}).
+		Where("portfolios.id = ? AND portfolios.status = ? AND portfolios.deleted_at IS NULL", id, domain.StatusPublished).
+		Where(visibility, visibilityArgs...).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *PortfolioRepository) UpdateVisibility(id uuid.UUID, visibility domain.PortfolioVisibility) error {
+	return r.db.Model(&domain.Portfolio{}).Where("id = ?", id).Update("visibility", visibility).Error
 }
 
 func (r *PortfolioRepository) Delete(id uuid.UUID) error {
-	return r.db.Where("id = ?", id).Delete(&domain.Portfolio{}).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("portfolio_id = ?", id).Delete(&domain.BookmarkItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&domain.Portfolio{}).Error
+	})
 }
 
-func (r *PortfolioRepository) ListPublished(search string, tagIDs []uuid.UUID, jurusanID, kelasID, userID *uuid.UUID, sort string, page, limit int) ([]domain.Portfolio, int64, error) {
+// FindDueScheduled returns scheduled portfolios whose publish_at has passed, oldest first
+func (r *PortfolioRepository) FindDueScheduled(now time.Time, limit int) ([]domain.Portfolio, error) {
+	var portfolios []domain.Portfolio
+	err := r.db.Where("status = ? AND publish_at <= ? AND deleted_at IS NULL", domain.StatusScheduled, now).
+		Order("publish_at ASC").
+		Limit(limit).
+		Find(&portfolios).Error
+	return portfolios, err
+}
+
+// PublishScheduled flips a scheduled portfolio to published. It reports false when the
+// portfolio is no longer scheduled, e.g. another instance published it first.
+func (r *PortfolioRepository) PublishScheduled(id uuid.UUID, publishedAt time.Time) (bool, error) {
+	result := r.db.Model(&domain.Portfolio{}).
+		Where("id = ? AND status = ?", id, domain.StatusScheduled).
+		Updates(map[string]interface{}{"status": domain.StatusPublished, "published_at": publishedAt})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListPublished lists the published portfolios viewer may see; unlisted ones are left out
+func (r *PortfolioRepository) ListPublished(viewer Viewer, search string, tagIDs []uuid.UUID, jurusanID, kelasID, userID *uuid.UUID, sort string, page, limit int) ([]domain.Portfolio, int64, error) {
 	var portfolios []domain.Portfolio
 	var total int64
 
 	// Base condition
 	baseCondition := "portfolios.status = 'published' AND portfolios.deleted_at IS NULL"
+	visibility, visibilityArgs := visibilityCondition("portfolios", viewer, true)
 
 	// Build count query
-	countQuery := r.db.Model(&domain.Portfolio{}).Where(baseCondition)
+	countQuery := r.db.Model(&domain.Portfolio{}).Where(baseCondition).Where(visibility, visibilityArgs...)
 
 	// Build fetch query
-	fetchQuery := r.db.Model(&domain.Portfolio{}).Where(baseCondition)
+	fetchQuery := r.db.Model(&domain.Portfolio{}).Where(baseCondition).Where(visibility, visibilityArgs...)
 
 	if search != "" {
 		searchCondition := "portfolios.judul ILIKE ? OR portfolios.user_id IN (SELECT id FROM users WHERE nama ILIKE ?)"
@@ -92,8 +194,15 @@ func (r *PortfolioRepository) ListPublished(search string, tagIDs []uuid.UUID, j
 	}
 
 	if userID != nil {
-		countQuery = countQuery.Where("portfolios.user_id = ?", *userID)
-		fetchQuery = fetchQuery.Where("portfolios.user_id = ?", *userID)
+		countQuery = countQuery.Where(authoredByCondition, *userID, *userID)
+		fetchQuery = fetchQuery.Where(authoredByCondition, *userID, *userID).
+			Select("portfolios.*").
+			Joins("LEFT JOIN profile_portfolios ON profile_portfolios.portfolio_id = portfolios.id AND profile_portfolios.user_id = ?", *userID).
+			Order("COALESCE(profile_portfolios.is_pinned, false) DESC")
+		// A profile listed in its default order follows the order its owner set
+		if sort == "" || sort == "-published_at" {
+			fetchQuery = fetchQuery.Order("profile_portfolios.display_order IS NULL").Order("profile_portfolios.display_order ASC")
+		}
 	}
 
 	if kelasID != nil {
@@ -128,15 +237,16 @@ func (r *PortfolioRepository) ListPublished(search string, tagIDs []uuid.UUID, j
 	return portfolios, total, err
 }
 
+// ListByUser lists portfolios the user owns or co-authors
 func (r *PortfolioRepository) ListByUser(userID uuid.UUID, status *string, page, limit int) ([]domain.Portfolio, int64, error) {
 	var portfolios []domain.Portfolio
 	var total int64
 
 	query := r.db.Model(&domain.Portfolio{}).
-		Where("user_id = ? AND deleted_at IS NULL", userID)
+		Where(authoredByCondition+" AND portfolios.deleted_at IS NULL", userID, userID)
 
 	if status != nil {
-		query = query.Where("status = ?", *status)
+		query = query.Where("portfolios.status = ?", *status)
 	}
 
 	query.Count(&total)
@@ -209,6 +319,39 @@ func (r *PortfolioRepository) CreateContentBlock(block *domain.ContentBlock) err
 	return r.db.Create(block).Error
 }
 
+func (r *PortfolioRepository) CreateContentBlocks(blocks []domain.ContentBlock) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+	return r.db.Create(&blocks).Error
+}
+
+// FindSeries returns a live series without its blocks
+func (r *PortfolioRepository) FindSeries(id uuid.UUID) (*domain.Series, error) {
+	var series domain.Series
+	err := r.db.Where("id = ? AND deleted_at IS NULL", id).First(&series).Error
+	if err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+// FindSeriesBlocks returns the block template of a series in template order
+func (r *PortfolioRepository) FindSeriesBlocks(seriesID uuid.UUID) ([]domain.SeriesBlock, error) {
+	var blocks []domain.SeriesBlock
+	err := r.db.Where("series_id = ?", seriesID).Order("block_order ASC").Find(&blocks).Error
+	return blocks, err
+}
+
+func (r *PortfolioRepository) FindSeriesBlockByID(id uuid.UUID) (*domain.SeriesBlock, error) {
+	var block domain.SeriesBlock
+	err := r.db.Where("id = ?", id).First(&block).Error
+	if err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
 func (r *PortfolioRepository) FindContentBlockByID(id uuid.UUID) (*domain.ContentBlock, error) {
 	var block domain.ContentBlock
 	err := r.db.Where("id = ?", id).First(&block).Error
@@ -252,13 +395,15 @@ func (r *PortfolioRepository) GetMaxBlockOrder(portfolioID uuid.UUID) (int, erro
 }
 
 // GetFeed returns portfolios from users that the given user follows
-func (r *PortfolioRepository) GetFeed(userID uuid.UUID, page, limit int) ([]domain.Portfolio, int64, error) {
+func (r *PortfolioRepository) GetFeed(viewer Viewer, userID uuid.UUID, page, limit int) ([]domain.Portfolio, int64, error) {
 	var portfolios []domain.Portfolio
 	var total int64
 
+	visibility, visibilityArgs := visibilityCondition("portfolios", viewer, true)
 	query := r.db.Model(&domain.Portfolio{}).
 		Joins("JOIN follows ON portfolios.user_id = follows.following_id").
-		Where("follows.follower_id = ? AND portfolios.status = 'published' AND portfolios.deleted_at IS NULL", userID)
+		Where("follows.follower_id = ? AND portfolios.status = 'published' AND portfolios.deleted_at IS NULL", userID).
+		Where(visibility, visibilityArgs...)
 
 	query.Count(&total)
 
@@ -270,3 +415,73 @@ func (r *PortfolioRepository) GetFeed(userID uuid.UUID, page, limit int) ([]doma
 
 	return portfolios, total, err
 }
+
+// AuthoredIDs returns which of ids the user owns or co-authors, leaving out deleted portfolios
+func (r *PortfolioRepository) AuthoredIDs(userID uuid.UUID, ids []uuid.UUID) ([]uuid.UUID, error) {
+	var authored []uuid.UUID
+	if len(ids) == 0 {
+		return authored, nil
+	}
+	err := r.db.Model(&domain.Portfolio{}).
+		Where("portfolios.id IN ? AND portfolios.deleted_at IS NULL", ids).
+		Where(authoredByCondition, userID, userID).
+		Pluck("portfolios.id", &authored).Error
+	return authored, err
+}
+
+// ProfileOrder returns the pins and display order the user set on their profile, by portfolio ID
+func (r *PortfolioRepository) ProfileOrder(userID uuid.UUID) (map[uuid.UUID]domain.ProfilePortfolio, error) {
+	var rows []domain.ProfilePortfolio
+	if err := r.db.Where("user_id = ?", userID).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[uuid.UUID]domain.ProfilePortfolio, len(rows))
+	for _, row := range rows {
+		result[row.PortfolioID] = row
+	}
+	return result, nil
+}
+
+// CountPinned counts the pins on a profile that still show, i.e. on published portfolios
+func (r *PortfolioRepository) CountPinned(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.ProfilePortfolio{}).
+		Joins("JOIN portfolios ON portfolios.id = profile_portfolios.portfolio_id").
+		Where("profile_portfolios.user_id = ? AND profile_portfolios.is_pinned = ?", userID, true).
+		Where("portfolios.status = ? AND portfolios.deleted_at IS NULL", domain.StatusPublished).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *PortfolioRepository) SetPinned(userID, portfolioID uuid.UUID, pinned bool, at time.Time) error {
+	row := domain.ProfilePortfolio{UserID: userID, PortfolioID: portfolioID, IsPinned: pinned, UpdatedAt: at}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "portfolio_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"is_pinned", "updated_at"}),
+	}).Create(&row).Error
+}
+
+// ReorderProfile gives the listed portfolios the display order of their position on the profile
+// and clears it on the user's other portfolios, which drop back to the default order
+func (r *PortfolioRepository) ReorderProfile(userID uuid.UUID, portfolioIDs []uuid.UUID, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		others := tx.Model(&domain.ProfilePortfolio{}).Where("user_id = ?", userID)
+		if len(portfolioIDs) > 0 {
+			others = others.Where("portfolio_id NOT IN ?", portfolioIDs)
+		}
+		if err := others.Updates(map[string]interface{}{"display_order": nil, "updated_at": at}).Error; err != nil {
+			return err
+		}
+		for i, portfolioID := range portfolioIDs {
+			order := i
+			row := domain.ProfilePortfolio{UserID: userID, PortfolioID: portfolioID, DisplayOrder: &order, UpdatedAt: at}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "portfolio_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"display_order", "updated_at"}),
+			}).Create(&row).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
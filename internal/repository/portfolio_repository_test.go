@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Scheduled portfolios go live only once publish_at has passed, and only once
+func TestPublishScheduled_OnlyWhenDue(t *testing.T) {
+	db := setupDraftTestDB(t)
+	repo := NewPortfolioRepository(db)
+
+	now := time.Now()
+	due := now.Add(-time.Minute)
+	later := now.Add(time.Hour)
+	duePortfolio := domain.Portfolio{UserID: uuid.New(), Judul: "Pameran", Slug: "pameran", Status: domain.StatusScheduled, PublishAt: &due}
+	laterPortfolio := domain.Portfolio{UserID: uuid.New(), Judul: "Nanti", Slug: "nanti", Status: domain.StatusScheduled, PublishAt: &later}
+	require.NoError(t, db.Create(&duePortfolio).Error)
+	require.NoError(t, db.Create(&laterPortfolio).Error)
+
+	found, err := repo.FindDueScheduled(now, 10)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, duePortfolio.ID, found[0].ID)
+
+	ok, err := repo.PublishScheduled(duePortfolio.ID, now)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = repo.PublishScheduled(duePortfolio.ID, now)
+	require.NoError(t, err)
+	assert.False(t, ok, "an already published portfolio must not be published twice")
+
+	var live domain.Portfolio
+	require.NoError(t, db.First(&live, "id = ?", duePortfolio.ID).Error)
+	assert.Equal(t, domain.StatusPublished, live.Status)
+	require.NotNil(t, live.PublishedAt)
+
+	found, err = repo.FindDueScheduled(now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+// Listings respect each visibility level and leave out unlisted work, which stays reachable by link
+func TestListPublished_Visibility(t *testing.T) {
+	db := setupDraftTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.User{}, &domain.Kelas{}, &domain.Jurusan{}, &domain.PortfolioCollaborator{}))
+	repo := NewPortfolioRepository(db)
+
+	rpl, dkv := uuid.New(), uuid.New()
+	kelasRPL := domain.Kelas{Nama: "XII RPL 1", JurusanID: rpl}
+	kelasDKV := domain.Kelas{Nama: "XII DKV 1", JurusanID: dkv}
+	require.NoError(t, db.Create(&kelasRPL).Error)
+	require.NoError(t, db.Create(&kelasDKV).Error)
+	author := domain.User{Username: "penulis", Email: "penulis@example.com", Nama: "Penulis", Role: domain.RoleStudent, KelasID: &kelasRPL.ID}
+	require.NoError(t, db.Create(&author).Error)
+
+	levels := []domain.PortfolioVisibility{domain.VisibilityPublic, domain.VisibilityLoggedIn, domain.VisibilityJurusan, domain.VisibilityUnlisted}
+	ids := map[domain.PortfolioVisibility]uuid.UUID{}
+	for _, v := range levels {
+		p := domain.Portfolio{UserID: author.ID, Judul: string(v), Slug: string(v), Status: domain.StatusPublished, Visibility: v}
+		require.NoError(t, db.Create(&p).Error)
+		ids[v] = p.ID
+	}
+
+	listed := func(viewer Viewer) []domain.PortfolioVisibility {
+		portfolios, total, err := repo.ListPublished(viewer, "", nil, nil, nil, nil, "judul", 1, 20)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(portfolios)), total)
+		var got []domain.PortfolioVisibility
+		for _, p := range portfolios {
+			got = append(got, p.Visibility)
+		}
+		return got
+	}
+
+	sameJurusan, otherJurusan := uuid.New(), uuid.New()
+	assert.Equal(t, []domain.PortfolioVisibility{domain.VisibilityPublic}, listed(Viewer{}))
+	assert.ElementsMatch(t, []domain.PortfolioVisibility{domain.VisibilityPublic, domain.VisibilityLoggedIn},
+		listed(Viewer{UserID: &otherJurusan, JurusanID: &dkv}))
+	assert.ElementsMatch(t, []domain.PortfolioVisibility{domain.VisibilityPublic, domain.VisibilityLoggedIn, domain.VisibilityJurusan},
+		listed(Viewer{UserID: &sameJurusan, JurusanID: &rpl}))
+	assert.ElementsMatch(t, []domain.PortfolioVisibility{domain.VisibilityPublic, domain.VisibilityLoggedIn, domain.VisibilityJurusan},
+		listed(Viewer{UserID: &otherJurusan, IsStaff: true}))
+	assert.ElementsMatch(t, levels, listed(Viewer{UserID: &author.ID, JurusanID: &rpl}), "authors see all of their own work")
+
+	visible, err := repo.IsVisibleTo(ids[domain.VisibilityUnlisted], Viewer{})
+	require.NoError(t, err)
+	assert.True(t, visible, "unlisted work opens by its link")
+	visible, err = repo.IsVisibleTo(ids[domain.VisibilityJurusan], Viewer{UserID: &otherJurusan, JurusanID: &dkv})
+	require.NoError(t, err)
+	assert.False(t, visible)
+}
+
+// A profile lists pinned work first, then the owner's manual order, then the newest
+func TestListPublished_ProfileOrder(t *testing.T) {
+	db := setupDraftTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.User{}, &domain.Kelas{}, &domain.Tag{}, &domain.PortfolioCollaborator{}, &domain.ProfilePortfolio{}))
+	repo := NewPortfolioRepository(db)
+
+	owner := uuid.New()
+	now := time.Now()
+	var ids []uuid.UUID
+	for i, slug := range []string{"lama", "tengah", "baru", "terbaru"} {
+		publishedAt := now.Add(time.Duration(i) * time.Hour)
+		p := domain.Portfolio{UserID: owner, Judul: slug, Slug: slug, Status: domain.StatusPublished, Visibility: domain.VisibilityPublic, PublishedAt: &publishedAt}
+		require.NoError(t, db.Create(&p).Error)
+		ids = append(ids, p.ID)
+	}
+
+	require.NoError(t, repo.ReorderProfile(owner, []uuid.UUID{ids[0], ids[1]}, now))
+	require.NoError(t, repo.SetPinned(owner, ids[2], true, now))
+
+	portfolios, total, err := repo.ListPublished(Viewer{}, "", nil, nil, nil, &owner, "-published_at", 1, 20)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+	var got []uuid.UUID
+	for _, p := range portfolios {
+		got = append(got, p.ID)
+		assert.Equal(t, owner, p.UserID)
+	}
+	assert.Equal(t, []uuid.UUID{ids[2], ids[0], ids[1], ids[3]}, got)
+
+	pinned, err := repo.CountPinned(owner)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pinned)
+	require.NoError(t, repo.SetPinned(owner, ids[2], false, now))
+	order, err := repo.ProfileOrder(owner)
+	require.NoError(t, err)
+	assert.False(t, order[ids[2]].IsPinned)
+	require.NotNil(t, order[ids[1]].DisplayOrder)
+	assert.Equal(t, 1, *order[ids[1]].DisplayOrder)
+
+	// A shorter second order leaves the dropped portfolios unordered again
+	require.NoError(t, repo.ReorderProfile(owner, []uuid.UUID{ids[1]}, now))
+	order, err = repo.ProfileOrder(owner)
+	require.NoError(t, err)
+	require.NotNil(t, order[ids[1]].DisplayOrder)
+	assert.Equal(t, 0, *order[ids[1]].DisplayOrder)
+	assert.Nil(t, order[ids[0]].DisplayOrder)
+
+	portfolios, _, err = repo.ListPublished(Viewer{}, "", nil, nil, nil, &owner, "-published_at", 1, 20)
+	require.NoError(t, err)
+	got = got[:0]
+	for _, p := range portfolios {
+		got = append(got, p.ID)
+	}
+	assert.Equal(t, []uuid.UUID{ids[1], ids[3], ids[2], ids[0]}, got)
+}
+
+// Links shared before a title or username change still find the portfolio
+func TestFindPublishedBySlug_FollowsRenames(t *testing.T) {
+	db := setupDraftTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.User{}, &domain.Kelas{}, &domain.Jurusan{}, &domain.Tag{}, &domain.Series{}, &domain.PortfolioCollaborator{}, &domain.PortfolioSlugHistory{}, &domain.UsernameHistory{}))
+	repo := NewPortfolioRepository(db)
+	userRepo := NewUserRepository(db)
+
+	author := domain.User{Username: "lama", Email: "penulis@example.com", Nama: "Penulis", Role: domain.RoleStudent}
+	require.NoError(t, db.Create(&author).Error)
+	portfolio := domain.Portfolio{UserID: author.ID, Judul: "Poster Baru", Slug: "poster-baru", Status: domain.StatusPublished, Visibility: domain.VisibilityPublic}
+	require.NoError(t, db.Create(&portfolio).Error)
+	// Written by a trigger in postgres when the title changes
+	require.NoError(t, db.Create(&domain.PortfolioSlugHistory{PortfolioID: portfolio.ID, UserID: author.ID, Slug: "poster"}).Error)
+
+	author.Username = "baru"
+	require.NoError(t, userRepo.ChangeUsername(&author, "lama"))
+
+	for _, lookup := range []struct{ slug, username string }{
+		{"poster-baru", "baru"},
+		{"poster", ""},
+		{"poster", "lama"},
+		{"poster-baru", "lama"},
+	} {
+		found, err := repo.FindPublishedBySlugAndUsername(lookup.slug, lookup.username, Viewer{})
+		require.NoError(t, err, lookup)
+		assert.Equal(t, portfolio.ID, found.ID)
+		assert.Equal(t, "poster-baru", found.Slug)
+		assert.Equal(t, "baru", found.User.Username)
+	}
+
+	found, err := repo.FindBySlugAndUserID("poster", author.ID)
+	require.NoError(t, err)
+	assert.Equal(t, portfolio.ID, found.ID)
+
+	// The old username stays reserved for its owner, who can take it back
+	taken, err := userRepo.UsernameExists("lama", nil)
+	require.NoError(t, err)
+	assert.True(t, taken)
+	taken, err = userRepo.UsernameExists("lama", &author.ID)
+	require.NoError(t, err)
+	assert.False(t, taken)
+
+	author.Username = "lama"
+	require.NoError(t, userRepo.ChangeUsername(&author, "baru"))
+	var previous []domain.UsernameHistory
+	require.NoError(t, db.Find(&previous).Error)
+	require.Len(t, previous, 1)
+	assert.Equal(t, "baru", previous[0].Username)
+}
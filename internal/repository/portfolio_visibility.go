@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+)
+
+// Viewer is whoever reads published portfolios; the zero value is an anonymous visitor
+type Viewer struct {
+	UserID *uuid.UUID
+	// JurusanID is the jurusan of the viewer's class, if any
+	JurusanID *uuid.UUID
+	// IsStaff lets admins and teachers see jurusan-only work of every jurusan
+	IsStaff bool
+}
+
+// visibilityCondition restricts published portfolios, aliased as table, to what viewer may see.
+// Listings (listed) leave out unlisted portfolios; direct access by link does not. Authors
+// always see their own work.
+func visibilityCondition(table string, viewer Viewer, listed bool) (string, []interface{}) {
+	levels := []domain.PortfolioVisibility{domain.VisibilityPublic}
+	if !listed {
+		levels = append(levels, domain.VisibilityUnlisted)
+	}
+	if viewer.UserID != nil {
+		levels = append(levels, domain.VisibilityLoggedIn)
+		if viewer.IsStaff {
+			levels = append(levels, domain.VisibilityJurusan)
+		}
+	}
+
+	conditions := []string{table + ".visibility IN ?"}
+	args := []interface{}{levels}
+	if viewer.UserID == nil {
+		return "(" + conditions[0] + ")", args
+	}
+
+	if !viewer.IsStaff && viewer.JurusanID != nil {
+		conditions = append(conditions, "("+table+".visibility = 'jurusan' AND "+table+".user_id IN "+
+			"(SELECT users.id FROM users JOIN kelas ON kelas.id = users.kelas_id WHERE kelas.jurusan_id = ?))")
+		args = append(args, *viewer.JurusanID)
+	}
+	conditions = append(conditions, strings.ReplaceAll(authoredByCondition, "portfolios.", table+"."))
+	args = append(args, *viewer.UserID, *viewer.UserID)
+
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"gorm.io/gorm"
+)
+
+// RejectionReasonStat counts how often a rejection reason was picked, overall and recently
+type RejectionReasonStat struct {
+	ReasonID uuid.UUID
+	Nama     string
+	Total    int64
+	Recent   int64
+}
+
+type RejectionRepository struct {
+	db *gorm.DB
+}
+
+func NewRejectionRepository(db *gorm.DB) *RejectionRepository {
+	return &RejectionRepository{db: db}
+}
+
+// ============================================================================
+// REJECTION REASONS
+// ============================================================================
+
+func (r *RejectionRepository) CreateReason(reason *domain.RejectionReason) error {
+	var maxUrutan int
+	r.db.Model(&domain.RejectionReason{}).
+		Where("deleted_at IS NULL").
+		Select("COALESCE(MAX(urutan), 0)").
+		Scan(&maxUrutan)
+	reason.Urutan = maxUrutan + 1
+	return r.db.Create(reason).Error
+}
+
+func (r *RejectionRepository) FindReasonByID(id uuid.UUID) (*domain.RejectionReason, error) {
+	var reason domain.RejectionReason
+	err := r.db.Where("id = ? AND deleted_at IS NULL", id).First(&reason).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reason, nil
+}
+
+// FindActiveReasons returns the active reasons among ids, in their configured order
+func (r *RejectionRepository) FindActiveReasons(ids []uuid.UUID) ([]domain.RejectionReason, error) {
+	var reasons []domain.RejectionReason
+	if len(ids) == 0 {
+		return reasons, nil
+	}
+	err := r.db.Where("id IN ? AND deleted_at IS NULL AND is_active = ?", ids, true).
+		Order("urutan ASC").Find(&reasons).Error
+	return reasons, err
+}
+
+func (r *RejectionRepository) UpdateReason(reason *domain.RejectionReason) error {
+	return r.db.Save(reason).Error
+}
+
+// DeleteReason hides the reason from admins; past rejections keep referring to it
+func (r *RejectionRepository) DeleteReason(id uuid.UUID) error {
+	return r.db.Model(&domain.RejectionReason{}).
+		Where("id = ?", id).
+		Update("deleted_at", gorm.Expr("NOW()")).Error
+}
+
+func (r *RejectionRepository) ListReasons(activeOnly bool) ([]domain.RejectionReason, error) {
+	var reasons []domain.RejectionReason
+	query := r.db.Where("deleted_at IS NULL")
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Order("urutan ASC").Find(&reasons).Error
+	return reasons, err
+}
+
+func (r *RejectionRepository) ReorderReasons(orders []struct {
+	ID     uuid.UUID
+	Urutan int
+}) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, order := range orders {
+			if err := tx.Model(&domain.RejectionReason{}).
+				Where("id = ?", order.ID).
+				Update("urutan", order.Urutan).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ReasonStats counts the rejections citing each reason, most used first
+func (r *RejectionRepository) ReasonStats(since time.Time) ([]RejectionReasonStat, error) {
+	var stats []RejectionReasonStat
+	err := r.db.Table("portfolio_rejection_reasons prr").
+		Select("rr.id AS reason_id, rr.nama, COUNT(*) AS total, "+
+			"SUM(CASE WHEN pr.created_at >= ? THEN 1 ELSE 0 END) AS recent", since).
+		Joins("JOIN portfolio_rejections pr ON pr.id = prr.rejection_id").
+		Joins("JOIN rejection_reasons rr ON rr.id = prr.reason_id").
+		Group("rr.id, rr.nama").
+		Order("total DESC, rr.nama ASC").
+		Scan(&stats).Error
+	return stats, err
+}
+
+// ============================================================================
+// PORTFOLIO REJECTIONS
+// ============================================================================
+
+// CreateRejection stores a rejection with its reasons and block pointers
+func (r *RejectionRepository) CreateRejection(rejection *domain.PortfolioRejection) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Reasons").Create(rejection).Error; err != nil {
+			return err
+		}
+		for _, reason := range rejection.Reasons {
+			link := domain.PortfolioRejectionReason{RejectionID: rejection.ID, ReasonID: reason.ID}
+			if err := tx.Create(&link).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// LatestRejection returns the most recent rejection of a portfolio, or nil when it was never rejected
+func (r *RejectionRepository) LatestRejection(portfolioID uuid.UUID) (*domain.PortfolioRejection, error) {
+	var rejections []domain.PortfolioRejection
+	err := r.db.
+		Preload("Reasons", func(db *gorm.DB) *gorm.DB { return db.Order("urutan ASC") }).
+		Preload("Blocks").
+		Where("portfolio_id = ?", portfolioID).
+		Order("created_at DESC").
+		Limit(1).Find(&rejections).Error
+	if err != nil || len(rejections) == 0 {
+		return nil, err
+	}
+	return &rejections[0], nil
+}
+
+// ============================================================================
+// SERIES REVIEW CHECKLISTS
+// ============================================================================
+
+func (r *RejectionRepository) ListChecklist(seriesID uuid.UUID) ([]domain.SeriesChecklistItem, error) {
+	var items []domain.SeriesChecklistItem
+	err := r.db.Where("series_id = ?", seriesID).Order("urutan ASC").Find(&items).Error
+	return items, err
+}
+
+// ReplaceChecklist swaps the checklist of a series for labels, in the given order
+func (r *RejectionRepository) ReplaceChecklist(seriesID uuid.UUID, labels []string) ([]domain.SeriesChecklistItem, error) {
+	items := make([]domain.SeriesChecklistItem, 0, len(labels))
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", seriesID).Delete(&domain.SeriesChecklistItem{}).Error; err != nil {
+			return err
+		}
+		for i, label := range labels {
+			item := domain.SeriesChecklistItem{SeriesID: seriesID, Label: label, Urutan: i + 1}
+			if err := tx.Create(&item).Error; err != nil {
+				return err
+			}
+			items = append(items, item)
+		}
+		return nil
+	})
+	return items, err
+}
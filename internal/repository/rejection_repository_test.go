@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Rejections keep their reasons and block pointers, and feed the per-reason statistics
+func TestRejections_RecordAndStats(t *testing.T) {
+	db := setupDraftTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.RejectionReason{}, &domain.PortfolioRejection{}, &domain.PortfolioRejectionReason{}, &domain.PortfolioRejectionBlock{}))
+	repo := NewRejectionRepository(db)
+
+	incomplete := domain.RejectionReason{Nama: "Konten tidak lengkap", IsActive: true}
+	blurry := domain.RejectionReason{Nama: "Kualitas gambar rendah", IsActive: true}
+	retired := domain.RejectionReason{Nama: "Format lama", IsActive: true}
+	for _, reason := range []*domain.RejectionReason{&incomplete, &blurry, &retired} {
+		require.NoError(t, repo.CreateReason(reason))
+	}
+	assert.Equal(t, 2, blurry.Urutan)
+	retired.IsActive = false
+	require.NoError(t, repo.UpdateReason(&retired))
+
+	active, err := repo.FindActiveReasons([]uuid.UUID{incomplete.ID, retired.ID})
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Equal(t, incomplete.ID, active[0].ID)
+
+	portfolioID, blockID := uuid.New(), uuid.New()
+	note := "Tambahkan foto proses"
+	first := domain.PortfolioRejection{PortfolioID: portfolioID, Reasons: []domain.RejectionReason{incomplete}}
+	require.NoError(t, repo.CreateRejection(&first))
+	require.NoError(t, db.Model(&first).Update("created_at", time.Now().AddDate(0, -2, 0)).Error)
+
+	latest := domain.PortfolioRejection{
+		PortfolioID: portfolioID,
+		Note:        &note,
+		Reasons:     []domain.RejectionReason{incomplete, blurry},
+		Blocks:      []domain.PortfolioRejectionBlock{{BlockID: blockID, Note: "Gambar pecah"}},
+	}
+	require.NoError(t, repo.CreateRejection(&latest))
+
+	found, err := repo.LatestRejection(portfolioID)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, latest.ID, found.ID)
+	require.Len(t, found.Reasons, 2)
+	assert.Equal(t, incomplete.ID, found.Reasons[0].ID)
+	require.Len(t, found.Blocks, 1)
+	assert.Equal(t, blockID, found.Blocks[0].BlockID)
+
+	none, err := repo.LatestRejection(uuid.New())
+	require.NoError(t, err)
+	assert.Nil(t, none)
+
+	stats, err := repo.ReasonStats(time.Now().AddDate(0, -1, 0))
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	assert.Equal(t, incomplete.ID, stats[0].ReasonID)
+	assert.Equal(t, int64(2), stats[0].Total)
+	assert.Equal(t, int64(1), stats[0].Recent)
+	assert.Equal(t, int64(1), stats[1].Total)
+}
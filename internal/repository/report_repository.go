@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"gorm.io/gorm"
+)
+
+// ReportCase groups the reports filed against one target
+type ReportCase struct {
+	TargetType   domain.ReportTargetType
+	TargetID     uuid.UUID
+	TargetUserID uuid.UUID
+	ReportCount  int64
+	Reasons      map[domain.ReportReason]int64 `gorm:"-"`
+	TargetUser   *domain.User                  `gorm:"-"`
+}
+
+type ReportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+func (r *ReportRepository) Create(report *domain.ContentReport) error {
+	return r.db.Create(report).Error
+}
+
+// HasPending reports whether the reporter already has an open report on the target
+func (r *ReportRepository) HasPending(reporterID uuid.UUID, targetType domain.ReportTargetType, targetID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.ContentReport{}).
+		Where("reporter_id = ? AND target_type = ? AND target_id = ? AND status = ?", reporterID, targetType, targetID, domain.ReportStatusPending).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// HasPendingFlag reports whether the text filter already queued the target for review
+func (r *ReportRepository) HasPendingFlag(targetType domain.ReportTargetType, targetID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.ContentReport{}).
+		Where("reporter_id IS NULL AND target_type = ? AND target_id = ? AND status = ?", targetType, targetID, domain.ReportStatusPending).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindByTarget returns the reports on a target, newest first; an empty status returns all of them
+func (r *ReportRepository) FindByTarget(targetType domain.ReportTargetType, targetID uuid.UUID, status domain.ReportStatus) ([]domain.ContentReport, error) {
+	var reports []domain.ContentReport
+	query := r.db.Preload("Reporter").Where("target_type = ? AND target_id = ?", targetType, targetID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at DESC").Find(&reports).Error
+	return reports, err
+}
+
+// ListCases returns reported targets with their report count, most reported first, so each
+// target shows up once in the moderation queue however many users reported it
+func (r *ReportRepository) ListCases(status domain.ReportStatus, targetType string, page, limit int) ([]ReportCase, int64, error) {
+	query := r.db.Model(&domain.ContentReport{}).Where("status = ?", status)
+	if targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+	grouped := query.Select("target_type, target_id, target_user_id, COUNT(*) AS report_count").
+		Group("target_type, target_id, target_user_id")
+
+	var total int64
+	if err := r.db.Table("(?) AS cases", grouped).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var cases []ReportCase
+	offset := (page - 1) * limit
+	if err := grouped.Order("report_count DESC, MAX(created_at) DESC").
+		Offset(offset).Limit(limit).
+		Scan(&cases).Error; err != nil {
+		return nil, 0, err
+	}
+	if len(cases) == 0 {
+		return cases, total, nil
+	}
+
+	targetIDs := make([]uuid.UUID, 0, len(cases))
+	userIDs := make([]uuid.UUID, 0, len(cases))
+	for _, c := range cases {
+		targetIDs = append(targetIDs, c.TargetID)
+		userIDs = append(userIDs, c.TargetUserID)
+	}
+
+	var reasons []struct {
+		TargetID uuid.UUID
+		Reason   domain.ReportReason
+		Count    int64
+	}
+	if err := r.db.Model(&domain.ContentReport{}).
+		Select("target_id, reason, COUNT(*) AS count").
+		Where("status = ? AND target_id IN ?", status, targetIDs).
+		Group("target_id, reason").
+		Scan(&reasons).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []domain.User
+	if err := r.db.Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	usersByID := make(map[uuid.UUID]*domain.User, len(users))
+	for i := range users {
+		usersByID[users[i].ID] = &users[i]
+	}
+
+	for i := range cases {
+		cases[i].Reasons = make(map[domain.ReportReason]int64)
+		for _, reason := range reasons {
+			if reason.TargetID == cases[i].TargetID {
+				cases[i].Reasons[reason.Reason] = reason.Count
+			}
+		}
+		cases[i].TargetUser = usersByID[cases[i].TargetUserID]
+	}
+	return cases, total, nil
+}
+
+// ResolvePending closes every open report on a target and returns who filed them
+func (r *ReportRepository) ResolvePending(targetType domain.ReportTargetType, targetID uuid.UUID, status domain.ReportStatus, action domain.ReportAction, note *string, resolvedBy uuid.UUID, at time.Time) ([]uuid.UUID, error) {
+	var reporterIDs []uuid.UUID
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		pending := tx.Model(&domain.ContentReport{}).
+			Where("target_type = ? AND target_id = ? AND status = ?", targetType, targetID, domain.ReportStatusPending)
+		if err := pending.Session(&gorm.Session{}).Where("reporter_id IS NOT NULL").Distinct().Pluck("reporter_id", &reporterIDs).Error; err != nil {
+			return err
+		}
+		return pending.Session(&gorm.Session{}).Updates(map[string]interface{}{
+			"status":          status,
+			"action":          action,
+			"resolution_note": note,
+			"resolved_by":     resolvedBy,
+			"resolved_at":     at,
+			"updated_at":      at,
+		}).Error
+	})
+	return reporterIDs, err
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Reports on one target form a single case in the queue, and resolving it closes all of them
+func TestReportCases_GroupAndResolve(t *testing.T) {
+	db := setupDraftTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.User{}, &domain.ContentReport{}))
+	repo := NewReportRepository(db)
+
+	author := domain.User{Username: "siswa", Email: "siswa@example.com", Nama: "Siswa", Role: domain.RoleStudent}
+	require.NoError(t, db.Create(&author).Error)
+	spammed, other := uuid.New(), uuid.New()
+	reporters := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+
+	file := func(reporterID, targetID uuid.UUID, reason domain.ReportReason) {
+		require.NoError(t, repo.Create(&domain.ContentReport{
+			ReporterID: &reporterID, TargetType: domain.ReportTargetComment, TargetID: targetID,
+			TargetUserID: author.ID, Reason: reason, Status: domain.ReportStatusPending,
+		}))
+	}
+	file(reporters[0], spammed, domain.ReportReasonSpam)
+	file(reporters[1], spammed, domain.ReportReasonSpam)
+	file(reporters[2], spammed, domain.ReportReasonHarassment)
+	file(reporters[0], other, domain.ReportReasonOther)
+
+	dup, err := repo.HasPending(reporters[0], domain.ReportTargetComment, spammed)
+	require.NoError(t, err)
+	assert.True(t, dup)
+
+	cases, total, err := repo.ListCases(domain.ReportStatusPending, "", 1, 20)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, cases, 2)
+	assert.Equal(t, spammed, cases[0].TargetID, "most reported target leads the queue")
+	assert.Equal(t, int64(3), cases[0].ReportCount)
+	assert.Equal(t, map[domain.ReportReason]int64{domain.ReportReasonSpam: 2, domain.ReportReasonHarassment: 1}, cases[0].Reasons)
+	require.NotNil(t, cases[0].TargetUser)
+	assert.Equal(t, "siswa", cases[0].TargetUser.Username)
+
+	notified, err := repo.ResolvePending(domain.ReportTargetComment, spammed, domain.ReportStatusResolved, domain.ReportActionHideContent, nil, uuid.New(), time.Now())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, reporters, notified)
+
+	cases, total, err = repo.ListCases(domain.ReportStatusPending, "", 1, 20)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, cases, 1)
+	assert.Equal(t, other, cases[0].TargetID)
+
+	dup, err = repo.HasPending(reporters[0], domain.ReportTargetComment, spammed)
+	require.NoError(t, err)
+	assert.False(t, dup, "a closed case can be reported again")
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PendingAssignment is a pending portfolio nobody holds a claim on, with its author's jurusan
+type PendingAssignment struct {
+	PortfolioID uuid.UUID
+	UserID      uuid.UUID
+	JurusanID   uuid.UUID
+}
+
+type ReviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+// IsPendingReview reports whether the portfolio or its working draft waits for review
+func (r *ReviewRepository) IsPendingReview(portfolioID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.Portfolio{}).
+		Where("portfolios.id = ? AND portfolios.deleted_at IS NULL", portfolioID).
+		Where(pendingReviewCondition).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ActiveClaim returns the unexpired claim on a portfolio, or nil when it is free
+func (r *ReviewRepository) ActiveClaim(portfolioID uuid.UUID, now time.Time) (*domain.ReviewClaim, error) {
+	var claims []domain.ReviewClaim
+	err := r.db.Preload("Reviewer").
+		Where("portfolio_id = ? AND expires_at > ?", portfolioID, now).
+		Limit(1).Find(&claims).Error
+	if err != nil || len(claims) == 0 {
+		return nil, err
+	}
+	return &claims[0], nil
+}
+
+// ActiveClaims returns the unexpired claims on the given portfolios, keyed by portfolio
+func (r *ReviewRepository) ActiveClaims(portfolioIDs []uuid.UUID, now time.Time) (map[uuid.UUID]domain.ReviewClaim, error) {
+	result := make(map[uuid.UUID]domain.ReviewClaim, len(portfolioIDs))
+	if len(portfolioIDs) == 0 {
+		return result, nil
+	}
+
+	var claims []domain.ReviewClaim
+	err := r.db.Preload("Reviewer").
+		Where("portfolio_id IN ? AND expires_at > ?", portfolioIDs, now).
+		Find(&claims).Error
+	for _, claim := range claims {
+		result[claim.PortfolioID] = claim
+	}
+	return result, err
+}
+
+// Claim locks a portfolio for claim.ReviewerID unless another reviewer holds an unexpired
+// claim on it, and reports whether the lock was taken. Expired claims and the reviewer's own
+// earlier claim are replaced.
+func (r *ReviewRepository) Claim(claim *domain.ReviewClaim, now time.Time) (bool, error) {
+	var taken bool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		taken, err = r.claim(tx, claim, now)
+		return err
+	})
+	return taken, err
+}
+
+// Assign claims a portfolio on behalf of a reviewer from a jurusan pool and moves that
+// reviewer to the back of the rotation
+func (r *ReviewRepository) Assign(claim *domain.ReviewClaim, jurusanID uuid.UUID, now time.Time) (bool, error) {
+	var taken bool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if taken, err = r.claim(tx, claim, now); err != nil || !taken {
+			return err
+		}
+		return tx.Model(&domain.ReviewerPool{}).
+			Where("jurusan_id = ? AND reviewer_id = ?", jurusanID, claim.ReviewerID).
+			Update("last_assigned_at", now).Error
+	})
+	return taken, err
+}
+
+func (r *ReviewRepository) claim(tx *gorm.DB, claim *domain.ReviewClaim, now time.Time) (bool, error) {
+	if err := tx.Where("portfolio_id = ? AND (expires_at <= ? OR reviewer_id = ?)", claim.PortfolioID, now, claim.ReviewerID).
+		Delete(&domain.ReviewClaim{}).Error; err != nil {
+		return false, err
+	}
+	// A concurrent claim by someone else wins the primary key; ours is then dropped
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(claim)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Release drops the reviewer's claim on a portfolio and reports whether there was one
+func (r *ReviewRepository) Release(portfolioID, reviewerID uuid.UUID) (bool, error) {
+	result := r.db.Where("portfolio_id = ? AND reviewer_id = ?", portfolioID, reviewerID).
+		Delete(&domain.ReviewClaim{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ReleaseAll drops any claim on a portfolio, once it has been approved or rejected
+func (r *ReviewRepository) ReleaseAll(portfolioID uuid.UUID) error {
+	return r.db.Where("portfolio_id = ?", portfolioID).Delete(&domain.ReviewClaim{}).Error
+}
+
+// MyQueue returns the pending portfolios the reviewer holds an unexpired claim on, oldest claim first
+func (r *ReviewRepository) MyQueue(reviewerID uuid.UUID, now time.Time, page, limit int) ([]domain.Portfolio, int64, error) {
+	var portfolios []domain.Portfolio
+	var total int64
+
+	query := r.db.Model(&domain.Portfolio{}).
+		Joins("JOIN review_claims ON review_claims.portfolio_id = portfolios.id").
+		Where("review_claims.reviewer_id = ? AND review_claims.expires_at > ? AND portfolios.deleted_at IS NULL", reviewerID, now).
+		Where(pendingReviewCondition)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Preload("User.Kelas.Jurusan").
+		Order("review_claims.claimed_at ASC").
+		Offset(offset).Limit(limit).
+		Find(&portfolios).Error
+	return portfolios, total, err
+}
+
+// UnclaimedPending returns pending portfolios without an unexpired claim whose author's
+// jurusan has a reviewer pool, longest waiting first
+func (r *ReviewRepository) UnclaimedPending(now time.Time, limit int) ([]PendingAssignment, error) {
+	var rows []PendingAssignment
+	err := r.db.Model(&domain.Portfolio{}).
+		Select("portfolios.id AS portfolio_id, portfolios.user_id, kelas.jurusan_id").
+		Joins("JOIN users ON users.id = portfolios.user_id").
+		Joins("JOIN kelas ON kelas.id = users.kelas_id").
+		Where("portfolios.deleted_at IS NULL").
+		Where(pendingReviewCondition).
+		Where("kelas.jurusan_id IN (SELECT jurusan_id FROM reviewer_pools)").
+		Where("NOT EXISTS (SELECT 1 FROM review_claims rc WHERE rc.portfolio_id = portfolios.id AND rc.expires_at > ?)", now).
+		Order("portfolios.updated_at ASC").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// NextReviewers returns the active pool members of a jurusan in rotation order, least recently
+// assigned and never-assigned members first, leaving out the author of the portfolio
+func (r *ReviewRepository) NextReviewers(jurusanID, authorID uuid.UUID) ([]domain.ReviewerPool, error) {
+	var members []domain.ReviewerPool
+	err := r.db.Joins("JOIN users ON users.id = reviewer_pools.reviewer_id").
+		Where("reviewer_pools.jurusan_id = ? AND reviewer_pools.reviewer_id <> ?", jurusanID, authorID).
+		Where("users.is_active = ? AND users.deleted_at IS NULL", true).
+		Order("reviewer_pools.last_assigned_at IS NOT NULL, reviewer_pools.last_assigned_at ASC, reviewer_pools.created_at ASC").
+		Find(&members).Error
+	return members, err
+}
+
+// ListPools returns every reviewer pool member grouped by jurusan
+func (r *ReviewRepository) ListPools() ([]domain.ReviewerPool, error) {
+	var members []domain.ReviewerPool
+	err := r.db.Preload("Reviewer").Order("jurusan_id, created_at ASC").Find(&members).Error
+	return members, err
+}
+
+// SetPool replaces the reviewers of a jurusan; reviewers kept in the pool keep their place in the rotation
+func (r *ReviewRepository) SetPool(jurusanID uuid.UUID, reviewerIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		remove := tx.Where("jurusan_id = ?", jurusanID)
+		if len(reviewerIDs) > 0 {
+			remove = remove.Where("reviewer_id NOT IN ?", reviewerIDs)
+		}
+		if err := remove.Delete(&domain.ReviewerPool{}).Error; err != nil {
+			return err
+		}
+		for _, reviewerID := range reviewerIDs {
+			member := domain.ReviewerPool{JurusanID: jurusanID, ReviewerID: reviewerID}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&member).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A claim blocks other reviewers until it expires, and pool members take turns on new submissions
+func TestReviewClaims_LockAndRotation(t *testing.T) {
+	db := setupDraftTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.User{}, &domain.Kelas{}, &domain.ReviewClaim{}, &domain.ReviewerPool{}))
+	repo := NewReviewRepository(db)
+
+	rpl := uuid.New()
+	kelas := domain.Kelas{Nama: "XII RPL 1", JurusanID: rpl}
+	require.NoError(t, db.Create(&kelas).Error)
+	author := domain.User{Username: "penulis", Email: "penulis@example.com", Nama: "Penulis", Role: domain.RoleStudent, KelasID: &kelas.ID}
+	require.NoError(t, db.Create(&author).Error)
+
+	var pending []domain.Portfolio
+	for _, slug := range []string{"poster", "logo", "maskot"} {
+		p := domain.Portfolio{UserID: author.ID, Judul: slug, Slug: slug, Status: domain.StatusPendingReview}
+		require.NoError(t, db.Create(&p).Error)
+		pending = append(pending, p)
+	}
+
+	now := time.Now()
+	var reviewers []uuid.UUID
+	for _, name := range []string{"alice", "bob", "carol"} {
+		u := domain.User{Username: name, Email: name + "@example.com", Nama: name, Role: domain.RoleTeacher}
+		require.NoError(t, db.Create(&u).Error)
+		reviewers = append(reviewers, u.ID)
+	}
+	alice, bob, carol := reviewers[0], reviewers[1], reviewers[2]
+	require.NoError(t, db.Model(&domain.User{}).Where("id = ?", carol).Update("is_active", false).Error)
+	taken, err := repo.Claim(&domain.ReviewClaim{PortfolioID: pending[0].ID, ReviewerID: alice, ClaimedAt: now, ExpiresAt: now.Add(30 * time.Minute)}, now)
+	require.NoError(t, err)
+	assert.True(t, taken)
+
+	taken, err = repo.Claim(&domain.ReviewClaim{PortfolioID: pending[0].ID, ReviewerID: bob, ClaimedAt: now, ExpiresAt: now.Add(30 * time.Minute)}, now)
+	require.NoError(t, err)
+	assert.False(t, taken, "an unexpired claim by someone else holds")
+
+	later := now.Add(time.Hour)
+	taken, err = repo.Claim(&domain.ReviewClaim{PortfolioID: pending[0].ID, ReviewerID: bob, ClaimedAt: later, ExpiresAt: later.Add(30 * time.Minute)}, later)
+	require.NoError(t, err)
+	assert.True(t, taken, "an expired claim can be taken over")
+
+	queue, total, err := repo.MyQueue(bob, later, 1, 20)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, queue, 1)
+	assert.Equal(t, pending[0].ID, queue[0].ID)
+
+	require.NoError(t, repo.SetPool(rpl, []uuid.UUID{carol, alice, bob}))
+	unclaimed, err := repo.UnclaimedPending(later, 10)
+	require.NoError(t, err)
+	require.Len(t, unclaimed, 2)
+
+	var assignees []uuid.UUID
+	for _, item := range unclaimed {
+		assert.Equal(t, rpl, item.JurusanID)
+		members, err := repo.NextReviewers(item.JurusanID, item.UserID)
+		require.NoError(t, err)
+		require.Len(t, members, 2, "deactivated members are left out")
+		next := members[0]
+		taken, err := repo.Assign(&domain.ReviewClaim{PortfolioID: item.PortfolioID, ReviewerID: next.ReviewerID, IsAssigned: true, ClaimedAt: later, ExpiresAt: later.Add(24 * time.Hour)}, rpl, later)
+		require.NoError(t, err)
+		require.True(t, taken)
+		assignees = append(assignees, next.ReviewerID)
+		later = later.Add(time.Second)
+	}
+	assert.ElementsMatch(t, []uuid.UUID{alice, bob}, assignees, "assignments alternate between pool members")
+
+	unclaimed, err = repo.UnclaimedPending(later, 10)
+	require.NoError(t, err)
+	assert.Empty(t, unclaimed)
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewRevisionRepository(db *gorm.DB) *RevisionRepository {
+	return &RevisionRepository{db: db}
+}
+
+// Create stores a revision with the next revision number of its portfolio.
+// The portfolio row is locked so concurrent snapshots cannot take the same number.
+func (r *RevisionRepository) Create(revision *domain.PortfolioRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var portfolio domain.Portfolio
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").Where("id = ?", revision.PortfolioID).
+			First(&portfolio).Error; err != nil {
+			return err
+		}
+
+		var last int
+		if err := tx.Model(&domain.PortfolioRevision{}).
+			Where("portfolio_id = ?", revision.PortfolioID).
+			Select("COALESCE(MAX(revision_number), 0)").
+			Scan(&last).Error; err != nil {
+			return err
+		}
+
+		revision.RevisionNumber = last + 1
+		return tx.Create(revision).Error
+	})
+}
+
+// ListByPortfolio returns all revisions of a portfolio, newest first
+func (r *RevisionRepository) ListByPortfolio(portfolioID uuid.UUID) ([]domain.PortfolioRevision, error) {
+	var revisions []domain.PortfolioRevision
+	err := r.db.Preload("Creator").
+		Where("portfolio_id = ?", portfolioID).
+		Order("revision_number DESC").
+		Find(&revisions).Error
+	return revisions, err
+}
+
+// FindByNumber returns a single revision of a portfolio
+func (r *RevisionRepository) FindByNumber(portfolioID uuid.UUID, number int) (*domain.PortfolioRevision, error) {
+	var revision domain.PortfolioRevision
+	err := r.db.Preload("Creator").
+		Where("portfolio_id = ? AND revision_number = ?", portfolioID, number).
+		First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
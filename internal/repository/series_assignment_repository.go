@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"gorm.io/gorm"
+)
+
+// SeriesAssignmentRepository serves the assignment side of series: which kelas/jurusan a
+// series targets, who those students are, and how far each of them got.
+type SeriesAssignmentRepository struct {
+	db *gorm.DB
+}
+
+func NewSeriesAssignmentRepository(db *gorm.DB) *SeriesAssignmentRepository {
+	return &SeriesAssignmentRepository{db: db}
+}
+
+// SeriesWork is a portfolio in a series seen from one of its authors
+type SeriesWork struct {
+	AuthorID    uuid.UUID
+	PortfolioID uuid.UUID
+	Judul       string
+	Status      domain.PortfolioStatus
+	SubmittedAt *time.Time
+}
+
+// targetedKelasCondition matches users whose kelas is targeted by a series, directly or
+// through its jurusan; it takes the series ID twice
+const targetedKelasCondition = `users.kelas_id IN (
+	SELECT kelas_id FROM series_targets WHERE series_id = ? AND kelas_id IS NOT NULL
+	UNION
+	SELECT kelas.id FROM kelas JOIN series_targets ON series_targets.jurusan_id = kelas.jurusan_id
+	WHERE series_targets.series_id = ? AND kelas.deleted_at IS NULL
+)`
+
+// ListTargets returns the kelas and jurusan targets of a series
+func (r *SeriesAssignmentRepository) ListTargets(seriesID uuid.UUID) ([]domain.SeriesTarget, error) {
+	var targets []domain.SeriesTarget
+	err := r.db.Where("series_id = ?", seriesID).
+		Preload("Kelas").Preload("Jurusan").
+		Order("created_at ASC").
+		Find(&targets).Error
+	return targets, err
+}
+
+// ReplaceTargets swaps the targets of a series for the given kelas and jurusan
+func (r *SeriesAssignmentRepository) ReplaceTargets(seriesID uuid.UUID, kelasIDs, jurusanIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", seriesID).Delete(&domain.SeriesTarget{}).Error; err != nil {
+			return err
+		}
+
+		targets := make([]domain.SeriesTarget, 0, len(kelasIDs)+len(jurusanIDs))
+		for i := range kelasIDs {
+			targets = append(targets, domain.SeriesTarget{SeriesID: seriesID, KelasID: &kelasIDs[i]})
+		}
+		for i := range jurusanIDs {
+			targets = append(targets, domain.SeriesTarget{SeriesID: seriesID, JurusanID: &jurusanIDs[i]})
+		}
+		if len(targets) == 0 {
+			return nil
+		}
+		return tx.Create(&targets).Error
+	})
+}
+
+// CountExistingKelas returns how many of ids are live kelas
+func (r *SeriesAssignmentRepository) CountExistingKelas(ids []uuid.UUID) (int64, error) {
+	var count int64
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	err := r.db.Model(&domain.Kelas{}).Where("id IN ? AND deleted_at IS NULL", ids).Count(&count).Error
+	return count, err
+}
+
+// CountExistingJurusan returns how many of ids are live jurusan
+func (r *SeriesAssignmentRepository) CountExistingJurusan(ids []uuid.UUID) (int64, error) {
+	var count int64
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	err := r.db.Model(&domain.Jurusan{}).Where("id IN ? AND deleted_at IS NULL", ids).Count(&count).Error
+	return count, err
+}
+
+// ListTargetStudents returns the active students the series is assigned to. With waliKelasID
+// set only students of that teacher's classes are returned.
+func (r *SeriesAssignmentRepository) ListTargetStudents(seriesID uuid.UUID, waliKelasID *uuid.UUID) ([]domain.User, error) {
+	var users []domain.User
+	query := r.db.Model(&domain.User{}).
+		Where("users.role = ? AND users.is_active = ? AND users.deleted_at IS NULL", domain.RoleStudent, true).
+		Where(targetedKelasCondition, seriesID, seriesID)
+	if waliKelasID != nil {
+		query = query.Where("users.kelas_id IN (SELECT id FROM kelas WHERE wali_kelas_id = ? AND deleted_at IS NULL)", *waliKelasID)
+	}
+	err := query.Preload("Kelas").Order("users.nama ASC").Find(&users).Error
+	return users, err
+}
+
+// ListWork returns the portfolios in the series authored by any of userIDs, once per author,
+// counting accepted co-authors as authors
+func (r *SeriesAssignmentRepository) ListWork(seriesID uuid.UUID, userIDs []uuid.UUID) ([]SeriesWork, error) {
+	var work []SeriesWork
+	if len(userIDs) == 0 {
+		return work, nil
+	}
+	err := r.db.Raw(`
+		SELECT p.user_id AS author_id, p.id AS portfolio_id, p.judul, p.status, p.submitted_at
+		FROM portfolios p
+		WHERE p.series_id = ? AND p.deleted_at IS NULL AND p.user_id IN ?
+		UNION ALL
+		SELECT pc.user_id AS author_id, p.id AS portfolio_id, p.judul, p.status, p.submitted_at
+		FROM portfolios p
+		JOIN portfolio_collaborators pc ON pc.portfolio_id = p.id AND pc.status = 'accepted'
+		WHERE p.series_id = ? AND p.deleted_at IS NULL AND pc.user_id IN ?
+	`, seriesID, userIDs, seriesID, userIDs).Scan(&work).Error
+	return work, err
+}
+
+// FindDueReminders returns active, targeted series whose deadline falls in (now, until]
+// and whose reminder has not been sent yet
+func (r *SeriesAssignmentRepository) FindDueReminders(now, until time.Time) ([]domain.Series, error) {
+	var series []domain.Series
+	err := r.db.Where("deleted_at IS NULL AND is_active = ? AND reminder_sent_at IS NULL", true).
+		Where("closes_at > ? AND closes_at <= ?", now, until).
+		Where("EXISTS (SELECT 1 FROM series_targets WHERE series_targets.series_id = series.id)").
+		Order("closes_at ASC").
+		Find(&series).Error
+	return series, err
+}
+
+// MarkReminderSent claims the deadline reminder of a series. It reports false when another
+// run already sent it.
+func (r *SeriesAssignmentRepository) MarkReminderSent(seriesID uuid.UUID, at time.Time) (bool, error) {
+	result := r.db.Model(&domain.Series{}).
+		Where("id = ? AND reminder_sent_at IS NULL", seriesID).
+		Update("reminder_sent_at", at)
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"gorm.io/gorm"
+)
+
+type ShareLinkRepository struct {
+	db *gorm.DB
+}
+
+func NewShareLinkRepository(db *gorm.DB) *ShareLinkRepository {
+	return &ShareLinkRepository{db: db}
+}
+
+func (r *ShareLinkRepository) Create(link *domain.PortfolioShareLink) error {
+	return r.db.Create(link).Error
+}
+
+func (r *ShareLinkRepository) FindByID(id uuid.UUID) (*domain.PortfolioShareLink, error) {
+	var link domain.PortfolioShareLink
+	if err := r.db.Where("id = ?", id).First(&link).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *ShareLinkRepository) FindByTokenHash(hash string) (*domain.PortfolioShareLink, error) {
+	var link domain.PortfolioShareLink
+	if err := r.db.Where("token_hash = ?", hash).First(&link).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// ListByPortfolio returns every link of a portfolio, newest first, revoked and expired ones included
+func (r *ShareLinkRepository) ListByPortfolio(portfolioID uuid.UUID) ([]domain.PortfolioShareLink, error) {
+	var links []domain.PortfolioShareLink
+	err := r.db.Where("portfolio_id = ?", portfolioID).Order("created_at DESC").Find(&links).Error
+	return links, err
+}
+
+// CountActive counts the links of a portfolio that still open it at now
+func (r *ShareLinkRepository) CountActive(portfolioID uuid.UUID, now time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.PortfolioShareLink{}).
+		Where("portfolio_id = ? AND revoked_at IS NULL AND expires_at > ?", portfolioID, now).
+		Count(&count).Error
+	return count, err
+}
+
+// Revoke disables a link; revoking twice keeps the first revocation time
+func (r *ShareLinkRepository) Revoke(id uuid.UUID, at time.Time) error {
+	return r.db.Model(&domain.PortfolioShareLink{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+}
+
+// RecordView bumps the view counter of a link in place so concurrent views are not lost
+func (r *ShareLinkRepository) RecordView(id uuid.UUID, at time.Time) error {
+	return r.db.Model(&domain.PortfolioShareLink{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"view_count":     gorm.Expr("view_count + 1"),
+			"last_viewed_at": at,
+		}).Error
+}
+
+func (r *ShareLinkRepository) CreateComment(comment *domain.ShareLinkComment) error {
+	return r.db.Create(comment).Error
+}
+
+// ListComments returns guest comments oldest first, for one link or, with a nil linkID,
+// for every link of the portfolio
+func (r *ShareLinkRepository) ListComments(portfolioID uuid.UUID, linkID *uuid.UUID) ([]domain.ShareLinkComment, error) {
+	var comments []domain.ShareLinkComment
+	query := r.db.Where("portfolio_id = ?", portfolioID)
+	if linkID != nil {
+		query = query.Where("share_link_id = ?", *linkID)
+	}
+	err := query.Order("created_at ASC").Find(&comments).Error
+	return comments, err
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"gorm.io/gorm"
+)
+
+// TeacherRepository serves class-scoped queries for wali kelas
+type TeacherRepository struct {
+	db *gorm.DB
+}
+
+func NewTeacherRepository(db *gorm.DB) *TeacherRepository {
+	return &TeacherRepository{db: db}
+}
+
+// ListClasses returns every kelas the teacher is wali kelas of, newest academic year first
+func (r *TeacherRepository) ListClasses(teacherID uuid.UUID) ([]domain.Kelas, error) {
+	var kelas []domain.Kelas
+	err := r.db.Model(&domain.Kelas{}).
+		Joins("JOIN tahun_ajaran ON kelas.tahun_ajaran_id = tahun_ajaran.id").
+		Where("kelas.wali_kelas_id = ? AND kelas.deleted_at IS NULL", teacherID).
+		Preload("TahunAjaran").Preload("Jurusan").
+		Order("tahun_ajaran.tahun_mulai DESC, kelas.nama ASC").
+		Find(&kelas).Error
+	return kelas, err
+}
+
+// IsWaliKelas reports whether the teacher is wali kelas of the kelas
+func (r *TeacherRepository) IsWaliKelas(teacherID, kelasID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.Kelas{}).
+		Where("id = ? AND wali_kelas_id = ? AND deleted_at IS NULL", kelasID, teacherID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// IsStudentOfTeacher reports whether the student currently sits in one of the teacher's classes
+func (r *TeacherRepository) IsStudentOfTeacher(teacherID, studentID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.User{}).
+		Joins("JOIN kelas ON users.kelas_id = kelas.id").
+		Where("users.id = ? AND users.deleted_at IS NULL", studentID).
+		Where("kelas.wali_kelas_id = ? AND kelas.deleted_at IS NULL", teacherID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ListStudents returns the students currently in the kelas
+func (r *TeacherRepository) ListStudents(kelasID uuid.UUID) ([]domain.User, error) {
+	var users []domain.User
+	err := r.db.Model(&domain.User{}).
+		Where("kelas_id = ? AND role = ? AND deleted_at IS NULL", kelasID, domain.RoleStudent).
+		Order("nama ASC").
+		Find(&users).Error
+	return users, err
+}
+
+// GetStudentPortfolioCounts returns portfolio counts per status for each student in the kelas
+func (r *TeacherRepository) GetStudentPortfolioCounts(kelasID uuid.UUID) (map[uuid.UUID]map[string]int64, error) {
+	var rows []struct {
+		UserID uuid.UUID
+		Status string
+		Count  int64
+	}
+	err := r.db.Model(&domain.Portfolio{}).
+		Select("portfolios.user_id, portfolios.status, COUNT(*) AS count").
+		Joins("JOIN users ON portfolios.user_id = users.id").
+		Where("users.kelas_id = ? AND users.deleted_at IS NULL AND portfolios.deleted_at IS NULL", kelasID).
+		Group("portfolios.user_id, portfolios.status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]map[string]int64)
+	for _, row := range rows {
+		if counts[row.UserID] == nil {
+			counts[row.UserID] = make(map[string]int64)
+		}
+		counts[row.UserID][row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// ListClassPortfolios returns portfolios in any status from students currently in the kelas
+func (r *TeacherRepository) ListClassPortfolios(kelasID uuid.UUID, status *string, search string, page, limit int) ([]domain.Portfolio, int64, error) {
+	var portfolios []domain.Portfolio
+	var total int64
+
+	query := r.db.Model(&domain.Portfolio{}).
+		Joins("JOIN users ON portfolios.user_id = users.id").
+		Where("users.kelas_id = ? AND users.deleted_at IS NULL AND portfolios.deleted_at IS NULL", kelasID)
+
+	if status != nil {
+		query = query.Where("portfolios.status = ?", *status)
+	}
+	if search != "" {
+		query = query.Where("portfolios.judul ILIKE ? OR users.nama ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.Preload("User.Kelas.Jurusan").
+		Offset(offset).Limit(limit).
+		Order("portfolios.updated_at DESC").
+		Find(&portfolios).Error
+
+	return portfolios, total, err
+}
+
+// ListClassAssessments returns published portfolios of the kelas with their assessment, if any
+func (r *TeacherRepository) ListClassAssessments(kelasID uuid.UUID, page, limit int) ([]PortfolioWithAssessment, int64, error) {
+	var total int64
+
+	query := r.db.Model(&domain.Portfolio{}).
+		Joins("JOIN users ON portfolios.user_id = users.id").
+		Where("users.kelas_id = ? AND users.deleted_at IS NULL", kelasID).
+		Where("portfolios.status = ? AND portfolios.deleted_at IS NULL", domain.StatusPublished)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var portfolios []domain.Portfolio
+	offset := (page - 1) * limit
+	err := query.Preload("User").
+		Order("users.nama ASC, portfolios.published_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&portfolios).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	portfolioIDs := make([]uuid.UUID, len(portfolios))
+	for i, p := range portfolios {
+		portfolioIDs[i] = p.ID
+	}
+
+	var assessments []domain.PortfolioAssessment
+	if len(portfolioIDs) > 0 {
+		r.db.Preload("Assessor").
+			Where("portfolio_id IN ?", portfolioIDs).
+			Find(&assessments)
+	}
+
+	assessmentMap := make(map[uuid.UUID]*domain.PortfolioAssessment)
+	for i := range assessments {
+		assessmentMap[assessments[i].PortfolioID] = &assessments[i]
+	}
+
+	results := make([]PortfolioWithAssessment, 0, len(portfolios))
+	for _, p := range portfolios {
+		results = append(results, PortfolioWithAssessment{
+			Portfolio:  p,
+			Assessment: assessmentMap[p.ID],
+		})
+	}
+
+	return results, total, nil
+}
+
+// GetClassAssessmentSummary returns how many published portfolios of the kelas are assessed and their average score
+func (r *TeacherRepository) GetClassAssessmentSummary(kelasID uuid.UUID) (published, assessed int64, averageScore *float64, err error) {
+	var row struct {
+		Published    int64
+		Assessed     int64
+		AverageScore *float64
+	}
+	err = r.db.Model(&domain.Portfolio{}).
+		Select("COUNT(*) AS published, COUNT(pa.id) AS assessed, AVG(pa.total_score) AS average_score").
+		Joins("JOIN users ON portfolios.user_id = users.id").
+		Joins("LEFT JOIN portfolio_assessments pa ON pa.portfolio_id = portfolios.id").
+		Where("users.kelas_id = ? AND users.deleted_at IS NULL", kelasID).
+		Where("portfolios.status = ? AND portfolios.deleted_at IS NULL", domain.StatusPublished).
+		Scan(&row).Error
+	return row.Published, row.Assessed, row.AverageScore, err
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"gorm.io/gorm"
+)
+
+type TextFilterRepository struct {
+	db *gorm.DB
+}
+
+func NewTextFilterRepository(db *gorm.DB) *TextFilterRepository {
+	return &TextFilterRepository{db: db}
+}
+
+// List returns the filter rules, optionally narrowed to one action or language, newest first
+func (r *TextFilterRepository) List(action, bahasa string) ([]domain.TextFilterRule, error) {
+	var rules []domain.TextFilterRule
+	query := r.db.Model(&domain.TextFilterRule{})
+	if action != "" {
+		query = query.Where("action = ?", action)
+	}
+	if bahasa != "" {
+		query = query.Where("bahasa = ?", bahasa)
+	}
+	err := query.Order("created_at DESC").Find(&rules).Error
+	return rules, err
+}
+
+// ListActive returns the rules the filter applies
+func (r *TextFilterRepository) ListActive() ([]domain.TextFilterRule, error) {
+	var rules []domain.TextFilterRule
+	err := r.db.Where("is_active = ?", true).Order("created_at ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (r *TextFilterRepository) FindByID(id uuid.UUID) (*domain.TextFilterRule, error) {
+	var rule domain.TextFilterRule
+	if err := r.db.Where("id = ?", id).First(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *TextFilterRepository) Create(rule *domain.TextFilterRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *TextFilterRepository) Update(rule *domain.TextFilterRule) error {
+	return r.db.Save(rule).Error
+}
+
+func (r *TextFilterRepository) Delete(id uuid.UUID) error {
+	return r.db.Where("id = ?", id).Delete(&domain.TextFilterRule{}).Error
+}
@@ -61,20 +61,61 @@ func (r *UserRepository) Update(user *domain.User) error {
 	return r.db.Save(user).Error
 }
 
+// UpdateFields updates only the given columns of a user
+func (r *UserRepository) UpdateFields(id uuid.UUID, fields map[string]interface{}) error {
+	return r.db.Model(&domain.User{}).Where("id = ?", id).Updates(fields).Error
+}
+
 func (r *UserRepository) Delete(id uuid.UUID) error {
 	return r.db.Where("id = ?", id).Delete(&domain.User{}).Error
 }
 
+// UsernameExists reports whether username is taken, either as someone's current username or
+// as one they had before, which stays reserved so old profile links cannot be taken over
 func (r *UserRepository) UsernameExists(username string, excludeID *uuid.UUID) (bool, error) {
 	var count int64
 	query := r.db.Model(&domain.User{}).Where("username = ? AND deleted_at IS NULL", username)
 	if excludeID != nil {
 		query = query.Where("id != ?", *excludeID)
 	}
-	err := query.Count(&count).Error
+	if err := query.Count(&count).Error; err != nil || count > 0 {
+		return count > 0, err
+	}
+
+	previous := r.db.Model(&domain.UsernameHistory{}).Where("username = ?", username)
+	if excludeID != nil {
+		previous = previous.Where("user_id != ?", *excludeID)
+	}
+	err := previous.Count(&count).Error
 	return count > 0, err
 }
 
+// FindByPreviousUsername finds the user who went by username before renaming
+func (r *UserRepository) FindByPreviousUsername(username string) (*domain.User, error) {
+	var user domain.User
+	err := r.db.Preload("Kelas.Jurusan").Preload("SocialLinks").
+		Where("id IN (SELECT user_id FROM username_history WHERE username = ?) AND deleted_at IS NULL", username).
+		First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ChangeUsername saves the user under a new username and keeps previous in the history.
+// Taking back an old username removes it from the history.
+func (r *UserRepository) ChangeUsername(user *domain.User, previous string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(user).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("username IN ?", []string{user.Username, previous}).Delete(&domain.UsernameHistory{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&domain.UsernameHistory{UserID: user.ID, Username: previous}).Error
+	})
+}
+
 func (r *UserRepository) EmailExists(email string, excludeID *uuid.UUID) (bool, error) {
 	var count int64
 	query := r.db.Model(&domain.User{}).Where("email = ? AND deleted_at IS NULL", email)
@@ -140,10 +181,13 @@ func (r *UserRepository) GetFollowingCount(userID uuid.UUID) (int64, error) {
 	return count, err
 }
 
-func (r *UserRepository) GetPublishedPortfolioCount(userID uuid.UUID) (int64, error) {
+// GetPublishedPortfolioCount counts the published portfolios of a profile that viewer may see
+func (r *UserRepository) GetPublishedPortfolioCount(viewer Viewer, userID uuid.UUID) (int64, error) {
 	var count int64
+	visibility, visibilityArgs := visibilityCondition("portfolios", viewer, true)
 	err := r.db.Model(&domain.Portfolio{}).
-		Where("user_id = ? AND status = 'published' AND deleted_at IS NULL", userID).
+		Where(authoredByCondition+" AND portfolios.status = 'published' AND portfolios.deleted_at IS NULL", userID, userID).
+		Where(visibility, visibilityArgs...).
 		Count(&count).Error
 	return count, err
 }
@@ -160,7 +204,7 @@ func (r *UserRepository) GetClassHistory(userID uuid.UUID) ([]domain.StudentClas
 	var history []domain.StudentClassHistory
 	err := r.db.Preload("Kelas").Preload("TahunAjaran").
 		Where("user_id = ?", userID).
-		Order("created_at ASC").
+		Order("effective_date ASC, created_at ASC").
 		Find(&history).Error
 	return history, err
 }
@@ -209,12 +253,16 @@ type TopStudentResult struct {
 	Score              float64   `json:"score"`
 }
 
-// GetTopStudents returns top students based on portfolio count, likes, assessment scores, and followers
+// GetTopStudents returns top students based on portfolio count, likes, assessment scores, and followers.
+// Portfolios a student co-authors count the same as their own.
 func (r *UserRepository) GetTopStudents(limit int) ([]TopStudentResult, error) {
 	var results []TopStudentResult
 
 	query := `
-		WITH student_stats AS (
+		WITH co_authored AS (
+			SELECT portfolio_id, user_id FROM portfolio_collaborators WHERE status = 'accepted'
+		),
+		student_stats AS (
 			SELECT 
 				u.id,
 				u.username,
@@ -227,18 +275,21 @@ func (r *UserRepository) GetTopStudents(limit int) ([]TopStudentResult, error) {
 				COALESCE((
 					SELECT COUNT(*) FROM portfolio_likes pl 
 					JOIN portfolios pp ON pl.portfolio_id = pp.id 
-					WHERE pp.user_id = u.id AND pp.status = 'published' AND pp.deleted_at IS NULL
+					WHERE (pp.user_id = u.id OR pp.id IN (SELECT ca.portfolio_id FROM co_authored ca WHERE ca.user_id = u.id))
+						AND pp.status = 'published' AND pp.deleted_at IS NULL
 				), 0) as total_likes,
 				COALESCE((
 					SELECT AVG(pa.total_score) FROM portfolio_assessments pa 
 					JOIN portfolios pp ON pa.portfolio_id = pp.id 
-					WHERE pp.user_id = u.id AND pp.status = 'published' AND pp.deleted_at IS NULL AND pa.total_score IS NOT NULL
+					WHERE (pp.user_id = u.id OR pp.id IN (SELECT ca.portfolio_id FROM co_authored ca WHERE ca.user_id = u.id))
+						AND pp.status = 'published' AND pp.deleted_at IS NULL AND pa.total_score IS NOT NULL
 				), 0) as avg_assessment_score,
 				(SELECT COUNT(*) FROM follows f WHERE f.following_id = u.id) as follower_count
 			FROM users u
 			LEFT JOIN kelas k ON u.kelas_id = k.id AND k.deleted_at IS NULL
 			LEFT JOIN jurusan j ON k.jurusan_id = j.id AND j.deleted_at IS NULL
-			LEFT JOIN portfolios p ON p.user_id = u.id AND p.status = 'published' AND p.deleted_at IS NULL
+			LEFT JOIN portfolios p ON (p.user_id = u.id OR p.id IN (SELECT ca.portfolio_id FROM co_authored ca WHERE ca.user_id = u.id))
+				AND p.status = 'published' AND p.deleted_at IS NULL
 			WHERE u.role = 'student'
 				AND u.is_active = true
 				AND u.deleted_at IS NULL
@@ -295,9 +346,10 @@ type TopProjectResult struct {
 	Score           float64   `json:"score"`
 }
 
-// GetTopProjects returns top projects based on assessment scores, likes, and recency
-func (r *UserRepository) GetTopProjects(limit int) ([]TopProjectResult, error) {
+// GetTopProjects returns top projects visible to viewer based on assessment scores, likes, and recency
+func (r *UserRepository) GetTopProjects(viewer Viewer, limit int) ([]TopProjectResult, error) {
 	var results []TopProjectResult
+	visibility, visibilityArgs := visibilityCondition("p", viewer, true)
 
 	query := `
 		WITH project_stats AS (
@@ -327,6 +379,7 @@ func (r *UserRepository) GetTopProjects(limit int) ([]TopProjectResult, error) {
 				AND u.role = 'student'
 				AND u.is_active = true
 				AND u.deleted_at IS NULL
+				AND ` + visibility + `
 		),
 		max_values AS (
 			SELECT GREATEST(MAX(like_count), 1) as max_likes FROM project_stats
@@ -353,6 +406,6 @@ func (r *UserRepository) GetTopProjects(limit int) ([]TopProjectResult, error) {
 		LIMIT ?
 	`
 
-	err := r.db.Raw(query, limit).Scan(&results).Error
+	err := r.db.Raw(query, append(visibilityArgs, limit)...).Scan(&results).Error
 	return results, err
 }
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+)
+
+// payloadFieldKind is the JSON shape a payload field must have
+type payloadFieldKind int
+
+const (
+	fieldString payloadFieldKind = iota
+	fieldURL
+	fieldStringList
+	fieldStringTable
+	fieldObjectList
+)
+
+// maxPayloadText bounds a single string field of a payload
+const maxPayloadText = 100_000
+
+// payloadField describes one field of a block payload
+type payloadField struct {
+	Name     string
+	Kind     payloadFieldKind
+	Required bool
+	// Check validates the field further once its kind is right; it returns a message or ""
+	Check func(value interface{}) string
+	// Items and MaxItems describe each element of a fieldObjectList
+	Items    []payloadField
+	MaxItems int
+}
+
+var (
+	youtubeIDPattern    = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	codeLanguagePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9+#.-]{0,29}$`)
+)
+
+// maxGalleryImages bounds the images of one gallery block
+const maxGalleryImages = 30
+
+// blockPayloadSchemas is the payload definition of every content block type.
+// Fields not listed are left alone so clients can keep presentation hints on a block.
+var blockPayloadSchemas = map[domain.ContentBlockType][]payloadField{
+	domain.BlockText: {
+		{Name: "content", Kind: fieldString, Required: true},
+	},
+	domain.BlockImage: {
+		{Name: "url", Kind: fieldURL, Required: true},
+		{Name: "caption", Kind: fieldString},
+	},
+	domain.BlockTable: {
+		{Name: "headers", Kind: fieldStringList, Required: true},
+		{Name: "rows", Kind: fieldStringTable},
+	},
+	domain.BlockYoutube: {
+		{Name: "video_id", Kind: fieldString, Required: true, Check: func(v interface{}) string {
+			if !youtubeIDPattern.MatchString(v.(string)) {
+				return "Video ID YouTube harus 11 karakter huruf, angka, - atau _"
+			}
+			return ""
+		}},
+		{Name: "title", Kind: fieldString},
+	},
+	domain.BlockButton: {
+		{Name: "text", Kind: fieldString, Required: true},
+		{Name: "url", Kind: fieldURL, Required: true},
+	},
+	domain.BlockEmbed: {
+		{Name: "html", Kind: fieldString, Required: true},
+		{Name: "title", Kind: fieldString},
+	},
+	domain.BlockFigma: {
+		{Name: "url", Kind: fieldURL, Required: true, Check: hostCheck("figma.com", "Figma")},
+		{Name: "title", Kind: fieldString},
+	},
+	domain.BlockCanva: {
+		{Name: "url", Kind: fieldURL, Required: true, Check: hostCheck("canva.com", "Canva")},
+		{Name: "title", Kind: fieldString},
+	},
+	domain.BlockPPT: documentPayload,
+	domain.BlockPDF: documentPayload,
+	domain.BlockDoc: documentPayload,
+	domain.BlockCode: {
+		{Name: "code", Kind: fieldString, Required: true},
+		{Name: "language", Kind: fieldString, Check: func(v interface{}) string {
+			if lang := v.(string); lang != "" && !codeLanguagePattern.MatchString(lang) {
+				return "Bahasa harus berupa nama pendek huruf kecil, misalnya go atau typescript"
+			}
+			return ""
+		}},
+		{Name: "filename", Kind: fieldString},
+	},
+	domain.BlockGallery: {
+		{Name: "images", Kind: fieldObjectList, Required: true, MaxItems: maxGalleryImages, Items: []payloadField{
+			{Name: "url", Kind: fieldURL, Required: true},
+			{Name: "caption", Kind: fieldString},
+		}},
+	},
+	domain.BlockAudio: {
+		{Name: "url", Kind: fieldURL, Required: true},
+		{Name: "title", Kind: fieldString},
+	},
+	domain.BlockModel3D: {
+		{Name: "url", Kind: fieldURL, Required: true, Check: func(v interface{}) string {
+			u, err := url.Parse(v.(string))
+			if err != nil {
+				return "URL tidak valid"
+			}
+			if ext := strings.ToLower(path.Ext(u.Path)); ext != ".glb" && ext != ".gltf" {
+				return "Model 3D harus berupa file .glb atau .gltf"
+			}
+			return ""
+		}},
+		{Name: "title", Kind: fieldString},
+		{Name: "poster_url", Kind: fieldURL},
+	},
+	domain.BlockMarkdown: {
+		{Name: "content", Kind: fieldString, Required: true},
+	},
+}
+
+// documentPayload is shared by the uploaded document blocks
+var documentPayload = []payloadField{
+	{Name: "url", Kind: fieldURL, Required: true},
+	{Name: "filename", Kind: fieldString},
+	{Name: "title", Kind: fieldString},
+}
+
+// IsValidBlockType reports whether t is a known content block type
+func IsValidBlockType(t domain.ContentBlockType) bool {
+	_, ok := blockPayloadSchemas[t]
+	return ok
+}
+
+// ValidateBlockPayload checks payload against the definition of blockType and returns one
+// detail per problem, with fields named "payload.<field>" or "payload.<field>.<index>"
+func ValidateBlockPayload(blockType domain.ContentBlockType, payload domain.JSONB) []dto.ErrorDetail {
+	schema, ok := blockPayloadSchemas[blockType]
+	if !ok {
+		return []dto.ErrorDetail{{Field: "block_type", Message: fmt.Sprintf("Tipe block %q tidak dikenal", blockType)}}
+	}
+
+	details := validateFields("payload", schema, payload)
+	if blockType == domain.BlockTable && len(details) == 0 {
+		details = append(details, checkTableShape(payload)...)
+	}
+	return details
+}
+
+// validateFields checks obj against schema, naming fields "<prefix>.<field>"
+func validateFields(prefix string, schema []payloadField, obj map[string]interface{}) []dto.ErrorDetail {
+	var details []dto.ErrorDetail
+	for _, f := range schema {
+		field := prefix + "." + f.Name
+		value, present := obj[f.Name]
+		if !present || value == nil {
+			if f.Required {
+				details = append(details, dto.ErrorDetail{Field: field, Message: "Wajib diisi"})
+			}
+			continue
+		}
+
+		fieldDetails := checkPayloadKind(field, f, value)
+		if len(fieldDetails) == 0 && f.Check != nil {
+			if msg := f.Check(value); msg != "" {
+				fieldDetails = append(fieldDetails, dto.ErrorDetail{Field: field, Message: msg})
+			}
+		}
+		details = append(details, fieldDetails...)
+	}
+	return details
+}
+
+func checkPayloadKind(field string, f payloadField, value interface{}) []dto.ErrorDetail {
+	switch f.Kind {
+	case fieldString, fieldURL:
+		s, ok := value.(string)
+		if !ok {
+			return []dto.ErrorDetail{{Field: field, Message: "Harus berupa teks"}}
+		}
+		if f.Required && strings.TrimSpace(s) == "" {
+			return []dto.ErrorDetail{{Field: field, Message: "Wajib diisi"}}
+		}
+		if len(s) > maxPayloadText {
+			return []dto.ErrorDetail{{Field: field, Message: fmt.Sprintf("Maksimal %d karakter", maxPayloadText)}}
+		}
+		if f.Kind == fieldURL && s != "" && !isHTTPURL(s) {
+			return []dto.ErrorDetail{{Field: field, Message: "Harus berupa URL http atau https"}}
+		}
+	case fieldStringList:
+		items, ok := value.([]interface{})
+		if !ok {
+			return []dto.ErrorDetail{{Field: field, Message: "Harus berupa daftar teks"}}
+		}
+		if f.Required && len(items) == 0 {
+			return []dto.ErrorDetail{{Field: field, Message: "Minimal 1 item"}}
+		}
+		var details []dto.ErrorDetail
+		for i, item := range items {
+			if _, ok := item.(string); !ok {
+				details = append(details, dto.ErrorDetail{Field: fmt.Sprintf("%s.%d", field, i), Message: "Harus berupa teks"})
+			}
+		}
+		return details
+	case fieldStringTable:
+		rows, ok := value.([]interface{})
+		if !ok {
+			return []dto.ErrorDetail{{Field: field, Message: "Harus berupa daftar baris"}}
+		}
+		var details []dto.ErrorDetail
+		for i, row := range rows {
+			cells, ok := row.([]interface{})
+			if !ok {
+				details = append(details, dto.ErrorDetail{Field: fmt.Sprintf("%s.%d", field, i), Message: "Baris harus berupa daftar teks"})
+				continue
+			}
+			for _, cell := range cells {
+				if _, ok := cell.(string); !ok {
+					details = append(details, dto.ErrorDetail{Field: fmt.Sprintf("%s.%d", field, i), Message: "Sel tabel harus berupa teks"})
+					break
+				}
+			}
+		}
+		return details
+	case fieldObjectList:
+		items, ok := value.([]interface{})
+		if !ok {
+			return []dto.ErrorDetail{{Field: field, Message: "Harus berupa daftar objek"}}
+		}
+		if f.Required && len(items) == 0 {
+			return []dto.ErrorDetail{{Field: field, Message: "Minimal 1 item"}}
+		}
+		if f.MaxItems > 0 && len(items) > f.MaxItems {
+			return []dto.ErrorDetail{{Field: field, Message: fmt.Sprintf("Maksimal %d item", f.MaxItems)}}
+		}
+		var details []dto.ErrorDetail
+		for i, item := range items {
+			obj, ok := item.(map[string]interface{})
+			if !ok {
+				details = append(details, dto.ErrorDetail{Field: fmt.Sprintf("%s.%d", field, i), Message: "Harus berupa objek"})
+				continue
+			}
+			details = append(details, validateFields(fmt.Sprintf("%s.%d", field, i), f.Items, obj)...)
+		}
+		return details
+	}
+	return nil
+}
+
+// checkTableShape requires every row of a well-typed table to have one cell per header
+func checkTableShape(payload domain.JSONB) []dto.ErrorDetail {
+	headers := payload["headers"].([]interface{})
+	rows, _ := payload["rows"].([]interface{})
+
+	var details []dto.ErrorDetail
+	for i, row := range rows {
+		if cells := row.([]interface{}); len(cells) != len(headers) {
+			details = append(details, dto.ErrorDetail{
+				Field:   fmt.Sprintf("payload.rows.%d", i),
+				Message: fmt.Sprintf("Jumlah sel harus %d sesuai jumlah header", len(headers)),
+			})
+		}
+	}
+	return details
+}
+
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// hostCheck accepts URLs on domain or one of its subdomains
+func hostCheck(domainName, label string) func(interface{}) string {
+	return func(v interface{}) string {
+		u, err := url.Parse(v.(string))
+		if err != nil {
+			return "URL tidak valid"
+		}
+		host := strings.ToLower(u.Hostname())
+		if host != domainName && !strings.HasSuffix(host, "."+domainName) {
+			return "URL harus mengarah ke " + label
+		}
+		return ""
+	}
+}
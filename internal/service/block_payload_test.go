@@ -0,0 +1,56 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBlockPayload(t *testing.T) {
+	assert.Empty(t, ValidateBlockPayload(domain.BlockText, domain.JSONB{"content": "<p>Halo</p>"}))
+	assert.Empty(t, ValidateBlockPayload(domain.BlockImage, domain.JSONB{"url": "https://cdn.example/a.png", "alt": "bebas"}))
+	assert.Empty(t, ValidateBlockPayload(domain.BlockFigma, domain.JSONB{"url": "https://www.figma.com/file/abc"}))
+
+	assert.Equal(t, []string{"payload.content"}, detailFields(ValidateBlockPayload(domain.BlockText, domain.JSONB{"content": "   "})))
+	assert.Equal(t, []string{"payload.url"}, detailFields(ValidateBlockPayload(domain.BlockImage, domain.JSONB{"url": "javascript:alert(1)"})))
+	assert.Equal(t, []string{"payload.video_id"}, detailFields(ValidateBlockPayload(domain.BlockYoutube, domain.JSONB{"video_id": "https://youtu.be/x"})))
+	assert.Equal(t, []string{"payload.text", "payload.url"}, detailFields(ValidateBlockPayload(domain.BlockButton, domain.JSONB{"text": 5})))
+	assert.Equal(t, []string{"payload.url"}, detailFields(ValidateBlockPayload(domain.BlockCanva, domain.JSONB{"url": "https://notcanva.com/design"})))
+	assert.Equal(t, []string{"block_type"}, detailFields(ValidateBlockPayload("hologram", domain.JSONB{})))
+
+	table := domain.JSONB{
+		"headers": []interface{}{"Fitur", "Deskripsi"},
+		"rows":    []interface{}{[]interface{}{"Login", "Autentikasi"}, []interface{}{"Dashboard"}},
+	}
+	assert.Equal(t, []string{"payload.rows.1"}, detailFields(ValidateBlockPayload(domain.BlockTable, table)))
+	table["rows"] = []interface{}{[]interface{}{"Login", 1}}
+	assert.Equal(t, []string{"payload.rows.0"}, detailFields(ValidateBlockPayload(domain.BlockTable, table)))
+}
+
+func TestValidateBlockPayload_CodeAndMediaBlocks(t *testing.T) {
+	assert.Empty(t, ValidateBlockPayload(domain.BlockCode, domain.JSONB{"code": "fmt.Println(1)", "language": "go", "filename": "main.go"}))
+	assert.Equal(t, []string{"payload.language"}, detailFields(ValidateBlockPayload(domain.BlockCode, domain.JSONB{"code": "x", "language": "Go Lang"})))
+	assert.Empty(t, ValidateBlockPayload(domain.BlockMarkdown, domain.JSONB{"content": "# Judul"}))
+
+	gallery := domain.JSONB{"images": []interface{}{
+		map[string]interface{}{"url": "https://cdn.example/a.png", "caption": "Depan"},
+		map[string]interface{}{"caption": "Belakang"},
+		"https://cdn.example/c.png",
+	}}
+	assert.Equal(t, []string{"payload.images.1.url", "payload.images.2"}, detailFields(ValidateBlockPayload(domain.BlockGallery, gallery)))
+	assert.Equal(t, []string{"payload.images"}, detailFields(ValidateBlockPayload(domain.BlockGallery, domain.JSONB{"images": []interface{}{}})))
+
+	assert.Empty(t, ValidateBlockPayload(domain.BlockAudio, domain.JSONB{"url": "https://cdn.example/lagu.mp3"}))
+	assert.Empty(t, ValidateBlockPayload(domain.BlockModel3D, domain.JSONB{"url": "https://cdn.example/models/kursi.GLB?v=2"}))
+	assert.Equal(t, []string{"payload.url"}, detailFields(ValidateBlockPayload(domain.BlockModel3D, domain.JSONB{"url": "https://cdn.example/kursi.obj"})))
+}
+
+func detailFields(details []dto.ErrorDetail) []string {
+	var result []string
+	for _, d := range details {
+		result = append(result, d.Field)
+	}
+	return result
+}
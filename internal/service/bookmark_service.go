@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/repository"
+)
+
+const (
+	// MaxBookmarkCollections bounds the collections one user can keep
+	MaxBookmarkCollections = 50
+	// MaxBookmarkItems bounds the portfolios in one collection
+	MaxBookmarkItems = 500
+)
+
+var (
+	ErrCollectionLimit   = errors.New("too many bookmark collections")
+	ErrCollectionFull    = errors.New("bookmark collection is full")
+	ErrAlreadyBookmarked = errors.New("portfolio is already in the collection")
+	ErrNotBookmarkable   = errors.New("portfolio cannot be bookmarked")
+)
+
+// BookmarkService keeps users' bookmark collections of published portfolios
+type BookmarkService struct {
+	repo          *repository.BookmarkRepository
+	portfolioRepo *repository.PortfolioRepository
+}
+
+func NewBookmarkService(repo *repository.BookmarkRepository, portfolioRepo *repository.PortfolioRepository) *BookmarkService {
+	return &BookmarkService{repo: repo, portfolioRepo: portfolioRepo}
+}
+
+// CreateCollection starts an empty collection for userID
+func (s *BookmarkService) CreateCollection(userID uuid.UUID, nama string, deskripsi *string, isPublic bool) (*domain.BookmarkCollection, error) {
+	count, err := s.repo.CountCollections(userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= MaxBookmarkCollections {
+		return nil, ErrCollectionLimit
+	}
+
+	collection := &domain.BookmarkCollection{
+		UserID:    userID,
+		Nama:      strings.TrimSpace(nama),
+		Deskripsi: deskripsi,
+		IsPublic:  isPublic,
+	}
+	if err := s.repo.CreateCollection(collection); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// AddItem puts a portfolio at the end of the collection. Only published portfolios the owner
+// can open may be bookmarked.
+func (s *BookmarkService) AddItem(collection *domain.BookmarkCollection, portfolioID uuid.UUID, viewer repository.Viewer) (*domain.BookmarkItem, error) {
+	visible, err := s.portfolioRepo.IsVisibleTo(portfolioID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, ErrNotBookmarkable
+	}
+
+	exists, err := s.repo.HasItem(collection.ID, portfolioID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrAlreadyBookmarked
+	}
+
+	counts, err := s.repo.CountItems([]uuid.UUID{collection.ID})
+	if err != nil {
+		return nil, err
+	}
+	if counts[collection.ID] >= MaxBookmarkItems {
+		return nil, ErrCollectionFull
+	}
+
+	item := &domain.BookmarkItem{CollectionID: collection.ID, PortfolioID: portfolioID}
+	if err := s.repo.AddItem(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCollaboratorIsOwner     = errors.New("portfolio owner cannot be invited")
+	ErrCollaboratorExists      = errors.New("user is already invited")
+	ErrInvitationNotPending    = errors.New("invitation was already answered")
+	ErrInvitationNotForUser    = errors.New("invitation belongs to another user")
+	ErrInvalidCollaboratorRole = errors.New("invalid collaborator role")
+)
+
+// CollaborationService manages the co-authors of team portfolios. The owner stays
+// Portfolio.UserID; everyone else joins through an invitation answered from a notification.
+type CollaborationService struct {
+	collabRepo   *repository.CollaboratorRepository
+	userRepo     *repository.UserRepository
+	notifService *NotificationService
+}
+
+func NewCollaborationService(collabRepo *repository.CollaboratorRepository, userRepo *repository.UserRepository, notifService *NotificationService) *CollaborationService {
+	return &CollaborationService{collabRepo: collabRepo, userRepo: userRepo, notifService: notifService}
+}
+
+// IsValidInviteRole reports whether role can be given to an invited co-author
+func IsValidInviteRole(role domain.CollaboratorRole) bool {
+	return role == domain.CollaboratorEditor || role == domain.CollaboratorContributor
+}
+
+// CanEdit reports whether userID may edit the content of portfolio: its owner or an accepted editor
+func (s *CollaborationService) CanEdit(portfolio *domain.Portfolio, userID *uuid.UUID) bool {
+	if userID == nil {
+		return false
+	}
+	if *userID == portfolio.UserID {
+		return true
+	}
+	isEditor, _ := s.collabRepo.IsEditor(portfolio.ID, *userID)
+	return isEditor
+}
+
+// List returns the collaborators of a portfolio; only accepted ones unless includePending is set
+func (s *CollaborationService) List(portfolioID uuid.UUID, includePending bool) ([]domain.PortfolioCollaborator, error) {
+	return s.collabRepo.ListByPortfolio(portfolioID, !includePending)
+}
+
+// Invite creates a pending invitation and notifies the invitee. A declined invitation is reopened.
+func (s *CollaborationService) Invite(portfolio *domain.Portfolio, inviter *domain.User, inviteeID uuid.UUID, role domain.CollaboratorRole) (*domain.PortfolioCollaborator, error) {
+	if !IsValidInviteRole(role) {
+		return nil, ErrInvalidCollaboratorRole
+	}
+	if inviteeID == portfolio.UserID {
+		return nil, ErrCollaboratorIsOwner
+	}
+
+	collaborator, err := s.collabRepo.FindByPortfolioAndUser(portfolio.ID, inviteeID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		collaborator = &domain.PortfolioCollaborator{
+			PortfolioID: portfolio.ID,
+			UserID:      inviteeID,
+			Role:        role,
+			Status:      domain.CollaboratorPending,
+			InvitedBy:   &inviter.ID,
+		}
+		if err := s.collabRepo.Create(collaborator); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	case collaborator.Status != domain.CollaboratorDeclined:
+		return nil, ErrCollaboratorExists
+	default:
+		collaborator.Role = role
+		collaborator.InvitedBy = &inviter.ID
+		if err := s.collabRepo.Reinvite(collaborator); err != nil {
+			return nil, err
+		}
+		collaborator.Status = domain.CollaboratorPending
+		collaborator.RespondedAt = nil
+	}
+
+	if err := s.notifService.NotifyCollaboratorInvite(inviter, portfolio, collaborator); err != nil {
+		log.Printf("[COLLAB] Failed to notify invitation %s: %v", collaborator.ID, err)
+	}
+	return collaborator, nil
+}
+
+// Respond accepts or declines an invitation on behalf of the invitee and tells the owner
+func (s *CollaborationService) Respond(collaboratorID, userID uuid.UUID, accept bool) (*domain.PortfolioCollaborator, error) {
+	collaborator, err := s.collabRepo.FindByID(collaboratorID)
+	if err != nil {
+		return nil, err
+	}
+	if collaborator.UserID != userID {
+		return nil, ErrInvitationNotForUser
+	}
+
+	status := domain.CollaboratorDeclined
+	if accept {
+		status = domain.CollaboratorAccepted
+	}
+	now := time.Now()
+	updated, err := s.collabRepo.Respond(collaborator.ID, status, now)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrInvitationNotPending
+	}
+	collaborator.Status = status
+	collaborator.RespondedAt = &now
+
+	if invitee, err := s.userRepo.FindByID(userID); err == nil && collaborator.Portfolio != nil {
+		if err := s.notifService.NotifyCollaboratorResponded(invitee, collaborator.Portfolio, collaborator); err != nil {
+			log.Printf("[COLLAB] Failed to notify response %s: %v", collaborator.ID, err)
+		}
+	}
+	return collaborator, nil
+}
+
+// UpdateRole switches a co-author between editor and contributor
+func (s *CollaborationService) UpdateRole(collaborator *domain.PortfolioCollaborator, role domain.CollaboratorRole) error {
+	if !IsValidInviteRole(role) {
+		return ErrInvalidCollaboratorRole
+	}
+	if err := s.collabRepo.UpdateRole(collaborator.ID, role); err != nil {
+		return err
+	}
+	collaborator.Role = role
+	return nil
+}
+
+// Remove drops a co-author or withdraws an invitation
+func (s *CollaborationService) Remove(collaboratorID uuid.UUID) error {
+	return s.collabRepo.Delete(collaboratorID)
+}
+
+// RolesByPortfolioIDs returns the co-author role of userID on each portfolio in ids
+func (s *CollaborationService) RolesByPortfolioIDs(userID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]domain.CollaboratorRole, error) {
+	return s.collabRepo.RolesByPortfolioIDs(userID, ids)
+}
@@ -9,11 +9,15 @@ import (
 	"github.com/grafikarsa/backend/internal/repository"
 )
 
+// HiddenCommentPlaceholder replaces the content of a comment hidden by a moderator
+const HiddenCommentPlaceholder = "Komentar ini disembunyikan oleh moderator"
+
 type CommentService struct {
 	commentRepo         *repository.CommentRepository
 	userRepo            *repository.UserRepository
 	portfolioRepo       *repository.PortfolioRepository
 	notificationService *NotificationService
+	textFilter          *TextFilterService
 }
 
 func NewCommentService(
@@ -33,6 +37,11 @@ func NewCommentService(
 	}
 }
 
+// SetTextFilter enables screening of comment text for banned terms
+func (s *CommentService) SetTextFilter(f *TextFilterService) {
+	s.textFilter = f
+}
+
 // SetNotificationService - safer way to inject circular prod dependency if needed
 func (s *CommentService) SetNotificationService(ns *NotificationService) {
 	s.notificationService = ns
@@ -44,16 +53,28 @@ func (s *CommentService) Create(userID uuid.UUID, req dto.CreateCommentRequest)
 		return nil, fmt.Errorf("portfolio not found")
 	}
 
+	content := req.Content
+	var filtered TextFilterResult
+	if s.textFilter != nil {
+		content, filtered = s.textFilter.Apply(content)
+		if filtered.Blocked {
+			return nil, ErrContentBlocked
+		}
+	}
+
 	comment := &domain.Comment{
 		PortfolioID: req.PortfolioID,
 		UserID:      userID,
 		ParentID:    req.ParentID,
-		Content:     req.Content,
+		Content:     content,
 	}
 
 	if err := s.commentRepo.Create(comment); err != nil {
 		return nil, err
 	}
+	if s.textFilter != nil {
+		s.textFilter.Flag(domain.ReportTargetComment, comment.ID, userID, filtered)
+	}
 
 	// Trigger Notification
 	go func() {
@@ -64,6 +85,7 @@ func (s *CommentService) Create(userID uuid.UUID, req dto.CreateCommentRequest)
 		}
 
 		// If replying to a comment
+		var alreadyNotified []uuid.UUID
 		if req.ParentID != nil {
 			parentComment, err := s.commentRepo.FindByID(*req.ParentID)
 			if err == nil && parentComment.UserID != userID {
@@ -71,26 +93,15 @@ func (s *CommentService) Create(userID uuid.UUID, req dto.CreateCommentRequest)
 				replier, _ := s.userRepo.FindByID(userID)
 				if replier != nil {
 					s.notificationService.NotifyReplyComment(parentComment, portfolio, replier, comment)
+					alreadyNotified = append(alreadyNotified, parentComment.UserID) // Already notified as reply
 				}
 			}
 		}
 
-		// Notify Portfolio Owner
-		if portfolio.UserID != userID && (req.ParentID == nil || (req.ParentID != nil)) {
-			shouldNotifyOwner := true
-			if req.ParentID != nil {
-				parentComment, _ := s.commentRepo.FindByID(*req.ParentID)
-				if parentComment != nil && parentComment.UserID == portfolio.UserID {
-					shouldNotifyOwner = false // Already notified as reply
-				}
-			}
-
-			if shouldNotifyOwner {
-				commenter, _ := s.userRepo.FindByID(userID)
-				if commenter != nil {
-					s.notificationService.NotifyNewComment(portfolio, commenter, comment)
-				}
-			}
+		// Notify portfolio owner and co-authors, except the commenter
+		commenter, _ := s.userRepo.FindByID(userID)
+		if commenter != nil {
+			s.notificationService.NotifyNewComment(portfolio, commenter, comment, alreadyNotified...)
 		}
 	}()
 
@@ -117,6 +128,7 @@ func (s *CommentService) buildCommentTree(comments []domain.Comment) []dto.Comme
 		resp := &dto.CommentResponse{
 			ID:        c.ID,
 			Content:   c.Content,
+			IsHidden:  c.IsHidden,
 			CreatedAt: c.CreatedAt,
 			UpdatedAt: c.UpdatedAt,
 			User: dto.UserBriefDTO{
@@ -128,6 +140,10 @@ func (s *CommentService) buildCommentTree(comments []domain.Comment) []dto.Comme
 			},
 			Children: []*dto.CommentResponse{},
 		}
+		if c.IsHidden {
+			// Keep the place in the thread so replies stay attached
+			resp.Content = HiddenCommentPlaceholder
+		}
 		commentMap[c.ID] = resp
 	}
 
@@ -23,6 +23,7 @@ type DMService struct {
 	dmRepo     *repository.DMRepository
 	userRepo   *repository.UserRepository
 	followRepo *repository.FollowRepository
+	textFilter *TextFilterService
 }
 
 func NewDMService(dmRepo *repository.DMRepository, userRepo *repository.UserRepository, followRepo *repository.FollowRepository) *DMService {
@@ -33,6 +34,11 @@ func NewDMService(dmRepo *repository.DMRepository, userRepo *repository.UserRepo
 	}
 }
 
+// SetTextFilter enables screening of text messages for banned terms
+func (s *DMService) SetTextFilter(f *TextFilterService) {
+	s.textFilter = f
+}
+
 // CanUserMessage checks if sender can message recipient based on privacy settings
 func (s *DMService) CanUserMessage(senderID, recipientID uuid.UUID) (bool, error) {
 	// Check if blocked
@@ -85,6 +91,13 @@ func (s *DMService) StartConversation(senderID, recipientID uuid.UUID, initialMe
 		return nil, nil, ErrCannotMessageUser
 	}
 
+	// Reject a blocked opening message before a conversation is created for it
+	if s.textFilter != nil && initialMessage != "" {
+		if _, filtered := s.textFilter.Apply(initialMessage); filtered.Blocked {
+			return nil, nil, ErrContentBlocked
+		}
+	}
+
 	// Check if conversation already exists
 	existingConv, err := s.dmRepo.FindConversationByParticipants(senderID, recipientID)
 	if err == nil {
@@ -154,6 +167,16 @@ func (s *DMService) SendMessage(convID, senderID uuid.UUID, msgType domain.Messa
 		return nil, ErrNotInConversation
 	}
 
+	var filtered TextFilterResult
+	if s.textFilter != nil && msgType == domain.MessageTypeText {
+		if text, ok := content["text"].(string); ok {
+			content["text"], filtered = s.textFilter.Apply(text)
+			if filtered.Blocked {
+				return nil, ErrContentBlocked
+			}
+		}
+	}
+
 	// Create message
 	msg := &domain.Message{
 		ConversationID: convID,
@@ -166,6 +189,9 @@ func (s *DMService) SendMessage(convID, senderID uuid.UUID, msgType domain.Messa
 	if err := s.dmRepo.CreateMessage(msg); err != nil {
 		return nil, err
 	}
+	if s.textFilter != nil {
+		s.textFilter.Flag(domain.ReportTargetMessage, msg.ID, senderID, filtered)
+	}
 
 	// Update conversation last message
 	preview := s.getMessagePreview(msgType, content)
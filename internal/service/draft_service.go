@@ -0,0 +1,179 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDraftUnderReview   = errors.New("draft is under review")
+	ErrDraftBlockNotFound = errors.New("draft block not found")
+)
+
+// DraftService keeps edits to published portfolios in a working draft so the live
+// version stays untouched until a reviewer approves the changes.
+type DraftService struct {
+	draftRepo *repository.DraftRepository
+	adminRepo *repository.AdminRepository
+}
+
+func NewDraftService(draftRepo *repository.DraftRepository, adminRepo *repository.AdminRepository) *DraftService {
+	return &DraftService{draftRepo: draftRepo, adminRepo: adminRepo}
+}
+
+// Find returns the working draft of a portfolio, or nil when it has none
+func (s *DraftService) Find(portfolioID uuid.UUID) (*domain.PortfolioDraft, error) {
+	draft, err := s.draftRepo.FindByPortfolioID(portfolioID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return draft, err
+}
+
+// Open returns the draft to edit, seeding it from the live portfolio when none exists.
+// Drafts waiting for review cannot be edited; a rejected draft goes back to editing.
+// Tags and content blocks of portfolio must be preloaded.
+func (s *DraftService) Open(portfolio *domain.Portfolio) (*domain.PortfolioDraft, error) {
+	draft, err := s.Find(portfolio.ID)
+	if err != nil {
+		return nil, err
+	}
+	if draft == nil {
+		return &domain.PortfolioDraft{
+			PortfolioID: portfolio.ID,
+			Status:      domain.DraftEditing,
+			Content:     domain.NewPortfolioSnapshot(portfolio),
+		}, nil
+	}
+	if draft.Status == domain.DraftPendingReview {
+		return nil, ErrDraftUnderReview
+	}
+	draft.Status = domain.DraftEditing
+	return draft, nil
+}
+
+// Save persists the draft after it was changed through Open
+func (s *DraftService) Save(draft *domain.PortfolioDraft) error {
+	return s.draftRepo.Save(draft)
+}
+
+// ApplyUpdate copies the fields set in req onto the draft content
+func (s *DraftService) ApplyUpdate(draft *domain.PortfolioDraft, req dto.UpdatePortfolioRequest) error {
+	if req.Judul != nil {
+		draft.Content.Judul = *req.Judul
+	}
+	if req.ThumbnailURL != nil {
+		draft.Content.ThumbnailURL = req.ThumbnailURL
+	}
+	if req.SeriesID != nil {
+		draft.Content.SeriesID = req.SeriesID
+	}
+	if req.TagIDs != nil {
+		tags, err := s.adminRepo.FindTagsByIDs(req.TagIDs)
+		if err != nil {
+			return err
+		}
+		draft.Content.Tags = make([]domain.SnapshotTag, 0, len(tags))
+		for _, t := range tags {
+			draft.Content.Tags = append(draft.Content.Tags, domain.SnapshotTag{ID: t.ID, Nama: t.Nama})
+		}
+	}
+	return s.draftRepo.Save(draft)
+}
+
+// AddBlock appends a block to the draft; order <= 0 places it after the last block
+func (s *DraftService) AddBlock(draft *domain.PortfolioDraft, blockType domain.ContentBlockType, order int, payload domain.JSONB) (domain.SnapshotBlock, error) {
+	if order <= 0 {
+		order = 0
+		for _, b := range draft.Content.Blocks {
+			if b.BlockOrder >= order {
+				order = b.BlockOrder + 1
+			}
+		}
+	}
+	block := domain.SnapshotBlock{ID: uuid.New(), BlockType: blockType, BlockOrder: order, Payload: payload}
+	draft.Content.Blocks = append(draft.Content.Blocks, block)
+	sortDraftBlocks(draft)
+	return block, s.draftRepo.Save(draft)
+}
+
+// UpdateBlock replaces the payload of a draft block
+func (s *DraftService) UpdateBlock(draft *domain.PortfolioDraft, blockID uuid.UUID, payload domain.JSONB) (domain.SnapshotBlock, error) {
+	for i := range draft.Content.Blocks {
+		if draft.Content.Blocks[i].ID == blockID {
+			if payload != nil {
+				draft.Content.Blocks[i].Payload = payload
+			}
+			return draft.Content.Blocks[i], s.draftRepo.Save(draft)
+		}
+	}
+	return domain.SnapshotBlock{}, ErrDraftBlockNotFound
+}
+
+// DeleteBlock removes a block from the draft
+func (s *DraftService) DeleteBlock(draft *domain.PortfolioDraft, blockID uuid.UUID) error {
+	for i, b := range draft.Content.Blocks {
+		if b.ID == blockID {
+			draft.Content.Blocks = append(draft.Content.Blocks[:i], draft.Content.Blocks[i+1:]...)
+			return s.draftRepo.Save(draft)
+		}
+	}
+	return ErrDraftBlockNotFound
+}
+
+// ReorderBlocks sets the order of the given draft blocks; unknown IDs are ignored
+func (s *DraftService) ReorderBlocks(draft *domain.PortfolioDraft, orders map[uuid.UUID]int) error {
+	for i := range draft.Content.Blocks {
+		if order, ok := orders[draft.Content.Blocks[i].ID]; ok {
+			draft.Content.Blocks[i].BlockOrder = order
+		}
+	}
+	sortDraftBlocks(draft)
+	return s.draftRepo.Save(draft)
+}
+
+// Submit sends the draft to the review queue
+func (s *DraftService) Submit(draft *domain.PortfolioDraft) error {
+	now := time.Now()
+	draft.Status = domain.DraftPendingReview
+	draft.SubmittedAt = &now
+	return s.draftRepo.Save(draft)
+}
+
+// Approve swaps the draft content into the live portfolio and drops the draft
+func (s *DraftService) Approve(draft *domain.PortfolioDraft, reviewedBy *uuid.UUID, note *string) error {
+	return s.draftRepo.Publish(draft, reviewedBy, note, time.Now())
+}
+
+// Reject returns the draft to its author; the live version is not touched
+func (s *DraftService) Reject(draft *domain.PortfolioDraft, reviewedBy *uuid.UUID, note string) error {
+	now := time.Now()
+	draft.Status = domain.DraftRejected
+	draft.AdminReviewNote = &note
+	draft.ReviewedBy = reviewedBy
+	draft.ReviewedAt = &now
+	return s.draftRepo.Save(draft)
+}
+
+// Discard throws the working draft away
+func (s *DraftService) Discard(portfolioID uuid.UUID) error {
+	return s.draftRepo.DeleteByPortfolioID(portfolioID)
+}
+
+// StatusesByPortfolioIDs reports which portfolios in ids have a draft and its status
+func (s *DraftService) StatusesByPortfolioIDs(ids []uuid.UUID) (map[uuid.UUID]domain.DraftStatus, error) {
+	return s.draftRepo.StatusesByPortfolioIDs(ids)
+}
+
+func sortDraftBlocks(draft *domain.PortfolioDraft) {
+	sort.SliceStable(draft.Content.Blocks, func(i, j int) bool {
+		return draft.Content.Blocks[i].BlockOrder < draft.Content.Blocks[j].BlockOrder
+	})
+}
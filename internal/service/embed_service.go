@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/embed"
+	"github.com/grafikarsa/backend/internal/repository"
+)
+
+const (
+	// embedRecheckAfter is how long a check result is trusted before the URL is looked up again
+	embedRecheckAfter = 24 * time.Hour
+	// embedBatchSize bounds the lookups per tick so a tick never floods the providers
+	embedBatchSize = 50
+	// embedLookupTimeout bounds a single lookup, both on save and in the background check
+	embedLookupTimeout = 8 * time.Second
+)
+
+// embedBlockTypes are the block types whose payload points at external content
+var embedBlockTypes = []domain.ContentBlockType{
+	domain.BlockYoutube,
+	domain.BlockFigma,
+	domain.BlockCanva,
+	domain.BlockEmbed,
+	domain.BlockPPT,
+	domain.BlockPDF,
+	domain.BlockDoc,
+}
+
+var iframeSrcPattern = regexp.MustCompile(`(?i)<iframe[^>]*\ssrc\s*=\s*["']([^"']+)["']`)
+
+// EmbedService resolves the external URLs of content blocks when they are saved and
+// re-checks them in the background so dead or private links surface before anyone looks.
+type EmbedService struct {
+	resolver      *embed.Resolver
+	embedRepo     *repository.EmbedRepository
+	portfolioRepo *repository.PortfolioRepository
+	notifService  *NotificationService
+}
+
+func NewEmbedService(resolver *embed.Resolver, embedRepo *repository.EmbedRepository, portfolioRepo *repository.PortfolioRepository, notifService *NotificationService) *EmbedService {
+	return &EmbedService{
+		resolver:      resolver,
+		embedRepo:     embedRepo,
+		portfolioRepo: portfolioRepo,
+		notifService:  notifService,
+	}
+}
+
+// ResolvePayload looks up the external URL of a block payload and rewrites it in place to
+// its canonical form. A dead, private or unsupported URL comes back as an error detail.
+// When the provider cannot be reached the payload is accepted unresolved and a nil result
+// is returned, leaving the block to the background check.
+func (s *EmbedService) ResolvePayload(ctx context.Context, blockType domain.ContentBlockType, payload domain.JSONB) (*embed.Result, *dto.ErrorDetail) {
+	source, field, ok := embedSource(blockType, payload)
+	if !ok {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, embedLookupTimeout)
+	defer cancel()
+	res, err := s.resolver.Resolve(ctx, source)
+	if err != nil {
+		if embed.IsPermanent(err) {
+			return nil, &dto.ErrorDetail{Field: "payload." + field, Message: embedErrorMessage(err)}
+		}
+		log.Printf("[EMBED] Lookup of %s failed, leaving it to the background check: %v", source, err)
+		return nil, nil
+	}
+
+	if field == "url" {
+		payload["url"] = res.CanonicalURL
+	}
+	return res, nil
+}
+
+// CacheEmbed stores res on block; a nil res clears the cache so the background check picks
+// the block up on its next tick
+func CacheEmbed(block *domain.ContentBlock, res *embed.Result, now time.Time) {
+	if res == nil {
+		block.EmbedStatus, block.EmbedMeta, block.EmbedCheckedAt = nil, nil, nil
+		return
+	}
+	status := domain.EmbedOK
+	block.EmbedStatus = &status
+	block.EmbedMeta = embedMetaJSON(res)
+	block.EmbedCheckedAt = &now
+}
+
+// Start runs RecheckDue every interval until ctx is cancelled
+func (s *EmbedService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RecheckDue(ctx, time.Now()); err != nil {
+			log.Printf("[EMBED] Failed to re-check embeds: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RecheckDue looks up one batch of unchecked or stale blocks and returns how many it checked.
+// Authors are notified when a link that used to work turns out dead or private.
+func (s *EmbedService) RecheckDue(ctx context.Context, now time.Time) (int, error) {
+	blocks, err := s.embedRepo.FindDueChecks(embedBlockTypes, now.Add(-embedRecheckAfter), embedBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	checked := 0
+	for i := range blocks {
+		if ctx.Err() != nil {
+			return checked, nil
+		}
+		block := &blocks[i]
+		status, meta, err := s.check(ctx, block)
+		if err != nil {
+			// Keep the previous result and try again after the next recheck interval
+			log.Printf("[EMBED] Lookup for block %s failed: %v", block.ID, err)
+			status, meta = block.EmbedStatus, block.EmbedMeta
+		}
+		if err := s.embedRepo.SaveCheck(block.ID, status, meta, now); err != nil {
+			return checked, err
+		}
+		checked++
+
+		if status != nil && *status != domain.EmbedOK && (block.EmbedStatus == nil || *block.EmbedStatus == domain.EmbedOK) {
+			s.notifyBroken(block, *status)
+		}
+	}
+	return checked, nil
+}
+
+// check resolves the URL of a stored block; blocks without a URL yet get a nil status
+func (s *EmbedService) check(ctx context.Context, block *domain.ContentBlock) (*domain.EmbedStatus, domain.JSONB, error) {
+	source, _, ok := embedSource(block.BlockType, block.Payload)
+	if !ok {
+		return nil, nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, embedLookupTimeout)
+	defer cancel()
+	res, err := s.resolver.Resolve(ctx, source)
+
+	var status domain.EmbedStatus
+	switch {
+	case err == nil:
+		status = domain.EmbedOK
+		return &status, embedMetaJSON(res), nil
+	case errors.Is(err, embed.ErrNotEmbeddable):
+		status = domain.EmbedPrivate
+	case embed.IsPermanent(err):
+		status = domain.EmbedDead
+	default:
+		return nil, nil, err
+	}
+	return &status, nil, nil
+}
+
+func (s *EmbedService) notifyBroken(block *domain.ContentBlock, status domain.EmbedStatus) {
+	if s.notifService == nil {
+		return
+	}
+	portfolio, err := s.portfolioRepo.FindByID(block.PortfolioID)
+	if err != nil {
+		return
+	}
+	if err := s.notifService.NotifyEmbedBroken(portfolio, block, status); err != nil {
+		log.Printf("[EMBED] Failed to notify authors of portfolio %s: %v", portfolio.ID, err)
+	}
+}
+
+// embedSource returns the external URL of a payload and the payload field it came from
+func embedSource(blockType domain.ContentBlockType, payload domain.JSONB) (string, string, bool) {
+	if blockType == domain.BlockYoutube {
+		if id, _ := payload["video_id"].(string); id != "" {
+			return embed.YouTubeURL(id), "video_id", true
+		}
+		return "", "", false
+	}
+
+	isEmbedType := false
+	for _, t := range embedBlockTypes {
+		if t == blockType {
+			isEmbedType = true
+			break
+		}
+	}
+	if !isEmbedType {
+		return "", "", false
+	}
+
+	if u, _ := payload["url"].(string); strings.TrimSpace(u) != "" {
+		return u, "url", true
+	}
+	if blockType == domain.BlockEmbed {
+		html, _ := payload["html"].(string)
+		if m := iframeSrcPattern.FindStringSubmatch(html); m != nil {
+			return m[1], "html", true
+		}
+	}
+	return "", "", false
+}
+
+func embedMetaJSON(res *embed.Result) domain.JSONB {
+	meta := domain.JSONB{
+		"provider":      res.Metadata.Provider,
+		"canonical_url": res.CanonicalURL,
+	}
+	if res.Metadata.Title != "" {
+		meta["title"] = res.Metadata.Title
+	}
+	if res.Metadata.ThumbnailURL != "" {
+		meta["thumbnail_url"] = res.Metadata.ThumbnailURL
+	}
+	if res.Metadata.AspectRatio > 0 {
+		meta["aspect_ratio"] = res.Metadata.AspectRatio
+	}
+	return meta
+}
+
+func embedErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, embed.ErrNotFound):
+		return "Link tidak ditemukan atau sudah dihapus"
+	case errors.Is(err, embed.ErrNotEmbeddable):
+		return "Konten bersifat privat atau tidak bisa di-embed, ubah akses berbagi menjadi publik"
+	case errors.Is(err, embed.ErrUnreachable):
+		return "Link tidak dapat dijangkau, pastikan link bisa dibuka secara publik"
+	}
+	return "URL tidak didukung"
+}
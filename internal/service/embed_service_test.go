@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/embed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBucket map[string]bool
+
+func (b fakeBucket) ObjectExists(objectKey string) (bool, error) { return b[objectKey], nil }
+
+// Uploaded documents live on our own storage, which the link probe would refuse as internal
+func TestResolvePayload_StorageDocument(t *testing.T) {
+	bucket := fakeBucket{"portfolios/p1/laporan.pdf": true}
+	providers := append([]embed.Provider{embed.NewStorageProvider("http://localhost:9000/grafikarsa", bucket)}, embed.DefaultProviders(embed.NewHTTPClient(2*time.Second))...)
+	s := NewEmbedService(embed.NewResolver(providers...), nil, nil, nil)
+
+	payload := domain.JSONB{"url": "http://localhost:9000/grafikarsa/portfolios/p1/laporan.pdf", "filename": "laporan.pdf"}
+	res, detail := s.ResolvePayload(context.Background(), domain.BlockPDF, payload)
+	assert.Nil(t, detail)
+	require.NotNil(t, res)
+	assert.Equal(t, "storage", res.Metadata.Provider)
+	assert.Equal(t, "http://localhost:9000/grafikarsa/portfolios/p1/laporan.pdf", payload["url"])
+
+	payload = domain.JSONB{"url": "http://localhost:9000/grafikarsa/portfolios/p1/hilang.pdf"}
+	_, detail = s.ResolvePayload(context.Background(), domain.BlockPDF, payload)
+	require.NotNil(t, detail, "a deleted upload is still reported")
+	assert.Equal(t, "payload.url", detail.Field)
+
+	// anything else on the same host is still refused by the link probe
+	payload = domain.JSONB{"url": "http://localhost:9000/lain/rahasia.pdf"}
+	_, detail = s.ResolvePayload(context.Background(), domain.BlockPDF, payload)
+	assert.NotNil(t, detail)
+}
@@ -1,17 +1,57 @@
 package service
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/grafikarsa/backend/internal/domain"
 	"github.com/grafikarsa/backend/internal/repository"
 )
 
 type NotificationService struct {
-	repo *repository.NotificationRepository
+	repo       *repository.NotificationRepository
+	collabRepo *repository.CollaboratorRepository
+}
+
+func NewNotificationService(repo *repository.NotificationRepository, collabRepo *repository.CollaboratorRepository) *NotificationService {
+	return &NotificationService{repo: repo, collabRepo: collabRepo}
 }
 
-func NewNotificationService(repo *repository.NotificationRepository) *NotificationService {
-	return &NotificationService{repo: repo}
+// authors returns the owner and accepted co-authors of a portfolio, leaving out the skipped users
+func (s *NotificationService) authors(portfolio *domain.Portfolio, skip ...uuid.UUID) []uuid.UUID {
+	ids := []uuid.UUID{portfolio.UserID}
+	if s.collabRepo != nil {
+		coAuthors, _ := s.collabRepo.AcceptedUserIDs(portfolio.ID)
+		ids = append(ids, coAuthors...)
+	}
+
+	recipients := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		skipped := false
+		for _, sk := range skip {
+			if id == sk {
+				skipped = true
+				break
+			}
+		}
+		if !skipped {
+			recipients = append(recipients, id)
+		}
+	}
+	return recipients
+}
+
+// createForEach sends a copy of notification to every recipient
+func (s *NotificationService) createForEach(recipients []uuid.UUID, notification domain.Notification) error {
+	var firstErr error
+	for _, userID := range recipients {
+		n := notification
+		n.UserID = userID
+		if err := s.repo.Create(&n); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
 }
 
 // NotifyNewFollower creates notification when someone follows a user
@@ -31,15 +71,15 @@ func (s *NotificationService) NotifyNewFollower(follower *domain.User, followedU
 	return s.repo.Create(notification)
 }
 
-// NotifyPortfolioLiked creates notification when someone likes a portfolio
+// NotifyPortfolioLiked creates notification for every author when someone likes a portfolio
 func (s *NotificationService) NotifyPortfolioLiked(liker *domain.User, portfolio *domain.Portfolio) error {
 	// Don't notify if user likes their own portfolio
-	if liker.ID == portfolio.UserID {
+	recipients := s.authors(portfolio, liker.ID)
+	if len(recipients) == 0 {
 		return nil
 	}
 
-	notification := &domain.Notification{
-		UserID:  portfolio.UserID,
+	notification := domain.Notification{
 		Type:    domain.NotifPortfolioLiked,
 		Title:   "Portfolio Disukai",
 		Message: strPtr("@" + liker.Username + " menyukai portfolio \"" + portfolio.Judul + "\""),
@@ -53,7 +93,7 @@ func (s *NotificationService) NotifyPortfolioLiked(liker *domain.User, portfolio
 			"portfolio_slug":  portfolio.Slug,
 		},
 	}
-	return s.repo.Create(notification)
+	return s.createForEach(recipients, notification)
 }
 
 // NotifyPortfolioApproved creates notification when portfolio is approved
@@ -129,10 +169,15 @@ func (s *NotificationService) NotifyFeedbackStatusUpdated(feedback *domain.Feedb
 	return s.repo.Create(notification)
 }
 
-// NotifyNewComment creates notification when someone comments on a portfolio
-func (s *NotificationService) NotifyNewComment(portfolio *domain.Portfolio, commenter *domain.User, comment *domain.Comment) error {
-	notification := &domain.Notification{
-		UserID:  portfolio.UserID,
+// NotifyNewComment creates notification for every author except the commenter when someone
+// comments on a portfolio. Authors in skip were already notified another way.
+func (s *NotificationService) NotifyNewComment(portfolio *domain.Portfolio, commenter *domain.User, comment *domain.Comment, skip ...uuid.UUID) error {
+	recipients := s.authors(portfolio, append(skip, commenter.ID)...)
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	notification := domain.Notification{
 		Type:    domain.NotifNewComment,
 		Title:   "Komentar Baru di Portfolio",
 		Message: strPtr("@" + commenter.Username + " berkomentar di \"" + portfolio.Judul + "\""),
@@ -150,7 +195,7 @@ func (s *NotificationService) NotifyNewComment(portfolio *domain.Portfolio, comm
 			"comment_id":               comment.ID.String(),
 		},
 	}
-	return s.repo.Create(notification)
+	return s.createForEach(recipients, notification)
 }
 
 // NotifyReplyComment creates notification when someone replies to a comment
@@ -175,6 +220,151 @@ func (s *NotificationService) NotifyReplyComment(parentComment *domain.Comment,
 	return s.repo.Create(notification)
 }
 
+// NotifyCollaboratorInvite asks a user to join a team portfolio; the notification carries
+// the invitation ID so it can be accepted or declined from the notification list
+func (s *NotificationService) NotifyCollaboratorInvite(inviter *domain.User, portfolio *domain.Portfolio, collaborator *domain.PortfolioCollaborator) error {
+	notification := &domain.Notification{
+		UserID:  collaborator.UserID,
+		Type:    domain.NotifCollabInvite,
+		Title:   "Undangan Portfolio Tim",
+		Message: strPtr("@" + inviter.Username + " mengundang kamu sebagai " + collaboratorRoleLabel(collaborator.Role) + " di \"" + portfolio.Judul + "\""),
+		Data: domain.JSONB{
+			"actor_id":        inviter.ID.String(),
+			"actor_username":  inviter.Username,
+			"actor_nama":      inviter.Nama,
+			"actor_avatar":    inviter.AvatarURL,
+			"portfolio_id":    portfolio.ID.String(),
+			"portfolio_judul": portfolio.Judul,
+			"portfolio_slug":  portfolio.Slug,
+			"collaborator_id": collaborator.ID.String(),
+			"role":            string(collaborator.Role),
+		},
+	}
+	return s.repo.Create(notification)
+}
+
+// NotifyCollaboratorResponded tells the portfolio owner whether an invitation was accepted
+func (s *NotificationService) NotifyCollaboratorResponded(invitee *domain.User, portfolio *domain.Portfolio, collaborator *domain.PortfolioCollaborator) error {
+	accepted := collaborator.Status == domain.CollaboratorAccepted
+	verb := "menolak"
+	if accepted {
+		verb = "menerima"
+	}
+
+	notification := &domain.Notification{
+		UserID:  portfolio.UserID,
+		Type:    domain.NotifCollabResponded,
+		Title:   "Undangan Portfolio Tim Dijawab",
+		Message: strPtr("@" + invitee.Username + " " + verb + " undangan di \"" + portfolio.Judul + "\""),
+		Data: domain.JSONB{
+			"actor_id":        invitee.ID.String(),
+			"actor_username":  invitee.Username,
+			"actor_nama":      invitee.Nama,
+			"actor_avatar":    invitee.AvatarURL,
+			"portfolio_id":    portfolio.ID.String(),
+			"portfolio_judul": portfolio.Judul,
+			"portfolio_slug":  portfolio.Slug,
+			"collaborator_id": collaborator.ID.String(),
+			"accepted":        accepted,
+		},
+	}
+	return s.repo.Create(notification)
+}
+
+// NotifySeriesReminder reminds a targeted student that a series assignment closes soon
+func (s *NotificationService) NotifySeriesReminder(userID uuid.UUID, series *domain.Series) error {
+	data := domain.JSONB{
+		"series_id":   series.ID.String(),
+		"series_nama": series.Nama,
+	}
+	message := "Tugas series \"" + series.Nama + "\" segera ditutup, ajukan portfolio kamu sebelum deadline"
+	if series.ClosesAt != nil {
+		data["closes_at"] = series.ClosesAt.Format(time.RFC3339)
+	}
+
+	notification := &domain.Notification{
+		UserID:  userID,
+		Type:    domain.NotifSeriesReminder,
+		Title:   "Deadline Tugas Series",
+		Message: &message,
+		Data:    data,
+	}
+	return s.repo.Create(notification)
+}
+
+// NotifyEmbedBroken tells the authors that an embedded link of their portfolio stopped working
+func (s *NotificationService) NotifyEmbedBroken(portfolio *domain.Portfolio, block *domain.ContentBlock, status domain.EmbedStatus) error {
+	message := "Salah satu link di portfolio \"" + portfolio.Judul + "\" sudah tidak bisa dibuka"
+	if status == domain.EmbedPrivate {
+		message = "Salah satu link di portfolio \"" + portfolio.Judul + "\" sekarang bersifat privat"
+	}
+
+	notification := domain.Notification{
+		Type:    domain.NotifEmbedBroken,
+		Title:   "Link Portfolio Bermasalah",
+		Message: &message,
+		Data: domain.JSONB{
+			"portfolio_id":    portfolio.ID.String(),
+			"portfolio_judul": portfolio.Judul,
+			"portfolio_slug":  portfolio.Slug,
+			"block_id":        block.ID.String(),
+			"block_type":      string(block.BlockType),
+			"embed_status":    string(status),
+		},
+	}
+	return s.createForEach(s.authors(portfolio), notification)
+}
+
+// NotifyShareComment tells the authors that a guest commented through one of their share links
+func (s *NotificationService) NotifyShareComment(portfolio *domain.Portfolio, link *domain.PortfolioShareLink, comment *domain.ShareLinkComment) error {
+	data := domain.JSONB{
+		"portfolio_id":    portfolio.ID.String(),
+		"portfolio_judul": portfolio.Judul,
+		"share_link_id":   link.ID.String(),
+		"comment_id":      comment.ID.String(),
+		"author_name":     comment.AuthorName,
+	}
+	if link.Label != nil {
+		data["share_link_label"] = *link.Label
+	}
+
+	notification := domain.Notification{
+		Type:    domain.NotifShareComment,
+		Title:   "Komentar Baru dari Link Berbagi",
+		Message: strPtr(comment.AuthorName + " mengomentari portfolio \"" + portfolio.Judul + "\""),
+		Data:    data,
+	}
+	return s.createForEach(s.authors(portfolio), notification)
+}
+
+// NotifyReportResolved tells the reporters that a moderator has reviewed what they reported.
+// The action taken against the reported user is not disclosed.
+func (s *NotificationService) NotifyReportResolved(reporterIDs []uuid.UUID, targetType domain.ReportTargetType, targetID uuid.UUID, status domain.ReportStatus) error {
+	message := "Terima kasih, laporan Anda sudah ditinjau dan telah ditindaklanjuti oleh moderator"
+	if status == domain.ReportStatusDismissed {
+		message = "Laporan Anda sudah ditinjau. Moderator tidak menemukan pelanggaran pada konten tersebut"
+	}
+
+	notification := domain.Notification{
+		Type:    domain.NotifReportResolved,
+		Title:   "Laporan Ditinjau",
+		Message: &message,
+		Data: domain.JSONB{
+			"target_type": string(targetType),
+			"target_id":   targetID.String(),
+			"status":      string(status),
+		},
+	}
+	return s.createForEach(reporterIDs, notification)
+}
+
+func collaboratorRoleLabel(role domain.CollaboratorRole) string {
+	if role == domain.CollaboratorEditor {
+		return "editor"
+	}
+	return "kontributor"
+}
+
 func strPtr(s string) *string {
 	return &s
 }
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/repository"
+)
+
+const (
+	DefaultAnalyticsDays = 30
+	MaxAnalyticsDays     = 90
+	// minAudienceGroup is the smallest audience group shown on its own; smaller ones are
+	// folded into "lainnya" so a single viewer cannot be picked out
+	minAudienceGroup = 3
+	audienceOther    = "lainnya"
+)
+
+// viewSources lists the traffic sources in the order they are reported
+var viewSources = []domain.ViewSource{
+	domain.ViewSourceFeed,
+	domain.ViewSourceSearch,
+	domain.ViewSourceProfile,
+	domain.ViewSourceDirect,
+	domain.ViewSourceShareLink,
+}
+
+// ParseViewSource reads the source a client reports for a visit; anything unknown counts as direct.
+// Share link visits are only recorded by the share link endpoint itself.
+func ParseViewSource(raw string) domain.ViewSource {
+	switch source := domain.ViewSource(raw); source {
+	case domain.ViewSourceFeed, domain.ViewSourceSearch, domain.ViewSourceProfile:
+		return source
+	}
+	return domain.ViewSourceDirect
+}
+
+// PortfolioAnalyticsService records visits and builds the statistics owners see on their portfolios
+type PortfolioAnalyticsService struct {
+	repo *repository.AnalyticsRepository
+}
+
+func NewPortfolioAnalyticsService(repo *repository.AnalyticsRepository) *PortfolioAnalyticsService {
+	return &PortfolioAnalyticsService{repo: repo}
+}
+
+// RecordView logs a visit; it runs off the request path, so failures are only logged
+func (s *PortfolioAnalyticsService) RecordView(portfolioID uuid.UUID, userID *uuid.UUID, sessionID *string, source domain.ViewSource, at time.Time) {
+	event := &domain.PortfolioViewEvent{
+		PortfolioID: portfolioID,
+		UserID:      userID,
+		SessionID:   sessionID,
+		Source:      source,
+		ViewedAt:    at,
+	}
+	if err := s.repo.RecordViewEvent(event); err != nil {
+		log.Printf("[ANALYTICS] Failed to record view of %s: %v", portfolioID, err)
+	}
+}
+
+// Build returns the statistics of a portfolio over the last days days, today included
+func (s *PortfolioAnalyticsService) Build(portfolio *domain.Portfolio, days int, now time.Time) (*dto.PortfolioAnalyticsDTO, error) {
+	if days <= 0 {
+		days = DefaultAnalyticsDays
+	}
+	if days > MaxAnalyticsDays {
+		days = MaxAnalyticsDays
+	}
+	y, m, d := now.Date()
+	from := time.Date(y, m, d, 0, 0, 0, 0, now.Location()).AddDate(0, 0, -(days - 1))
+
+	totals, err := s.repo.ViewTotals(portfolio.ID, from)
+	if err != nil {
+		return nil, err
+	}
+	dailyViews, err := s.repo.DailyViews(portfolio.ID, from, now.Location())
+	if err != nil {
+		return nil, err
+	}
+	dailyLikes, err := s.repo.DailyLikes(portfolio.ID, from, now.Location())
+	if err != nil {
+		return nil, err
+	}
+	sources, err := s.repo.SourceCounts(portfolio.ID, from)
+	if err != nil {
+		return nil, err
+	}
+	likesBefore, err := s.repo.CountLikesBefore(portfolio.ID, from)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.repo.AudienceGroups(portfolio.ID, from)
+	if err != nil {
+		return nil, err
+	}
+	engagement, err := s.repo.AuthorEngagement(portfolio.UserID, portfolio.ID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.PortfolioAnalyticsDTO{
+		PortfolioID: portfolio.ID,
+		Days:        days,
+		From:        from,
+		To:          now,
+		Daily:       analyticsDays(dailyViews, dailyLikes, likesBefore, from, days),
+		Sources:     sourceCounts(sources),
+		Audience:    audienceBreakdown(groups, totals.Guests),
+		Comparison:  compareEngagement(engagement, portfolio.ID),
+	}
+	var likes int64
+	for _, d := range dailyLikes {
+		likes += d.Count
+	}
+	result.Summary = dto.AnalyticsSummaryDTO{
+		Views:         totals.Views,
+		UniqueViewers: totals.UniqueViewers,
+		Likes:         likes,
+		TotalLikes:    likesBefore + likes,
+	}
+	return result, nil
+}
+
+// analyticsDays lays the per-day counts out over every calendar day starting at from,
+// filling days without visits or likes with zeros
+func analyticsDays(views, likes []repository.DayCount, likesBefore int64, from time.Time, days int) []dto.AnalyticsDayDTO {
+	result := make([]dto.AnalyticsDayDTO, days)
+	index := make(map[string]int, days)
+	for i := range result {
+		date := from.AddDate(0, 0, i).Format("2006-01-02")
+		result[i].Date = date
+		index[date] = i
+	}
+
+	for _, v := range views {
+		if i, ok := index[v.Day]; ok {
+			result[i].Views = v.Count
+			result[i].UniqueViewers = v.UniqueViewers
+		}
+	}
+	for _, l := range likes {
+		if i, ok := index[l.Day]; ok {
+			result[i].Likes = l.Count
+		}
+	}
+
+	total := likesBefore
+	for i := range result {
+		total += result[i].Likes
+		result[i].TotalLikes = total
+	}
+	return result
+}
+
+func sourceCounts(rows []repository.SourceCount) []dto.ViewSourceCountDTO {
+	counts := make(map[domain.ViewSource]int64, len(viewSources))
+	for _, row := range rows {
+		counts[row.Source] += row.Views
+	}
+	result := make([]dto.ViewSourceCountDTO, 0, len(viewSources))
+	for _, source := range viewSources {
+		result = append(result, dto.ViewSourceCountDTO{Source: string(source), Views: counts[source]})
+	}
+	return result
+}
+
+// audienceBreakdown sums the signed-in viewers by role and by jurusan next to the number of
+// distinct guest sessions. Viewers without a jurusan, such as teachers, only appear by role.
+func audienceBreakdown(groups []repository.AudienceGroup, guests int64) dto.AudienceDTO {
+	byRole := make(map[string]int64)
+	byJurusan := make(map[string]int64)
+	for _, g := range groups {
+		byRole[g.Role] += g.Viewers
+		if g.Jurusan != nil {
+			byJurusan[*g.Jurusan] += g.Viewers
+		}
+	}
+
+	return dto.AudienceDTO{
+		ByRole:    foldAudience(byRole),
+		ByJurusan: foldAudience(byJurusan),
+		Guests:    guests,
+	}
+}
+
+// foldAudience orders groups by size and merges those below minAudienceGroup into one. The
+// merged group is left out too while it is below minAudienceGroup, since with a single small
+// group folded it would name that group's viewers all the same.
+func foldAudience(counts map[string]int64) []dto.AudienceGroupDTO {
+	result := make([]dto.AudienceGroupDTO, 0, len(counts))
+	var other int64
+	for label, viewers := range counts {
+		if viewers < minAudienceGroup {
+			other += viewers
+			continue
+		}
+		result = append(result, dto.AudienceGroupDTO{Label: label, Viewers: viewers})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Viewers != result[j].Viewers {
+			return result[i].Viewers > result[j].Viewers
+		}
+		return result[i].Label < result[j].Label
+	})
+	if other >= minAudienceGroup {
+		result = append(result, dto.AudienceGroupDTO{Label: audienceOther, Viewers: other})
+	}
+	return result
+}
+
+// compareEngagement ranks the portfolio among its author's portfolios by views and averages
+// the others
+func compareEngagement(rows []repository.PortfolioEngagement, currentID uuid.UUID) dto.AnalyticsComparisonDTO {
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Views > rows[j].Views })
+
+	result := dto.AnalyticsComparisonDTO{Portfolios: make([]dto.PortfolioEngagementDTO, 0, len(rows))}
+	var others, views, likes int64
+	for i, row := range rows {
+		isCurrent := row.ID == currentID
+		result.Portfolios = append(result.Portfolios, dto.PortfolioEngagementDTO{
+			ID:        row.ID,
+			Judul:     row.Judul,
+			Slug:      row.Slug,
+			Views:     row.Views,
+			Likes:     row.Likes,
+			IsCurrent: isCurrent,
+		})
+		if isCurrent {
+			result.ViewsRank = i + 1
+			continue
+		}
+		others++
+		views += row.Views
+		likes += row.Likes
+	}
+	if others > 0 {
+		result.AverageViews = float64(views) / float64(others)
+		result.AverageLikes = float64(likes) / float64(others)
+	}
+	return result
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyticsDays(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	views := []repository.DayCount{
+		{Day: "2025-03-01", Count: 3, UniqueViewers: 2},
+		{Day: "2025-03-03", Count: 1, UniqueViewers: 1},
+		{Day: "2025-02-28", Count: 4, UniqueViewers: 4},
+	}
+	likes := []repository.DayCount{{Day: "2025-03-01", Count: 1}, {Day: "2025-03-03", Count: 1}}
+
+	days := analyticsDays(views, likes, 5, from, 3)
+	require.Len(t, days, 3)
+	assert.Equal(t, dto.AnalyticsDayDTO{Date: "2025-03-01", Views: 3, UniqueViewers: 2, Likes: 1, TotalLikes: 6}, days[0])
+	assert.Equal(t, dto.AnalyticsDayDTO{Date: "2025-03-02", TotalLikes: 6}, days[1])
+	assert.Equal(t, dto.AnalyticsDayDTO{Date: "2025-03-03", Views: 1, UniqueViewers: 1, Likes: 1, TotalLikes: 7}, days[2])
+}
+
+func TestFoldAudience_HidesSmallGroups(t *testing.T) {
+	groups := foldAudience(map[string]int64{"DKV": 12, "RPL": 3, "TKJ": 2, "AKL": 1})
+	assert.Equal(t, []dto.AudienceGroupDTO{
+		{Label: "DKV", Viewers: 12},
+		{Label: "RPL", Viewers: 3},
+		{Label: audienceOther, Viewers: 3},
+	}, groups)
+
+	// a lone folded viewer would be as easy to spot under "lainnya" as under its own label
+	groups = foldAudience(map[string]int64{"student": 10, "teacher": 1})
+	assert.Equal(t, []dto.AudienceGroupDTO{{Label: "student", Viewers: 10}}, groups)
+
+	groups = foldAudience(map[string]int64{"DKV": 5, "RPL": 1, "TKJ": 1})
+	assert.Equal(t, []dto.AudienceGroupDTO{{Label: "DKV", Viewers: 5}}, groups)
+}
+
+func TestParseViewSource(t *testing.T) {
+	assert.Equal(t, domain.ViewSourceFeed, ParseViewSource("feed"))
+	assert.Equal(t, domain.ViewSourceDirect, ParseViewSource(""))
+	// share link visits cannot be claimed by the client
+	assert.Equal(t, domain.ViewSourceDirect, ParseViewSource("share_link"))
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/repository"
+)
+
+// MaxPinnedPortfolios bounds the portfolios pinned on top of one profile
+const MaxPinnedPortfolios = 6
+
+var (
+	ErrPinLimit        = errors.New("too many pinned portfolios")
+	ErrPinNotPublished = errors.New("only published portfolios can be pinned")
+	ErrNotAuthored     = errors.New("portfolio is not authored by the user")
+)
+
+// ProfileOrderService manages which portfolios lead a profile and in what order they show
+type ProfileOrderService struct {
+	portfolioRepo *repository.PortfolioRepository
+}
+
+func NewProfileOrderService(portfolioRepo *repository.PortfolioRepository) *ProfileOrderService {
+	return &ProfileOrderService{portfolioRepo: portfolioRepo}
+}
+
+// Pin puts a published portfolio the user authors on top of their profile
+func (s *ProfileOrderService) Pin(userID uuid.UUID, portfolio *domain.Portfolio, now time.Time) error {
+	if err := s.checkAuthored(userID, []uuid.UUID{portfolio.ID}); err != nil {
+		return err
+	}
+	if portfolio.Status != domain.StatusPublished {
+		return ErrPinNotPublished
+	}
+
+	order, err := s.portfolioRepo.ProfileOrder(userID)
+	if err != nil {
+		return err
+	}
+	if order[portfolio.ID].IsPinned {
+		return nil
+	}
+	pinned, err := s.portfolioRepo.CountPinned(userID)
+	if err != nil {
+		return err
+	}
+	if pinned >= MaxPinnedPortfolios {
+		return ErrPinLimit
+	}
+	return s.portfolioRepo.SetPinned(userID, portfolio.ID, true, now)
+}
+
+// Unpin takes a portfolio off the top of the user's profile
+func (s *ProfileOrderService) Unpin(userID uuid.UUID, portfolio *domain.Portfolio, now time.Time) error {
+	if err := s.checkAuthored(userID, []uuid.UUID{portfolio.ID}); err != nil {
+		return err
+	}
+	return s.portfolioRepo.SetPinned(userID, portfolio.ID, false, now)
+}
+
+// Reorder sets the display order of the user's portfolios to the order of portfolioIDs.
+// Portfolios left out fall behind the ordered ones, newest first.
+func (s *ProfileOrderService) Reorder(userID uuid.UUID, portfolioIDs []uuid.UUID, now time.Time) error {
+	if err := s.checkAuthored(userID, portfolioIDs); err != nil {
+		return err
+	}
+	return s.portfolioRepo.ReorderProfile(userID, portfolioIDs, now)
+}
+
+func (s *ProfileOrderService) checkAuthored(userID uuid.UUID, portfolioIDs []uuid.UUID) error {
+	authored, err := s.portfolioRepo.AuthoredIDs(userID, portfolioIDs)
+	if err != nil {
+		return err
+	}
+	if len(authored) != len(uniqueIDs(portfolioIDs)) {
+		return ErrNotAuthored
+	}
+	return nil
+}
+
+func uniqueIDs(ids []uuid.UUID) map[uuid.UUID]struct{} {
+	set := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return set
+}
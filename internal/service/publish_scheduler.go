@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/grafikarsa/backend/internal/repository"
+)
+
+// publishBatchSize bounds the portfolios published per tick
+const publishBatchSize = 100
+
+// PublishScheduler publishes approved portfolios once their publish_at time arrives
+// and sends the approval notification at that moment.
+type PublishScheduler struct {
+	portfolioRepo *repository.PortfolioRepository
+	notifService  *NotificationService
+}
+
+func NewPublishScheduler(portfolioRepo *repository.PortfolioRepository, notifService *NotificationService) *PublishScheduler {
+	return &PublishScheduler{portfolioRepo: portfolioRepo, notifService: notifService}
+}
+
+// Start runs PublishDue every interval until ctx is cancelled
+func (s *PublishScheduler) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.PublishDue(time.Now()); err != nil {
+			log.Printf("[SCHEDULER] Failed to publish scheduled portfolios: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishDue publishes every scheduled portfolio due at now and returns how many went live
+func (s *PublishScheduler) PublishDue(now time.Time) (int, error) {
+	published := 0
+	for {
+		due, err := s.portfolioRepo.FindDueScheduled(now, publishBatchSize)
+		if err != nil {
+			return published, err
+		}
+
+		for i := range due {
+			portfolio := &due[i]
+			ok, err := s.portfolioRepo.PublishScheduled(portfolio.ID, now)
+			if err != nil {
+				return published, err
+			}
+			if !ok {
+				continue
+			}
+			published++
+
+			if s.notifService != nil {
+				if err := s.notifService.NotifyPortfolioApproved(portfolio); err != nil {
+					log.Printf("[SCHEDULER] Failed to notify owner of portfolio %s: %v", portfolio.ID, err)
+				}
+			}
+		}
+
+		if len(due) < publishBatchSize {
+			return published, nil
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/repository"
+)
+
+var (
+	ErrRejectionReasonRequired = errors.New("at least one rejection reason is required")
+	ErrRejectionReasonInvalid  = errors.New("rejection reason is unknown or inactive")
+	ErrRejectionBlockInvalid   = errors.New("block is not part of the reviewed content")
+)
+
+// RejectionBlockNote points the student at a content block that needs fixing
+type RejectionBlockNote struct {
+	BlockID uuid.UUID
+	Note    string
+}
+
+// RejectionService turns a moderator's picked reasons into a structured rejection record
+type RejectionService struct {
+	repo *repository.RejectionRepository
+}
+
+func NewRejectionService(repo *repository.RejectionRepository) *RejectionService {
+	return &RejectionService{repo: repo}
+}
+
+// Prepare checks the picked reasons and block pointers against the content under review, the
+// pending draft when draft is set and the portfolio itself otherwise. It returns the rejection
+// to record and the review note shown to the student.
+func (s *RejectionService) Prepare(portfolio *domain.Portfolio, draft *domain.PortfolioDraft, reasonIDs []uuid.UUID, note string, blocks []RejectionBlockNote) (*domain.PortfolioRejection, string, error) {
+	picked := uniqueIDs(reasonIDs)
+	if len(picked) == 0 {
+		return nil, "", ErrRejectionReasonRequired
+	}
+	ids := make([]uuid.UUID, 0, len(picked))
+	for id := range picked {
+		ids = append(ids, id)
+	}
+	reasons, err := s.repo.FindActiveReasons(ids)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(reasons) != len(ids) {
+		return nil, "", ErrRejectionReasonInvalid
+	}
+
+	reviewed := make(map[uuid.UUID]bool)
+	if draft != nil {
+		for _, b := range draft.Content.Blocks {
+			reviewed[b.ID] = true
+		}
+	} else {
+		for _, b := range portfolio.ContentBlocks {
+			reviewed[b.ID] = true
+		}
+	}
+	pointers := make([]domain.PortfolioRejectionBlock, 0, len(blocks))
+	for _, b := range blocks {
+		if !reviewed[b.BlockID] {
+			return nil, "", ErrRejectionBlockInvalid
+		}
+		pointers = append(pointers, domain.PortfolioRejectionBlock{BlockID: b.BlockID, Note: b.Note})
+	}
+
+	rejection := &domain.PortfolioRejection{
+		PortfolioID: portfolio.ID,
+		IsDraft:     draft != nil,
+		Reasons:     reasons,
+		Blocks:      pointers,
+	}
+	if note = strings.TrimSpace(note); note != "" {
+		rejection.Note = &note
+	}
+	return rejection, ComposeReviewNote(reasons, note), nil
+}
+
+// Record stores a rejection built by Prepare
+func (s *RejectionService) Record(rejection *domain.PortfolioRejection, rejectedBy *uuid.UUID) error {
+	rejection.RejectedBy = rejectedBy
+	return s.repo.CreateRejection(rejection)
+}
+
+// ComposeReviewNote renders the picked reasons as a list followed by the moderator's note. It
+// fills admin_review_note, which older clients and the notification still read as plain text.
+func ComposeReviewNote(reasons []domain.RejectionReason, note string) string {
+	lines := make([]string, 0, len(reasons))
+	for _, r := range reasons {
+		lines = append(lines, "- "+r.Nama)
+	}
+	composed := strings.Join(lines, "\n")
+	if note = strings.TrimSpace(note); note != "" {
+		if composed != "" {
+			composed += "\n\n"
+		}
+		composed += note
+	}
+	return composed
+}
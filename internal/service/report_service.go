@@ -0,0 +1,318 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/repository"
+)
+
+// reportExcerptLength bounds the text of a reported comment or message shown in the queue
+const reportExcerptLength = 280
+
+var (
+	ErrReportTargetNotFound = errors.New("report target not found")
+	ErrReportOwnContent     = errors.New("users cannot report their own content")
+	ErrAlreadyReported      = errors.New("target already reported by the user")
+	ErrNoPendingReports     = errors.New("target has no pending reports")
+	ErrReportActionInvalid  = errors.New("action does not apply to the report target")
+)
+
+// ReportService takes user reports on content and carries out moderators' decisions on them
+type ReportService struct {
+	repo                *repository.ReportRepository
+	portfolioRepo       *repository.PortfolioRepository
+	commentRepo         *repository.CommentRepository
+	dmRepo              *repository.DMRepository
+	userRepo            *repository.UserRepository
+	adminRepo           *repository.AdminRepository
+	notificationService *NotificationService
+}
+
+func NewReportService(
+	repo *repository.ReportRepository,
+	portfolioRepo *repository.PortfolioRepository,
+	commentRepo *repository.CommentRepository,
+	dmRepo *repository.DMRepository,
+	userRepo *repository.UserRepository,
+	adminRepo *repository.AdminRepository,
+	notificationService *NotificationService,
+) *ReportService {
+	return &ReportService{
+		repo:                repo,
+		portfolioRepo:       portfolioRepo,
+		commentRepo:         commentRepo,
+		dmRepo:              dmRepo,
+		userRepo:            userRepo,
+		adminRepo:           adminRepo,
+		notificationService: notificationService,
+	}
+}
+
+// CanDeactivate reports whether a moderator may deactivate a reported user from the report
+// queue. Admins are never deactivated this way, and a moderator cannot take out someone
+// holding capabilities the moderator lacks; admins hold them all.
+func CanDeactivate(target *domain.User, targetCaps []string, moderator *domain.User, moderatorCaps []string) bool {
+	if target.Role == domain.RoleAdmin {
+		return false
+	}
+	if moderator.Role == domain.RoleAdmin {
+		return true
+	}
+	held := make(map[string]bool, len(moderatorCaps))
+	for _, c := range moderatorCaps {
+		held[c] = true
+	}
+	for _, c := range targetCaps {
+		if !held[c] {
+			return false
+		}
+	}
+	return true
+}
+
+// ReportActionApplies reports whether a moderator can take action on a target of targetType
+func ReportActionApplies(targetType domain.ReportTargetType, action domain.ReportAction) bool {
+	switch action {
+	case domain.ReportActionDismiss, domain.ReportActionDeactivateUser:
+		return true
+	case domain.ReportActionHideContent:
+		return targetType == domain.ReportTargetComment || targetType == domain.ReportTargetMessage
+	case domain.ReportActionUnpublish:
+		return targetType == domain.ReportTargetPortfolio
+	}
+	return false
+}
+
+// Submit files a report on something the reporter can see. A reporter has at most one open
+// report per target; reports from different users on the same target are kept side by side.
+func (s *ReportService) Submit(reporterID uuid.UUID, viewer repository.Viewer, targetType domain.ReportTargetType, targetID uuid.UUID, reason domain.ReportReason, details *string) (*domain.ContentReport, error) {
+	ownerID, err := s.targetOwner(reporterID, viewer, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if ownerID == reporterID {
+		return nil, ErrReportOwnContent
+	}
+
+	exists, err := s.repo.HasPending(reporterID, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrAlreadyReported
+	}
+
+	report := &domain.ContentReport{
+		ReporterID:   &reporterID,
+		TargetType:   targetType,
+		TargetID:     targetID,
+		TargetUserID: ownerID,
+		Reason:       reason,
+		Details:      details,
+		Status:       domain.ReportStatusPending,
+	}
+	if err := s.repo.Create(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Resolve carries out action on a reported target, closes every open report on it and lets
+// the reporters know. It returns the number of reports closed.
+func (s *ReportService) Resolve(targetType domain.ReportTargetType, targetID uuid.UUID, action domain.ReportAction, note *string, moderatorID uuid.UUID, now time.Time) (int, error) {
+	if !ReportActionApplies(targetType, action) {
+		return 0, ErrReportActionInvalid
+	}
+	pending, err := s.repo.FindByTarget(targetType, targetID, domain.ReportStatusPending)
+	if err != nil {
+		return 0, err
+	}
+	if len(pending) == 0 {
+		return 0, ErrNoPendingReports
+	}
+
+	switch action {
+	case domain.ReportActionHideContent:
+		if targetType == domain.ReportTargetComment {
+			err = s.commentRepo.SetHidden(targetID, true)
+		} else {
+			err = s.dmRepo.DeleteMessage(targetID)
+		}
+	case domain.ReportActionUnpublish:
+		err = s.unpublish(targetID, note, moderatorID, now)
+	case domain.ReportActionDeactivateUser:
+		err = s.deactivateAuthor(pending[0].TargetUserID, moderatorID)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	status := domain.ReportStatusResolved
+	if action == domain.ReportActionDismiss {
+		status = domain.ReportStatusDismissed
+	}
+	reporterIDs, err := s.repo.ResolvePending(targetType, targetID, status, action, note, moderatorID, now)
+	if err != nil {
+		return 0, err
+	}
+	if s.notificationService != nil {
+		_ = s.notificationService.NotifyReportResolved(reporterIDs, targetType, targetID, status)
+	}
+	return len(pending), nil
+}
+
+// Preview summarizes a reported target for the moderation queue. Targets that were removed
+// since they were reported come back as unavailable.
+func (s *ReportService) Preview(targetType domain.ReportTargetType, targetID uuid.UUID) dto.ReportTargetDTO {
+	preview := dto.ReportTargetDTO{Type: string(targetType), ID: targetID}
+	switch targetType {
+	case domain.ReportTargetPortfolio:
+		if p, err := s.portfolioRepo.FindByID(targetID); err == nil {
+			status := string(p.Status)
+			preview.Available = true
+			preview.Title = p.Judul
+			preview.Slug = &p.Slug
+			preview.Status = &status
+		}
+	case domain.ReportTargetComment:
+		if c, err := s.commentRepo.FindByID(targetID); err == nil {
+			preview.Available = true
+			preview.Excerpt = excerpt(c.Content)
+			if c.Portfolio != nil {
+				preview.Title = c.Portfolio.Judul
+				preview.Slug = &c.Portfolio.Slug
+			}
+			if c.IsHidden {
+				status := "hidden"
+				preview.Status = &status
+			}
+		}
+	case domain.ReportTargetUser:
+		if u, err := s.userRepo.FindByID(targetID); err == nil {
+			status := "active"
+			if !u.IsActive {
+				status = "inactive"
+			}
+			preview.Available = true
+			preview.Title = u.Nama
+			preview.Slug = &u.Username
+			preview.Excerpt = u.Bio
+			preview.Status = &status
+		}
+	case domain.ReportTargetMessage:
+		if m, err := s.dmRepo.FindMessageByID(targetID); err == nil {
+			preview.Available = true
+			preview.Title = string(m.MessageType)
+			if text, ok := m.Content["text"].(string); ok {
+				preview.Excerpt = excerpt(text)
+			}
+		}
+	}
+	return preview
+}
+
+// targetOwner returns the user responsible for a target the reporter is able to see
+func (s *ReportService) targetOwner(reporterID uuid.UUID, viewer repository.Viewer, targetType domain.ReportTargetType, targetID uuid.UUID) (uuid.UUID, error) {
+	switch targetType {
+	case domain.ReportTargetPortfolio:
+		p, err := s.portfolioRepo.FindByID(targetID)
+		if err != nil {
+			return uuid.Nil, ErrReportTargetNotFound
+		}
+		if visible, err := s.portfolioRepo.IsVisibleTo(p.ID, viewer); err != nil || !visible {
+			return uuid.Nil, ErrReportTargetNotFound
+		}
+		return p.UserID, nil
+	case domain.ReportTargetComment:
+		c, err := s.commentRepo.FindByID(targetID)
+		if err != nil || c.IsHidden {
+			return uuid.Nil, ErrReportTargetNotFound
+		}
+		if visible, err := s.portfolioRepo.IsVisibleTo(c.PortfolioID, viewer); err != nil || !visible {
+			return uuid.Nil, ErrReportTargetNotFound
+		}
+		return c.UserID, nil
+	case domain.ReportTargetUser:
+		u, err := s.userRepo.FindByID(targetID)
+		if err != nil {
+			return uuid.Nil, ErrReportTargetNotFound
+		}
+		return u.ID, nil
+	case domain.ReportTargetMessage:
+		m, err := s.dmRepo.FindMessageByID(targetID)
+		if err != nil {
+			return uuid.Nil, ErrReportTargetNotFound
+		}
+		// Only the other side of a conversation can report its messages
+		if inConv, err := s.dmRepo.IsUserInConversation(m.ConversationID, reporterID); err != nil || !inConv {
+			return uuid.Nil, ErrReportTargetNotFound
+		}
+		return m.SenderID, nil
+	}
+	return uuid.Nil, ErrReportTargetNotFound
+}
+
+// deactivateAuthor deactivates a reported user unless CanDeactivate rules it out for the moderator
+func (s *ReportService) deactivateAuthor(targetUserID, moderatorID uuid.UUID) error {
+	target, err := s.userRepo.FindByID(targetUserID)
+	if err != nil {
+		return ErrReportTargetNotFound
+	}
+	moderator, err := s.userRepo.FindByID(moderatorID)
+	if err != nil {
+		return err
+	}
+	targetCaps, err := s.adminRepo.GetUserCapabilities(targetUserID)
+	if err != nil {
+		return err
+	}
+	moderatorCaps, err := s.adminRepo.GetUserCapabilities(moderatorID)
+	if err != nil {
+		return err
+	}
+	if !CanDeactivate(target, targetCaps, moderator, moderatorCaps) {
+		return ErrReportActionInvalid
+	}
+	return s.userRepo.UpdateFields(targetUserID, map[string]interface{}{"is_active": false})
+}
+
+// unpublish takes a reported portfolio off the public pages the way a rejection does, so its
+// authors see the moderator's note and can fix and resubmit it
+func (s *ReportService) unpublish(portfolioID uuid.UUID, note *string, moderatorID uuid.UUID, now time.Time) error {
+	portfolio, err := s.portfolioRepo.FindByID(portfolioID)
+	if err != nil {
+		return ErrReportTargetNotFound
+	}
+	if portfolio.Status != domain.StatusPublished {
+		return nil
+	}
+
+	reviewNote := "Portfolio diturunkan oleh moderator karena laporan pengguna"
+	if note != nil && strings.TrimSpace(*note) != "" {
+		reviewNote = strings.TrimSpace(*note)
+	}
+	portfolio.Status = domain.StatusRejected
+	portfolio.AdminReviewNote = &reviewNote
+	portfolio.ReviewedBy = &moderatorID
+	portfolio.ReviewedAt = &now
+	if err := s.portfolioRepo.Update(portfolio); err != nil {
+		return err
+	}
+	if s.notificationService != nil {
+		_ = s.notificationService.NotifyPortfolioRejected(portfolio, reviewNote)
+	}
+	return nil
+}
+
+func excerpt(text string) *string {
+	runes := []rune(text)
+	if len(runes) > reportExcerptLength {
+		text = string(runes[:reportExcerptLength]) + "…"
+	}
+	return &text
+}
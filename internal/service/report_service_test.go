@@ -0,0 +1,23 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// Moderators can only deactivate reported users who hold no more access than they do
+func TestCanDeactivate(t *testing.T) {
+	admin := &domain.User{Role: domain.RoleAdmin}
+	teacher := &domain.User{Role: domain.RoleTeacher}
+	student := &domain.User{Role: domain.RoleStudent}
+	moderation := []string{"moderation"}
+
+	assert.True(t, CanDeactivate(student, nil, teacher, moderation))
+	assert.True(t, CanDeactivate(teacher, moderation, student, moderation), "an equal peer can be deactivated")
+	assert.False(t, CanDeactivate(admin, nil, teacher, moderation), "admins are never deactivated from reports")
+	assert.False(t, CanDeactivate(admin, nil, admin, nil), "not even by another admin")
+	assert.False(t, CanDeactivate(teacher, []string{"moderation", "users"}, student, moderation), "the target holds access the moderator lacks")
+	assert.True(t, CanDeactivate(teacher, []string{"moderation", "users"}, admin, nil), "admins hold every capability")
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/repository"
+)
+
+const (
+	// ReviewClaimTTL is how long a claim taken by a moderator locks a pending portfolio
+	ReviewClaimTTL = 30 * time.Minute
+	// ReviewAssignmentTTL is how long an automatic assignment the reviewer has not picked up
+	// holds before the item goes back to the pool
+	ReviewAssignmentTTL = 2 * time.Hour
+	// assignBatchSize bounds the portfolios assigned per tick
+	assignBatchSize = 100
+)
+
+var (
+	ErrClaimedByOther   = errors.New("portfolio is claimed by another reviewer")
+	ErrNotPendingReview = errors.New("portfolio is not waiting for review")
+	ErrNotClaimed       = errors.New("reviewer holds no claim on the portfolio")
+	ErrNotReviewer      = errors.New("user cannot review portfolios")
+)
+
+// ReviewClaimService keeps two moderators off the same pending portfolio and hands new
+// submissions to the reviewer pool of the author's jurusan in turn
+type ReviewClaimService struct {
+	repo      *repository.ReviewRepository
+	adminRepo *repository.AdminRepository
+	userRepo  *repository.UserRepository
+}
+
+func NewReviewClaimService(repo *repository.ReviewRepository, adminRepo *repository.AdminRepository, userRepo *repository.UserRepository) *ReviewClaimService {
+	return &ReviewClaimService{repo: repo, adminRepo: adminRepo, userRepo: userRepo}
+}
+
+// Claim locks a pending portfolio for the reviewer for ReviewClaimTTL. Claiming again extends
+// the lock; an automatic assignment that lasts longer keeps its expiry.
+func (s *ReviewClaimService) Claim(portfolioID, reviewerID uuid.UUID, now time.Time) (*domain.ReviewClaim, error) {
+	pending, err := s.repo.IsPendingReview(portfolioID)
+	if err != nil {
+		return nil, err
+	}
+	if !pending {
+		return nil, ErrNotPendingReview
+	}
+
+	current, err := s.repo.ActiveClaim(portfolioID, now)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil && current.ReviewerID != reviewerID {
+		return current, ErrClaimedByOther
+	}
+
+	claim := &domain.ReviewClaim{PortfolioID: portfolioID, ReviewerID: reviewerID, ClaimedAt: now, ExpiresAt: now.Add(ReviewClaimTTL)}
+	if current != nil {
+		claim.IsAssigned = current.IsAssigned
+		claim.ClaimedAt = current.ClaimedAt
+		if current.ExpiresAt.After(claim.ExpiresAt) {
+			claim.ExpiresAt = current.ExpiresAt
+		}
+	}
+	taken, err := s.repo.Claim(claim, now)
+	if err != nil {
+		return nil, err
+	}
+	if !taken {
+		// Someone claimed it between our check and the insert
+		current, err = s.repo.ActiveClaim(portfolioID, now)
+		if err != nil {
+			return nil, err
+		}
+		return current, ErrClaimedByOther
+	}
+	return claim, nil
+}
+
+// Release gives up the reviewer's claim so others can pick the portfolio up
+func (s *ReviewClaimService) Release(portfolioID, reviewerID uuid.UUID) error {
+	released, err := s.repo.Release(portfolioID, reviewerID)
+	if err != nil {
+		return err
+	}
+	if !released {
+		return ErrNotClaimed
+	}
+	return nil
+}
+
+// CheckClaim returns ErrClaimedByOther, along with that claim, when a reviewer other than
+// reviewerID holds the portfolio. Unclaimed portfolios may be acted on by anyone.
+func (s *ReviewClaimService) CheckClaim(portfolioID, reviewerID uuid.UUID, now time.Time) (*domain.ReviewClaim, error) {
+	current, err := s.repo.ActiveClaim(portfolioID, now)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil && current.ReviewerID != reviewerID {
+		return current, ErrClaimedByOther
+	}
+	return current, nil
+}
+
+// ForceRelease drops every claim on a portfolio so an admin can free one held by a reviewer
+// who is not getting to it
+func (s *ReviewClaimService) ForceRelease(portfolioID uuid.UUID) error {
+	return s.repo.ReleaseAll(portfolioID)
+}
+
+// Done drops the claims on a portfolio after it was approved or rejected
+func (s *ReviewClaimService) Done(portfolioID uuid.UUID) {
+	if err := s.repo.ReleaseAll(portfolioID); err != nil {
+		log.Printf("[REVIEW] Failed to release claims on portfolio %s: %v", portfolioID, err)
+	}
+}
+
+// SetPool replaces the reviewers that receive automatic assignments for a jurusan
+func (s *ReviewClaimService) SetPool(jurusanID uuid.UUID, reviewerIDs []uuid.UUID) error {
+	ids := make([]uuid.UUID, 0, len(reviewerIDs))
+	for id := range uniqueIDs(reviewerIDs) {
+		ok, err := s.canReview(id)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrNotReviewer
+		}
+		ids = append(ids, id)
+	}
+	return s.repo.SetPool(jurusanID, ids)
+}
+
+func (s *ReviewClaimService) canReview(userID uuid.UUID) (bool, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || !user.IsActive {
+		return false, nil
+	}
+	if user.Role == domain.RoleAdmin {
+		return true, nil
+	}
+	return s.adminRepo.HasCapability(userID, "moderation")
+}
+
+// Start runs AssignPending every interval until ctx is cancelled
+func (s *ReviewClaimService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.AssignPending(time.Now()); err != nil {
+			log.Printf("[REVIEW] Failed to assign pending portfolios: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// AssignPending hands every unclaimed pending portfolio to the pool member of its author's
+// jurusan whose last assignment is oldest, and returns how many were assigned. Members who
+// were deactivated or lost moderation access since joining the pool are passed over.
+func (s *ReviewClaimService) AssignPending(now time.Time) (int, error) {
+	items, err := s.repo.UnclaimedPending(now, assignBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	eligible := make(map[uuid.UUID]bool)
+	assigned := 0
+	for _, item := range items {
+		members, err := s.repo.NextReviewers(item.JurusanID, item.UserID)
+		if err != nil {
+			return assigned, err
+		}
+		next, err := s.firstReviewer(members, eligible)
+		if err != nil {
+			return assigned, err
+		}
+		if next == nil {
+			continue
+		}
+		claim := &domain.ReviewClaim{
+			PortfolioID: item.PortfolioID,
+			ReviewerID:  next.ReviewerID,
+			IsAssigned:  true,
+			ClaimedAt:   now,
+			ExpiresAt:   now.Add(ReviewAssignmentTTL),
+		}
+		taken, err := s.repo.Assign(claim, item.JurusanID, now)
+		if err != nil {
+			return assigned, err
+		}
+		if taken {
+			assigned++
+		}
+	}
+	return assigned, nil
+}
+
+// firstReviewer returns the first pool member who may still review, remembering each answer
+// in eligible for the rest of the tick
+func (s *ReviewClaimService) firstReviewer(members []domain.ReviewerPool, eligible map[uuid.UUID]bool) (*domain.ReviewerPool, error) {
+	for i := range members {
+		id := members[i].ReviewerID
+		ok, seen := eligible[id]
+		if !seen {
+			var err error
+			if ok, err = s.canReview(id); err != nil {
+				return nil, err
+			}
+			eligible[id] = ok
+		}
+		if ok {
+			return &members[i], nil
+		}
+	}
+	return nil, nil
+}
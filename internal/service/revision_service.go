@@ -0,0 +1,182 @@
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/repository"
+)
+
+// Block change kinds reported by DiffSnapshots
+const (
+	BlockAdded    = "added"
+	BlockRemoved  = "removed"
+	BlockModified = "modified"
+	BlockMoved    = "moved"
+)
+
+type RevisionService struct {
+	repo *repository.RevisionRepository
+}
+
+func NewRevisionService(repo *repository.RevisionRepository) *RevisionService {
+	return &RevisionService{repo: repo}
+}
+
+// Record stores an immutable snapshot of the portfolio; tags and content blocks must be preloaded
+func (s *RevisionService) Record(portfolio *domain.Portfolio, reason domain.RevisionReason, createdBy *uuid.UUID) (*domain.PortfolioRevision, error) {
+	return s.RecordSnapshot(portfolio.ID, domain.NewPortfolioSnapshot(portfolio), reason, createdBy)
+}
+
+// RecordSnapshot stores an already captured snapshot, e.g. the content of a working draft
+func (s *RevisionService) RecordSnapshot(portfolioID uuid.UUID, snapshot domain.PortfolioSnapshot, reason domain.RevisionReason, createdBy *uuid.UUID) (*domain.PortfolioRevision, error) {
+	revision := &domain.PortfolioRevision{
+		PortfolioID: portfolioID,
+		Reason:      reason,
+		CreatedBy:   createdBy,
+		Snapshot:    snapshot,
+	}
+	if err := s.repo.Create(revision); err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// DiffSnapshots compares two snapshots: changed metadata fields, added/removed tags and
+// per-block changes matched by block ID. From/To summaries are left for the caller.
+func DiffSnapshots(from, to domain.PortfolioSnapshot) dto.RevisionDiffDTO {
+	diff := dto.RevisionDiffDTO{
+		Fields:      []dto.RevisionFieldChange{},
+		TagsAdded:   []dto.TagDTO{},
+		TagsRemoved: []dto.TagDTO{},
+		Blocks:      []dto.RevisionBlockChange{},
+	}
+
+	addField := func(field string, a, b interface{}) {
+		if !reflect.DeepEqual(a, b) {
+			diff.Fields = append(diff.Fields, dto.RevisionFieldChange{Field: field, From: a, To: b})
+		}
+	}
+	addField("judul", from.Judul, to.Judul)
+	addField("slug", from.Slug, to.Slug)
+	addField("thumbnail_url", derefString(from.ThumbnailURL), derefString(to.ThumbnailURL))
+	addField("status", string(from.Status), string(to.Status))
+	addField("series_id", uuidString(from.SeriesID), uuidString(to.SeriesID))
+
+	fromTags := make(map[uuid.UUID]string, len(from.Tags))
+	for _, t := range from.Tags {
+		fromTags[t.ID] = t.Nama
+	}
+	toTags := make(map[uuid.UUID]bool, len(to.Tags))
+	for _, t := range to.Tags {
+		toTags[t.ID] = true
+		if _, ok := fromTags[t.ID]; !ok {
+			diff.TagsAdded = append(diff.TagsAdded, dto.TagDTO{ID: t.ID, Nama: t.Nama})
+		}
+	}
+	for _, t := range from.Tags {
+		if !toTags[t.ID] {
+			diff.TagsRemoved = append(diff.TagsRemoved, dto.TagDTO{ID: t.ID, Nama: t.Nama})
+		}
+	}
+
+	fromBlocks := make(map[uuid.UUID]domain.SnapshotBlock, len(from.Blocks))
+	for _, b := range from.Blocks {
+		fromBlocks[b.ID] = b
+	}
+	toBlocks := make(map[uuid.UUID]bool, len(to.Blocks))
+	for _, b := range to.Blocks {
+		toBlocks[b.ID] = true
+		toOrder := b.BlockOrder
+		old, ok := fromBlocks[b.ID]
+		if !ok {
+			diff.Blocks = append(diff.Blocks, dto.RevisionBlockChange{
+				BlockID: b.ID, BlockType: string(b.BlockType), Change: BlockAdded,
+				ToOrder: &toOrder, ToPayload: b.Payload,
+			})
+			continue
+		}
+
+		fromOrder := old.BlockOrder
+		change := dto.RevisionBlockChange{
+			BlockID: b.ID, BlockType: string(b.BlockType), FromOrder: &fromOrder, ToOrder: &toOrder,
+		}
+		if keys := changedPayloadKeys(old.Payload, b.Payload); len(keys) > 0 || old.BlockType != b.BlockType {
+			change.Change = BlockModified
+			change.ChangedKeys = keys
+			change.FromPayload = old.Payload
+			change.ToPayload = b.Payload
+		} else if fromOrder != toOrder {
+			change.Change = BlockMoved
+		} else {
+			continue
+		}
+		diff.Blocks = append(diff.Blocks, change)
+	}
+	for _, b := range from.Blocks {
+		if !toBlocks[b.ID] {
+			fromOrder := b.BlockOrder
+			diff.Blocks = append(diff.Blocks, dto.RevisionBlockChange{
+				BlockID: b.ID, BlockType: string(b.BlockType), Change: BlockRemoved,
+				FromOrder: &fromOrder, FromPayload: b.Payload,
+			})
+		}
+	}
+
+	return diff
+}
+
+// changedPayloadKeys returns the sorted top-level keys whose values differ.
+// Values are compared after a JSON round trip so numbers decoded from the database
+// compare equal to freshly built payloads.
+func changedPayloadKeys(a, b domain.JSONB) []string {
+	a, b = normalizePayload(a), normalizePayload(b)
+	keys := make(map[string]bool)
+	for k, v := range a {
+		if !reflect.DeepEqual(v, b[k]) {
+			keys[k] = true
+		}
+	}
+	for k, v := range b {
+		if _, ok := a[k]; !ok || !reflect.DeepEqual(v, a[k]) {
+			keys[k] = true
+		}
+	}
+
+	result := make([]string, 0, len(keys))
+	for k := range keys {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func normalizePayload(p domain.JSONB) domain.JSONB {
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return p
+	}
+	var out domain.JSONB
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return p
+	}
+	return out
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func uuidString(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
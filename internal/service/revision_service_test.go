@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	kept, moved, edited, removed, added := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	tagA, tagB := uuid.New(), uuid.New()
+
+	from := domain.PortfolioSnapshot{
+		Judul:  "Poster Lama",
+		Slug:   "poster-lama",
+		Status: domain.StatusPendingReview,
+		Tags:   []domain.SnapshotTag{{ID: tagA, Nama: "Desain"}},
+		Blocks: []domain.SnapshotBlock{
+			{ID: kept, BlockType: domain.BlockText, BlockOrder: 0, Payload: domain.JSONB{"content": "halo"}},
+			{ID: moved, BlockType: domain.BlockImage, BlockOrder: 1, Payload: domain.JSONB{"url": "a.png"}},
+			{ID: edited, BlockType: domain.BlockText, BlockOrder: 2, Payload: domain.JSONB{"content": "lama", "size": 12}},
+			{ID: removed, BlockType: domain.BlockText, BlockOrder: 3, Payload: domain.JSONB{"content": "hapus"}},
+		},
+	}
+	to := domain.PortfolioSnapshot{
+		Judul:  "Poster Baru",
+		Slug:   "poster-lama",
+		Status: domain.StatusPublished,
+		Tags:   []domain.SnapshotTag{{ID: tagB, Nama: "Ilustrasi"}},
+		Blocks: []domain.SnapshotBlock{
+			{ID: kept, BlockType: domain.BlockText, BlockOrder: 0, Payload: domain.JSONB{"content": "halo"}},
+			{ID: edited, BlockType: domain.BlockText, BlockOrder: 1, Payload: domain.JSONB{"content": "baru", "size": float64(12)}},
+			{ID: moved, BlockType: domain.BlockImage, BlockOrder: 2, Payload: domain.JSONB{"url": "a.png"}},
+			{ID: added, BlockType: domain.BlockText, BlockOrder: 3, Payload: domain.JSONB{"content": "tambah"}},
+		},
+	}
+
+	diff := DiffSnapshots(from, to)
+
+	fields := map[string]dto.RevisionFieldChange{}
+	for _, f := range diff.Fields {
+		fields[f.Field] = f
+	}
+	assert.Len(t, fields, 2)
+	assert.Equal(t, "Poster Baru", fields["judul"].To)
+	assert.Equal(t, string(domain.StatusPublished), fields["status"].To)
+
+	assert.Equal(t, []dto.TagDTO{{ID: tagB, Nama: "Ilustrasi"}}, diff.TagsAdded)
+	assert.Equal(t, []dto.TagDTO{{ID: tagA, Nama: "Desain"}}, diff.TagsRemoved)
+
+	changes := map[uuid.UUID]dto.RevisionBlockChange{}
+	for _, b := range diff.Blocks {
+		changes[b.BlockID] = b
+	}
+	assert.Len(t, changes, 4)
+	assert.NotContains(t, changes, kept)
+	assert.Equal(t, BlockMoved, changes[moved].Change)
+	assert.Equal(t, BlockModified, changes[edited].Change)
+	assert.Equal(t, []string{"content"}, changes[edited].ChangedKeys)
+	assert.Equal(t, BlockRemoved, changes[removed].Change)
+	assert.Nil(t, changes[removed].ToOrder)
+	assert.Equal(t, BlockAdded, changes[added].Change)
+	assert.Equal(t, 3, *changes[added].ToOrder)
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/repository"
+)
+
+// Assignment progress of a targeted student, from least to most advanced
+const (
+	AssignmentNotStarted = "not_started"
+	AssignmentDraft      = "draft"
+	AssignmentPending    = "pending"
+	AssignmentPublished  = "published"
+)
+
+// reminderLead is how long before the deadline students who have not submitted are reminded
+const reminderLead = 24 * time.Hour
+
+var assignmentRank = map[string]int{
+	AssignmentNotStarted: 0,
+	AssignmentDraft:      1,
+	AssignmentPending:    2,
+	AssignmentPublished:  3,
+}
+
+// SeriesAssignmentService tracks series handed out as assignments to kelas/jurusan:
+// the per-student submission report and the reminder sent ahead of the deadline.
+type SeriesAssignmentService struct {
+	assignmentRepo *repository.SeriesAssignmentRepository
+	notifService   *NotificationService
+}
+
+func NewSeriesAssignmentService(assignmentRepo *repository.SeriesAssignmentRepository, notifService *NotificationService) *SeriesAssignmentService {
+	return &SeriesAssignmentService{assignmentRepo: assignmentRepo, notifService: notifService}
+}
+
+// AssignmentStatus maps a portfolio status onto the assignment progress it stands for
+func AssignmentStatus(status domain.PortfolioStatus) string {
+	switch status {
+	case domain.StatusPendingReview:
+		return AssignmentPending
+	case domain.StatusPublished, domain.StatusScheduled, domain.StatusArchived:
+		return AssignmentPublished
+	default:
+		return AssignmentDraft
+	}
+}
+
+// Report lists every targeted student with their most advanced portfolio in the series.
+// With waliKelasID set the report is limited to that teacher's classes.
+func (s *SeriesAssignmentService) Report(series *domain.Series, waliKelasID *uuid.UUID, now time.Time) (*dto.SeriesReportDTO, error) {
+	students, err := s.assignmentRepo.ListTargetStudents(series.ID, waliKelasID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(students))
+	for i, st := range students {
+		ids[i] = st.ID
+	}
+	work, err := s.assignmentRepo.ListWork(series.ID, ids)
+	if err != nil {
+		return nil, err
+	}
+	best := bestWork(work)
+
+	report := &dto.SeriesReportDTO{
+		SeriesID: series.ID,
+		Nama:     series.Nama,
+		OpensAt:  series.OpensAt,
+		ClosesAt: series.ClosesAt,
+		Students: make([]dto.AssignmentStudentDTO, 0, len(students)),
+	}
+	for _, st := range students {
+		row := dto.AssignmentStudentDTO{
+			ID:        st.ID,
+			Username:  st.Username,
+			Nama:      st.Nama,
+			AvatarURL: st.AvatarURL,
+			Status:    AssignmentNotStarted,
+		}
+		if st.Kelas != nil {
+			row.KelasNama = &st.Kelas.Nama
+		}
+		if w, ok := best[st.ID]; ok {
+			portfolioID, judul := w.PortfolioID, w.Judul
+			row.Status = AssignmentStatus(w.Status)
+			row.PortfolioID = &portfolioID
+			row.PortfolioJudul = &judul
+			row.SubmittedAt = w.SubmittedAt
+		}
+		row.IsLate = isLateAssignment(series, row.Status, row.SubmittedAt, now)
+
+		report.Summary.Total++
+		switch row.Status {
+		case AssignmentNotStarted:
+			report.Summary.NotStarted++
+		case AssignmentDraft:
+			report.Summary.Draft++
+		case AssignmentPending:
+			report.Summary.Pending++
+		case AssignmentPublished:
+			report.Summary.Published++
+		}
+		if row.IsLate {
+			report.Summary.Late++
+		}
+		report.Students = append(report.Students, row)
+	}
+	return report, nil
+}
+
+// Start runs RemindDue every interval until ctx is cancelled
+func (s *SeriesAssignmentService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RemindDue(time.Now()); err != nil {
+			log.Printf("[ASSIGNMENT] Failed to send deadline reminders: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RemindDue notifies targeted students who have not submitted yet of every series closing
+// within reminderLead of now, once per deadline, and returns how many were reminded
+func (s *SeriesAssignmentService) RemindDue(now time.Time) (int, error) {
+	due, err := s.assignmentRepo.FindDueReminders(now, now.Add(reminderLead))
+	if err != nil {
+		return 0, err
+	}
+
+	reminded := 0
+	for i := range due {
+		series := &due[i]
+		claimed, err := s.assignmentRepo.MarkReminderSent(series.ID, now)
+		if err != nil {
+			return reminded, err
+		}
+		if !claimed {
+			continue
+		}
+
+		report, err := s.Report(series, nil, now)
+		if err != nil {
+			return reminded, err
+		}
+		for _, st := range report.Students {
+			if st.Status != AssignmentNotStarted && st.Status != AssignmentDraft {
+				continue
+			}
+			if err := s.notifService.NotifySeriesReminder(st.ID, series); err != nil {
+				log.Printf("[ASSIGNMENT] Failed to remind user %s of series %s: %v", st.ID, series.ID, err)
+				continue
+			}
+			reminded++
+		}
+	}
+	return reminded, nil
+}
+
+// bestWork keeps the most advanced portfolio of each author
+func bestWork(work []repository.SeriesWork) map[uuid.UUID]repository.SeriesWork {
+	best := make(map[uuid.UUID]repository.SeriesWork, len(work))
+	for _, w := range work {
+		current, ok := best[w.AuthorID]
+		if !ok || assignmentRank[AssignmentStatus(w.Status)] > assignmentRank[AssignmentStatus(current.Status)] {
+			best[w.AuthorID] = w
+		}
+	}
+	return best
+}
+
+// isLateAssignment flags work submitted after the deadline, and work still unsubmitted once
+// it has passed. Published work without a submission time was placed by an admin and is never late.
+func isLateAssignment(series *domain.Series, status string, submittedAt *time.Time, now time.Time) bool {
+	if submittedAt == nil && (status == AssignmentPending || status == AssignmentPublished) {
+		return false
+	}
+	return series.IsLate(submittedAt, now)
+}
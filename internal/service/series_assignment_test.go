@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBestWork_KeepsMostAdvancedPortfolio(t *testing.T) {
+	student, coAuthor := uuid.New(), uuid.New()
+	draft, pending, published := uuid.New(), uuid.New(), uuid.New()
+
+	best := bestWork([]repository.SeriesWork{
+		{AuthorID: student, PortfolioID: draft, Status: domain.StatusRejected},
+		{AuthorID: student, PortfolioID: pending, Status: domain.StatusPendingReview},
+		{AuthorID: student, PortfolioID: draft, Status: domain.StatusDraft},
+		{AuthorID: coAuthor, PortfolioID: published, Status: domain.StatusScheduled},
+	})
+
+	assert.Equal(t, pending, best[student].PortfolioID)
+	assert.Equal(t, AssignmentPublished, AssignmentStatus(best[coAuthor].Status))
+}
+
+func TestIsLateAssignment(t *testing.T) {
+	closes := time.Date(2025, 3, 1, 17, 0, 0, 0, time.UTC)
+	series := &domain.Series{ClosesAt: &closes}
+	before, after := closes.Add(-time.Hour), closes.Add(time.Hour)
+
+	assert.False(t, isLateAssignment(series, AssignmentPending, &before, after))
+	assert.True(t, isLateAssignment(series, AssignmentPending, &after, after))
+	assert.False(t, isLateAssignment(series, AssignmentNotStarted, nil, before))
+	assert.True(t, isLateAssignment(series, AssignmentNotStarted, nil, after))
+	assert.False(t, isLateAssignment(series, AssignmentPublished, nil, after))
+	assert.False(t, isLateAssignment(&domain.Series{}, AssignmentDraft, nil, after))
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/dto"
+	"github.com/grafikarsa/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// SeriesTemplateService scaffolds portfolios from the block template of their series and
+// checks the required template blocks before a portfolio goes to review.
+type SeriesTemplateService struct {
+	portfolioRepo *repository.PortfolioRepository
+}
+
+func NewSeriesTemplateService(portfolioRepo *repository.PortfolioRepository) *SeriesTemplateService {
+	return &SeriesTemplateService{portfolioRepo: portfolioRepo}
+}
+
+// TemplateBlocks returns the block template of a series; a nil series has no template
+func (s *SeriesTemplateService) TemplateBlocks(seriesID *uuid.UUID) ([]domain.SeriesBlock, error) {
+	if seriesID == nil {
+		return nil, nil
+	}
+	return s.portfolioRepo.FindSeriesBlocks(*seriesID)
+}
+
+// Scaffold adds an empty placeholder block for every template block of the portfolio's series
+// that has no linked block yet. Content blocks of portfolio must be preloaded.
+func (s *SeriesTemplateService) Scaffold(portfolio *domain.Portfolio) error {
+	template, err := s.TemplateBlocks(portfolio.SeriesID)
+	if err != nil || len(template) == 0 {
+		return err
+	}
+
+	placeholders := placeholderBlocks(template, domain.NewPortfolioSnapshot(portfolio).Blocks)
+	blocks := make([]domain.ContentBlock, 0, len(placeholders))
+	for _, p := range placeholders {
+		blocks = append(blocks, domain.ContentBlock{
+			ID:            p.ID,
+			PortfolioID:   portfolio.ID,
+			SeriesBlockID: p.SeriesBlockID,
+			BlockType:     p.BlockType,
+			BlockOrder:    p.BlockOrder,
+			Payload:       p.Payload,
+		})
+	}
+	return s.portfolioRepo.CreateContentBlocks(blocks)
+}
+
+// ScaffoldDraft is Scaffold for the working draft of an approved portfolio; the caller saves the draft
+func (s *SeriesTemplateService) ScaffoldDraft(draft *domain.PortfolioDraft) error {
+	template, err := s.TemplateBlocks(draft.Content.SeriesID)
+	if err != nil {
+		return err
+	}
+	draft.Content.Blocks = append(draft.Content.Blocks, placeholderBlocks(template, draft.Content.Blocks)...)
+	return nil
+}
+
+// MissingRequired lists the required template blocks of the series that are absent from
+// blocks or still empty, as validation details for the submit response
+func (s *SeriesTemplateService) MissingRequired(seriesID *uuid.UUID, blocks []domain.SnapshotBlock) ([]dto.ErrorDetail, error) {
+	template, err := s.TemplateBlocks(seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	linked := make(map[uuid.UUID]domain.SnapshotBlock, len(blocks))
+	for _, b := range blocks {
+		if b.SeriesBlockID != nil {
+			linked[*b.SeriesBlockID] = b
+		}
+	}
+
+	var details []dto.ErrorDetail
+	for _, t := range template {
+		if !t.IsRequired {
+			continue
+		}
+		if b, ok := linked[t.ID]; ok && IsPayloadFilled(b.Payload) {
+			continue
+		}
+		details = append(details, dto.ErrorDetail{
+			Field:   "content_blocks." + t.ID.String(),
+			Message: fmt.Sprintf("Block template #%d (%s) wajib diisi: %s", t.BlockOrder+1, t.BlockType, t.Instruksi),
+		})
+	}
+	return details, nil
+}
+
+// IsRequired reports whether a block linked to seriesBlockID is a required block of the
+// portfolio's current series and so may not be deleted
+func (s *SeriesTemplateService) IsRequired(seriesID, seriesBlockID *uuid.UUID) (bool, error) {
+	if seriesID == nil || seriesBlockID == nil {
+		return false, nil
+	}
+	block, err := s.portfolioRepo.FindSeriesBlockByID(*seriesBlockID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return block.IsRequired && block.SeriesID == *seriesID, nil
+}
+
+// IsPayloadFilled reports whether a block payload holds any content: a non-blank string,
+// a number or bool, or a non-empty list or object
+func IsPayloadFilled(payload domain.JSONB) bool {
+	for _, v := range payload {
+		if isValueFilled(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func isValueFilled(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return false
+	case string:
+		return strings.TrimSpace(val) != ""
+	case []interface{}:
+		for _, item := range val {
+			if isValueFilled(item) {
+				return true
+			}
+		}
+		return false
+	case map[string]interface{}:
+		return IsPayloadFilled(val)
+	default:
+		return true
+	}
+}
+
+// placeholderBlocks returns empty blocks for the template blocks not linked from existing,
+// in template order after the last existing block
+func placeholderBlocks(template []domain.SeriesBlock, existing []domain.SnapshotBlock) []domain.SnapshotBlock {
+	linked := make(map[uuid.UUID]bool, len(existing))
+	order := 0
+	for _, b := range existing {
+		if b.SeriesBlockID != nil {
+			linked[*b.SeriesBlockID] = true
+		}
+		if b.BlockOrder >= order {
+			order = b.BlockOrder + 1
+		}
+	}
+
+	var placeholders []domain.SnapshotBlock
+	for _, t := range template {
+		if linked[t.ID] {
+			continue
+		}
+		seriesBlockID := t.ID
+		placeholders = append(placeholders, domain.SnapshotBlock{
+			ID:            uuid.New(),
+			SeriesBlockID: &seriesBlockID,
+			BlockType:     t.BlockType,
+			BlockOrder:    order,
+			Payload:       domain.JSONB{},
+		})
+		order++
+	}
+	return placeholders
+}
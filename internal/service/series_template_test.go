@@ -0,0 +1,41 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaceholderBlocks_SkipsLinkedAndAppends(t *testing.T) {
+	intro, gallery, video := uuid.New(), uuid.New(), uuid.New()
+	template := []domain.SeriesBlock{
+		{ID: intro, BlockType: domain.BlockText, BlockOrder: 0, Instruksi: "Ceritakan konsep"},
+		{ID: gallery, BlockType: domain.BlockImage, BlockOrder: 1, Instruksi: "Unggah hasil akhir"},
+		{ID: video, BlockType: domain.BlockYoutube, BlockOrder: 2, Instruksi: "Video proses"},
+	}
+	existing := []domain.SnapshotBlock{
+		{ID: uuid.New(), SeriesBlockID: &intro, BlockType: domain.BlockText, BlockOrder: 0},
+		{ID: uuid.New(), BlockType: domain.BlockText, BlockOrder: 4},
+	}
+
+	placeholders := placeholderBlocks(template, existing)
+
+	require.Len(t, placeholders, 2)
+	assert.Equal(t, gallery, *placeholders[0].SeriesBlockID)
+	assert.Equal(t, domain.BlockImage, placeholders[0].BlockType)
+	assert.Equal(t, 5, placeholders[0].BlockOrder)
+	assert.Equal(t, video, *placeholders[1].SeriesBlockID)
+	assert.Equal(t, 6, placeholders[1].BlockOrder)
+	assert.False(t, IsPayloadFilled(placeholders[0].Payload))
+}
+
+func TestIsPayloadFilled(t *testing.T) {
+	assert.False(t, IsPayloadFilled(domain.JSONB{}))
+	assert.False(t, IsPayloadFilled(domain.JSONB{"content": "  ", "rows": []interface{}{}}))
+	assert.False(t, IsPayloadFilled(domain.JSONB{"meta": map[string]interface{}{"url": ""}}))
+	assert.True(t, IsPayloadFilled(domain.JSONB{"content": "Konsep poster"}))
+	assert.True(t, IsPayloadFilled(domain.JSONB{"rows": []interface{}{[]interface{}{"a"}}}))
+}
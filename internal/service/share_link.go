@@ -0,0 +1,155 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/auth"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultShareLinkDays = 7
+	MaxShareLinkDays     = 30
+	// MaxActiveShareLinks bounds the links that can be open on one portfolio at a time
+	MaxActiveShareLinks = 10
+	// MaxShareCommentLength bounds a guest comment
+	MaxShareCommentLength = 2000
+)
+
+var (
+	ErrShareLinkNotFound = errors.New("share link not found")
+	ErrShareLinkExpired  = errors.New("share link expired")
+	ErrShareLinkLimit    = errors.New("too many active share links")
+	ErrShareCommentsOff  = errors.New("comments are disabled for this share link")
+)
+
+// ShareLinkService mints and opens private share links of portfolios
+type ShareLinkService struct {
+	repo          *repository.ShareLinkRepository
+	portfolioRepo *repository.PortfolioRepository
+	notifService  *NotificationService
+}
+
+func NewShareLinkService(repo *repository.ShareLinkRepository, portfolioRepo *repository.PortfolioRepository, notifService *NotificationService) *ShareLinkService {
+	return &ShareLinkService{repo: repo, portfolioRepo: portfolioRepo, notifService: notifService}
+}
+
+// Mint creates a link valid for days and returns it with its token, which is not stored
+// and cannot be shown again
+func (s *ShareLinkService) Mint(portfolioID, createdBy uuid.UUID, label *string, allowComments bool, days int, now time.Time) (*domain.PortfolioShareLink, string, error) {
+	active, err := s.repo.CountActive(portfolioID, now)
+	if err != nil {
+		return nil, "", err
+	}
+	if active >= MaxActiveShareLinks {
+		return nil, "", ErrShareLinkLimit
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return nil, "", err
+	}
+	link := &domain.PortfolioShareLink{
+		PortfolioID:   portfolioID,
+		CreatedBy:     createdBy,
+		TokenHash:     auth.HashToken(token),
+		Label:         label,
+		AllowComments: allowComments,
+		ExpiresAt:     ShareLinkExpiry(days, now),
+	}
+	if err := s.repo.Create(link); err != nil {
+		return nil, "", err
+	}
+	return link, token, nil
+}
+
+// Open resolves a token to its link and counts the visit
+func (s *ShareLinkService) Open(token string, now time.Time) (*domain.PortfolioShareLink, error) {
+	link, err := s.find(token, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RecordView(link.ID, now); err != nil {
+		log.Printf("[SHARE] Failed to count view of share link %s: %v", link.ID, err)
+	}
+	link.ViewCount++
+	return link, nil
+}
+
+// Find resolves a token to its link without counting a visit
+func (s *ShareLinkService) Find(token string, now time.Time) (*domain.PortfolioShareLink, error) {
+	return s.find(token, now)
+}
+
+// Comment stores a guest comment left through link and tells the authors about it
+func (s *ShareLinkService) Comment(link *domain.PortfolioShareLink, authorName, content string) (*domain.ShareLinkComment, error) {
+	if !link.AllowComments {
+		return nil, ErrShareCommentsOff
+	}
+	comment := &domain.ShareLinkComment{
+		ShareLinkID: link.ID,
+		PortfolioID: link.PortfolioID,
+		AuthorName:  strings.TrimSpace(authorName),
+		Content:     strings.TrimSpace(content),
+	}
+	if err := s.repo.CreateComment(comment); err != nil {
+		return nil, err
+	}
+
+	if s.notifService != nil {
+		if portfolio, err := s.portfolioRepo.FindByID(link.PortfolioID); err == nil {
+			if err := s.notifService.NotifyShareComment(portfolio, link, comment); err != nil {
+				log.Printf("[SHARE] Failed to notify authors of portfolio %s: %v", portfolio.ID, err)
+			}
+		}
+	}
+	return comment, nil
+}
+
+func (s *ShareLinkService) find(token string, now time.Time) (*domain.PortfolioShareLink, error) {
+	if token == "" {
+		return nil, ErrShareLinkNotFound
+	}
+	link, err := s.repo.FindByTokenHash(auth.HashToken(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrShareLinkNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if link.RevokedAt != nil {
+		return nil, ErrShareLinkNotFound
+	}
+	if !link.IsActive(now) {
+		return nil, ErrShareLinkExpired
+	}
+	return link, nil
+}
+
+// ShareLinkExpiry is the expiry of a link minted at now for days, with 0 meaning the
+// default and anything longer than the maximum capped
+func ShareLinkExpiry(days int, now time.Time) time.Time {
+	if days <= 0 {
+		days = DefaultShareLinkDays
+	}
+	if days > MaxShareLinkDays {
+		days = MaxShareLinkDays
+	}
+	return now.Add(time.Duration(days) * 24 * time.Hour)
+}
+
+func newShareToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShareLinkExpiry(t *testing.T) {
+	now := time.Date(2025, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, now.AddDate(0, 0, DefaultShareLinkDays), ShareLinkExpiry(0, now))
+	assert.Equal(t, now.AddDate(0, 0, 3), ShareLinkExpiry(3, now))
+	assert.Equal(t, now.AddDate(0, 0, MaxShareLinkDays), ShareLinkExpiry(90, now))
+
+	link := domain.PortfolioShareLink{ExpiresAt: ShareLinkExpiry(1, now)}
+	assert.True(t, link.IsActive(now))
+	assert.False(t, link.IsActive(link.ExpiresAt))
+	link.RevokedAt = &now
+	assert.False(t, link.IsActive(now))
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/grafikarsa/backend/internal/repository"
+)
+
+// textFilterRefresh is how long loaded rules are used before they are read again, so rule
+// changes made on another instance reach this one as well
+const textFilterRefresh = time.Minute
+
+var ErrContentBlocked = errors.New("content contains a blocked term")
+
+// TextFilterResult is what the filter found in a piece of text
+type TextFilterResult struct {
+	Blocked bool
+	Flagged bool
+	// Matches lists the patterns that hit, shown to moderators on flagged content
+	Matches []string
+}
+
+// Merge adds the findings of another check, for content made of several fields
+func (r *TextFilterResult) Merge(other TextFilterResult) {
+	r.Blocked = r.Blocked || other.Blocked
+	r.Flagged = r.Flagged || other.Flagged
+	r.Matches = append(r.Matches, other.Matches...)
+}
+
+type compiledFilterRule struct {
+	pattern string
+	action  domain.TextFilterAction
+	re      *regexp.Regexp
+}
+
+// CompileFilterRule turns a rule into a case-insensitive expression. Words and phrases only
+// match whole words, so a rule for "ass" leaves "class" alone. A boundary is only required
+// on a side that ends in a word character, so "c++" and "@admin" still match.
+func CompileFilterRule(matchType domain.TextFilterMatch, pattern string) (*regexp.Regexp, error) {
+	if matchType == domain.TextFilterWord {
+		word := strings.TrimSpace(pattern)
+		expr := regexp.QuoteMeta(word)
+		if first, _ := utf8.DecodeRuneInString(word); isWordRune(first) {
+			expr = `\b` + expr
+		}
+		if last, _ := utf8.DecodeLastRuneInString(word); isWordRune(last) {
+			expr += `\b`
+		}
+		return regexp.Compile(`(?i)` + expr)
+	}
+	return regexp.Compile(`(?i)` + pattern)
+}
+
+// isWordRune reports whether r counts as a word character for \b, which is ASCII only
+func isWordRune(r rune) bool {
+	return r == '_' || ('0' <= r && r <= '9') || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z')
+}
+
+// applyFilterRules checks text against every rule; mask rules star out their matches
+func applyFilterRules(rules []compiledFilterRule, text string) (string, TextFilterResult) {
+	var result TextFilterResult
+	for _, rule := range rules {
+		if !rule.re.MatchString(text) {
+			continue
+		}
+		result.Matches = append(result.Matches, rule.pattern)
+		switch rule.action {
+		case domain.TextFilterBlock:
+			result.Blocked = true
+		case domain.TextFilterFlag:
+			result.Flagged = true
+		case domain.TextFilterMask:
+			text = rule.re.ReplaceAllStringFunc(text, func(m string) string {
+				return strings.Repeat("*", utf8.RuneCountInString(m))
+			})
+		}
+	}
+	return text, result
+}
+
+// TextFilterService screens user-written text for banned terms when it is saved
+type TextFilterService struct {
+	repo       *repository.TextFilterRepository
+	reportRepo *repository.ReportRepository
+
+	mu       sync.RWMutex
+	rules    []compiledFilterRule
+	loadedAt time.Time
+}
+
+func NewTextFilterService(repo *repository.TextFilterRepository, reportRepo *repository.ReportRepository) *TextFilterService {
+	return &TextFilterService{repo: repo, reportRepo: reportRepo}
+}
+
+// Invalidate makes the next check read the rules again, after an admin changed them
+func (s *TextFilterService) Invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// Apply runs the active rules over text and returns it with masked terms starred out
+func (s *TextFilterService) Apply(text string) (string, TextFilterResult) {
+	if strings.TrimSpace(text) == "" {
+		return text, TextFilterResult{}
+	}
+	return applyFilterRules(s.activeRules(), text)
+}
+
+// ApplyPayload filters the free-text fields of a content block payload in place. Code,
+// embed markup, URLs and identifiers are left alone.
+func (s *TextFilterService) ApplyPayload(blockType domain.ContentBlockType, payload domain.JSONB) TextFilterResult {
+	var result TextFilterResult
+	if blockType == domain.BlockCode || blockType == domain.BlockEmbed {
+		return result
+	}
+	s.applyFields(blockPayloadSchemas[blockType], payload, &result)
+	return result
+}
+
+func (s *TextFilterService) applyFields(fields []payloadField, payload map[string]interface{}, result *TextFilterResult) {
+	for _, field := range fields {
+		value, ok := payload[field.Name]
+		if !ok {
+			continue
+		}
+		switch field.Kind {
+		case fieldString:
+			if field.Check == nil {
+				payload[field.Name] = s.applyValue(value, result)
+			}
+		case fieldStringList, fieldStringTable:
+			payload[field.Name] = s.applyValue(value, result)
+		case fieldObjectList:
+			items, _ := value.([]interface{})
+			for _, item := range items {
+				if obj, ok := item.(map[string]interface{}); ok {
+					s.applyFields(field.Items, obj, result)
+				}
+			}
+		}
+	}
+}
+
+// applyValue filters a string or a (nested) list of strings
+func (s *TextFilterService) applyValue(value interface{}, result *TextFilterResult) interface{} {
+	switch v := value.(type) {
+	case string:
+		filtered, r := s.Apply(v)
+		result.Merge(r)
+		return filtered
+	case []interface{}:
+		for i := range v {
+			v[i] = s.applyValue(v[i], result)
+		}
+	}
+	return value
+}
+
+// Flag puts content that hit a flag rule into the moderation queue, once per target
+func (s *TextFilterService) Flag(targetType domain.ReportTargetType, targetID, ownerID uuid.UUID, result TextFilterResult) {
+	if !result.Flagged {
+		return
+	}
+	exists, err := s.reportRepo.HasPendingFlag(targetType, targetID)
+	if err != nil || exists {
+		return
+	}
+	seen := make(map[string]bool, len(result.Matches))
+	var matches []string
+	for _, m := range result.Matches {
+		if !seen[m] {
+			seen[m] = true
+			matches = append(matches, m)
+		}
+	}
+	details := "Terdeteksi filter otomatis: " + strings.Join(matches, ", ")
+	report := &domain.ContentReport{
+		TargetType:   targetType,
+		TargetID:     targetID,
+		TargetUserID: ownerID,
+		Reason:       domain.ReportReasonAutoFilter,
+		Details:      &details,
+		Status:       domain.ReportStatusPending,
+	}
+	if err := s.reportRepo.Create(report); err != nil {
+		log.Printf("[FILTER] Failed to flag %s %s: %v", targetType, targetID, err)
+	}
+}
+
+// activeRules returns the compiled active rules, reading them again once they are stale.
+// When the read fails the previous rules stay in use.
+func (s *TextFilterService) activeRules() []compiledFilterRule {
+	s.mu.RLock()
+	rules, fresh := s.rules, time.Since(s.loadedAt) < textFilterRefresh
+	s.mu.RUnlock()
+	if fresh {
+		return rules
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if time.Since(s.loadedAt) < textFilterRefresh {
+		return s.rules
+	}
+	stored, err := s.repo.ListActive()
+	if err != nil {
+		log.Printf("[FILTER] Failed to load text filter rules: %v", err)
+		return s.rules
+	}
+	compiled := make([]compiledFilterRule, 0, len(stored))
+	for _, rule := range stored {
+		re, err := CompileFilterRule(rule.MatchType, rule.Pattern)
+		if err != nil {
+			log.Printf("[FILTER] Skipping rule %s: %v", rule.ID, err)
+			continue
+		}
+		compiled = append(compiled, compiledFilterRule{pattern: rule.Pattern, action: rule.Action, re: re})
+	}
+	s.rules, s.loadedAt = compiled, time.Now()
+	return s.rules
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafikarsa/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFilterRules(t *testing.T) []compiledFilterRule {
+	rules := []struct {
+		match   domain.TextFilterMatch
+		pattern string
+		action  domain.TextFilterAction
+	}{
+		{domain.TextFilterWord, "ass", domain.TextFilterMask},
+		{domain.TextFilterWord, "judi online", domain.TextFilterBlock},
+		{domain.TextFilterRegex, `wa\.me/\d+`, domain.TextFilterFlag},
+	}
+	var compiled []compiledFilterRule
+	for _, r := range rules {
+		re, err := CompileFilterRule(r.match, r.pattern)
+		require.NoError(t, err)
+		compiled = append(compiled, compiledFilterRule{pattern: r.pattern, action: r.action, re: re})
+	}
+	return compiled
+}
+
+func TestApplyFilterRules(t *testing.T) {
+	rules := testFilterRules(t)
+
+	text, result := applyFilterRules(rules, "Kelas desain, bukan ASS biasa")
+	assert.Equal(t, "Kelas desain, bukan *** biasa", text, "whole words only, any case")
+	assert.False(t, result.Blocked)
+	assert.False(t, result.Flagged)
+
+	_, result = applyFilterRules(rules, "Promo Judi Online hari ini")
+	assert.True(t, result.Blocked)
+
+	text, result = applyFilterRules(rules, "Hubungi wa.me/628123 ya")
+	assert.True(t, result.Flagged)
+	assert.Equal(t, "Hubungi wa.me/628123 ya", text, "flagged text is kept as written")
+	assert.Equal(t, []string{`wa\.me/\d+`}, result.Matches)
+
+	_, err := CompileFilterRule(domain.TextFilterRegex, "(unclosed")
+	assert.Error(t, err)
+}
+
+// Words that start or end with a symbol only need a boundary on their word side
+func TestCompileFilterRule_SymbolEdges(t *testing.T) {
+	cpp, err := CompileFilterRule(domain.TextFilterWord, "c++")
+	require.NoError(t, err)
+	assert.True(t, cpp.MatchString("belajar C++ dasar"))
+	assert.True(t, cpp.MatchString("belajar c++"))
+	assert.False(t, cpp.MatchString("abc++"))
+
+	mention, err := CompileFilterRule(domain.TextFilterWord, "@admin")
+	require.NoError(t, err)
+	assert.True(t, mention.MatchString("tolong @admin cek"))
+	assert.True(t, mention.MatchString("halo,@admin"))
+	assert.False(t, mention.MatchString("@administrator"))
+}
+
+func TestApplyPayload_OnlyFreeText(t *testing.T) {
+	s := &TextFilterService{rules: testFilterRules(t), loadedAt: time.Now()}
+
+	gallery := domain.JSONB{"images": []interface{}{
+		map[string]interface{}{"url": "https://cdn.example.com/ass.png", "caption": "ass"},
+	}}
+	s.ApplyPayload(domain.BlockGallery, gallery)
+	image := gallery["images"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "https://cdn.example.com/ass.png", image["url"])
+	assert.Equal(t, "***", image["caption"])
+
+	code := domain.JSONB{"code": "let ass = 1", "language": "js"}
+	result := s.ApplyPayload(domain.BlockCode, code)
+	assert.Equal(t, "let ass = 1", code["code"])
+	assert.Empty(t, result.Matches)
+}